@@ -36,7 +36,8 @@ func (h *Handlers) Add(w http.ResponseWriter, r *http.Request) {
 		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
 		return
 	}
-	key, err := h.apiKeyManager.Add(r.Context(), ak)
+	orgName := chi.URLParam(r, "orgName")
+	key, err := h.apiKeyManager.Add(r.Context(), orgName, ak)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "Add").Send()
 		helpers.RenderErrorJSON(w, err)
@@ -49,8 +50,9 @@ func (h *Handlers) Add(w http.ResponseWriter, r *http.Request) {
 
 // Delete is an http handler that deletes the provided api key from the database.
 func (h *Handlers) Delete(w http.ResponseWriter, r *http.Request) {
+	orgName := chi.URLParam(r, "orgName")
 	apiKeyID := chi.URLParam(r, "apiKeyID")
-	if err := h.apiKeyManager.Delete(r.Context(), apiKeyID); err != nil {
+	if err := h.apiKeyManager.Delete(r.Context(), orgName, apiKeyID); err != nil {
 		h.logger.Error().Err(err).Str("method", "Delete").Send()
 		helpers.RenderErrorJSON(w, err)
 		return
@@ -60,8 +62,9 @@ func (h *Handlers) Delete(w http.ResponseWriter, r *http.Request) {
 
 // Get is an http handler that returns the requested api key.
 func (h *Handlers) Get(w http.ResponseWriter, r *http.Request) {
+	orgName := chi.URLParam(r, "orgName")
 	apiKeyID := chi.URLParam(r, "apiKeyID")
-	dataJSON, err := h.apiKeyManager.GetJSON(r.Context(), apiKeyID)
+	dataJSON, err := h.apiKeyManager.GetJSON(r.Context(), orgName, apiKeyID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "Get").Send()
 		helpers.RenderErrorJSON(w, err)
@@ -70,6 +73,20 @@ func (h *Handlers) Get(w http.ResponseWriter, r *http.Request) {
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
 }
 
+// GetOwnedByOrg is an http handler that returns the api keys owned by the
+// organization provided. The user doing the request must belong to the
+// organization.
+func (h *Handlers) GetOwnedByOrg(w http.ResponseWriter, r *http.Request) {
+	orgName := chi.URLParam(r, "orgName")
+	dataJSON, err := h.apiKeyManager.GetOwnedByOrgJSON(r.Context(), orgName)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetOwnedByOrg").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
 // GetOwnedByUser is an http handler that returns the api keys owned by the
 // user doing the request.
 func (h *Handlers) GetOwnedByUser(w http.ResponseWriter, r *http.Request) {
@@ -90,8 +107,9 @@ func (h *Handlers) Update(w http.ResponseWriter, r *http.Request) {
 		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
 		return
 	}
+	orgName := chi.URLParam(r, "orgName")
 	ak.APIKeyID = chi.URLParam(r, "apiKeyID")
-	if err := h.apiKeyManager.Update(r.Context(), ak); err != nil {
+	if err := h.apiKeyManager.Update(r.Context(), orgName, ak); err != nil {
 		h.logger.Error().Err(err).Str("method", "Update").Send()
 		helpers.RenderErrorJSON(w, err)
 		return
@@ -67,7 +67,7 @@ func TestAdd(t *testing.T) {
 
 				hw := newHandlersWrapper()
 				if tc.err != nil {
-					hw.am.On("Add", r.Context(), mock.Anything).Return(nil, tc.err)
+					hw.am.On("Add", r.Context(), "", mock.Anything).Return(nil, tc.err)
 				}
 				hw.h.Add(w, r)
 				resp := w.Result()
@@ -86,7 +86,7 @@ func TestAdd(t *testing.T) {
 		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
 
 		hw := newHandlersWrapper()
-		hw.am.On("Add", r.Context(), ak).Return(nil, tests.ErrFakeDB)
+		hw.am.On("Add", r.Context(), "", ak).Return(nil, tests.ErrFakeDB)
 		hw.h.Add(w, r)
 		resp := w.Result()
 		defer resp.Body.Close()
@@ -102,7 +102,7 @@ func TestAdd(t *testing.T) {
 		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
 
 		hw := newHandlersWrapper()
-		hw.am.On("Add", r.Context(), ak).Return([]byte("key"), nil)
+		hw.am.On("Add", r.Context(), "", ak).Return([]byte("key"), nil)
 		hw.h.Add(w, r)
 		resp := w.Result()
 		defer resp.Body.Close()
@@ -117,6 +117,29 @@ func TestAdd(t *testing.T) {
 		assert.Equal(t, expectedData, data)
 		hw.am.AssertExpectations(t)
 	})
+
+	t.Run("org api key added successfully", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(akJSON))
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+		rctx := &chi.Context{
+			URLParams: chi.RouteParams{
+				Keys:   []string{"orgName"},
+				Values: []string{"org1"},
+			},
+		}
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.am.On("Add", r.Context(), "org1", ak).Return([]byte("key"), nil)
+		hw.h.Add(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		hw.am.AssertExpectations(t)
+	})
 }
 
 func TestDelete(t *testing.T) {
@@ -151,7 +174,7 @@ func TestDelete(t *testing.T) {
 				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
 				hw := newHandlersWrapper()
-				hw.am.On("Delete", r.Context(), apiKeyID).Return(tc.err)
+				hw.am.On("Delete", r.Context(), "", apiKeyID).Return(tc.err)
 				hw.h.Delete(w, r)
 				resp := w.Result()
 				defer resp.Body.Close()
@@ -170,7 +193,7 @@ func TestDelete(t *testing.T) {
 		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
 		hw := newHandlersWrapper()
-		hw.am.On("Delete", r.Context(), apiKeyID).Return(nil)
+		hw.am.On("Delete", r.Context(), "", apiKeyID).Return(nil)
 		hw.h.Delete(w, r)
 		resp := w.Result()
 		defer resp.Body.Close()
@@ -212,7 +235,7 @@ func TestGet(t *testing.T) {
 				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
 				hw := newHandlersWrapper()
-				hw.am.On("GetJSON", r.Context(), apiKeyID).Return(nil, tc.err)
+				hw.am.On("GetJSON", r.Context(), "", apiKeyID).Return(nil, tc.err)
 				hw.h.Get(w, r)
 				resp := w.Result()
 				defer resp.Body.Close()
@@ -231,7 +254,7 @@ func TestGet(t *testing.T) {
 		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
 		hw := newHandlersWrapper()
-		hw.am.On("GetJSON", r.Context(), apiKeyID).Return([]byte("dataJSON"), nil)
+		hw.am.On("GetJSON", r.Context(), "", apiKeyID).Return([]byte("dataJSON"), nil)
 		hw.h.Get(w, r)
 		resp := w.Result()
 		defer resp.Body.Close()
@@ -246,6 +269,72 @@ func TestGet(t *testing.T) {
 	})
 }
 
+func TestGetOwnedByOrg(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"orgName"},
+			Values: []string{"org1"},
+		},
+	}
+
+	t.Run("error getting api keys owned by organization", func(t *testing.T) {
+		testCases := []struct {
+			err                error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				tests.ErrFakeDB,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.err.Error(), func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/", nil)
+				r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.am.On("GetOwnedByOrgJSON", r.Context(), "org1").Return(nil, tc.err)
+				hw.h.GetOwnedByOrg(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.am.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("get api keys owned by organization succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.am.On("GetOwnedByOrgJSON", r.Context(), "org1").Return([]byte("dataJSON"), nil)
+		hw.h.GetOwnedByOrg(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.am.AssertExpectations(t)
+	})
+}
+
 func TestGetOwnedByUser(t *testing.T) {
 	t.Run("error getting api keys owned by user", func(t *testing.T) {
 		t.Parallel()
@@ -318,7 +407,7 @@ func TestUpdate(t *testing.T) {
 
 				hw := newHandlersWrapper()
 				if tc.err != nil {
-					hw.am.On("Update", r.Context(), mock.Anything).Return(tc.err)
+					hw.am.On("Update", r.Context(), "", mock.Anything).Return(tc.err)
 				}
 				hw.h.Update(w, r)
 				resp := w.Result()
@@ -369,7 +458,7 @@ func TestUpdate(t *testing.T) {
 				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
 				hw := newHandlersWrapper()
-				hw.am.On("Update", r.Context(), ak).Return(tc.err)
+				hw.am.On("Update", r.Context(), "", ak).Return(tc.err)
 				hw.h.Update(w, r)
 				resp := w.Result()
 				defer resp.Body.Close()
@@ -0,0 +1,257 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
+	"github.com/artifacthub/hub/cmd/hub/handlers/pkg"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/subscription"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+const (
+	// streamBatchSize represents the maximum number of events fetched from
+	// the database at once while streaming events.
+	streamBatchSize = 50
+
+	// streamPollInterval represents how often the database is checked for new
+	// events while streaming events.
+	streamPollInterval = 5 * time.Second
+
+	// streamMaxDuration represents how long a stream is kept open. It must be
+	// shorter than the server write timeout. Clients are expected to reconnect
+	// when it's closed, resuming it using the Last-Event-ID header (browsers'
+	// EventSource does it automatically).
+	streamMaxDuration = 25 * time.Second
+
+	// streamRetryDelay represents how long clients should wait before
+	// reconnecting once the stream is closed.
+	streamRetryDelay = 1 * time.Second
+
+	// cloudEventsSource represents the source used in the CloudEvents sent.
+	cloudEventsSource = "https://artifacthub.io/cloudevents"
+)
+
+// Handlers represents a group of http handlers in charge of handling events
+// operations.
+type Handlers struct {
+	eventManager hub.EventManager
+	cfg          *viper.Viper
+	logger       zerolog.Logger
+}
+
+// NewHandlers creates a new Handlers instance.
+func NewHandlers(eventManager hub.EventManager, cfg *viper.Viper) *Handlers {
+	return &Handlers{
+		eventManager: eventManager,
+		cfg:          cfg,
+		logger:       log.With().Str("handlers", "event").Logger(),
+	}
+}
+
+// Stream is an http handler that streams the events the user doing the request
+// can receive as CloudEvents using Server-Sent Events. Users receive the
+// events of the packages they are subscribed to and the ones of the
+// repositories they own, and they can filter them by package, repository,
+// organization and event kind. Streams can be resumed providing the id of the
+// last event received in the Last-Event-ID header.
+func (h *Handlers) Stream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.logger.Error().Str("method", "Stream").Msg("streaming not supported")
+		helpers.RenderErrorJSON(w, nil)
+		return
+	}
+
+	// Prepare events input
+	input := &hub.GetUserEventsInput{
+		PackageID:      r.FormValue("package_id"),
+		RepositoryID:   r.FormValue("repository_id"),
+		OrganizationID: r.FormValue("organization_id"),
+		Limit:          streamBatchSize,
+	}
+	for _, kindStr := range r.Form["kind"] {
+		kind, err := strconv.Atoi(kindStr)
+		if err != nil {
+			h.logger.Error().Err(err).Str("method", "Stream").Msg("invalid kind")
+			helpers.RenderErrorJSON(w, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid kind"))
+			return
+		}
+		input.EventKinds = append(input.EventKinds, hub.EventKind(kind))
+	}
+	cursor, err := h.eventManager.GetCursor(r.Context(), r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "Stream").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	input.Cursor = cursor
+
+	// Get first batch of events before starting the stream, so that any
+	// invalid input can still be reported as a regular error
+	events, err := h.eventManager.GetUserEvents(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "Stream").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+
+	// Start stream
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetryDelay.Milliseconds())
+	flusher.Flush()
+
+	baseURL := h.cfg.GetString("server.baseURL")
+	timeout := time.NewTimer(streamMaxDuration)
+	defer timeout.Stop()
+	ticker := time.NewTicker(streamPollInterval)
+	defer ticker.Stop()
+	for {
+		// Send events, skipping the ones that don't pass the filters of the
+		// subscriptions they come from
+		var lastEventIDSent string
+		for _, e := range events {
+			input.Cursor = &hub.EventsCursor{CreatedAt: e.CreatedAt, EventID: e.EventID}
+			if !passesSubscriptionFilters(e) {
+				continue
+			}
+			dataJSON, err := json.Marshal(newCloudEvent(baseURL, e))
+			if err != nil {
+				h.logger.Error().Err(err).Str("method", "Stream").Send()
+				return
+			}
+			fmt.Fprintf(w, "id: %s\ndata: %s\n\n", e.EventID, dataJSON)
+			lastEventIDSent = e.EventID
+		}
+
+		// Let the client know the stream position when the last events were
+		// skipped, so that they aren't processed again when it's resumed
+		if input.Cursor.EventID != "" && input.Cursor.EventID != lastEventIDSent && len(events) > 0 {
+			fmt.Fprintf(w, "id: %s\n\n", input.Cursor.EventID)
+		}
+		flusher.Flush()
+
+		// Get next batch of events (wait a bit if we are up to date)
+		if len(events) < streamBatchSize {
+			select {
+			case <-ticker.C:
+			case <-timeout.C:
+				return
+			case <-r.Context().Done():
+				return
+			}
+		}
+		events, err = h.eventManager.GetUserEvents(r.Context(), input)
+		if err != nil {
+			h.logger.Error().Err(err).Str("method", "Stream").Send()
+			return
+		}
+	}
+}
+
+// passesSubscriptionFilters checks if the event provided passes any of the
+// filters of the subscriptions it comes from.
+func passesSubscriptionFilters(e *hub.UserEvent) bool {
+	if e.EventKind != hub.NewRelease || len(e.SubscriptionFilters) == 0 {
+		return true
+	}
+	for _, f := range e.SubscriptionFilters {
+		if subscription.MatchFilters(f, e.Package) {
+			return true
+		}
+	}
+	return false
+}
+
+// cloudEvent represents an event in the CloudEvents format.
+type cloudEvent struct {
+	SpecVersion     string                 `json:"specversion"`
+	ID              string                 `json:"id"`
+	Source          string                 `json:"source"`
+	Type            string                 `json:"type"`
+	Time            time.Time              `json:"time"`
+	DataContentType string                 `json:"datacontenttype"`
+	Data            map[string]interface{} `json:"data"`
+}
+
+// newCloudEvent creates a new CloudEvent from the event provided. The data
+// included follows the same format used in the webhooks default payloads.
+func newCloudEvent(baseURL string, e *hub.UserEvent) *cloudEvent {
+	ce := &cloudEvent{
+		SpecVersion:     "1.0",
+		ID:              e.EventID,
+		Source:          cloudEventsSource,
+		Time:            e.CreatedAt,
+		DataContentType: "application/json",
+	}
+	switch e.EventKind {
+	case hub.NewRelease:
+		ce.Type = "io.artifacthub.package.new-release"
+		pkgData := newPkgData(baseURL, e)
+		pkgData["containsSecurityUpdates"] = e.Package.ContainsSecurityUpdates
+		pkgData["prerelease"] = e.Package.Prerelease
+		ce.Data = map[string]interface{}{
+			"package": pkgData,
+		}
+	case hub.SecurityAlert:
+		ce.Type = "io.artifacthub.package.security-alert"
+		ce.Data = map[string]interface{}{
+			"package":              newPkgData(baseURL, e),
+			"addedVulnerabilities": e.Data["added_vulnerabilities"],
+		}
+	case hub.RepositoryTrackingErrors:
+		ce.Type = "io.artifacthub.repository.tracking-errors"
+		ce.Data = map[string]interface{}{
+			"repository": newRepoData(e),
+		}
+	case hub.RepositoryOwnershipClaim:
+		ce.Type = "io.artifacthub.repository.ownership-claim"
+		ce.Data = map[string]interface{}{
+			"repository": newRepoData(e),
+		}
+	}
+	return ce
+}
+
+// newPkgData prepares the package data included in the CloudEvents of
+// package events.
+func newPkgData(baseURL string, e *hub.UserEvent) map[string]interface{} {
+	p := *e.Package
+	p.Repository = e.Repository
+	publisher := p.Repository.OrganizationName
+	if publisher == "" {
+		publisher = p.Repository.UserAlias
+	}
+	return map[string]interface{}{
+		"name":    p.Name,
+		"version": e.PackageVersion,
+		"url":     pkg.BuildURL(baseURL, &p, e.PackageVersion),
+		"repository": map[string]interface{}{
+			"kind":      hub.GetKindName(p.Repository.Kind),
+			"name":      p.Repository.Name,
+			"publisher": publisher,
+		},
+	}
+}
+
+// newRepoData prepares the repository data included in the CloudEvents of
+// repository events.
+func newRepoData(e *hub.UserEvent) map[string]interface{} {
+	r := e.Repository
+	return map[string]interface{}{
+		"kind":             hub.GetKindName(r.Kind),
+		"name":             r.Name,
+		"userAlias":        r.UserAlias,
+		"organizationName": r.OrganizationName,
+	}
+}
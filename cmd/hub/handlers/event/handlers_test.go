@@ -0,0 +1,208 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/event"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	os.Exit(m.Run())
+}
+
+func TestStream(t *testing.T) {
+	cursor := &hub.EventsCursor{
+		CreatedAt: time.Date(2020, 6, 16, 9, 20, 34, 0, time.UTC),
+		EventID:   "eventID",
+	}
+
+	t.Run("invalid kind provided", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?kind=invalid", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.h.Stream(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		hw.em.AssertExpectations(t)
+	})
+
+	t.Run("error getting cursor", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.em.On("GetCursor", r.Context(), "").Return(nil, tests.ErrFakeDB)
+		hw.h.Stream(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.em.AssertExpectations(t)
+	})
+
+	t.Run("error getting events", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?package_id=invalid", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.em.On("GetCursor", r.Context(), "").Return(cursor, nil)
+		hw.em.On("GetUserEvents", r.Context(), &hub.GetUserEventsInput{
+			Cursor:    cursor,
+			PackageID: "invalid",
+			Limit:     streamBatchSize,
+		}).Return(nil, hub.ErrInvalidInput)
+		hw.h.Stream(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		hw.em.AssertExpectations(t)
+	})
+
+	t.Run("events streamed successfully", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?repository_id=repositoryID&kind=0&kind=2", nil)
+		r.Header.Set("Last-Event-ID", "eventID")
+		ctx, cancel := context.WithCancel(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+		cancel()
+		r = r.WithContext(ctx)
+
+		repository := &hub.Repository{
+			Kind:             hub.Helm,
+			Name:             "repo1",
+			OrganizationName: "org1",
+		}
+		hw := newHandlersWrapper()
+		hw.em.On("GetCursor", r.Context(), "eventID").Return(cursor, nil)
+		hw.em.On("GetUserEvents", r.Context(), mock.Anything).Return([]*hub.UserEvent{
+			{
+				Event: hub.Event{
+					EventID:        "event1ID",
+					EventKind:      hub.NewRelease,
+					PackageID:      "packageID",
+					PackageVersion: "1.0.0",
+				},
+				CreatedAt: time.Date(2020, 6, 16, 9, 20, 35, 0, time.UTC),
+				Package: &hub.Package{
+					Name:           "package1",
+					NormalizedName: "package1",
+					Version:        "1.0.0",
+				},
+				Repository: repository,
+			},
+			{
+				Event: hub.Event{
+					EventID:        "event2ID",
+					EventKind:      hub.NewRelease,
+					PackageID:      "packageID",
+					PackageVersion: "1.0.1",
+				},
+				CreatedAt: time.Date(2020, 6, 16, 9, 20, 36, 0, time.UTC),
+				Package: &hub.Package{
+					Name:           "package1",
+					NormalizedName: "package1",
+					Version:        "1.0.1",
+				},
+				Repository: repository,
+				SubscriptionFilters: []*hub.NotificationFilters{
+					{MajorMinorOnly: true},
+				},
+			},
+		}, nil)
+		hw.h.Stream(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", h.Get("Content-Type"))
+		assert.Equal(t, "no-cache", h.Get("Cache-Control"))
+		messages := strings.Split(strings.TrimSpace(string(data)), "\n\n")
+		require.Len(t, messages, 3)
+		assert.Equal(t, "retry: 1000", messages[0])
+		require.True(t, strings.HasPrefix(messages[1], "id: event1ID\ndata: "))
+		var ce map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(messages[1], "id: event1ID\ndata: ")), &ce))
+		assert.Equal(t, "io.artifacthub.package.new-release", ce["type"])
+		assert.Equal(t, "2020-06-16T09:20:35Z", ce["time"])
+		pkg := ce["data"].(map[string]interface{})["package"].(map[string]interface{})
+		assert.Equal(t, "http://localhost:8000/packages/helm/repo1/package1/1.0.0", pkg["url"])
+		assert.Equal(t, "org1", pkg["repository"].(map[string]interface{})["publisher"])
+		assert.Equal(t, "id: event2ID", messages[2])
+		hw.em.AssertExpectations(t)
+		input := hw.em.Calls[1].Arguments.Get(1).(*hub.GetUserEventsInput)
+		assert.Equal(t, "repositoryID", input.RepositoryID)
+		assert.Equal(t, []hub.EventKind{hub.NewRelease, hub.RepositoryTrackingErrors}, input.EventKinds)
+	})
+}
+
+func TestNewCloudEvent(t *testing.T) {
+	e := &hub.UserEvent{
+		Event: hub.Event{
+			EventID:   "eventID",
+			EventKind: hub.RepositoryOwnershipClaim,
+		},
+		Repository: &hub.Repository{
+			Kind:      hub.Helm,
+			Name:      "repo1",
+			UserAlias: "user1",
+		},
+	}
+
+	ce := newCloudEvent("http://localhost:8000", e)
+	assert.Equal(t, "1.0", ce.SpecVersion)
+	assert.Equal(t, "eventID", ce.ID)
+	assert.Equal(t, cloudEventsSource, ce.Source)
+	assert.Equal(t, "io.artifacthub.repository.ownership-claim", ce.Type)
+	assert.Equal(t, map[string]interface{}{
+		"repository": map[string]interface{}{
+			"kind":             "helm",
+			"name":             "repo1",
+			"userAlias":        "user1",
+			"organizationName": "",
+		},
+	}, ce.Data)
+}
+
+type handlersWrapper struct {
+	em *event.ManagerMock
+	h  *Handlers
+}
+
+func newHandlersWrapper() *handlersWrapper {
+	cfg := viper.New()
+	cfg.Set("server.baseURL", "http://localhost:8000")
+	em := &event.ManagerMock{}
+
+	return &handlersWrapper{
+		em: em,
+		h:  NewHandlers(em, cfg),
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/artifacthub/hub/cmd/hub/handlers/apikey"
+	"github.com/artifacthub/hub/cmd/hub/handlers/event"
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/cmd/hub/handlers/org"
 	"github.com/artifacthub/hub/cmd/hub/handlers/pkg"
@@ -39,6 +40,7 @@ type Services struct {
 	SubscriptionManager hub.SubscriptionManager
 	WebhookManager      hub.WebhookManager
 	APIKeyManager       hub.APIKeyManager
+	EventManager        hub.EventManager
 	ImageStore          img.Store
 	Authorizer          hub.Authorizer
 }
@@ -64,6 +66,7 @@ type Handlers struct {
 	Subscriptions *subscription.Handlers
 	Webhooks      *webhook.Handlers
 	APIKeys       *apikey.Handlers
+	Events        *event.Handlers
 	Static        *static.Handlers
 }
 
@@ -83,9 +86,10 @@ func Setup(ctx context.Context, cfg *viper.Viper, svc *Services) (*Handlers, err
 		Users:         userHandlers,
 		Repositories:  repo.NewHandlers(svc.RepositoryManager),
 		Packages:      pkg.NewHandlers(svc.PackageManager, cfg),
-		Subscriptions: subscription.NewHandlers(svc.SubscriptionManager),
-		Webhooks:      webhook.NewHandlers(svc.WebhookManager),
+		Subscriptions: subscription.NewHandlers(svc.SubscriptionManager, cfg),
+		Webhooks:      webhook.NewHandlers(svc.WebhookManager, svc.PackageManager, cfg),
 		APIKeys:       apikey.NewHandlers(svc.APIKeyManager),
+		Events:        event.NewHandlers(svc.EventManager, cfg),
 		Static:        static.NewHandlers(cfg, svc.ImageStore),
 	}
 	h.setupRouter()
@@ -123,19 +127,46 @@ func (h *Handlers) setupRouter() {
 	}
 	r.NotFound(h.Static.ServeIndex)
 
+	// API keys scopes requirements (routes that require login but don't use
+	// any of these cannot be accessed using scoped api keys)
+	var (
+		noScope     = h.Users.RequireScope()
+		readOnly    = h.Users.RequireScope(hub.ReadOnlyScope)
+		readRepos   = h.Users.RequireScope(hub.ReadOnlyScope, hub.ManageRepositoriesScope)
+		manageRepos = h.Users.RequireScope(hub.ManageRepositoriesScope)
+		readSubs    = h.Users.RequireScope(hub.ReadOnlyScope, hub.ManageSubscriptionsScope)
+		manageSubs  = h.Users.RequireScope(hub.ManageSubscriptionsScope)
+		readWhs     = h.Users.RequireScope(hub.ReadOnlyScope, hub.ManageWebhooksScope)
+		manageWhs   = h.Users.RequireScope(hub.ManageWebhooksScope)
+	)
+
 	// API
 	r.Route("/api/v1", func(r chi.Router) {
 		// Users
 		r.Route("/users", func(r chi.Router) {
 			r.Post("/", h.Users.RegisterUser)
+			r.Put("/approve-session", h.Users.ApproveSession)
 			r.Post("/login", h.Users.Login)
+			r.Post("/password-reset-code", h.Users.RegisterPasswordResetCode)
+			r.Put("/reset-password", h.Users.ResetPassword)
 			r.Post("/verify-email", h.Users.VerifyEmail)
 			r.Group(func(r chi.Router) {
 				r.Use(h.Users.RequireLogin)
-				r.Get("/logout", h.Users.Logout)
-				r.Get("/profile", h.Users.GetProfile)
-				r.Put("/profile", h.Users.UpdateProfile)
-				r.Put("/password", h.Users.UpdatePassword)
+				r.With(noScope).Get("/logout", h.Users.Logout)
+				r.With(readOnly).Get("/notifications", h.Users.GetNotificationPreferences)
+				r.With(noScope).Put("/notifications", h.Users.UpdateNotificationPreferences)
+				r.With(readOnly).Get("/profile", h.Users.GetProfile)
+				r.With(noScope).Put("/profile", h.Users.UpdateProfile)
+				r.With(noScope).Put("/password", h.Users.UpdatePassword)
+				r.Route("/sessions", func(r chi.Router) {
+					r.Use(noScope)
+					r.Get("/", h.Users.GetSessions)
+					r.Delete("/others", h.Users.RevokeOtherSessions)
+					r.Delete("/{sessionID}", h.Users.RevokeSession)
+				})
+				r.With(noScope).Post("/tfa", h.Users.SetupTFA)
+				r.With(noScope).Put("/tfa/enable", h.Users.EnableTFA)
+				r.With(noScope).Put("/tfa/disable", h.Users.DisableTFA)
 			})
 		})
 
@@ -143,53 +174,61 @@ func (h *Handlers) setupRouter() {
 		r.Route("/orgs", func(r chi.Router) {
 			r.Group(func(r chi.Router) {
 				r.Use(h.Users.RequireLogin)
-				r.Post("/", h.Organizations.Add)
-				r.Get("/user", h.Organizations.GetByUser)
+				r.With(noScope).Post("/", h.Organizations.Add)
+				r.With(readOnly).Get("/user", h.Organizations.GetByUser)
 			})
 			r.Route("/{orgName}", func(r chi.Router) {
 				r.Get("/", h.Organizations.Get)
 				r.Group(func(r chi.Router) {
 					r.Use(h.Users.RequireLogin)
-					r.Delete("/", h.Organizations.Delete)
-					r.Put("/", h.Organizations.Update)
+					r.With(noScope).Delete("/", h.Organizations.Delete)
+					r.With(noScope).Put("/", h.Organizations.Update)
 					r.Route("/authorizationPolicy", func(r chi.Router) {
-						r.Get("/", h.Organizations.GetAuthorizationPolicy)
-						r.Put("/", h.Organizations.UpdateAuthorizationPolicy)
+						r.With(readOnly).Get("/", h.Organizations.GetAuthorizationPolicy)
+						r.With(noScope).Put("/", h.Organizations.UpdateAuthorizationPolicy)
 					})
-					r.Get("/accept-invitation", h.Organizations.ConfirmMembership)
-					r.Get("/members", h.Organizations.GetMembers)
+					r.With(noScope).Get("/accept-invitation", h.Organizations.ConfirmMembership)
+					r.With(readOnly).Get("/members", h.Organizations.GetMembers)
 					r.Route("/member/{userAlias}", func(r chi.Router) {
+						r.Use(noScope)
 						r.Post("/", h.Organizations.AddMember)
 						r.Delete("/", h.Organizations.DeleteMember)
 					})
-					r.Get("/userAllowedActions", h.Organizations.GetUserAllowedActions)
+					r.With(readOnly).Get("/userAllowedActions", h.Organizations.GetUserAllowedActions)
 				})
 			})
 		})
 
 		// Repositories
 		r.Route("/repositories", func(r chi.Router) {
-			r.Use(h.Users.RequireLogin)
-			r.Get("/", h.Repositories.GetAll)
-			r.Get("/{kind:^helm$|^falco$|^olm$|^opa|^tbaction|^krew|^helm-plugin|^tekton-task$}", h.Repositories.GetByKind)
-			r.Route("/user", func(r chi.Router) {
-				r.Get("/", h.Repositories.GetOwnedByUser)
-				r.Post("/", h.Repositories.Add)
-				r.Route("/{repoName}", func(r chi.Router) {
-					r.Put("/claimOwnership", h.Repositories.ClaimOwnership)
-					r.Put("/transfer", h.Repositories.Transfer)
-					r.Put("/", h.Repositories.Update)
-					r.Delete("/", h.Repositories.Delete)
+			r.Post("/push/{provider:^github$|^gitlab$|^gitea$}", h.Repositories.TrackPushed)
+			r.Group(func(r chi.Router) {
+				r.Use(h.Users.RequireLogin)
+				r.With(readRepos).Get("/", h.Repositories.GetAll)
+				r.With(readRepos).Get("/{kind:^helm$|^falco$|^olm$|^opa|^tbaction|^krew|^helm-plugin|^tekton-task$}", h.Repositories.GetByKind)
+				r.Route("/user", func(r chi.Router) {
+					r.With(readRepos).Get("/", h.Repositories.GetOwnedByUser)
+					r.With(manageRepos).Post("/", h.Repositories.Add)
+					r.Route("/{repoName}", func(r chi.Router) {
+						r.With(manageRepos).Put("/claimOwnership", h.Repositories.ClaimOwnership)
+						r.With(manageRepos).Put("/track", h.Repositories.Track)
+						r.With(readRepos).Get("/track/{trackingJobID}", h.Repositories.GetTrackingJob)
+						r.With(manageRepos).Put("/transfer", h.Repositories.Transfer)
+						r.With(manageRepos).Put("/", h.Repositories.Update)
+						r.With(manageRepos).Delete("/", h.Repositories.Delete)
+					})
 				})
-			})
-			r.Route("/org/{orgName}", func(r chi.Router) {
-				r.Get("/", h.Repositories.GetOwnedByOrg)
-				r.Post("/", h.Repositories.Add)
-				r.Route("/{repoName}", func(r chi.Router) {
-					r.Put("/claimOwnership", h.Repositories.ClaimOwnership)
-					r.Put("/transfer", h.Repositories.Transfer)
-					r.Put("/", h.Repositories.Update)
-					r.Delete("/", h.Repositories.Delete)
+				r.Route("/org/{orgName}", func(r chi.Router) {
+					r.With(readRepos).Get("/", h.Repositories.GetOwnedByOrg)
+					r.With(manageRepos).Post("/", h.Repositories.Add)
+					r.Route("/{repoName}", func(r chi.Router) {
+						r.With(manageRepos).Put("/claimOwnership", h.Repositories.ClaimOwnership)
+						r.With(manageRepos).Put("/track", h.Repositories.Track)
+						r.With(readRepos).Get("/track/{trackingJobID}", h.Repositories.GetTrackingJob)
+						r.With(manageRepos).Put("/transfer", h.Repositories.Transfer)
+						r.With(manageRepos).Put("/", h.Repositories.Update)
+						r.With(manageRepos).Delete("/", h.Repositories.Delete)
+					})
 				})
 			})
 		})
@@ -199,7 +238,7 @@ func (h *Handlers) setupRouter() {
 			r.Get("/random", h.Packages.GetRandom)
 			r.Get("/stats", h.Packages.GetStats)
 			r.Get("/search", h.Packages.Search)
-			r.With(h.Users.RequireLogin).Get("/starred", h.Packages.GetStarredByUser)
+			r.With(h.Users.RequireLogin, readOnly).Get("/starred", h.Packages.GetStarredByUser)
 			r.Route("/{^helm$|^falco$|^opa$|^olm|^tbaction|^krew|^helm-plugin|^tekton-task$}/{repoName}/{packageName}", func(r chi.Router) {
 				r.Get("/feed/rss", h.Packages.RssFeed)
 				r.Get("/{version}", h.Packages.Get)
@@ -207,7 +246,7 @@ func (h *Handlers) setupRouter() {
 			})
 			r.Route("/{packageID}/stars", func(r chi.Router) {
 				r.With(h.Users.InjectUserID).Get("/", h.Packages.GetStars)
-				r.With(h.Users.RequireLogin).Put("/", h.Packages.ToggleStar)
+				r.With(h.Users.RequireLogin, noScope).Put("/", h.Packages.ToggleStar)
 			})
 			r.Get("/{packageID}/{version}/securityReport", h.Packages.GetSnapshotSecurityReport)
 			r.Get("/{packageID}/{version}/valuesSchema", h.Packages.GetValuesSchema)
@@ -216,45 +255,57 @@ func (h *Handlers) setupRouter() {
 
 		// Subscriptions
 		r.Route("/subscriptions", func(r chi.Router) {
-			r.Use(h.Users.RequireLogin)
-			r.Route("/opt-out", func(r chi.Router) {
-				r.Get("/", h.Subscriptions.GetOptOutList)
-				r.Post("/", h.Subscriptions.AddOptOut)
-				r.Delete("/{optOutID}", h.Subscriptions.DeleteOptOut)
+			r.Get("/unsubscribe", h.Subscriptions.Unsubscribe)
+			r.Post("/unsubscribe", h.Subscriptions.Unsubscribe)
+			r.Group(func(r chi.Router) {
+				r.Use(h.Users.RequireLogin)
+				r.Route("/opt-out", func(r chi.Router) {
+					r.With(readSubs).Get("/", h.Subscriptions.GetOptOutList)
+					r.With(manageSubs).Post("/", h.Subscriptions.AddOptOut)
+					r.With(manageSubs).Delete("/{optOutID}", h.Subscriptions.DeleteOptOut)
+				})
+				r.With(readSubs).Get("/organizations", h.Subscriptions.GetOrganizationsByUser)
+				r.With(readSubs).Get("/repositories", h.Subscriptions.GetRepositoriesByUser)
+				r.With(readSubs).Get("/{packageID}", h.Subscriptions.GetByPackage)
+				r.With(readSubs).Get("/", h.Subscriptions.GetByUser)
+				r.With(manageSubs).Post("/", h.Subscriptions.Add)
+				r.With(manageSubs).Delete("/", h.Subscriptions.Delete)
 			})
-			r.Get("/{packageID}", h.Subscriptions.GetByPackage)
-			r.Get("/", h.Subscriptions.GetByUser)
-			r.Post("/", h.Subscriptions.Add)
-			r.Delete("/", h.Subscriptions.Delete)
 		})
 
 		// Webhooks
 		r.Route("/webhooks", func(r chi.Router) {
 			r.Use(h.Users.RequireLogin)
 			r.Route("/user", func(r chi.Router) {
-				r.Get("/", h.Webhooks.GetOwnedByUser)
-				r.Post("/", h.Webhooks.Add)
+				r.With(readWhs).Get("/", h.Webhooks.GetOwnedByUser)
+				r.With(manageWhs).Post("/", h.Webhooks.Add)
 				r.Route("/{webhookID}", func(r chi.Router) {
-					r.Get("/", h.Webhooks.Get)
-					r.Put("/", h.Webhooks.Update)
-					r.Delete("/", h.Webhooks.Delete)
+					r.With(readWhs).Get("/", h.Webhooks.Get)
+					r.With(manageWhs).Put("/", h.Webhooks.Update)
+					r.With(manageWhs).Delete("/", h.Webhooks.Delete)
+					r.With(readWhs).Get("/deliveries", h.Webhooks.GetDeliveries)
+					r.With(manageWhs).Post("/deliveries/{webhookDeliveryID}/redeliver", h.Webhooks.Redeliver)
 				})
 			})
 			r.Route("/org/{orgName}", func(r chi.Router) {
-				r.Get("/", h.Webhooks.GetOwnedByOrg)
-				r.Post("/", h.Webhooks.Add)
+				r.With(readWhs).Get("/", h.Webhooks.GetOwnedByOrg)
+				r.With(manageWhs).Post("/", h.Webhooks.Add)
 				r.Route("/{webhookID}", func(r chi.Router) {
-					r.Get("/", h.Webhooks.Get)
-					r.Put("/", h.Webhooks.Update)
-					r.Delete("/", h.Webhooks.Delete)
+					r.With(readWhs).Get("/", h.Webhooks.Get)
+					r.With(manageWhs).Put("/", h.Webhooks.Update)
+					r.With(manageWhs).Delete("/", h.Webhooks.Delete)
+					r.With(readWhs).Get("/deliveries", h.Webhooks.GetDeliveries)
+					r.With(manageWhs).Post("/deliveries/{webhookDeliveryID}/redeliver", h.Webhooks.Redeliver)
 				})
 			})
-			r.Post("/test", h.Webhooks.TriggerTest)
+			r.With(manageWhs).Post("/preview", h.Webhooks.Preview)
+			r.With(manageWhs).Post("/test", h.Webhooks.TriggerTest)
 		})
 
 		// API keys
 		r.Route("/api-keys", func(r chi.Router) {
 			r.Use(h.Users.RequireLogin)
+			r.Use(noScope)
 			r.Get("/", h.APIKeys.GetOwnedByUser)
 			r.Post("/", h.APIKeys.Add)
 			r.Route("/{apiKeyID}", func(r chi.Router) {
@@ -262,8 +313,20 @@ func (h *Handlers) setupRouter() {
 				r.Put("/", h.APIKeys.Update)
 				r.Delete("/", h.APIKeys.Delete)
 			})
+			r.Route("/org/{orgName}", func(r chi.Router) {
+				r.Get("/", h.APIKeys.GetOwnedByOrg)
+				r.Post("/", h.APIKeys.Add)
+				r.Route("/{apiKeyID}", func(r chi.Router) {
+					r.Get("/", h.APIKeys.Get)
+					r.Put("/", h.APIKeys.Update)
+					r.Delete("/", h.APIKeys.Delete)
+				})
+			})
 		})
 
+		// Events
+		r.With(h.Users.RequireLogin, readOnly).Get("/events/stream", h.Events.Stream)
+
 		// Availability checks
 		r.Route("/check-availability", func(r chi.Router) {
 			r.Head("/{resourceKind:^repositoryName$|^repositoryURL$}", h.Repositories.CheckAvailability)
@@ -272,7 +335,7 @@ func (h *Handlers) setupRouter() {
 		})
 
 		// Images
-		r.With(h.Users.RequireLogin).Post("/images", h.Static.SaveImage)
+		r.With(h.Users.RequireLogin, noScope).Post("/images", h.Static.SaveImage)
 
 		// Harbor replication
 		//
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"encoding/json"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
@@ -12,6 +13,10 @@ import (
 )
 
 const (
+	// maxPushEventSize represents the maximum size of the push events payloads
+	// accepted by the TrackPushed handler.
+	maxPushEventSize = 512 * 1024
+
 	logoSVG = `<svg xmlns="http://www.w3.org/2000/svg" width="14" height="14" viewBox="0 0 24 24" fill="none" stroke="#ffffff" stroke-width="2" stroke-linecap="round" stroke-linejoin="round" class="feather feather-hexagon"><path d="M21 16V8a2 2 0 0 0-1-1.73l-7-4a2 2 0 0 0-2 0l-7 4A2 2 0 0 0 3 8v8a2 2 0 0 0 1 1.73l7 4a2 2 0 0 0 2 0l7-4A2 2 0 0 0 21 16z"></path></svg>`
 )
 
@@ -169,6 +174,66 @@ func (h *Handlers) GetOwnedByUser(w http.ResponseWriter, r *http.Request) {
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
 }
 
+// GetTrackingJob is an http handler that returns the requested tracking job of
+// the provided repository.
+func (h *Handlers) GetTrackingJob(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	trackingJobID := chi.URLParam(r, "trackingJobID")
+	dataJSON, err := h.repoManager.GetTrackingJobJSON(r.Context(), repoName, trackingJobID)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetTrackingJob").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// Track is an http handler that requests the tracking of the provided
+// repository as soon as possible. The id of the tracking job that will
+// process the repository is returned, so that its status can be checked.
+func (h *Handlers) Track(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	trackingJobID, err := h.repoManager.Track(r.Context(), repoName)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "Track").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	dataJSON, _ := json.Marshal(map[string]string{
+		"tracking_job_id": trackingJobID,
+	})
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusAccepted)
+}
+
+// TrackPushed is an http handler that processes the push events sent by git
+// providers, requesting the tracking of the repositories affected by them.
+func (h *Handlers) TrackPushed(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxPushEventSize))
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "TrackPushed").Msg("error reading body data")
+		helpers.RenderErrorWithCodeJSON(w, nil, http.StatusRequestEntityTooLarge)
+		return
+	}
+	e := &hub.RepositoryPushEvent{
+		Provider: chi.URLParam(r, "provider"),
+		Header:   r.Header,
+		Body:     body,
+	}
+	names, err := h.repoManager.TrackPushed(r.Context(), e)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "TrackPushed").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	if names == nil {
+		names = []string{}
+	}
+	dataJSON, _ := json.Marshal(map[string][]string{
+		"repositories": names,
+	})
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusAccepted)
+}
+
 // Transfer is an http handler that transfers the provided repository to a
 // different owner.
 func (h *Handlers) Transfer(w http.ResponseWriter, r *http.Request) {
@@ -598,6 +598,261 @@ func TestGetOwnedByUser(t *testing.T) {
 	})
 }
 
+func TestGetTrackingJob(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"repoName", "trackingJobID"},
+			Values: []string{"repo1", "jobID"},
+		},
+	}
+
+	t.Run("get tracking job succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.rm.On("GetTrackingJobJSON", r.Context(), "repo1", "jobID").Return([]byte("dataJSON"), nil)
+		hw.h.GetTrackingJob(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.rm.AssertExpectations(t)
+	})
+
+	t.Run("error getting tracking job", func(t *testing.T) {
+		testCases := []struct {
+			rmErr              error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				hub.ErrInsufficientPrivilege,
+				http.StatusForbidden,
+			},
+			{
+				hub.ErrNotFound,
+				http.StatusNotFound,
+			},
+			{
+				tests.ErrFakeDB,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.rmErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/", nil)
+				r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.rm.On("GetTrackingJobJSON", r.Context(), "repo1", "jobID").Return(nil, tc.rmErr)
+				hw.h.GetTrackingJob(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.rm.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestTrack(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"repoName"},
+			Values: []string{"repo1"},
+		},
+	}
+
+	t.Run("track repository succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.rm.On("Track", r.Context(), "repo1").Return("jobID", nil)
+		hw.h.Track(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte(`{"tracking_job_id":"jobID"}`), data)
+		hw.rm.AssertExpectations(t)
+	})
+
+	t.Run("error tracking repository", func(t *testing.T) {
+		testCases := []struct {
+			rmErr              error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				hub.ErrInsufficientPrivilege,
+				http.StatusForbidden,
+			},
+			{
+				tests.ErrFakeDB,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.rmErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("PUT", "/", nil)
+				r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.rm.On("Track", r.Context(), "repo1").Return("", tc.rmErr)
+				hw.h.Track(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.rm.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestTrackPushed(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"provider"},
+			Values: []string{"github"},
+		},
+	}
+	body := `{"ref": "refs/heads/main"}`
+
+	t.Run("push event payload too large", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(strings.Repeat("a", maxPushEventSize+1)))
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.h.TrackPushed(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+		hw.rm.AssertExpectations(t)
+	})
+
+	t.Run("track pushed repositories succeeded", func(t *testing.T) {
+		testCases := []struct {
+			names        []string
+			expectedData string
+		}{
+			{
+				[]string{"repo1", "repo2"},
+				`{"repositories":["repo1","repo2"]}`,
+			},
+			{
+				nil,
+				`{"repositories":[]}`,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.expectedData, func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+				r.Header.Set("X-GitHub-Event", "push")
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.rm.On("TrackPushed", r.Context(), &hub.RepositoryPushEvent{
+					Provider: "github",
+					Header:   r.Header,
+					Body:     []byte(body),
+				}).Return(tc.names, nil)
+				hw.h.TrackPushed(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+				h := resp.Header
+				data, _ := ioutil.ReadAll(resp.Body)
+
+				assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+				assert.Equal(t, "application/json", h.Get("Content-Type"))
+				assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+				assert.Equal(t, []byte(tc.expectedData), data)
+				hw.rm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("error tracking pushed repositories", func(t *testing.T) {
+		testCases := []struct {
+			rmErr              error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				hub.ErrInsufficientPrivilege,
+				http.StatusForbidden,
+			},
+			{
+				hub.ErrNotFound,
+				http.StatusNotFound,
+			},
+			{
+				tests.ErrFakeDB,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.rmErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.rm.On("TrackPushed", r.Context(), mock.Anything).Return(nil, tc.rmErr)
+				hw.h.TrackPushed(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.rm.AssertExpectations(t)
+			})
+		}
+	})
+}
+
 func TestTransfer(t *testing.T) {
 	t.Run("invalid input - missing repo name", func(t *testing.T) {
 		t.Parallel()
@@ -2,28 +2,34 @@ package subscription
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html/template"
 	"net/http"
 	"strconv"
 
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/subscription"
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
 )
 
 // Handlers represents a group of http handlers in charge of handling
 // subscriptions operations.
 type Handlers struct {
 	subscriptionManager hub.SubscriptionManager
+	cfg                 *viper.Viper
 	logger              zerolog.Logger
 }
 
 // NewHandlers creates a new Handlers instance.
-func NewHandlers(subscriptionManager hub.SubscriptionManager) *Handlers {
+func NewHandlers(subscriptionManager hub.SubscriptionManager, cfg *viper.Viper) *Handlers {
 	return &Handlers{
 		subscriptionManager: subscriptionManager,
+		cfg:                 cfg,
 		logger:              log.With().Str("handlers", "subscription").Logger(),
 	}
 }
@@ -61,7 +67,8 @@ func (h *Handlers) AddOptOut(w http.ResponseWriter, r *http.Request) {
 }
 
 // Delete is an http handler that removes the provided subscription from the
-// database.
+// database. The subscription can be to a package, a repository or an
+// organization.
 func (h *Handlers) Delete(w http.ResponseWriter, r *http.Request) {
 	eventKind, err := strconv.Atoi(r.FormValue("event_kind"))
 	if err != nil {
@@ -71,8 +78,10 @@ func (h *Handlers) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	s := &hub.Subscription{
-		PackageID: r.FormValue("package_id"),
-		EventKind: hub.EventKind(eventKind),
+		PackageID:      r.FormValue("package_id"),
+		RepositoryID:   r.FormValue("repository_id"),
+		OrganizationID: r.FormValue("organization_id"),
+		EventKind:      hub.EventKind(eventKind),
 	}
 	if err := h.subscriptionManager.Delete(r.Context(), s); err != nil {
 		h.logger.Error().Err(err).Str("method", "Delete").Send()
@@ -119,6 +128,30 @@ func (h *Handlers) GetByUser(w http.ResponseWriter, r *http.Request) {
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
 }
 
+// GetOrganizationsByUser is an http handler that returns the organizations
+// subscriptions of the user doing the request.
+func (h *Handlers) GetOrganizationsByUser(w http.ResponseWriter, r *http.Request) {
+	dataJSON, err := h.subscriptionManager.GetOrganizationsByUserJSON(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetOrganizationsByUser").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// GetRepositoriesByUser is an http handler that returns the repositories
+// subscriptions of the user doing the request.
+func (h *Handlers) GetRepositoriesByUser(w http.ResponseWriter, r *http.Request) {
+	dataJSON, err := h.subscriptionManager.GetRepositoriesByUserJSON(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetRepositoriesByUser").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
 // GetOptOutList is an http handler that returns the opt-out entries of the
 // user doing the request.
 func (h *Handlers) GetOptOutList(w http.ResponseWriter, r *http.Request) {
@@ -130,3 +163,79 @@ func (h *Handlers) GetOptOutList(w http.ResponseWriter, r *http.Request) {
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
 }
+
+// Unsubscribe is an http handler used to stop receiving the notifications
+// described in the unsubscribe token provided, which is embedded in the
+// notifications emails. No session is required, as the token is signed. GET
+// requests render a confirmation page, whereas POST requests (sent by that
+// page or by email clients supporting one-click unsubscribe) unsubscribe the
+// user.
+func (h *Handlers) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	data := map[string]interface{}{
+		"baseURL": h.cfg.GetString("server.baseURL"),
+	}
+	key := []byte(h.cfg.GetString("server.unsubscribe.hashKey"))
+	if len(key) == 0 {
+		h.logger.Error().Err(subscription.ErrUnsubscribeKeyNotSet).Str("method", "Unsubscribe").Send()
+		data["error"] = "Unsubscribe links are not available."
+		h.renderUnsubscribePage(w, data, http.StatusNotFound)
+		return
+	}
+	t, err := subscription.ParseUnsubscribeToken(key, r.URL.Query().Get("token"))
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "Unsubscribe").Send()
+		data["error"] = "This unsubscribe link is not valid or has expired."
+		h.renderUnsubscribePage(w, data, http.StatusBadRequest)
+		return
+	}
+	if r.Method == http.MethodPost {
+		if err := h.subscriptionManager.Unsubscribe(r.Context(), t); err != nil {
+			h.logger.Error().Err(err).Str("method", "Unsubscribe").Send()
+			if errors.Is(err, hub.ErrInvalidInput) {
+				data["error"] = "This unsubscribe link is not valid or has expired."
+				h.renderUnsubscribePage(w, data, http.StatusBadRequest)
+			} else {
+				data["error"] = "Something went wrong, please try again later."
+				h.renderUnsubscribePage(w, data, http.StatusInternalServerError)
+			}
+			return
+		}
+		data["unsubscribed"] = true
+	}
+	h.renderUnsubscribePage(w, data, http.StatusOK)
+}
+
+// renderUnsubscribePage renders the unsubscribe page using the data and
+// status code provided.
+func (h *Handlers) renderUnsubscribePage(w http.ResponseWriter, data map[string]interface{}, code int) {
+	w.Header().Set("Content-Type", "text/html")
+	w.Header().Set("Cache-Control", helpers.BuildCacheControlHeader(0))
+	w.WriteHeader(code)
+	if err := unsubscribePageTmpl.Execute(w, data); err != nil {
+		h.logger.Error().Err(err).Str("method", "renderUnsubscribePage").Send()
+	}
+}
+
+var unsubscribePageTmpl = template.Must(template.New("").Parse(`<!doctype html>
+<html>
+  <head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Artifact Hub - Unsubscribe</title>
+  </head>
+  <body style="background-color: #f4f4f4; color: #1c2c35; font-family: sans-serif; font-size: 14px; text-align: center; padding: 60px 20px;">
+    <h2 style="color: #39596c;">Unsubscribe</h2>
+    {{ if .error }}
+    <p>{{ .error }}</p>
+    {{ else if .unsubscribed }}
+    <p>You have been unsubscribed successfully. You will not receive these notifications anymore.</p>
+    {{ else }}
+    <p>Please confirm that you want to stop receiving these notifications.</p>
+    <form method="post">
+      <button type="submit" style="color: #ffffff; background-color: #39596c; border: solid 1px #39596c; border-radius: 5px; cursor: pointer; font-size: 14px; font-weight: bold; padding: 12px 25px;">Unsubscribe</button>
+    </form>
+    {{ end }}
+    <p style="margin-top: 40px;"><a href="{{ .baseURL }}" style="color: #39596c; text-decoration: none;">Artifact Hub</a></p>
+  </body>
+</html>
+`))
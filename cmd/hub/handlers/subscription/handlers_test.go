@@ -9,6 +9,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
@@ -16,8 +17,10 @@ import (
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMain(m *testing.M) {
@@ -241,6 +244,11 @@ func TestDelete(t *testing.T) {
 				"package_id=00000000-0000-0000-0000-000000000001&event_kind=1",
 				hub.ErrInvalidInput,
 			},
+			{
+				"invalid repository id",
+				"repository_id=invalid&event_kind=0",
+				hub.ErrInvalidInput,
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -459,6 +467,84 @@ func TestGetByUser(t *testing.T) {
 	})
 }
 
+func TestGetOrganizationsByUser(t *testing.T) {
+	t.Run("error getting user organizations subscriptions", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.sm.On("GetOrganizationsByUserJSON", r.Context()).Return(nil, tests.ErrFakeDB)
+		hw.h.GetOrganizationsByUser(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.sm.AssertExpectations(t)
+	})
+
+	t.Run("get user organizations subscriptions succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.sm.On("GetOrganizationsByUserJSON", r.Context()).Return([]byte("dataJSON"), nil)
+		hw.h.GetOrganizationsByUser(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.sm.AssertExpectations(t)
+	})
+}
+
+func TestGetRepositoriesByUser(t *testing.T) {
+	t.Run("error getting user repositories subscriptions", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.sm.On("GetRepositoriesByUserJSON", r.Context()).Return(nil, tests.ErrFakeDB)
+		hw.h.GetRepositoriesByUser(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.sm.AssertExpectations(t)
+	})
+
+	t.Run("get user repositories subscriptions succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.sm.On("GetRepositoriesByUserJSON", r.Context()).Return([]byte("dataJSON"), nil)
+		hw.h.GetRepositoriesByUser(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.sm.AssertExpectations(t)
+	})
+}
+
 func TestGetOptOutList(t *testing.T) {
 	t.Run("error getting user opt-out entries", func(t *testing.T) {
 		t.Parallel()
@@ -498,16 +584,133 @@ func TestGetOptOutList(t *testing.T) {
 	})
 }
 
+func TestUnsubscribe(t *testing.T) {
+	key := []byte("key")
+	token, err := subscription.NewUnsubscribeToken(key, &hub.UnsubscribeToken{
+		UserID:    "00000000-0000-0000-0000-000000000001",
+		EventKind: hub.NewRelease,
+		PackageID: "00000000-0000-0000-0000-000000000001",
+	}, time.Hour)
+	require.NoError(t, err)
+	expiredToken, err := subscription.NewUnsubscribeToken(key, &hub.UnsubscribeToken{
+		UserID:    "00000000-0000-0000-0000-000000000001",
+		EventKind: hub.NewRelease,
+		PackageID: "00000000-0000-0000-0000-000000000001",
+	}, -time.Hour)
+	require.NoError(t, err)
+
+	t.Run("unsubscribe key not set", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/?token="+token, nil)
+
+		hw := newHandlersWrapper()
+		hw.h.cfg.Set("server.unsubscribe.hashKey", "")
+		hw.h.Unsubscribe(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Contains(t, string(data), "not available")
+		hw.sm.AssertExpectations(t)
+	})
+
+	t.Run("invalid token provided", func(t *testing.T) {
+		testCases := []string{
+			"",
+			"invalid",
+			expiredToken,
+		}
+		for _, token := range testCases {
+			token := token
+			t.Run(token, func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("POST", "/?token="+token, nil)
+
+				hw := newHandlersWrapper()
+				hw.h.Unsubscribe(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+				data, _ := ioutil.ReadAll(resp.Body)
+
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Contains(t, string(data), "not valid or has expired")
+				hw.sm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("confirmation page rendered", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?token="+token, nil)
+
+		hw := newHandlersWrapper()
+		hw.h.Unsubscribe(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/html", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Contains(t, string(data), `<form method="post">`)
+		hw.sm.AssertExpectations(t)
+	})
+
+	t.Run("error unsubscribing", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/?token="+token, nil)
+
+		hw := newHandlersWrapper()
+		hw.sm.On("Unsubscribe", r.Context(), mock.Anything).Return(tests.ErrFakeDB)
+		hw.h.Unsubscribe(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.sm.AssertExpectations(t)
+	})
+
+	t.Run("unsubscribe succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/?token="+token, nil)
+
+		hw := newHandlersWrapper()
+		hw.sm.On("Unsubscribe", r.Context(), mock.MatchedBy(func(t *hub.UnsubscribeToken) bool {
+			return t.UserID == "00000000-0000-0000-0000-000000000001" &&
+				t.EventKind == hub.NewRelease &&
+				t.PackageID == "00000000-0000-0000-0000-000000000001"
+		})).Return(nil)
+		hw.h.Unsubscribe(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(data), "unsubscribed successfully")
+		hw.sm.AssertExpectations(t)
+	})
+}
+
 type handlersWrapper struct {
 	sm *subscription.ManagerMock
 	h  *Handlers
 }
 
 func newHandlersWrapper() *handlersWrapper {
+	cfg := viper.New()
+	cfg.Set("server.baseURL", "baseURL")
+	cfg.Set("server.unsubscribe.hashKey", "key")
 	sm := &subscription.ManagerMock{}
 
 	return &handlersWrapper{
 		sm: sm,
-		h:  NewHandlers(sm),
+		h:  NewHandlers(sm, cfg),
 	}
 }
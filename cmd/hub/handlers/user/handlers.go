@@ -11,6 +11,7 @@ import (
 	"math/big"
 	"net"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -36,7 +37,6 @@ import (
 const (
 	sessionCookieName    = "sid"
 	oauthStateCookieName = "oas"
-	sessionDuration      = 30 * 24 * time.Hour
 	oauthFailedURL       = "/oauth-failed"
 	apiKeyHeader         = "X-API-KEY"
 )
@@ -47,6 +47,7 @@ type Handlers struct {
 	userManager  hub.UserManager
 	cfg          *viper.Viper
 	sc           *securecookie.SecureCookie
+	sessionTTL   time.Duration
 	oauthConfig  map[string]*oauth2.Config
 	oidcProvider *oidc.Provider
 	logger       zerolog.Logger
@@ -55,8 +56,12 @@ type Handlers struct {
 // NewHandlers creates a new Handlers instance.
 func NewHandlers(ctx context.Context, userManager hub.UserManager, cfg *viper.Viper) (*Handlers, error) {
 	// Setup secure cookie instance
+	sessionTTL := user.DefaultSessionAbsoluteTimeout
+	if cfg.IsSet("server.sessions.absoluteTimeout") {
+		sessionTTL = cfg.GetDuration("server.sessions.absoluteTimeout")
+	}
 	sc := securecookie.New([]byte(cfg.GetString("server.cookie.hashKey")), nil)
-	sc.MaxAge(int(sessionDuration.Seconds()))
+	sc.MaxAge(int(sessionTTL.Seconds()))
 
 	// Setup oauth providers configuration
 	oauthConfig := make(map[string]*oauth2.Config)
@@ -93,12 +98,49 @@ func NewHandlers(ctx context.Context, userManager hub.UserManager, cfg *viper.Vi
 		userManager:  userManager,
 		cfg:          cfg,
 		sc:           sc,
+		sessionTTL:   sessionTTL,
 		oauthConfig:  oauthConfig,
 		oidcProvider: oidcProvider,
 		logger:       log.With().Str("handlers", "user").Logger(),
 	}, nil
 }
 
+// ApproveSession is an http handler used to approve the session of a user who
+// has enabled two-factor authentication, using the passcode provided.
+func (h *Handlers) ApproveSession(w http.ResponseWriter, r *http.Request) {
+	// Extract session id from cookie
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		helpers.RenderErrorWithCodeJSON(w, nil, http.StatusUnauthorized)
+		return
+	}
+	var sessionID []byte
+	if err = h.sc.Decode(sessionCookieName, cookie.Value, &sessionID); err != nil {
+		h.logger.Error().Err(err).Str("method", "ApproveSession").Msg("sessionID decoding failed")
+		helpers.RenderErrorWithCodeJSON(w, nil, http.StatusUnauthorized)
+		return
+	}
+
+	// Approve session using the passcode provided
+	var input map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error().Err(err).Str("method", "ApproveSession").Msg(hub.ErrInvalidInput.Error())
+		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		return
+	}
+	err = h.userManager.ApproveSession(r.Context(), sessionID, input["passcode"])
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "ApproveSession").Send()
+		if errors.Is(err, user.ErrInvalidPasscode) {
+			helpers.RenderErrorWithCodeJSON(w, nil, http.StatusUnauthorized)
+		} else {
+			helpers.RenderErrorJSON(w, err)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // BasicAuth is a middleware that provides basic auth support.
 func (h *Handlers) BasicAuth(next http.Handler) http.Handler {
 	validUser := []byte(h.cfg.GetString("server.basicAuth.username"))
@@ -146,6 +188,62 @@ func (h *Handlers) CheckAvailability(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// DisableTFA is an http handler used to disable two-factor authentication for
+// the logged in user.
+func (h *Handlers) DisableTFA(w http.ResponseWriter, r *http.Request) {
+	var input map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error().Err(err).Str("method", "DisableTFA").Msg(hub.ErrInvalidInput.Error())
+		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		return
+	}
+	err := h.userManager.DisableTFA(r.Context(), input["passcode"])
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "DisableTFA").Send()
+		if errors.Is(err, user.ErrInvalidPasscode) {
+			helpers.RenderErrorWithCodeJSON(w, nil, http.StatusUnauthorized)
+		} else {
+			helpers.RenderErrorJSON(w, err)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EnableTFA is an http handler used to enable two-factor authentication for
+// the logged in user.
+func (h *Handlers) EnableTFA(w http.ResponseWriter, r *http.Request) {
+	var input map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error().Err(err).Str("method", "EnableTFA").Msg(hub.ErrInvalidInput.Error())
+		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		return
+	}
+	err := h.userManager.EnableTFA(r.Context(), input["passcode"])
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "EnableTFA").Send()
+		if errors.Is(err, user.ErrInvalidPasscode) {
+			helpers.RenderErrorWithCodeJSON(w, nil, http.StatusUnauthorized)
+		} else {
+			helpers.RenderErrorJSON(w, err)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetNotificationPreferences is an http handler used to get the notification
+// preferences of the logged in user.
+func (h *Handlers) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	dataJSON, err := h.userManager.GetNotificationPreferencesJSON(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetNotificationPreferences").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
 // GetProfile is an http handler used to get a logged in user profile.
 func (h *Handlers) GetProfile(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.userManager.GetProfileJSON(r.Context())
@@ -157,6 +255,32 @@ func (h *Handlers) GetProfile(w http.ResponseWriter, r *http.Request) {
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
 }
 
+// GetSessions is an http handler used to get the active sessions of the user
+// doing the request.
+func (h *Handlers) GetSessions(w http.ResponseWriter, r *http.Request) {
+	dataJSON, err := h.userManager.GetSessionsJSON(r.Context(), h.getSessionID(r))
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetSessions").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// getSessionID returns the id of the session provided in the request cookie,
+// if any.
+func (h *Handlers) getSessionID(r *http.Request) []byte {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return nil
+	}
+	var sessionID []byte
+	if err := h.sc.Decode(sessionCookieName, cookie.Value, &sessionID); err != nil {
+		return nil
+	}
+	return sessionID
+}
+
 // InjectUserID is a middleware that injects the id of the user doing the
 // request into the request context when a valid session id is provided.
 func (h *Handlers) InjectUserID(next http.Handler) http.Handler {
@@ -184,7 +308,7 @@ func (h *Handlers) InjectUserID(next http.Handler) http.Handler {
 		}
 
 		// Check the session provided is valid
-		checkSessionOutput, err := h.userManager.CheckSession(r.Context(), sessionID, sessionDuration)
+		checkSessionOutput, err := h.userManager.CheckSession(r.Context(), sessionID)
 		if err != nil {
 			return
 		}
@@ -225,7 +349,7 @@ func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
 		IP:        ip,
 		UserAgent: r.UserAgent(),
 	}
-	sessionID, err := h.userManager.RegisterSession(r.Context(), session)
+	registerSessionOutput, err := h.userManager.RegisterSession(r.Context(), session)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "Login").Msg("registerSession failed")
 		helpers.RenderErrorJSON(w, err)
@@ -233,7 +357,7 @@ func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate and set session cookie
-	encodedSessionID, err := h.sc.Encode(sessionCookieName, sessionID)
+	encodedSessionID, err := h.sc.Encode(sessionCookieName, registerSessionOutput.SessionID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "Login").Msg("sessionID encoding failed")
 		helpers.RenderErrorJSON(w, err)
@@ -243,13 +367,20 @@ func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
 		Name:     sessionCookieName,
 		Value:    encodedSessionID,
 		Path:     "/",
-		Expires:  time.Now().Add(sessionDuration),
+		Expires:  time.Now().Add(h.sessionTTL),
 		HttpOnly: true,
 	}
 	if h.cfg.GetBool("server.cookie.secure") {
 		cookie.Secure = true
 	}
 	http.SetCookie(w, cookie)
+
+	// Users who have enabled two-factor authentication must approve the
+	// session providing a passcode before using it
+	if !registerSessionOutput.Approved {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -336,13 +467,13 @@ func (h *Handlers) OauthCallback(w http.ResponseWriter, r *http.Request) {
 		IP:        ip,
 		UserAgent: r.UserAgent(),
 	}
-	sessionID, err := h.userManager.RegisterSession(r.Context(), session)
+	registerSessionOutput, err := h.userManager.RegisterSession(r.Context(), session)
 	if err != nil {
 		logger.Error().Err(err).Msg("registerSession failed")
 		http.Redirect(w, r, oauthFailedURL, http.StatusSeeOther)
 		return
 	}
-	encodedSessionID, err := h.sc.Encode(sessionCookieName, sessionID)
+	encodedSessionID, err := h.sc.Encode(sessionCookieName, registerSessionOutput.SessionID)
 	if err != nil {
 		logger.Error().Err(err).Msg("sessionID encoding failed")
 		http.Redirect(w, r, oauthFailedURL, http.StatusSeeOther)
@@ -352,14 +483,30 @@ func (h *Handlers) OauthCallback(w http.ResponseWriter, r *http.Request) {
 		Name:     sessionCookieName,
 		Value:    encodedSessionID,
 		Path:     "/",
-		Expires:  time.Now().Add(sessionDuration),
+		Expires:  time.Now().Add(h.sessionTTL),
 		HttpOnly: true,
 	}
 	if h.cfg.GetBool("server.cookie.secure") {
 		sessionCookie.Secure = true
 	}
 	http.SetCookie(w, sessionCookie)
-	http.Redirect(w, r, state.RedirectURL, http.StatusSeeOther)
+
+	// Let the web application know that the session must be approved when
+	// the user has enabled two-factor authentication
+	redirectURL := state.RedirectURL
+	if !registerSessionOutput.Approved {
+		u, err := url.Parse(redirectURL)
+		if err != nil {
+			logger.Error().Err(err).Msg("invalid redirect url")
+			http.Redirect(w, r, oauthFailedURL, http.StatusSeeOther)
+			return
+		}
+		q := u.Query()
+		q.Set("tfa", "true")
+		u.RawQuery = q.Encode()
+		redirectURL = u.String()
+	}
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
 }
 
 // OauthRedirect is an http handler that redirects the user to the oauth
@@ -396,6 +543,25 @@ func (h *Handlers) OauthRedirect(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, authCodeURL, http.StatusSeeOther)
 }
 
+// RegisterPasswordResetCode is an http handler used to register a code that
+// allows the user to reset the password. The code is sent to the user by email.
+func (h *Handlers) RegisterPasswordResetCode(w http.ResponseWriter, r *http.Request) {
+	var input map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error().Err(err).Str("method", "RegisterPasswordResetCode").Msg(hub.ErrInvalidInput.Error())
+		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		return
+	}
+	baseURL := h.cfg.GetString("server.baseURL")
+	err := h.userManager.RegisterPasswordResetCode(r.Context(), input["email"], baseURL)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "RegisterPasswordResetCode").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
 // RegisterUser is an http handler used to register a user in the hub database.
 func (h *Handlers) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	u := &hub.User{}
@@ -608,6 +774,7 @@ func (h *Handlers) newUserFromOIDProfile(
 func (h *Handlers) RequireLogin(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var userID string
+		var scopes []hub.APIKeyScope
 
 		// Try cookie based authentication
 		cookie, err := r.Cookie(sessionCookieName)
@@ -621,7 +788,7 @@ func (h *Handlers) RequireLogin(next http.Handler) http.Handler {
 			}
 
 			// Check the session provided is valid
-			checkSessionOutput, err := h.userManager.CheckSession(r.Context(), sessionID, sessionDuration)
+			checkSessionOutput, err := h.userManager.CheckSession(r.Context(), sessionID)
 			if err != nil {
 				h.logger.Error().Err(err).Str("method", "RequireLogin").Msg("checkSession failed")
 				helpers.RenderErrorWithCodeJSON(w, nil, http.StatusInternalServerError)
@@ -659,6 +826,7 @@ func (h *Handlers) RequireLogin(next http.Handler) http.Handler {
 			}
 
 			userID = checkAPIKeyOutput.UserID
+			scopes = checkAPIKeyOutput.Scopes
 		}
 
 		// Return if no authentication method succeeded
@@ -667,12 +835,118 @@ func (h *Handlers) RequireLogin(next http.Handler) http.Handler {
 			return
 		}
 
-		// Inject userID in context and call next handler
+		// Inject userID (and api key scopes, if any) in context and call next
+		// handler
 		ctx := context.WithValue(r.Context(), hub.UserIDKey, userID)
+		if len(scopes) > 0 {
+			ctx = context.WithValue(ctx, hub.APIKeyScopesKey, scopes)
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// RequireScope is a middleware that verifies if the api key used to
+// authenticate the request has been granted any of the scopes provided. Keys
+// with no scopes, as well as session based requests, are always allowed. When
+// no scopes are provided, requests using scoped keys are rejected. It must be
+// used after RequireLogin.
+func (h *Handlers) RequireScope(scopes ...hub.APIKeyScope) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keyScopes, _ := r.Context().Value(hub.APIKeyScopesKey).([]hub.APIKeyScope)
+			if len(keyScopes) > 0 && !hasAnyScope(keyScopes, scopes) {
+				helpers.RenderErrorWithCodeJSON(w, nil, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// hasAnyScope checks if any of the scopes provided is part of the scopes
+// granted to an api key.
+func hasAnyScope(keyScopes, scopes []hub.APIKeyScope) bool {
+	for _, keyScope := range keyScopes {
+		for _, scope := range scopes {
+			if keyScope == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ResetPassword is an http handler used to reset the password of the user the
+// password reset code provided belongs to.
+func (h *Handlers) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var input map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error().Err(err).Str("method", "ResetPassword").Msg(hub.ErrInvalidInput.Error())
+		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		return
+	}
+	err := h.userManager.ResetPassword(r.Context(), input["code"], input["password"])
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "ResetPassword").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeOtherSessions is an http handler used to revoke all the sessions of the
+// user doing the request except the current one.
+func (h *Handlers) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	if err := h.userManager.RevokeOtherSessions(r.Context(), h.getSessionID(r)); err != nil {
+		h.logger.Error().Err(err).Str("method", "RevokeOtherSessions").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeSession is an http handler used to revoke the provided session of the
+// user doing the request.
+func (h *Handlers) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionID")
+	if err := h.userManager.RevokeSession(r.Context(), sessionID); err != nil {
+		h.logger.Error().Err(err).Str("method", "RevokeSession").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetupTFA is an http handler used to set up two-factor authentication for the
+// logged in user. It returns the information needed to configure the
+// authenticator app, as well as the recovery codes.
+func (h *Handlers) SetupTFA(w http.ResponseWriter, r *http.Request) {
+	dataJSON, err := h.userManager.SetupTFA(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "SetupTFA").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusCreated)
+}
+
+// UpdateNotificationPreferences is an http handler used to update the
+// notification preferences of the logged in user.
+func (h *Handlers) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	p := &hub.NotificationPreferences{}
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		h.logger.Error().Err(err).Str("method", "UpdateNotificationPreferences").Msg("invalid notification preferences")
+		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		return
+	}
+	if err := h.userManager.UpdateNotificationPreferences(r.Context(), p); err != nil {
+		h.logger.Error().Err(err).Str("method", "UpdateNotificationPreferences").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // UpdatePassword is an http handler used to update the password in the hub
 // database.
 func (h *Handlers) UpdatePassword(w http.ResponseWriter, r *http.Request) {
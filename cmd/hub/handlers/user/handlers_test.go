@@ -30,6 +30,96 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
+func TestApproveSession(t *testing.T) {
+	t.Run("invalid session cookie provided", func(t *testing.T) {
+		testCases := []struct {
+			description string
+			cookie      *http.Cookie
+		}{
+			{
+				"no session cookie provided",
+				nil,
+			},
+			{
+				"invalid session cookie provided",
+				&http.Cookie{
+					Name:  sessionCookieName,
+					Value: "invalidValue",
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("PUT", "/", strings.NewReader(`{"passcode": "123456"}`))
+				if tc.cookie != nil {
+					r.AddCookie(tc.cookie)
+				}
+
+				hw := newHandlersWrapper()
+				hw.h.ApproveSession(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			})
+		}
+	})
+
+	t.Run("valid session cookie provided", func(t *testing.T) {
+		testCases := []struct {
+			description        string
+			err                error
+			expectedStatusCode int
+		}{
+			{
+				"invalid input",
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				"invalid passcode",
+				user.ErrInvalidPasscode,
+				http.StatusUnauthorized,
+			},
+			{
+				"database error",
+				tests.ErrFakeDB,
+				http.StatusInternalServerError,
+			},
+			{
+				"session approved successfully",
+				nil,
+				http.StatusNoContent,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("PUT", "/", strings.NewReader(`{"passcode": "123456"}`))
+
+				hw := newHandlersWrapper()
+				hw.um.On("ApproveSession", r.Context(), []byte("sessionID"), "123456").Return(tc.err)
+				encodedSessionID, _ := hw.h.sc.Encode(sessionCookieName, []byte("sessionID"))
+				r.AddCookie(&http.Cookie{
+					Name:  sessionCookieName,
+					Value: encodedSessionID,
+				})
+				hw.h.ApproveSession(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.um.AssertExpectations(t)
+			})
+		}
+	})
+}
+
 func TestBasicAuth(t *testing.T) {
 	hw := newHandlersWrapper()
 	hw.cfg.Set("server.basicAuth.enabled", true)
@@ -153,6 +243,167 @@ func TestCheckAvailability(t *testing.T) {
 	})
 }
 
+func TestDisableTFA(t *testing.T) {
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/", strings.NewReader("{invalid json"))
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.h.DisableTFA(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	testCases := []struct {
+		description        string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			"passcode not provided",
+			hub.ErrInvalidInput,
+			http.StatusBadRequest,
+		},
+		{
+			"invalid passcode",
+			user.ErrInvalidPasscode,
+			http.StatusUnauthorized,
+		},
+		{
+			"database error",
+			tests.ErrFakeDB,
+			http.StatusInternalServerError,
+		},
+		{
+			"tfa disabled successfully",
+			nil,
+			http.StatusNoContent,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("PUT", "/", strings.NewReader(`{"passcode": "123456"}`))
+			r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+			hw := newHandlersWrapper()
+			hw.um.On("DisableTFA", r.Context(), "123456").Return(tc.err)
+			hw.h.DisableTFA(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+			hw.um.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEnableTFA(t *testing.T) {
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/", strings.NewReader("{invalid json"))
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.h.EnableTFA(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	testCases := []struct {
+		description        string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			"passcode not provided",
+			hub.ErrInvalidInput,
+			http.StatusBadRequest,
+		},
+		{
+			"invalid passcode",
+			user.ErrInvalidPasscode,
+			http.StatusUnauthorized,
+		},
+		{
+			"database error",
+			tests.ErrFakeDB,
+			http.StatusInternalServerError,
+		},
+		{
+			"tfa enabled successfully",
+			nil,
+			http.StatusNoContent,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("PUT", "/", strings.NewReader(`{"passcode": "123456"}`))
+			r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+			hw := newHandlersWrapper()
+			hw.um.On("EnableTFA", r.Context(), "123456").Return(tc.err)
+			hw.h.EnableTFA(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+			hw.um.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetNotificationPreferences(t *testing.T) {
+	t.Run("error getting notification preferences", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.um.On("GetNotificationPreferencesJSON", r.Context()).Return(nil, tests.ErrFakeDB)
+		hw.h.GetNotificationPreferences(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("notification preferences get succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.um.On("GetNotificationPreferencesJSON", r.Context()).Return([]byte("dataJSON"), nil)
+		hw.h.GetNotificationPreferences(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.um.AssertExpectations(t)
+	})
+}
+
 func TestGetProfile(t *testing.T) {
 	t.Run("error getting profile", func(t *testing.T) {
 		t.Parallel()
@@ -192,6 +443,50 @@ func TestGetProfile(t *testing.T) {
 	})
 }
 
+func TestGetSessions(t *testing.T) {
+	t.Run("error getting sessions", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.um.On("GetSessionsJSON", r.Context(), []byte(nil)).Return(nil, tests.ErrFakeDB)
+		hw.h.GetSessions(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("sessions get succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.um.On("GetSessionsJSON", r.Context(), []byte("sessionID")).Return([]byte("dataJSON"), nil)
+		encodedSessionID, _ := hw.h.sc.Encode(sessionCookieName, []byte("sessionID"))
+		r.AddCookie(&http.Cookie{
+			Name:  sessionCookieName,
+			Value: encodedSessionID,
+		})
+		hw.h.GetSessions(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.um.AssertExpectations(t)
+	})
+}
+
 func TestInjectUserID(t *testing.T) {
 	checkUserID := func(expectedUserID interface{}) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
@@ -390,7 +685,7 @@ func TestLogin(t *testing.T) {
 		hw.um.On("CheckCredentials", r.Context(), "email", "pass").
 			Return(&hub.CheckCredentialsOutput{Valid: true, UserID: "userID"}, nil)
 		hw.um.On("RegisterSession", r.Context(), &hub.Session{UserID: "userID"}).
-			Return([]byte("sessionID"), nil)
+			Return(&hub.RegisterSessionOutput{SessionID: []byte("sessionID"), Approved: true}, nil)
 		hw.h.Login(w, r)
 		resp := w.Result()
 		defer resp.Body.Close()
@@ -408,30 +703,78 @@ func TestLogin(t *testing.T) {
 		assert.Equal(t, []byte("sessionID"), sessionID)
 		hw.um.AssertExpectations(t)
 	})
-}
 
-func TestLogout(t *testing.T) {
-	t.Run("invalid or no session cookie provided", func(t *testing.T) {
-		testCases := []struct {
-			description string
-			cookie      *http.Cookie
-		}{
-			{
-				"invalid session cookie provided",
-				nil,
-			},
-			{
-				"no session cookie provided",
-				&http.Cookie{
-					Name:  sessionCookieName,
-					Value: "invalidValue",
-				},
-			},
-		}
-		for _, tc := range testCases {
-			tc := tc
-			t.Run(tc.description, func(t *testing.T) {
-				t.Parallel()
+	t.Run("login succeeded, custom session absolute timeout", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		body := strings.NewReader(`{"email": "email", "password": "pass"}`)
+		r, _ := http.NewRequest("POST", "/", body)
+
+		cfg := viper.New()
+		cfg.Set("server.sessions.absoluteTimeout", "24h")
+		um := &user.ManagerMock{}
+		um.On("CheckCredentials", r.Context(), "email", "pass").
+			Return(&hub.CheckCredentialsOutput{Valid: true, UserID: "userID"}, nil)
+		um.On("RegisterSession", r.Context(), &hub.Session{UserID: "userID"}).
+			Return(&hub.RegisterSessionOutput{SessionID: []byte("sessionID"), Approved: true}, nil)
+		h, _ := NewHandlers(context.Background(), um, cfg)
+		h.Login(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		require.Len(t, resp.Cookies(), 1)
+		cookie := resp.Cookies()[0]
+		assert.True(t, cookie.Expires.Before(time.Now().Add(25*time.Hour)))
+		assert.True(t, cookie.Expires.After(time.Now().Add(23*time.Hour)))
+		assert.Equal(t, 24*time.Hour, h.sessionTTL)
+		um.AssertExpectations(t)
+	})
+
+	t.Run("login succeeded, session pending approval", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		body := strings.NewReader(`{"email": "email", "password": "pass"}`)
+		r, _ := http.NewRequest("POST", "/", body)
+
+		hw := newHandlersWrapper()
+		hw.um.On("CheckCredentials", r.Context(), "email", "pass").
+			Return(&hub.CheckCredentialsOutput{Valid: true, UserID: "userID"}, nil)
+		hw.um.On("RegisterSession", r.Context(), &hub.Session{UserID: "userID"}).
+			Return(&hub.RegisterSessionOutput{SessionID: []byte("sessionID"), Approved: false}, nil)
+		hw.h.Login(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		require.Len(t, resp.Cookies(), 1)
+		assert.Equal(t, sessionCookieName, resp.Cookies()[0].Name)
+		hw.um.AssertExpectations(t)
+	})
+}
+
+func TestLogout(t *testing.T) {
+	t.Run("invalid or no session cookie provided", func(t *testing.T) {
+		testCases := []struct {
+			description string
+			cookie      *http.Cookie
+		}{
+			{
+				"invalid session cookie provided",
+				nil,
+			},
+			{
+				"no session cookie provided",
+				&http.Cookie{
+					Name:  sessionCookieName,
+					Value: "invalidValue",
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				t.Parallel()
 				w := httptest.NewRecorder()
 				r, _ := http.NewRequest("GET", "/", nil)
 				if tc.cookie != nil {
@@ -606,6 +949,60 @@ func TestRegisterUser(t *testing.T) {
 	})
 }
 
+func TestRegisterPasswordResetCode(t *testing.T) {
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", strings.NewReader("{invalid json"))
+
+		hw := newHandlersWrapper()
+		hw.h.RegisterPasswordResetCode(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	testCases := []struct {
+		description        string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			"email not provided",
+			hub.ErrInvalidInput,
+			http.StatusBadRequest,
+		},
+		{
+			"database error",
+			tests.ErrFakeDB,
+			http.StatusInternalServerError,
+		},
+		{
+			"password reset code registered successfully",
+			nil,
+			http.StatusCreated,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("POST", "/", strings.NewReader(`{"email": "email@email.com"}`))
+
+			hw := newHandlersWrapper()
+			hw.um.On("RegisterPasswordResetCode", r.Context(), "email@email.com", "baseURL").Return(tc.err)
+			hw.h.RegisterPasswordResetCode(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+			hw.um.AssertExpectations(t)
+		})
+	}
+}
+
 func TestRequireLogin(t *testing.T) {
 	sessionID := []byte("sessionID")
 
@@ -633,7 +1030,7 @@ func TestRequireLogin(t *testing.T) {
 			r, _ := http.NewRequest("GET", "/", nil)
 
 			hw := newHandlersWrapper()
-			hw.um.On("CheckSession", r.Context(), sessionID, sessionDuration).
+			hw.um.On("CheckSession", r.Context(), sessionID).
 				Return(nil, tests.ErrFakeDB)
 			encodedSessionID, _ := hw.h.sc.Encode(sessionCookieName, sessionID)
 			r.AddCookie(&http.Cookie{
@@ -654,7 +1051,7 @@ func TestRequireLogin(t *testing.T) {
 			r, _ := http.NewRequest("GET", "/", nil)
 
 			hw := newHandlersWrapper()
-			hw.um.On("CheckSession", r.Context(), sessionID, sessionDuration).
+			hw.um.On("CheckSession", r.Context(), sessionID).
 				Return(&hub.CheckSessionOutput{UserID: "", Valid: false}, nil)
 			encodedSessionID, _ := hw.h.sc.Encode(sessionCookieName, sessionID)
 			r.AddCookie(&http.Cookie{
@@ -675,7 +1072,7 @@ func TestRequireLogin(t *testing.T) {
 			r, _ := http.NewRequest("GET", "/", nil)
 
 			hw := newHandlersWrapper()
-			hw.um.On("CheckSession", r.Context(), sessionID, sessionDuration).
+			hw.um.On("CheckSession", r.Context(), sessionID).
 				Return(&hub.CheckSessionOutput{UserID: "userID", Valid: true}, nil)
 			encodedSessionID, _ := hw.h.sc.Encode(sessionCookieName, sessionID)
 			r.AddCookie(&http.Cookie{
@@ -758,6 +1155,27 @@ func TestRequireLogin(t *testing.T) {
 			assert.Equal(t, http.StatusOK, resp.StatusCode)
 			hw.um.AssertExpectations(t)
 		})
+
+		t.Run("api key scopes are injected in context", func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Add(apiKeyHeader, keyB64)
+			scopes := []hub.APIKeyScope{hub.ReadOnlyScope}
+
+			hw := newHandlersWrapper()
+			hw.um.On("CheckAPIKey", r.Context(), key).
+				Return(&hub.CheckAPIKeyOutput{UserID: "userID", Valid: true, Scopes: scopes}, nil)
+			hw.h.RequireLogin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "userID", r.Context().Value(hub.UserIDKey).(string))
+				assert.Equal(t, scopes, r.Context().Value(hub.APIKeyScopesKey).([]hub.APIKeyScope))
+			})).ServeHTTP(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			hw.um.AssertExpectations(t)
+		})
 	})
 
 	t.Run("no authentication method used", func(t *testing.T) {
@@ -774,6 +1192,339 @@ func TestRequireLogin(t *testing.T) {
 	})
 }
 
+func TestRequireScope(t *testing.T) {
+	testCases := []struct {
+		keyScopes          []hub.APIKeyScope
+		scopes             []hub.APIKeyScope
+		expectedStatusCode int
+	}{
+		{
+			nil,
+			nil,
+			http.StatusOK,
+		},
+		{
+			nil,
+			[]hub.APIKeyScope{hub.ManageRepositoriesScope},
+			http.StatusOK,
+		},
+		{
+			[]hub.APIKeyScope{hub.ReadOnlyScope},
+			nil,
+			http.StatusForbidden,
+		},
+		{
+			[]hub.APIKeyScope{hub.ReadOnlyScope},
+			[]hub.APIKeyScope{hub.ReadOnlyScope},
+			http.StatusOK,
+		},
+		{
+			[]hub.APIKeyScope{hub.ReadOnlyScope},
+			[]hub.APIKeyScope{hub.ManageRepositoriesScope},
+			http.StatusForbidden,
+		},
+		{
+			[]hub.APIKeyScope{hub.ManageRepositoriesScope},
+			[]hub.APIKeyScope{hub.ReadOnlyScope, hub.ManageRepositoriesScope},
+			http.StatusOK,
+		},
+		{
+			[]hub.APIKeyScope{hub.ReadOnlyScope, hub.ManageWebhooksScope},
+			[]hub.APIKeyScope{hub.ManageWebhooksScope},
+			http.StatusOK,
+		},
+		{
+			[]hub.APIKeyScope{hub.ManageSubscriptionsScope},
+			[]hub.APIKeyScope{hub.ManageWebhooksScope},
+			http.StatusForbidden,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("key scopes: %v, scopes: %v", tc.keyScopes, tc.scopes), func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("GET", "/", nil)
+			if tc.keyScopes != nil {
+				r = r.WithContext(context.WithValue(r.Context(), hub.APIKeyScopesKey, tc.keyScopes))
+			}
+
+			hw := newHandlersWrapper()
+			hw.h.RequireScope(tc.scopes...)(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/", strings.NewReader("{invalid json"))
+
+		hw := newHandlersWrapper()
+		hw.h.ResetPassword(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	testCases := []struct {
+		description        string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			"invalid or expired code",
+			hub.ErrInvalidInput,
+			http.StatusBadRequest,
+		},
+		{
+			"database error",
+			tests.ErrFakeDB,
+			http.StatusInternalServerError,
+		},
+		{
+			"password reset successfully",
+			nil,
+			http.StatusNoContent,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("PUT", "/", strings.NewReader(`{"code": "1234", "password": "new"}`))
+
+			hw := newHandlersWrapper()
+			hw.um.On("ResetPassword", r.Context(), "1234", "new").Return(tc.err)
+			hw.h.ResetPassword(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+			hw.um.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeOtherSessions(t *testing.T) {
+	testCases := []struct {
+		description        string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			"database error",
+			tests.ErrFakeDB,
+			http.StatusInternalServerError,
+		},
+		{
+			"other sessions revoked successfully",
+			nil,
+			http.StatusNoContent,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("DELETE", "/", nil)
+			r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+			hw := newHandlersWrapper()
+			hw.um.On("RevokeOtherSessions", r.Context(), []byte("sessionID")).Return(tc.err)
+			encodedSessionID, _ := hw.h.sc.Encode(sessionCookieName, []byte("sessionID"))
+			r.AddCookie(&http.Cookie{
+				Name:  sessionCookieName,
+				Value: encodedSessionID,
+			})
+			hw.h.RevokeOtherSessions(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+			hw.um.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"sessionID"},
+			Values: []string{"sessionID"},
+		},
+	}
+
+	testCases := []struct {
+		description        string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			"invalid session id",
+			hub.ErrInvalidInput,
+			http.StatusBadRequest,
+		},
+		{
+			"database error",
+			tests.ErrFakeDB,
+			http.StatusInternalServerError,
+		},
+		{
+			"session revoked successfully",
+			nil,
+			http.StatusNoContent,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("DELETE", "/", nil)
+			r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			hw := newHandlersWrapper()
+			hw.um.On("RevokeSession", r.Context(), "sessionID").Return(tc.err)
+			hw.h.RevokeSession(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+			hw.um.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetupTFA(t *testing.T) {
+	t.Run("error setting up tfa", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.um.On("SetupTFA", r.Context()).Return(nil, tests.ErrFakeDB)
+		hw.h.SetupTFA(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("tfa set up successfully", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.um.On("SetupTFA", r.Context()).Return([]byte("dataJSON"), nil)
+		hw.h.SetupTFA(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.um.AssertExpectations(t)
+	})
+}
+
+func TestUpdateNotificationPreferences(t *testing.T) {
+	preferencesJSON := `{"event_kinds": [{"event_kind": 0, "digest": true}]}`
+	p := &hub.NotificationPreferences{}
+	_ = json.Unmarshal([]byte(preferencesJSON), &p)
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			desc            string
+			preferencesJSON string
+			umErr           error
+		}{
+			{
+				"no notification preferences provided",
+				"",
+				nil,
+			},
+			{
+				"invalid notification preferences json",
+				"{invalid json",
+				nil,
+			},
+			{
+				"invalid event kind",
+				`{"event_kinds": [{"event_kind": 9}]}`,
+				hub.ErrInvalidInput,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.desc, func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("PUT", "/", strings.NewReader(tc.preferencesJSON))
+
+				hw := newHandlersWrapper()
+				if tc.umErr != nil {
+					hw.um.On("UpdateNotificationPreferences", r.Context(), mock.Anything).Return(tc.umErr)
+				}
+				hw.h.UpdateNotificationPreferences(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				hw.um.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("error updating notification preferences", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/", strings.NewReader(preferencesJSON))
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.um.On("UpdateNotificationPreferences", r.Context(), p).Return(tests.ErrFakeDB)
+		hw.h.UpdateNotificationPreferences(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("notification preferences updated successfully", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("PUT", "/", strings.NewReader(preferencesJSON))
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.um.On("UpdateNotificationPreferences", r.Context(), p).Return(nil)
+		hw.h.UpdateNotificationPreferences(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		hw.um.AssertExpectations(t)
+	})
+}
+
 func TestUpdatePassword(t *testing.T) {
 	t.Run("no old password provided", func(t *testing.T) {
 		t.Parallel()
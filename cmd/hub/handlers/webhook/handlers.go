@@ -2,6 +2,7 @@ package webhook
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -13,19 +14,32 @@ import (
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
 )
 
+// webhookSampleEventID represents the id of the sample events used to render
+// webhooks templates.
+const webhookSampleEventID = "00000000-0000-0000-0000-000000000001"
+
 // Handlers represents a group of http handlers in charge of handling webhooks
 // operations.
 type Handlers struct {
 	webhookManager hub.WebhookManager
+	pkgManager     hub.PackageManager
+	cfg            *viper.Viper
 	logger         zerolog.Logger
 }
 
 // NewHandlers creates a new Handlers instance.
-func NewHandlers(webhookManager hub.WebhookManager) *Handlers {
+func NewHandlers(
+	webhookManager hub.WebhookManager,
+	pkgManager hub.PackageManager,
+	cfg *viper.Viper,
+) *Handlers {
 	return &Handlers{
 		webhookManager: webhookManager,
+		pkgManager:     pkgManager,
+		cfg:            cfg,
 		logger:         log.With().Str("handlers", "webhook").Logger(),
 	}
 }
@@ -70,6 +84,19 @@ func (h *Handlers) Get(w http.ResponseWriter, r *http.Request) {
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
 }
 
+// GetDeliveries is an http handler that returns the last deliveries attempts
+// of the provided webhook.
+func (h *Handlers) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhookID := chi.URLParam(r, "webhookID")
+	dataJSON, err := h.webhookManager.GetDeliveriesJSON(r.Context(), webhookID)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetDeliveries").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
 // GetOwnedByOrg is an http handler that returns the webhooks owned by the
 // organization provided. The user doing the request must belong to the
 // organization.
@@ -96,44 +123,174 @@ func (h *Handlers) GetOwnedByUser(w http.ResponseWriter, r *http.Request) {
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
 }
 
-// TriggerTest is an http handler used to test a webhook before adding or
-// updating it.
-func (h *Handlers) TriggerTest(w http.ResponseWriter, r *http.Request) {
-	// Read webhook from request body
-	wh := &hub.Webhook{}
-	if err := json.NewDecoder(r.Body).Decode(&wh); err != nil {
+// Preview is an http handler used to render a webhook template without calling
+// the webhook endpoint, so that it can be checked before adding or updating
+// the webhook. When no template is provided, the default one for the event
+// kind selected is rendered.
+func (h *Handlers) Preview(w http.ResponseWriter, r *http.Request) {
+	input := &hub.WebhookTemplatePreviewInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error().Err(err).Str("method", "Preview").Msg(hub.ErrInvalidInput.Error())
 		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
 		return
 	}
 
-	// Prepare payload
+	// Prepare template data
+	tmplData, err := h.prepareTemplateData(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "Preview").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+
+	// Render template
 	var tmpl *template.Template
-	if wh.Template != "" {
-		var err error
-		tmpl, err = template.New("").Parse(wh.Template)
+	if input.Template != "" {
+		tmpl, err = notification.ParseTemplate(input.Template)
 		if err != nil {
 			err = fmt.Errorf("error parsing template: %w", err)
 			helpers.RenderErrorWithCodeJSON(w, err, http.StatusBadRequest)
 			return
 		}
 	} else {
-		tmpl = notification.DefaultWebhookPayloadTmpl
+		tmpl = notification.DefaultWebhookPayloadTmplFor(input.EventKind)
 	}
-	var payload bytes.Buffer
-	if err := tmpl.Execute(&payload, webhookTestTemplateData); err != nil {
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, tmplData); err != nil {
 		err = fmt.Errorf("error executing template: %w", err)
 		helpers.RenderErrorWithCodeJSON(w, err, http.StatusBadRequest)
 		return
 	}
+	dataJSON, _ := json.Marshal(map[string]string{
+		"payload": b.String(),
+	})
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// prepareTemplateData prepares the data used to render a webhook template for
+// preview. Package events use the details of the package provided (if any),
+// whereas some sample data is used in any other case.
+func (h *Handlers) prepareTemplateData(
+	ctx context.Context,
+	input *hub.WebhookTemplatePreviewInput,
+) (interface{}, error) {
+	baseURL := h.cfg.GetString("server.baseURL")
+	e := &hub.Event{
+		EventID:   webhookSampleEventID,
+		EventKind: input.EventKind,
+	}
+	switch input.EventKind {
+	case hub.NewRelease, hub.SecurityAlert:
+		p := webhookSamplePackage
+		if input.PackageID != "" {
+			var err error
+			p, err = h.pkgManager.Get(ctx, &hub.GetPackageInput{
+				PackageID: input.PackageID,
+				Version:   input.PackageVersion,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		e.PackageID = p.PackageID
+		e.PackageVersion = p.Version
+		if input.EventKind == hub.SecurityAlert {
+			e.Data = webhookSampleSecurityAlertData
+		}
+		return notification.NewPkgNotificationTemplateData(baseURL, e, p), nil
+	case hub.RepositoryTrackingErrors, hub.RepositoryOwnershipClaim:
+		return notification.NewRepoNotificationTemplateData(baseURL, e, webhookSampleRepository), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid event kind")
+	}
+}
+
+// Redeliver is an http handler that schedules the notification of the
+// provided webhook delivery to be delivered again.
+func (h *Handlers) Redeliver(w http.ResponseWriter, r *http.Request) {
+	webhookID := chi.URLParam(r, "webhookID")
+	webhookDeliveryID := chi.URLParam(r, "webhookDeliveryID")
+	if err := h.webhookManager.Redeliver(r.Context(), webhookID, webhookDeliveryID); err != nil {
+		h.logger.Error().Err(err).Str("method", "Redeliver").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
 
-	// Call webhook endpoint
-	req, _ := http.NewRequest("POST", wh.URL, &payload)
+// TriggerTest is an http handler used to test a webhook before adding or
+// updating it. When testing an existing webhook whose secret has not been
+// changed, the secret currently stored is used to sign the request.
+func (h *Handlers) TriggerTest(w http.ResponseWriter, r *http.Request) {
+	// Read webhook from request body
+	wh := &hub.Webhook{}
+	if err := json.NewDecoder(r.Body).Decode(&wh); err != nil {
+		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		return
+	}
+	secret := wh.Secret
+	if secret == "" && wh.WebhookID != "" && !wh.ClearSecret {
+		var err error
+		secret, err = h.webhookManager.GetSecret(r.Context(), wh.WebhookID)
+		if err != nil {
+			h.logger.Error().Err(err).Str("method", "TriggerTest").Msg("error getting webhook secret")
+			helpers.RenderErrorJSON(w, err)
+			return
+		}
+	}
+
+	// Prepare payload. Webhooks only subscribed to repositories events are
+	// tested using a sample repository tracking errors notification.
+	eventKind := hub.NewRelease
+	var tmplData interface{} = webhookTestTemplateData
+	if onlyRepositoryEvents(wh.EventKinds) {
+		eventKind = hub.RepositoryTrackingErrors
+		tmplData = webhookTestRepoTemplateData
+	}
+	var payload []byte
 	contentType := wh.ContentType
+	if notification.IsChatWebhook(wh.Kind) {
+		var err error
+		if eventKind == hub.RepositoryTrackingErrors {
+			payload, err = notification.BuildRepoChatPayload(wh.Kind, webhookTestRepoTemplateData)
+		} else {
+			payload, err = notification.BuildPkgChatPayload(wh.Kind, webhookTestTemplateData)
+		}
+		if err != nil {
+			err = fmt.Errorf("error building payload: %w", err)
+			helpers.RenderErrorWithCodeJSON(w, err, http.StatusBadRequest)
+			return
+		}
+		contentType = "application/json"
+	} else {
+		var tmpl *template.Template
+		if wh.Template != "" {
+			var err error
+			tmpl, err = notification.ParseTemplate(wh.Template)
+			if err != nil {
+				err = fmt.Errorf("error parsing template: %w", err)
+				helpers.RenderErrorWithCodeJSON(w, err, http.StatusBadRequest)
+				return
+			}
+		} else {
+			tmpl = notification.DefaultWebhookPayloadTmplFor(eventKind)
+		}
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, tmplData); err != nil {
+			err = fmt.Errorf("error executing template: %w", err)
+			helpers.RenderErrorWithCodeJSON(w, err, http.StatusBadRequest)
+			return
+		}
+		payload = b.Bytes()
+	}
 	if contentType == "" {
 		contentType = notification.DefaultPayloadContentType
 	}
+
+	// Call webhook endpoint
+	req, _ := http.NewRequest("POST", wh.URL, bytes.NewReader(payload))
 	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("X-ArtifactHub-Secret", wh.Secret)
+	notification.SignWebhookRequest(req, payload, secret, wh.LegacySecretHeader)
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		err = fmt.Errorf("error doing request: %s", err.Error())
@@ -192,3 +349,81 @@ var webhookTestTemplateData = &hub.PackageNotificationTemplateData{
 		},
 	},
 }
+
+// webhookTestRepoTemplateData represents the repository notification template
+// data used by TriggerTest handler.
+var webhookTestRepoTemplateData = &hub.RepositoryNotificationTemplateData{
+	BaseURL: "https://artifacthub.io",
+	Event: map[string]interface{}{
+		"id":   "00000000-0000-0000-0000-000000000001",
+		"kind": "repository.tracking-errors",
+	},
+	Repository: map[string]interface{}{
+		"kind":             "helm",
+		"name":             "repo1",
+		"userAlias":        "user1",
+		"organizationName": "",
+		"lastTrackingErrors": []string{
+			"error processing package sample-package version 1.0.0",
+		},
+	},
+}
+
+// onlyRepositoryEvents checks if all the event kinds provided are repository
+// events.
+func onlyRepositoryEvents(kinds []hub.EventKind) bool {
+	if len(kinds) == 0 {
+		return false
+	}
+	for _, kind := range kinds {
+		switch kind {
+		case hub.RepositoryTrackingErrors, hub.RepositoryOwnershipClaim:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// webhookSamplePackage represents the package used to render webhooks
+// templates for preview when no package is provided.
+var webhookSamplePackage = &hub.Package{
+	PackageID:      "00000000-0000-0000-0000-000000000001",
+	Name:           "sample-package",
+	NormalizedName: "sample-package",
+	Version:        "1.0.0",
+	Changes: []string{
+		"Cool feature",
+		"Bug fixed",
+	},
+	ContainsSecurityUpdates: true,
+	Prerelease:              true,
+	CreatedAt:               1609459200,
+	SecurityReportSummary: &hub.SecurityReportSummary{
+		Critical: 1,
+		High:     2,
+	},
+	Repository: &hub.Repository{
+		Kind:             hub.Helm,
+		Name:             "repo1",
+		OrganizationName: "org1",
+	},
+}
+
+// webhookSampleSecurityAlertData represents the event data used to render
+// security alert webhooks templates for preview.
+var webhookSampleSecurityAlertData = map[string]interface{}{
+	"added_vulnerabilities": []interface{}{
+		map[string]interface{}{"id": "CVE-0000-0001", "severity": "critical"},
+		map[string]interface{}{"id": "CVE-0000-0002", "severity": "high"},
+	},
+}
+
+// webhookSampleRepository represents the repository used to render webhooks
+// templates for preview.
+var webhookSampleRepository = &hub.Repository{
+	Kind:               hub.Helm,
+	Name:               "repo1",
+	OrganizationName:   "org1",
+	LastTrackingErrors: "error processing package sample-package version 1.0.0",
+}
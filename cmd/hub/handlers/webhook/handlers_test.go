@@ -14,10 +14,12 @@ import (
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/notification"
+	"github.com/artifacthub/hub/internal/pkg"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/webhook"
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -273,6 +275,76 @@ func TestGet(t *testing.T) {
 	})
 }
 
+func TestGetDeliveries(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"webhookID"},
+			Values: []string{"000000001"},
+		},
+	}
+
+	t.Run("error getting webhook deliveries", func(t *testing.T) {
+		testCases := []struct {
+			err                error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				hub.ErrInsufficientPrivilege,
+				http.StatusForbidden,
+			},
+			{
+				tests.ErrFakeDB,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.err.Error(), func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/", nil)
+				r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.wm.On("GetDeliveriesJSON", r.Context(), "000000001").Return(nil, tc.err)
+				hw.h.GetDeliveries(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.wm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("webhook deliveries get succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.wm.On("GetDeliveriesJSON", r.Context(), "000000001").Return([]byte("dataJSON"), nil)
+		hw.h.GetDeliveries(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.wm.AssertExpectations(t)
+	})
+}
+
 func TestGetOwnedByOrg(t *testing.T) {
 	rctx := &chi.Context{
 		URLParams: chi.RouteParams{
@@ -378,6 +450,261 @@ func TestGetOwnedByUser(t *testing.T) {
 	})
 }
 
+func TestPreview(t *testing.T) {
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			description string
+			inputJSON   string
+		}{
+			{
+				"no input provided",
+				"",
+			},
+			{
+				"invalid json",
+				"-",
+			},
+			{
+				"invalid event kind",
+				`{"event_kind": 99}`,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("POST", "/", strings.NewReader(tc.inputJSON))
+
+				hw := newHandlersWrapper()
+				hw.h.Preview(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			})
+		}
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		testCases := []struct {
+			inputJSON string
+			err       string
+		}{
+			{
+				`{"template": "{{ .."}`,
+				"error parsing template",
+			},
+			{
+				`{"template": "{{ unknown .Package.name }}"}`,
+				"error parsing template",
+			},
+			{
+				`{"template": "{{ .Package.name.invalid }}"}`,
+				"error executing template",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.err, func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("POST", "/", strings.NewReader(tc.inputJSON))
+
+				hw := newHandlersWrapper()
+				hw.h.Preview(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+				data, _ := ioutil.ReadAll(resp.Body)
+
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.True(t, strings.HasPrefix(getErrorMessage(t, data), tc.err))
+			})
+		}
+	})
+
+	t.Run("error getting package", func(t *testing.T) {
+		t.Parallel()
+		inputJSON := `{"event_kind": 0, "package_id": "packageID"}`
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(inputJSON))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), &hub.GetPackageInput{PackageID: "packageID"}).Return(nil, hub.ErrNotFound)
+		hw.h.Preview(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("template rendered successfully", func(t *testing.T) {
+		testCases := []struct {
+			id              string
+			inputJSON       string
+			expectedPayload string
+		}{
+			{
+				"sample package data",
+				`{
+					"event_kind": 0,
+					"template": "{{ upper .Package.name }} {{ join \", \" .Package.changes }} {{ date \"2006-01-02\" .Package.createdAt }}"
+				}`,
+				"SAMPLE-PACKAGE Cool feature, Bug fixed 2021-01-01",
+			},
+			{
+				"security alert sample data",
+				`{
+					"event_kind": 1,
+					"template": "{{ .Event.kind }} {{ len .Event.addedVulnerabilities }} {{ .Package.securityReportSummary.critical }}"
+				}`,
+				"package.security-alert 2 1",
+			},
+			{
+				"repository sample data",
+				`{
+					"event_kind": 3,
+					"template": "{{ .Event.kind }} {{ .Repository.name }} {{ default \"-\" .Repository.userAlias }}"
+				}`,
+				"repository.ownership-claim repo1 -",
+			},
+			{
+				"default template",
+				`{"event_kind": 2}`,
+				`"lastTrackingErrors": ["error processing package sample-package version 1.0.0"]`,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.id, func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("POST", "/", strings.NewReader(tc.inputJSON))
+
+				hw := newHandlersWrapper()
+				hw.h.Preview(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+				data, _ := ioutil.ReadAll(resp.Body)
+
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				var output map[string]string
+				require.NoError(t, json.Unmarshal(data, &output))
+				assert.Contains(t, output["payload"], tc.expectedPayload)
+			})
+		}
+	})
+
+	t.Run("template rendered successfully using package data", func(t *testing.T) {
+		t.Parallel()
+		inputJSON := `{
+			"event_kind": 0,
+			"package_id": "packageID",
+			"package_version": "2.0.0",
+			"template": "{{ .Package.name }} {{ .Package.version }} {{ .Package.url }} {{ json .Package.repository.publisher }}"
+		}`
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(inputJSON))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), &hub.GetPackageInput{
+			PackageID: "packageID",
+			Version:   "2.0.0",
+		}).Return(&hub.Package{
+			PackageID:      "packageID",
+			Name:           "pkg1",
+			NormalizedName: "pkg1",
+			Version:        "2.0.0",
+			Repository: &hub.Repository{
+				Kind:      hub.Helm,
+				Name:      "repo1",
+				UserAlias: "user1",
+			},
+		}, nil)
+		hw.h.Preview(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), resp.Header.Get("Cache-Control"))
+		var output map[string]string
+		require.NoError(t, json.Unmarshal(data, &output))
+		assert.Equal(t, `pkg1 2.0.0 http://baseURL/packages/helm/repo1/pkg1/2.0.0 "user1"`, output["payload"])
+		hw.pm.AssertExpectations(t)
+	})
+}
+
+func TestRedeliver(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"webhookID", "webhookDeliveryID"},
+			Values: []string{"000000001", "000000002"},
+		},
+	}
+
+	t.Run("error redelivering webhook notification", func(t *testing.T) {
+		testCases := []struct {
+			err                error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				hub.ErrInsufficientPrivilege,
+				http.StatusForbidden,
+			},
+			{
+				hub.ErrNotFound,
+				http.StatusNotFound,
+			},
+			{
+				tests.ErrFakeDB,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.err.Error(), func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("POST", "/", nil)
+				r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.wm.On("Redeliver", r.Context(), "000000001", "000000002").Return(tc.err)
+				hw.h.Redeliver(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.wm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("redeliver webhook notification succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.wm.On("Redeliver", r.Context(), "000000001", "000000002").Return(nil)
+		hw.h.Redeliver(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		hw.wm.AssertExpectations(t)
+	})
+}
+
 func TestTriggerTest(t *testing.T) {
 	t.Run("invalid input", func(t *testing.T) {
 		testCases := []struct {
@@ -498,6 +825,66 @@ func TestTriggerTest(t *testing.T) {
 		assert.Equal(t, "received unexpected status code: 404", getErrorMessage(t, data))
 	})
 
+	t.Run("chat webhook endpoint call succeeded", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			var payload map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, "sample-package version 1.0.0 released", payload["text"])
+		}))
+		defer ts.Close()
+
+		wh := &hub.Webhook{URL: ts.URL, Kind: hub.SlackWebhook}
+		webhookJSON, _ := json.Marshal(wh)
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", bytes.NewReader(webhookJSON))
+
+		hw := newHandlersWrapper()
+		hw.h.TriggerTest(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("repository events webhook endpoint call succeeded", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload struct {
+				Type string `json:"type"`
+				Data struct {
+					Repository struct {
+						Name               string   `json:"name"`
+						LastTrackingErrors []string `json:"lastTrackingErrors"`
+					} `json:"repository"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, "io.artifacthub.repository.tracking-errors", payload.Type)
+			assert.Equal(t, "repo1", payload.Data.Repository.Name)
+			assert.Len(t, payload.Data.Repository.LastTrackingErrors, 1)
+		}))
+		defer ts.Close()
+
+		wh := &hub.Webhook{
+			URL:        ts.URL,
+			EventKinds: []hub.EventKind{hub.RepositoryTrackingErrors, hub.RepositoryOwnershipClaim},
+		}
+		webhookJSON, _ := json.Marshal(wh)
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", bytes.NewReader(webhookJSON))
+
+		hw := newHandlersWrapper()
+		hw.h.TriggerTest(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
 	t.Run("webhook endpoint call succeeded", func(t *testing.T) {
 		testCases := []struct {
 			id              string
@@ -555,9 +942,19 @@ func TestTriggerTest(t *testing.T) {
 					}
 					assert.Equal(t, "POST", r.Method)
 					assert.Equal(t, contentType, r.Header.Get("Content-Type"))
-					assert.Equal(t, tc.secret, r.Header.Get("X-ArtifactHub-Secret"))
+					assert.Empty(t, r.Header.Get("X-ArtifactHub-Secret"))
 					payload, _ := ioutil.ReadAll(r.Body)
 					assert.Equal(t, tc.expectedPayload, payload)
+					signature := r.Header.Get(notification.WebhookSignatureHeader)
+					if tc.secret != "" {
+						parts := strings.Split(signature, ",")
+						require.Len(t, parts, 2)
+						ts := strings.TrimPrefix(parts[0], "t=")
+						expectedSignature := notification.WebhookSignature(tc.secret, ts, payload)
+						assert.Equal(t, "v1="+expectedSignature, parts[1])
+					} else {
+						assert.Empty(t, signature)
+					}
 				}))
 				defer ts.Close()
 
@@ -581,6 +978,54 @@ func TestTriggerTest(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("error getting stored webhook secret", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{WebhookID: "webhookID", URL: "http://webhook1.url"}
+		webhookJSON, _ := json.Marshal(wh)
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", bytes.NewReader(webhookJSON))
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.wm.On("GetSecret", r.Context(), "webhookID").Return("", hub.ErrInsufficientPrivilege)
+		hw.h.TriggerTest(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		hw.wm.AssertExpectations(t)
+	})
+
+	t.Run("stored webhook secret used to sign the request", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			payload, _ := ioutil.ReadAll(r.Body)
+			parts := strings.Split(r.Header.Get(notification.WebhookSignatureHeader), ",")
+			require.Len(t, parts, 2)
+			ts := strings.TrimPrefix(parts[0], "t=")
+			expectedSignature := notification.WebhookSignature("storedSecret", ts, payload)
+			assert.Equal(t, "v1="+expectedSignature, parts[1])
+		}))
+		defer ts.Close()
+
+		wh := &hub.Webhook{WebhookID: "webhookID", URL: ts.URL}
+		webhookJSON, _ := json.Marshal(wh)
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", bytes.NewReader(webhookJSON))
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.wm.On("GetSecret", r.Context(), "webhookID").Return("storedSecret", nil)
+		hw.h.TriggerTest(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		hw.wm.AssertExpectations(t)
+	})
 }
 
 func TestUpdate(t *testing.T) {
@@ -694,15 +1139,20 @@ func TestUpdate(t *testing.T) {
 
 type handlersWrapper struct {
 	wm *webhook.ManagerMock
+	pm *pkg.ManagerMock
 	h  *Handlers
 }
 
 func newHandlersWrapper() *handlersWrapper {
 	wm := &webhook.ManagerMock{}
+	pm := &pkg.ManagerMock{}
+	cfg := viper.New()
+	cfg.Set("server.baseURL", "http://baseURL")
 
 	return &handlersWrapper{
 		wm: wm,
-		h:  NewHandlers(wm),
+		pm: pm,
+		h:  NewHandlers(wm, pm, cfg),
 	}
 }
 
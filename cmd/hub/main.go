@@ -9,11 +9,13 @@ import (
 	"sync"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Quiet hours time zones may not be available in the image
 
 	"github.com/artifacthub/hub/cmd/hub/handlers"
 	"github.com/artifacthub/hub/internal/apikey"
 	"github.com/artifacthub/hub/internal/authz"
 	"github.com/artifacthub/hub/internal/email"
+	"github.com/artifacthub/hub/internal/encryption"
 	"github.com/artifacthub/hub/internal/event"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/img/pg"
@@ -54,17 +56,51 @@ func main() {
 		log.Fatal().Err(err).Msg("authorizer setup failed")
 	}
 	hc := &http.Client{Timeout: 10 * time.Second}
+	enc, err := util.SetupEncrypter(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("encrypter setup failed")
+	}
+
+	// Encrypt credentials and secrets stored before encryption was enabled
+	// when requested (one-off command, it can be run again safely)
+	if len(os.Args) > 1 && os.Args[1] == "encrypt-secrets" {
+		if !enc.Enabled() {
+			log.Fatal().Msg("encryption key not configured")
+		}
+		n, err := encryption.EncryptStoredSecrets(context.Background(), db, enc)
+		if err != nil {
+			log.Fatal().Err(err).Int("count", n).Msg("error encrypting stored secrets")
+		}
+		log.Info().Int("count", n).Msg("stored secrets encrypted")
+		return
+	}
+	if !enc.Enabled() {
+		log.Warn().Msg("encryption key not configured: credentials and secrets will be stored in plaintext")
+	}
+	if cfg.GetString("server.unsubscribe.hashKey") == "" {
+		log.Warn().Msg("unsubscribe key not configured: notifications emails will not include unsubscribe links")
+	}
 
 	// Setup and launch http server
+	sessionIdleTimeout := user.DefaultSessionIdleTimeout
+	if cfg.IsSet("server.sessions.idleTimeout") {
+		sessionIdleTimeout = cfg.GetDuration("server.sessions.idleTimeout")
+	}
+	sessionAbsoluteTimeout := user.DefaultSessionAbsoluteTimeout
+	if cfg.IsSet("server.sessions.absoluteTimeout") {
+		sessionAbsoluteTimeout = cfg.GetDuration("server.sessions.absoluteTimeout")
+	}
+	sessionTimeouts := user.WithSessionTimeouts(sessionIdleTimeout, sessionAbsoluteTimeout)
 	ctx, stop := context.WithCancel(context.Background())
 	hSvc := &handlers.Services{
 		OrganizationManager: org.NewManager(db, es, az),
-		UserManager:         user.NewManager(db, es),
-		RepositoryManager:   repo.NewManager(cfg, db, az),
+		UserManager:         user.NewManager(db, es, user.WithEncrypter(enc), sessionTimeouts),
+		RepositoryManager:   repo.NewManager(cfg, db, az, repo.WithEncrypter(enc)),
 		PackageManager:      pkg.NewManager(db),
 		SubscriptionManager: subscription.NewManager(db),
-		WebhookManager:      webhook.NewManager(db),
-		APIKeyManager:       apikey.NewManager(db),
+		WebhookManager:      webhook.NewManager(db, webhook.WithEncrypter(enc)),
+		APIKeyManager:       apikey.NewManager(db, az),
+		EventManager:        event.NewManager(db),
 		ImageStore:          pg.NewImageStore(cfg, db, hc, nil),
 		Authorizer:          az,
 	}
@@ -100,10 +136,12 @@ func main() {
 	var wg sync.WaitGroup
 	eSvc := &event.Services{
 		DB:                  db,
-		EventManager:        event.NewManager(),
+		EventManager:        event.NewManager(db),
 		SubscriptionManager: subscription.NewManager(db),
 		WebhookManager:      webhook.NewManager(db),
 		NotificationManager: notification.NewManager(),
+		PackageManager:      pkg.NewManager(db),
+		UserManager:         user.NewManager(db, es, user.WithEncrypter(enc)),
 	}
 	eventsDispatcher := event.NewDispatcher(eSvc)
 	wg.Add(1)
@@ -117,6 +155,7 @@ func main() {
 		SubscriptionManager: subscription.NewManager(db),
 		RepositoryManager:   repo.NewManager(cfg, db, az),
 		PackageManager:      pkg.NewManager(db),
+		Encrypter:           enc,
 	}
 	notificationsDispatcher := notification.NewDispatcher(cfg, nSvc)
 	wg.Add(1)
@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"os"
-	"os/exec"
 	"os/signal"
 	"sync"
 	"syscall"
@@ -37,11 +36,6 @@ func main() {
 		log.Info().Msg("scanner shutting down..")
 	}()
 
-	// Check required external tools are available
-	if _, err := exec.LookPath("trivy"); err != nil {
-		log.Fatal().Err(err).Msg("trivy not found")
-	}
-
 	// Setup services
 	db, err := util.SetupDB(cfg)
 	if err != nil {
@@ -49,16 +43,13 @@ func main() {
 	}
 	pm := pkg.NewManager(db)
 
-	// Scan pending snapshots
-	trivyURL := cfg.GetString("scanner.trivyURL")
-	if trivyURL == "" {
-		log.Fatal().Err(err).Msg("trivy url not set")
-	}
-	trivyScanner := &scanner.TrivyScanner{
-		Ctx: ctx,
-		Cfg: cfg,
-		URL: trivyURL,
+	// Setup scanner backend
+	sc, err := scanner.New(ctx, cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("scanner backend setup failed")
 	}
+
+	// Scan pending snapshots
 	snapshots, err := pm.GetSnapshotsToScan(ctx)
 	if err != nil {
 		log.Fatal().Err(err).Msg("error getting snapshots to scan")
@@ -81,7 +72,7 @@ L:
 
 			logger := log.With().Str("pkg", snapshot.PackageID).Str("version", snapshot.Version).Logger()
 			logger.Info().Msg("scanning snapshot")
-			report, err := scanner.ScanSnapshot(ctx, trivyScanner, snapshot)
+			report, err := scanner.ScanSnapshot(ctx, sc, snapshot)
 			if err != nil {
 				logger.Error().Err(err).Send()
 			}
@@ -17,6 +17,7 @@ import (
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/tracker"
 	"github.com/artifacthub/hub/internal/tracker/errors"
+	"github.com/artifacthub/hub/internal/trackingjob"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/time/rate"
@@ -24,6 +25,14 @@ import (
 
 const (
 	githubMaxRequestsPerHour = 5000
+
+	// oneShotMode represents the tracker mode in which all the repositories
+	// are processed once and then the tracker exits.
+	oneShotMode = "oneshot"
+
+	// longRunningMode represents the tracker mode in which the tracker keeps
+	// running, processing the tracking jobs scheduled for each repository.
+	longRunningMode = "long-running"
 )
 
 func main() {
@@ -62,6 +71,10 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("authorizer setup failed")
 	}
+	enc, err := util.SetupEncrypter(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("encrypter setup failed")
+	}
 	rm := repo.NewManager(cfg, db, az)
 	pm := pkg.NewManager(db)
 	hc := &http.Client{Timeout: 10 * time.Second}
@@ -74,28 +87,49 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("image store setup failed")
 	}
-	ec := errors.NewCollector(rm)
 	svc := &hub.TrackerServices{
 		Ctx:                ctx,
 		Cfg:                cfg,
 		Rm:                 rm,
 		Pm:                 pm,
-		Rc:                 &repo.Cloner{},
+		Rc:                 repo.NewCloner(cfg),
 		Oe:                 &repo.OLMOCIExporter{},
-		Ec:                 ec,
+		Ec:                 errors.NewCollector(rm),
 		Hc:                 hc,
+		Enc:                enc,
 		Is:                 is,
 		GithubRL:           githubRL,
 		SetupTrackerSource: tracker.SetupSource,
 	}
 
 	// Track registered repositories
-	repos, err := tracker.GetRepositories(ctx, cfg, rm)
+	cfg.SetDefault("tracker.mode", oneShotMode)
+	cfg.SetDefault("tracker.concurrency", 1)
+	jm := trackingjob.NewManager(db)
+	switch mode := cfg.GetString("tracker.mode"); mode {
+	case oneShotMode:
+		tracker.DrainTrackingJobs(ctx, svc, jm, cfg.GetInt("tracker.concurrency"))
+		trackRepositories(ctx, svc)
+	case longRunningMode:
+		d := tracker.NewDispatcher(svc, jm, tracker.WithNumWorkers(cfg.GetInt("tracker.concurrency")))
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go d.Run(ctx, &wg)
+		wg.Wait()
+	default:
+		log.Fatal().Str("mode", mode).Msg("invalid tracker mode")
+	}
+	log.Info().Msg("tracker finished")
+}
+
+// trackRepositories runs the tracker once on all the repositories selected,
+// flushing the errors collected when done.
+func trackRepositories(ctx context.Context, svc *hub.TrackerServices) {
+	repos, err := tracker.GetRepositories(ctx, svc.Cfg, svc.Rm)
 	if err != nil {
 		log.Fatal().Err(err).Msg("error getting repositories")
 	}
-	cfg.SetDefault("tracker.concurrency", 1)
-	limiter := make(chan struct{}, cfg.GetInt("tracker.concurrency"))
+	limiter := make(chan struct{}, svc.Cfg.GetInt("tracker.concurrency"))
 	var wg sync.WaitGroup
 L:
 	for _, r := range repos {
@@ -126,6 +160,5 @@ L:
 		}(r)
 	}
 	wg.Wait()
-	ec.Flush()
-	log.Info().Msg("tracker finished")
+	svc.Ec.Flush()
 }
@@ -1,19 +1,31 @@
 {{ template "repositories/get_repository_by_id.sql" }}
 {{ template "repositories/get_repository_summary.sql" }}
+{{ template "repositories/normalize_git_url.sql" }}
 
 {{ template "api_keys/add_api_key.sql" }}
+{{ template "api_keys/add_org_api_key.sql" }}
+{{ template "api_keys/check_api_key.sql" }}
 {{ template "api_keys/delete_api_key.sql" }}
+{{ template "api_keys/delete_org_api_key.sql" }}
 {{ template "api_keys/get_api_key.sql" }}
+{{ template "api_keys/get_org_api_key.sql" }}
+{{ template "api_keys/get_org_api_keys.sql" }}
 {{ template "api_keys/get_user_api_keys.sql" }}
 {{ template "api_keys/update_api_key.sql" }}
+{{ template "api_keys/update_org_api_key.sql" }}
 
+{{ template "events/get_events_cursor.sql" }}
 {{ template "events/get_pending_event.sql" }}
+{{ template "events/get_user_events.sql" }}
 
 {{ template "images/get_image.sql" }}
 {{ template "images/register_image.sql" }}
 
 {{ template "notifications/add_notification.sql" }}
+{{ template "notifications/add_webhook_delivery.sql" }}
+{{ template "notifications/get_pending_digest.sql" }}
 {{ template "notifications/get_pending_notification.sql" }}
+{{ template "notifications/schedule_notification_retry.sql" }}
 {{ template "notifications/update_notification_status.sql" }}
 
 {{ template "organizations/add_organization_member.sql" }}
@@ -26,9 +38,11 @@
 {{ template "organizations/get_organization.sql" }}
 {{ template "organizations/get_organization_members.sql" }}
 {{ template "organizations/get_user_organizations.sql" }}
+{{ template "organizations/regular_user_belongs_to_organization.sql" }}
 {{ template "organizations/update_authorization_policy.sql" }}
 {{ template "organizations/update_organization.sql" }}
 {{ template "organizations/user_belongs_to_organization.sql" }}
+{{ template "organizations/user_meets_organization_tfa_requirement.sql" }}
 
 {{ template "packages/generate_package_tsdoc.sql" }}
 {{ template "packages/get_harbor_replication_dump.sql" }}
@@ -52,6 +66,7 @@
 {{ template "repositories/add_repository.sql" }}
 {{ template "repositories/delete_repository.sql" }}
 {{ template "repositories/get_all_repositories.sql" }}
+{{ template "repositories/get_repositories_by_git_url.sql" }}
 {{ template "repositories/get_repositories_by_kind.sql" }}
 {{ template "repositories/get_repository_by_name.sql" }}
 {{ template "repositories/get_repository_packages_digest.sql" }}
@@ -62,6 +77,9 @@
 {{ template "repositories/transfer_repository.sql" }}
 {{ template "repositories/update_repository.sql" }}
 
+{{ template "secrets/get_unencrypted_secrets.sql" }}
+{{ template "secrets/update_secret.sql" }}
+
 {{ template "subscriptions/add_opt_out.sql" }}
 {{ template "subscriptions/add_subscription.sql" }}
 {{ template "subscriptions/delete_opt_out.sql" }}
@@ -69,13 +87,32 @@
 {{ template "subscriptions/get_package_subscriptors.sql" }}
 {{ template "subscriptions/get_repository_subscriptors.sql" }}
 {{ template "subscriptions/get_user_opt_out_entries.sql" }}
+{{ template "subscriptions/get_user_organization_subscriptions.sql" }}
 {{ template "subscriptions/get_user_package_subscriptions.sql" }}
+{{ template "subscriptions/get_user_repository_subscriptions.sql" }}
 {{ template "subscriptions/get_user_subscriptions.sql" }}
+{{ template "subscriptions/unsubscribe.sql" }}
+
+{{ template "tracking_jobs/enqueue_tracking_job.sql" }}
+{{ template "tracking_jobs/enqueue_tracking_jobs.sql" }}
+{{ template "tracking_jobs/finish_tracking_job.sql" }}
+{{ template "tracking_jobs/get_tracking_job.sql" }}
+{{ template "tracking_jobs/lease_tracking_job.sql" }}
+{{ template "tracking_jobs/renew_tracking_job_lease.sql" }}
+{{ template "tracking_jobs/schedule_tracking_jobs.sql" }}
 
+{{ template "users/approve_session.sql" }}
 {{ template "users/check_user_alias_availability.sql" }}
+{{ template "users/get_user_notification_preferences.sql" }}
 {{ template "users/get_user_profile.sql" }}
+{{ template "users/get_user_sessions.sql" }}
+{{ template "users/get_user_tfa_config.sql" }}
+{{ template "users/register_failed_tfa_attempt.sql" }}
+{{ template "users/register_password_reset_code.sql" }}
 {{ template "users/register_session.sql" }}
 {{ template "users/register_user.sql" }}
+{{ template "users/reset_user_password.sql" }}
+{{ template "users/update_user_notification_preferences.sql" }}
 {{ template "users/update_user_password.sql" }}
 {{ template "users/update_user_profile.sql" }}
 {{ template "users/verify_email.sql" }}
@@ -85,7 +122,11 @@
 {{ template "webhooks/get_webhook.sql" }}
 {{ template "webhooks/get_org_webhooks.sql" }}
 {{ template "webhooks/get_user_webhooks.sql" }}
+{{ template "webhooks/get_webhook_deliveries.sql" }}
+{{ template "webhooks/get_webhook_secret.sql" }}
 {{ template "webhooks/get_webhooks_subscribed_to_package.sql" }}
+{{ template "webhooks/get_webhooks_subscribed_to_repository.sql" }}
+{{ template "webhooks/redeliver_webhook_notification.sql" }}
 {{ template "webhooks/update_webhook.sql" }}
 {{ template "webhooks/user_has_access_to_webhook.sql" }}
 
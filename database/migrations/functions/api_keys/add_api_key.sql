@@ -3,9 +3,13 @@ create or replace function add_api_key(p_api_key jsonb)
 returns bytea as $$
     insert into api_key (
         name,
+        scopes,
+        expires_at,
         user_id
     ) values (
         p_api_key->>'name',
+        (select nullif(array(select jsonb_array_elements_text(nullif(p_api_key->'scopes', 'null'::jsonb))), '{}')),
+        to_timestamp((p_api_key->>'expires_at')::bigint),
         (p_api_key->>'user_id')::uuid
     )
     returning key;
@@ -0,0 +1,58 @@
+-- add_org_api_key adds the provided api key to the organization given if the
+-- requesting user belongs to it. Organization api keys are bound to a service
+-- account that is created as a member of the organization, so that they keep
+-- working regardless of the users who belong to it.
+create or replace function add_org_api_key(
+    p_user_id uuid,
+    p_org_name text,
+    p_api_key jsonb
+) returns bytea as $$
+declare
+    v_organization_id uuid;
+    v_api_key_id uuid := gen_random_uuid();
+    v_service_account_alias text;
+    v_service_account_id uuid;
+    v_key bytea;
+begin
+    if not user_belongs_to_organization(p_user_id, p_org_name) then
+        raise insufficient_privilege;
+    end if;
+    select organization_id into v_organization_id
+    from organization where name = p_org_name;
+
+    -- Register service account and add it to the organization
+    v_service_account_alias := format('%s-sa-%s', p_org_name, left(replace(v_api_key_id::text, '-', ''), 8));
+    insert into "user" (
+        alias,
+        email,
+        service_account
+    ) values (
+        v_service_account_alias,
+        format('%s@service-account.invalid', v_service_account_alias),
+        true
+    )
+    returning user_id into v_service_account_id;
+    insert into user__organization (user_id, organization_id, confirmed)
+    values (v_service_account_id, v_organization_id, true);
+
+    -- Register api key
+    insert into api_key (
+        api_key_id,
+        name,
+        scopes,
+        expires_at,
+        user_id,
+        organization_id
+    ) values (
+        v_api_key_id,
+        p_api_key->>'name',
+        (select nullif(array(select jsonb_array_elements_text(nullif(p_api_key->'scopes', 'null'::jsonb))), '{}')),
+        to_timestamp((p_api_key->>'expires_at')::bigint),
+        v_service_account_id,
+        v_organization_id
+    )
+    returning key into v_key;
+
+    return v_key;
+end
+$$ language plpgsql;
@@ -0,0 +1,20 @@
+-- check_api_key checks if the provided api key is valid, returning the user
+-- it belongs to and the scopes granted to it. It also registers the time the
+-- key was last used at, at most once per minute to avoid updating the key on
+-- every request.
+create or replace function check_api_key(p_key bytea)
+returns table (user_id uuid, scopes text[]) as $$
+    with valid_key as (
+        select ak.api_key_id, ak.user_id, ak.scopes, ak.last_used_at
+        from api_key ak
+        where ak.key = p_key
+        and (ak.expires_at is null or ak.expires_at > current_timestamp)
+    ), last_used_update as (
+        update api_key ak set last_used_at = current_timestamp
+        from valid_key vk
+        where ak.api_key_id = vk.api_key_id
+        and (vk.last_used_at is null or vk.last_used_at < current_timestamp - '1 minute'::interval)
+    )
+    select vk.user_id, vk.scopes
+    from valid_key vk;
+$$ language sql;
@@ -0,0 +1,23 @@
+-- delete_org_api_key deletes the provided organization api key from the
+-- database, as well as the service account it is bound to.
+create or replace function delete_org_api_key(
+    p_user_id uuid,
+    p_org_name text,
+    p_api_key_id uuid
+) returns void as $$
+begin
+    if not user_belongs_to_organization(p_user_id, p_org_name) then
+        raise insufficient_privilege;
+    end if;
+
+    delete from "user"
+    where service_account = true
+    and user_id = (
+        select ak.user_id
+        from api_key ak
+        join organization o using (organization_id)
+        where ak.api_key_id = p_api_key_id
+        and o.name = p_org_name
+    );
+end
+$$ language plpgsql;
@@ -4,7 +4,10 @@ returns setof json as $$
     select json_build_object(
         'api_key_id', api_key_id,
         'name', name,
-        'created_at', floor(extract(epoch from created_at))
+        'scopes', scopes,
+        'created_at', floor(extract(epoch from created_at)),
+        'expires_at', floor(extract(epoch from expires_at)),
+        'last_used_at', floor(extract(epoch from last_used_at))
     )
     from api_key
     where api_key_id = p_api_key_id
@@ -0,0 +1,23 @@
+-- get_org_api_key returns the organization api key requested as a json object
+-- if the requesting user belongs to the organization.
+create or replace function get_org_api_key(
+    p_user_id uuid,
+    p_org_name text,
+    p_api_key_id uuid
+) returns setof json as $$
+    select json_build_object(
+        'api_key_id', ak.api_key_id,
+        'name', ak.name,
+        'scopes', ak.scopes,
+        'created_at', floor(extract(epoch from ak.created_at)),
+        'expires_at', floor(extract(epoch from ak.expires_at)),
+        'last_used_at', floor(extract(epoch from ak.last_used_at)),
+        'service_account_alias', u.alias
+    )
+    from api_key ak
+    join "user" u using (user_id)
+    join organization o using (organization_id)
+    where ak.api_key_id = p_api_key_id
+    and o.name = p_org_name
+    and user_belongs_to_organization(p_user_id, p_org_name) = true;
+$$ language sql;
@@ -0,0 +1,14 @@
+-- get_org_api_keys returns the api keys that belong to the organization
+-- provided if the requesting user belongs to it.
+create or replace function get_org_api_keys(p_user_id uuid, p_org_name text)
+returns setof json as $$
+    select coalesce(json_agg(akJSON), '[]')
+    from (
+        select akJSON
+        from api_key ak
+        join organization o using (organization_id)
+        cross join get_org_api_key(p_user_id, p_org_name, api_key_id) as akJSON
+        where o.name = p_org_name
+        order by ak.name asc
+    ) aks;
+$$ language sql;
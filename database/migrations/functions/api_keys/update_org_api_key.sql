@@ -0,0 +1,17 @@
+-- update_org_api_key updates the provided organization api key in the
+-- database.
+create or replace function update_org_api_key(
+    p_user_id uuid,
+    p_org_name text,
+    p_api_key jsonb
+) returns void as $$
+begin
+    if not user_belongs_to_organization(p_user_id, p_org_name) then
+        raise insufficient_privilege;
+    end if;
+
+    update api_key set name = p_api_key->>'name'
+    where api_key_id = (p_api_key->>'api_key_id')::uuid
+    and organization_id = (select organization_id from organization where name = p_org_name);
+end
+$$ language plpgsql;
@@ -0,0 +1,26 @@
+-- get_events_cursor returns the position in the events stream of the event
+-- provided. When no event is provided or it does not exist, the position of
+-- the last event registered is returned instead.
+create or replace function get_events_cursor(p_event_id uuid)
+returns setof json as $$
+    select coalesce(
+        (
+            select json_build_object(
+                'created_at', created_at,
+                'event_id', event_id
+            )
+            from event
+            where event_id = p_event_id
+        ),
+        (
+            select json_build_object(
+                'created_at', created_at,
+                'event_id', event_id
+            )
+            from event
+            order by created_at desc, event_id desc
+            limit 1
+        ),
+        json_build_object('created_at', current_timestamp)
+    );
+$$ language sql;
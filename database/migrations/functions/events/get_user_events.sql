@@ -0,0 +1,167 @@
+-- get_user_events returns the events the user provided can receive in the
+-- events stream that were created after the cursor position given. Users
+-- receive the package events they are subscribed to (directly or through the
+-- repository or organization owning the package) as well as the events of the
+-- repositories they own, unless they have opted out of them (packages events
+-- can be opted out individually even when the subscription was made through
+-- the repository or organization owning the package). Events can be
+-- filtered by package, repository, organization and event kind.
+create or replace function get_user_events(p_user_id uuid, p_input jsonb)
+returns setof json as $$
+    with params as (
+        select
+            (p_input->'cursor'->>'created_at')::timestamptz as after,
+            coalesce(
+                nullif(p_input->'cursor'->>'event_id', '')::uuid,
+                'ffffffff-ffff-ffff-ffff-ffffffffffff'
+            ) as after_event_id,
+            nullif(p_input->>'package_id', '')::uuid as package_id,
+            nullif(p_input->>'repository_id', '')::uuid as repository_id,
+            nullif(p_input->>'organization_id', '')::uuid as organization_id,
+            (
+                select array_agg(k::int)
+                from jsonb_array_elements_text(nullif(p_input->'event_kinds', 'null')) k
+            ) as event_kinds,
+            coalesce((p_input->>'limit')::int, 50) as max_events
+    )
+    select coalesce(json_agg(json_strip_nulls(json_build_object(
+        'event_id', event_id,
+        'event_kind', event_kind_id,
+        'created_at', created_at,
+        'repository_id', event_repository_id,
+        'package_id', package_id,
+        'package_version', package_version,
+        'data', data,
+        'package', package,
+        'repository', repository,
+        'subscription_filters', subscription_filters
+    )) order by created_at asc, event_id asc), '[]')
+    from (
+        select ev.*
+        from (
+            -- Package events the user is subscribed to
+            select
+                e.event_id,
+                e.event_kind_id,
+                e.created_at,
+                e.repository_id as event_repository_id,
+                r.repository_id,
+                r.organization_id,
+                e.package_id,
+                e.package_version,
+                e.data,
+                json_build_object(
+                    'package_id', p.package_id,
+                    'name', p.name,
+                    'normalized_name', p.normalized_name,
+                    'version', s.version,
+                    'prerelease', s.prerelease,
+                    'contains_security_updates', s.contains_security_updates
+                ) as package,
+                json_build_object(
+                    'repository_id', r.repository_id,
+                    'kind', r.repository_kind_id,
+                    'name', r.name,
+                    'user_alias', u.alias,
+                    'organization_id', r.organization_id,
+                    'organization_name', o.name
+                ) as repository,
+                case when sub.unfiltered then null else sub.filters end as subscription_filters
+            from event e
+            join package p using (package_id)
+            join snapshot s on s.package_id = e.package_id and s.version = e.package_version
+            join repository r on r.repository_id = p.repository_id
+            left join "user" u on u.user_id = r.user_id
+            left join organization o on o.organization_id = r.organization_id
+            join lateral (
+                select
+                    bool_or(filters is null) as unfiltered,
+                    jsonb_agg(filters) as filters
+                from (
+                    select filters
+                    from subscription
+                    where user_id = p_user_id
+                    and package_id = e.package_id
+                    and event_kind_id = e.event_kind_id
+                    union all
+                    select filters
+                    from repository_subscription
+                    where user_id = p_user_id
+                    and repository_id = r.repository_id
+                    and event_kind_id = e.event_kind_id
+                    union all
+                    select filters
+                    from organization_subscription
+                    where user_id = p_user_id
+                    and organization_id = r.organization_id
+                    and event_kind_id = e.event_kind_id
+                ) subscriptions
+                having count(*) > 0
+            ) sub on true
+            where e.event_kind_id in (0, 1)
+            and p_user_id not in (
+                select user_id
+                from package_opt_out
+                where package_id = e.package_id
+                and event_kind_id = e.event_kind_id
+            )
+            union all
+            -- Events of the repositories the user owns
+            select
+                e.event_id,
+                e.event_kind_id,
+                e.created_at,
+                e.repository_id as event_repository_id,
+                r.repository_id,
+                r.organization_id,
+                e.package_id,
+                e.package_version,
+                e.data,
+                null as package,
+                json_build_object(
+                    'repository_id', r.repository_id,
+                    'kind', r.repository_kind_id,
+                    'name', r.name,
+                    'user_alias', u.alias,
+                    'organization_id', r.organization_id,
+                    'organization_name', o.name
+                ) as repository,
+                null as subscription_filters
+            from event e
+            join repository r using (repository_id)
+            left join "user" u on u.user_id = r.user_id
+            left join organization o on o.organization_id = r.organization_id
+            where (
+                (
+                    e.event_kind_id = 2
+                    and (
+                        r.user_id = p_user_id
+                        or r.organization_id in (
+                            select organization_id
+                            from user__organization
+                            where user_id = p_user_id
+                            and confirmed = true
+                        )
+                    )
+                    and p_user_id not in (
+                        select user_id
+                        from opt_out
+                        where repository_id = e.repository_id
+                        and event_kind_id = e.event_kind_id
+                    )
+                )
+                or (
+                    e.event_kind_id = 3
+                    and e.data->'subscriptors' @> jsonb_build_array(jsonb_build_object('user_id', p_user_id))
+                )
+            )
+        ) ev, params i
+        where (ev.created_at, ev.event_id) > (i.after, i.after_event_id)
+        and (i.package_id is null or ev.package_id = i.package_id)
+        and (i.repository_id is null or ev.repository_id = i.repository_id)
+        and (i.organization_id is null or ev.organization_id = i.organization_id)
+        and (i.event_kinds is null or ev.event_kind_id = any(i.event_kinds))
+        order by ev.created_at asc, ev.event_id asc
+        limit (select max_events from params)
+    ) events;
+$$ language sql;
@@ -4,10 +4,14 @@ returns void as $$
     insert into notification (
         event_id,
         user_id,
-        webhook_id
+        webhook_id,
+        digest,
+        next_attempt_at
     ) values (
         ((p_notification->'event')->>'event_id')::uuid,
         ((p_notification->'user')->>'user_id')::uuid,
-        ((p_notification->'webhook')->>'webhook_id')::uuid
+        ((p_notification->'webhook')->>'webhook_id')::uuid,
+        coalesce((p_notification->>'digest')::boolean, false),
+        (p_notification->>'next_attempt_at')::timestamptz
     );
 $$ language sql;
@@ -0,0 +1,21 @@
+-- add_webhook_delivery registers the provided webhook delivery attempt.
+create or replace function add_webhook_delivery(p_delivery jsonb)
+returns void as $$
+    insert into webhook_delivery (
+        attempt,
+        status_code,
+        latency_ms,
+        response,
+        error,
+        notification_id,
+        webhook_id
+    ) values (
+        (p_delivery->>'attempt')::int,
+        nullif((p_delivery->>'status_code')::int, 0),
+        (p_delivery->>'latency_ms')::int,
+        nullif(p_delivery->>'response', ''),
+        nullif(p_delivery->>'error', ''),
+        (p_delivery->>'notification_id')::uuid,
+        (p_delivery->>'webhook_id')::uuid
+    );
+$$ language sql;
@@ -0,0 +1,51 @@
+-- get_pending_digest returns the pending digest notifications of a user, once
+-- the digest is due. A digest is due when its oldest pending notification is
+-- older than the period selected by the user (weekly digests are delivered
+-- every 7 days, daily ones otherwise). Digests whose delivery failed are not
+-- returned until the next attempt is due.
+create or replace function get_pending_digest()
+returns setof json as $$
+    with digest_user as (
+        select u.user_id, u.email, u.delivery_preference_id
+        from "user" u
+        where exists (
+            select 1
+            from notification n
+            where n.user_id = u.user_id
+            and n.processed = false
+            and n.digest = true
+            and (n.next_attempt_at is null or n.next_attempt_at <= current_timestamp)
+            and n.created_at <= current_timestamp - (
+                case u.delivery_preference_id when 2 then '7 days' else '1 day' end
+            )::interval
+        )
+        for update of u skip locked
+        limit 1
+    )
+    select json_build_object(
+        'user', json_build_object(
+            'user_id', du.user_id,
+            'email', du.email,
+            'delivery_preference', du.delivery_preference_id
+        ),
+        'notifications', (
+            select json_agg(json_build_object(
+                'notification_id', n.notification_id,
+                'attempts', n.attempts,
+                'event', json_strip_nulls(json_build_object(
+                    'event_id', e.event_id,
+                    'event_kind', e.event_kind_id,
+                    'package_id', e.package_id,
+                    'package_version', e.package_version,
+                    'data', e.data
+                ))
+            ) order by n.created_at asc)
+            from notification n
+            join event e using (event_id)
+            where n.user_id = du.user_id
+            and n.processed = false
+            and n.digest = true
+        )
+    )
+    from digest_user du;
+$$ language sql;
@@ -1,30 +1,38 @@
 -- get_pending_notification returns a pending notification if available.
+-- Notifications to be delivered in a digest are not returned, as they are
+-- delivered by get_pending_digest.
 create or replace function get_pending_notification()
 returns setof json as $$
     select json_strip_nulls(json_build_object(
         'notification_id', n.notification_id,
+        'attempts', n.attempts,
         'event', json_build_object(
             'event_id', e.event_id,
             'event_kind', e.event_kind_id,
             'repository_id', e.repository_id,
             'package_id', e.package_id,
-            'package_version', e.package_version
+            'package_version', e.package_version,
+            'data', e.data
         ),
         'user', (select nullif(
             jsonb_build_object(
+                'user_id', u.user_id,
                 'email', u.email
             ),
-            '{"email": null}'::jsonb
+            '{"user_id": null, "email": null}'::jsonb
         )),
         'webhook', (select nullif(
             jsonb_build_object(
+                'webhook_id', wh.webhook_id,
                 'name', wh.name,
                 'url', wh.url,
                 'secret', wh.secret,
                 'content_type', wh.content_type,
-                'template', wh.template
+                'template', wh.template,
+                'legacy_secret_header', wh.legacy_secret_header,
+                'kind', wh.webhook_kind_id
             ),
-            '{"name": null, "url": null, "secret": null, "content_type": null, "template": null}'::jsonb
+            '{"webhook_id": null, "name": null, "url": null, "secret": null, "content_type": null, "template": null, "legacy_secret_header": null, "kind": null}'::jsonb
         ))
     ))
     from notification n
@@ -32,6 +40,8 @@ returns setof json as $$
     left join "user" u using (user_id)
     left join webhook wh using (webhook_id)
     where n.processed = false
+    and (n.next_attempt_at is null or n.next_attempt_at <= current_timestamp)
+    and n.digest = false
     for update of n skip locked
     limit 1;
 $$ language sql;
@@ -6,6 +6,16 @@ begin
         raise insufficient_privilege;
     end if;
 
+    -- Delete the service accounts bound to the organization api keys
+    delete from "user"
+    where service_account = true
+    and user_id in (
+        select ak.user_id
+        from api_key ak
+        join organization o using (organization_id)
+        where o.name = p_org_name
+    );
+
     delete from organization where name = p_org_name;
 end
 $$ language plpgsql;
@@ -1,9 +1,9 @@
 -- get_organization_members returns the members of the organization provided as
--- a json array.
+-- a json array. Service accounts are not allowed to get them.
 create or replace function get_organization_members(p_requesting_user_id uuid, p_org_name text)
 returns setof json as $$
 begin
-    if not user_belongs_to_organization(p_requesting_user_id, p_org_name) then
+    if not regular_user_belongs_to_organization(p_requesting_user_id, p_org_name) then
         raise insufficient_privilege;
     end if;
 
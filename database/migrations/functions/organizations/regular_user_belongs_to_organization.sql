@@ -0,0 +1,18 @@
+-- regular_user_belongs_to_organization checks if a user who is not a service
+-- account belongs to the provided organization. Service accounts can only
+-- perform the actions explicitly allowed to them in the organization's
+-- authorization policy, so this check must be used instead of
+-- user_belongs_to_organization in the operations not covered by it.
+create or replace function regular_user_belongs_to_organization(p_user_id uuid, p_org_name text)
+returns boolean as $$
+    select exists (
+        select uo.user_id
+        from organization o
+        join user__organization uo using (organization_id)
+        join "user" u using (user_id)
+        where o.name = p_org_name
+        and uo.user_id = p_user_id
+        and uo.confirmed = true
+        and u.service_account = false
+    );
+$$ language sql;
@@ -12,7 +12,8 @@ begin
         display_name = nullif(p_org->>'display_name', ''),
         description = nullif(p_org->>'description', ''),
         home_url = nullif(p_org->>'home_url', ''),
-        logo_image_id = nullif(p_org->>'logo_image_id', '')::uuid
+        logo_image_id = nullif(p_org->>'logo_image_id', '')::uuid,
+        tfa_required = coalesce((p_org->>'tfa_required')::boolean, tfa_required)
     where name = p_org_name;
 end
 $$ language plpgsql;
@@ -0,0 +1,29 @@
+-- user_meets_organization_tfa_requirement checks if the provided user meets
+-- the two-factor authentication requirement of the given organization. Users
+-- must have two-factor authentication enabled to operate on organizations that
+-- require it or that own official repositories. Service accounts, which can
+-- only authenticate using api keys, are not subject to this requirement.
+create or replace function user_meets_organization_tfa_requirement(p_user_id uuid, p_org_name text)
+returns boolean as $$
+    select
+        not exists (
+            select 1
+            from organization o
+            where o.name = p_org_name
+            and (
+                o.tfa_required = true
+                or exists (
+                    select 1
+                    from repository r
+                    where r.organization_id = o.organization_id
+                    and r.official = true
+                )
+            )
+        )
+        or exists (
+            select 1
+            from "user" u
+            where u.user_id = p_user_id
+            and (u.tfa_enabled = true or u.service_account = true)
+        );
+$$ language sql;
@@ -1,11 +1,62 @@
 -- update_snapshot_security_report updates the security report of the package's
--- snapshot provides.
+-- snapshot provided. When new critical or high severity vulnerabilities are
+-- found in the package's latest version (compared to the previous report), a
+-- security alert event is registered as well. Vulnerabilities are compared by
+-- id, target and package, so changes in the severity of vulnerabilities
+-- already reported don't trigger new alerts.
 create or replace function update_snapshot_security_report(p_report jsonb)
 returns void as $$
+declare
+    v_package_id uuid := (p_report->>'package_id')::uuid;
+    v_version text := p_report->>'version';
+    v_latest_version text;
+    v_previous_summary jsonb;
+    v_previous_report jsonb;
+    v_added_vulnerabilities jsonb;
+begin
+    -- Get previous security report and package's latest version
+    select p.latest_version, s.security_report_summary, s.security_report
+    into v_latest_version, v_previous_summary, v_previous_report
+    from snapshot s
+    join package p using (package_id)
+    where s.package_id = v_package_id
+    and s.version = v_version;
+
+    -- Update snapshot security report
     update snapshot set
         security_report = p_report->'full',
         security_report_summary = p_report->'summary',
         security_report_created_at = current_timestamp
-    where package_id = (p_report->>'package_id')::uuid
-    and version = p_report->>'version';
-$$ language sql;
+    where package_id = v_package_id
+    and version = v_version;
+
+    -- Register security alert event if needed
+    if v_previous_summary is null or v_version <> v_latest_version then
+        return;
+    end if;
+    select jsonb_agg(jsonb_build_object(
+        'id', id,
+        'severity', severity
+    ) order by id) into v_added_vulnerabilities
+    from (
+        select distinct v->>'VulnerabilityID' as id, lower(v->>'Severity') as severity
+        from jsonb_path_query(p_report->'full', '$.*[*]') as t,
+        jsonb_path_query(t, '$.Vulnerabilities[*] ? (@.Severity == "CRITICAL" || @.Severity == "HIGH")') as v
+        where not exists (
+            select 1
+            from jsonb_path_query(v_previous_report, '$.*[*]') as pt,
+            jsonb_path_query(pt, '$.Vulnerabilities[*]') as pv
+            where pv->>'VulnerabilityID' = v->>'VulnerabilityID'
+            and pt->>'Target' is not distinct from t->>'Target'
+            and pv->>'PkgName' is not distinct from v->>'PkgName'
+        )
+    ) as added;
+    if v_added_vulnerabilities is not null then
+        insert into event (package_id, package_version, event_kind_id, data)
+        values (v_package_id, v_version, 1, jsonb_build_object(
+            'previous_security_report_summary', v_previous_summary,
+            'added_vulnerabilities', v_added_vulnerabilities
+        ));
+    end if;
+end
+$$ language plpgsql;
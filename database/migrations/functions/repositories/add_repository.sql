@@ -24,8 +24,11 @@ begin
         branch,
         auth_user,
         auth_pass,
+        ssh_key,
+        push_webhook_secret,
         disabled,
         scanner_disabled,
+        tracking_interval,
         repository_kind_id,
         user_id,
         organization_id
@@ -36,8 +39,11 @@ begin
         nullif(p_repository->>'branch', ''),
         nullif(p_repository->>'auth_user', ''),
         nullif(p_repository->>'auth_pass', ''),
+        nullif(p_repository->>'ssh_key', ''),
+        nullif(p_repository->>'push_webhook_secret', ''),
         (p_repository->>'disabled')::boolean,
         (p_repository->>'scanner_disabled')::boolean,
+        nullif((p_repository->>'tracking_interval')::int, 0) * '1 minute'::interval,
         (p_repository->>'kind')::int,
         v_owner_user_id,
         v_owner_organization_id
@@ -1,6 +1,6 @@
 -- get_org_repositories returns all available repositories that belong to the
 -- provided organization as a json array. The user provided must belong to the
--- organization used.
+-- organization used, and it cannot be a service account.
 create or replace function get_org_repositories(p_user_id uuid, p_org_name text, p_include_credentials boolean)
 returns setof json as $$
     select coalesce(json_agg(rJSON), '[]')
@@ -9,10 +9,12 @@ returns setof json as $$
         from repository r
         join organization o using (organization_id)
         join user__organization uo using (organization_id)
+        join "user" u on u.user_id = uo.user_id
         cross join get_repository_by_id(r.repository_id, p_include_credentials) as rJSON
         where o.name = p_org_name
         and uo.user_id = p_user_id
         and uo.confirmed = true
+        and u.service_account = false
         order by r.name asc
     ) rs;
 $$ language sql;
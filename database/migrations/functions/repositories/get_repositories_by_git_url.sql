@@ -0,0 +1,24 @@
+-- get_repositories_by_git_url returns all enabled repositories whose url
+-- points to the git repository provided (or to a path within it) as a json
+-- array. Urls are compared once normalized, so http and ssh urls pointing to
+-- the same git repository match.
+create or replace function get_repositories_by_git_url(p_url text, p_include_credentials boolean)
+returns setof json as $$
+    with normalized_url as (
+        select normalize_git_url(p_url) as url
+    )
+    select coalesce(json_agg(rJSON), '[]')
+    from (
+        select rJSON
+        from repository r
+        cross join normalized_url n
+        cross join get_repository_by_id(r.repository_id, p_include_credentials) as rJSON
+        where r.disabled = false
+        and n.url <> ''
+        and (
+            normalize_git_url(r.url) = n.url
+            or starts_with(normalize_git_url(r.url), n.url || '/')
+        )
+        order by r.name asc
+    ) rs;
+$$ language sql;
@@ -13,11 +13,14 @@ begin
             'branch', r.branch,
             'auth_user', r.auth_user,
             'auth_pass', r.auth_pass,
+            'ssh_key', r.ssh_key,
+            'push_webhook_secret', r.push_webhook_secret,
             'kind', r.repository_kind_id,
             'verified_publisher', verified_publisher,
             'official', r.official,
             'disabled', r.disabled,
             'scanner_disabled', r.scanner_disabled,
+            'tracking_interval', floor(extract(epoch from r.tracking_interval) / 60),
             'digest', r.digest,
             'last_tracking_ts', floor(extract(epoch from last_tracking_ts)),
             'last_tracking_errors', r.last_tracking_errors,
@@ -42,6 +45,7 @@ begin
             'official', r.official,
             'disabled', r.disabled,
             'scanner_disabled', r.scanner_disabled,
+            'tracking_interval', floor(extract(epoch from r.tracking_interval) / 60),
             'digest', r.digest,
             'last_tracking_ts', floor(extract(epoch from last_tracking_ts)),
             'last_tracking_errors', r.last_tracking_errors,
@@ -0,0 +1,24 @@
+-- normalize_git_url returns a normalized version of the git url provided that
+-- can be used to compare urls pointing to the same git repository. The scheme,
+-- the user info, the port, the trailing slash and the .git suffix are removed,
+-- scp-like ssh urls (git@host:org/repo) are converted to the host/org/repo
+-- form and the result is lower cased.
+create or replace function normalize_git_url(p_url text)
+returns text as $$
+    select regexp_replace(
+        regexp_replace(
+            regexp_replace(
+                regexp_replace(
+                    regexp_replace(
+                        lower(trim(trailing '/' from p_url)),
+                        '^([a-z][a-z0-9+.-]*://[^/]*):[0-9]+(/|$)', '\1\2'
+                    ),
+                    '^[a-z][a-z0-9+.-]*://', ''
+                ),
+                '^[^@/]+@', ''
+            ),
+            '^([^/:]+):', '\1/'
+        ),
+        '\.git$', ''
+    );
+$$ language sql immutable;
@@ -36,9 +36,21 @@ begin
         url = p_repository->>'url',
         branch = nullif(p_repository->>'branch', ''),
         auth_user = nullif(p_repository->>'auth_user', ''),
-        auth_pass = nullif(p_repository->>'auth_pass', ''),
+        auth_pass = case
+            when (p_repository->>'clear_auth_pass')::boolean = true then null
+            else coalesce(nullif(p_repository->>'auth_pass', ''), auth_pass)
+        end,
+        ssh_key = case
+            when (p_repository->>'clear_ssh_key')::boolean = true then null
+            else coalesce(nullif(p_repository->>'ssh_key', ''), ssh_key)
+        end,
+        push_webhook_secret = case
+            when (p_repository->>'clear_push_webhook_secret')::boolean = true then null
+            else coalesce(nullif(p_repository->>'push_webhook_secret', ''), push_webhook_secret)
+        end,
         disabled = (p_repository->>'disabled')::boolean,
-        scanner_disabled = (p_repository->>'scanner_disabled')::boolean
+        scanner_disabled = (p_repository->>'scanner_disabled')::boolean,
+        tracking_interval = nullif((p_repository->>'tracking_interval')::int, 0) * '1 minute'::interval
     where repository_id = v_repository_id;
 
     -- If the repository has been disabled, remove packages belonging to it
@@ -0,0 +1,37 @@
+-- get_unencrypted_secrets returns the secrets stored in the database that have
+-- not been encrypted yet (they don't start with the prefix provided) as a json
+-- array.
+create or replace function get_unencrypted_secrets(p_encrypted_prefix text)
+returns setof json as $$
+    select coalesce(json_agg(json_build_object(
+        'kind', kind,
+        'id', id,
+        'value', value
+    )), '[]')
+    from (
+        select 'repository_auth_pass' as kind, repository_id as id, auth_pass as value
+        from repository
+        where auth_pass is not null
+        and not starts_with(auth_pass, p_encrypted_prefix)
+        union all
+        select 'repository_ssh_key', repository_id, ssh_key
+        from repository
+        where ssh_key is not null
+        and not starts_with(ssh_key, p_encrypted_prefix)
+        union all
+        select 'repository_push_webhook_secret', repository_id, push_webhook_secret
+        from repository
+        where push_webhook_secret is not null
+        and not starts_with(push_webhook_secret, p_encrypted_prefix)
+        union all
+        select 'webhook_secret', webhook_id, secret
+        from webhook
+        where secret is not null
+        and not starts_with(secret, p_encrypted_prefix)
+        union all
+        select 'user_tfa_secret', user_id, tfa_secret
+        from "user"
+        where tfa_secret is not null
+        and not starts_with(tfa_secret, p_encrypted_prefix)
+    ) s;
+$$ language sql;
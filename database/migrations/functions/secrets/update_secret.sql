@@ -0,0 +1,32 @@
+-- update_secret replaces the value of the secret identified by the kind and id
+-- provided with its encrypted version. The secret is only updated if its value
+-- has not changed since it was read.
+create or replace function update_secret(
+    p_kind text,
+    p_id uuid,
+    p_value text,
+    p_encrypted_value text
+)
+returns void as $$
+begin
+    case p_kind
+    when 'repository_auth_pass' then
+        update repository set auth_pass = p_encrypted_value
+        where repository_id = p_id and auth_pass = p_value;
+    when 'repository_ssh_key' then
+        update repository set ssh_key = p_encrypted_value
+        where repository_id = p_id and ssh_key = p_value;
+    when 'repository_push_webhook_secret' then
+        update repository set push_webhook_secret = p_encrypted_value
+        where repository_id = p_id and push_webhook_secret = p_value;
+    when 'webhook_secret' then
+        update webhook set secret = p_encrypted_value
+        where webhook_id = p_id and secret = p_value;
+    when 'user_tfa_secret' then
+        update "user" set tfa_secret = p_encrypted_value
+        where user_id = p_id and tfa_secret = p_value;
+    else
+        raise 'invalid secret kind: %', p_kind;
+    end case;
+end
+$$ language plpgsql;
@@ -1,13 +1,35 @@
--- add_subscription adds the provided subscription to the database.
+-- add_subscription adds the provided subscription to the database. The
+-- subscription can be to a package, to all the packages in a repository or to
+-- all the packages in the repositories of an organization. When the
+-- subscription already exists, its filters are updated. Subscribing to a
+-- package removes any opt-out entry previously added for it.
 create or replace function add_subscription(p_subscription jsonb)
 returns void as $$
-    insert into subscription (
-        user_id,
-        package_id,
-        event_kind_id
-    ) values (
-        (p_subscription->>'user_id')::uuid,
-        (p_subscription->>'package_id')::uuid,
-        (p_subscription->>'event_kind')::int
-    );
-$$ language sql;
+declare
+    v_user_id uuid := (p_subscription->>'user_id')::uuid;
+    v_event_kind_id int := (p_subscription->>'event_kind')::int;
+    v_filters jsonb := nullif(p_subscription->'filters', 'null'::jsonb);
+begin
+    if nullif(p_subscription->>'package_id', '') is not null then
+        insert into subscription (user_id, package_id, event_kind_id, filters)
+        values (v_user_id, (p_subscription->>'package_id')::uuid, v_event_kind_id, v_filters)
+        on conflict (user_id, package_id, event_kind_id) do update
+        set filters = excluded.filters;
+
+        delete from package_opt_out
+        where user_id = v_user_id
+        and package_id = (p_subscription->>'package_id')::uuid
+        and event_kind_id = v_event_kind_id;
+    elsif nullif(p_subscription->>'repository_id', '') is not null then
+        insert into repository_subscription (user_id, repository_id, event_kind_id, filters)
+        values (v_user_id, (p_subscription->>'repository_id')::uuid, v_event_kind_id, v_filters)
+        on conflict (user_id, repository_id, event_kind_id) do update
+        set filters = excluded.filters;
+    else
+        insert into organization_subscription (user_id, organization_id, event_kind_id, filters)
+        values (v_user_id, (p_subscription->>'organization_id')::uuid, v_event_kind_id, v_filters)
+        on conflict (user_id, organization_id, event_kind_id) do update
+        set filters = excluded.filters;
+    end if;
+end
+$$ language plpgsql;
@@ -1,8 +1,25 @@
 -- delete_subscription deletes the provided subscription from the database.
 create or replace function delete_subscription(p_subscription jsonb)
 returns void as $$
-    delete from subscription
-    where user_id = (p_subscription->>'user_id')::uuid
-    and package_id = (p_subscription->>'package_id')::uuid
-    and event_kind_id = (p_subscription->>'event_kind')::int;
-$$ language sql;
+declare
+    v_user_id uuid := (p_subscription->>'user_id')::uuid;
+    v_event_kind_id int := (p_subscription->>'event_kind')::int;
+begin
+    if nullif(p_subscription->>'package_id', '') is not null then
+        delete from subscription
+        where user_id = v_user_id
+        and package_id = (p_subscription->>'package_id')::uuid
+        and event_kind_id = v_event_kind_id;
+    elsif nullif(p_subscription->>'repository_id', '') is not null then
+        delete from repository_subscription
+        where user_id = v_user_id
+        and repository_id = (p_subscription->>'repository_id')::uuid
+        and event_kind_id = v_event_kind_id;
+    else
+        delete from organization_subscription
+        where user_id = v_user_id
+        and organization_id = (p_subscription->>'organization_id')::uuid
+        and event_kind_id = v_event_kind_id;
+    end if;
+end
+$$ language plpgsql;
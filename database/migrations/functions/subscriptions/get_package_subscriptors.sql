@@ -1,12 +1,46 @@
 -- get_package_subscriptors returns the users subscribed to the package
--- provided for the given event kind.
+-- provided for the given event kind. Users subscribed to the repository the
+-- package belongs to, or to the organization owning that repository, are
+-- subscribed to the package as well. Users who have opted out of the
+-- notifications for the package and event are not returned. When all the
+-- subscriptions matching a given user have filters, they are returned so that
+-- they can be applied.
 create or replace function get_package_subscriptors(p_package_id uuid, p_event_kind int)
 returns setof json as $$
-    select coalesce(json_agg(json_build_object(
-        'user_id', u.user_id
-    )), '[]')
-    from subscription s
-    join "user" u using (user_id)
-    where s.package_id = p_package_id
-    and s.event_kind_id = p_event_kind;
+    select coalesce(json_agg(json_strip_nulls(json_build_object(
+        'user_id', user_id,
+        'subscription_filters', filters
+    ))), '[]')
+    from (
+        select
+            user_id,
+            case when bool_or(filters is null) then null else jsonb_agg(filters) end as filters
+        from (
+            select s.user_id, s.filters
+            from subscription s
+            where s.package_id = p_package_id
+            and s.event_kind_id = p_event_kind
+            union all
+            select rs.user_id, rs.filters
+            from repository_subscription rs
+            join package p using (repository_id)
+            where p.package_id = p_package_id
+            and rs.event_kind_id = p_event_kind
+            union all
+            select os.user_id, os.filters
+            from organization_subscription os
+            join repository r using (organization_id)
+            join package p using (repository_id)
+            where p.package_id = p_package_id
+            and os.event_kind_id = p_event_kind
+        ) s
+        where user_id not in (
+            select user_id
+            from package_opt_out
+            where package_id = p_package_id
+            and event_kind_id = p_event_kind
+        )
+        group by user_id
+        order by user_id asc
+    ) subscriptors;
 $$ language sql;
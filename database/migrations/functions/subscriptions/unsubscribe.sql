@@ -0,0 +1,30 @@
+-- unsubscribe stops the notifications described in the unsubscribe token
+-- provided from being delivered to the user. For packages events, the
+-- subscription to the package is removed and a package opt-out entry is added,
+-- so that subscriptions to the repository or organization the package belongs
+-- to are kept but don't apply to it any longer. An opt-out entry is added for
+-- repositories events.
+create or replace function unsubscribe(p_token jsonb)
+returns void as $$
+declare
+    v_user_id uuid := (p_token->>'user_id')::uuid;
+    v_event_kind_id int := (p_token->>'event_kind')::int;
+    v_package_id uuid := nullif(p_token->>'package_id', '')::uuid;
+    v_repository_id uuid := nullif(p_token->>'repository_id', '')::uuid;
+begin
+    if v_package_id is not null then
+        delete from subscription
+        where user_id = v_user_id
+        and package_id = v_package_id
+        and event_kind_id = v_event_kind_id;
+
+        insert into package_opt_out (user_id, package_id, event_kind_id)
+        values (v_user_id, v_package_id, v_event_kind_id)
+        on conflict (user_id, package_id, event_kind_id) do nothing;
+    else
+        insert into opt_out (user_id, repository_id, event_kind_id)
+        values (v_user_id, v_repository_id, v_event_kind_id)
+        on conflict (user_id, repository_id, event_kind_id) do nothing;
+    end if;
+end
+$$ language plpgsql;
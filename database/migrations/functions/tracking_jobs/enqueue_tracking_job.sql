@@ -0,0 +1,43 @@
+-- enqueue_tracking_job registers a tracking job for the provided repository
+-- to be run as soon as possible, returning its id. If the repository already
+-- has a pending job, it will be rescheduled to run now instead of registering
+-- a new one. Running jobs are never reused, as they may have already checked
+-- the repository's changes, so the new job will be run once they finish.
+create or replace function enqueue_tracking_job(p_user_id uuid, p_repository_name text)
+returns uuid as $$
+declare
+    v_repository_id uuid;
+    v_owner_user_id uuid;
+    v_owner_organization_name text;
+    v_tracking_job_id uuid;
+begin
+    -- Get user or organization owning the repository
+    select r.repository_id, r.user_id, o.name
+    into v_repository_id, v_owner_user_id, v_owner_organization_name
+    from repository r
+    left join organization o using (organization_id)
+    where r.name = p_repository_name;
+    if not found then
+        raise exception 'repository not found';
+    end if;
+
+    -- Check if the user doing the request is the owner or belongs to the
+    -- organization which owns it
+    if v_owner_organization_name is not null then
+        if not user_belongs_to_organization(p_user_id, v_owner_organization_name) then
+            raise insufficient_privilege;
+        end if;
+    elsif v_owner_user_id <> p_user_id then
+        raise insufficient_privilege;
+    end if;
+
+    -- Register tracking job (or reschedule the pending one)
+    insert into tracking_job (repository_id)
+    values (v_repository_id)
+    on conflict (repository_id) where status = 'pending'
+    do update set scheduled_at = least(tracking_job.scheduled_at, current_timestamp)
+    returning tracking_job_id into v_tracking_job_id;
+
+    return v_tracking_job_id;
+end
+$$ language plpgsql;
@@ -0,0 +1,11 @@
+-- enqueue_tracking_jobs registers a tracking job for each of the repositories
+-- provided to be run as soon as possible. Repositories that already have a
+-- pending job will have it rescheduled to run now instead. Running jobs are
+-- never reused (see enqueue_tracking_job for more details).
+create or replace function enqueue_tracking_jobs(p_repositories_ids uuid[])
+returns void as $$
+    insert into tracking_job (repository_id)
+    select unnest(p_repositories_ids)
+    on conflict (repository_id) where status = 'pending'
+    do update set scheduled_at = least(tracking_job.scheduled_at, current_timestamp);
+$$ language sql;
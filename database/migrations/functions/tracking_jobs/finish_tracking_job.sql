@@ -0,0 +1,37 @@
+-- finish_tracking_job updates the status of the tracking job provided once it
+-- has been run, keeping track of the repository's consecutive failures. The
+-- job must still be leased with the token provided (its lease may have expired
+-- and the job may have been leased again by another tracker).
+create or replace function finish_tracking_job(p_job jsonb)
+returns void as $$
+declare
+    v_tracking_job_id uuid := (p_job->>'tracking_job_id')::uuid;
+    v_status text := p_job->>'status';
+    v_repository_id uuid;
+begin
+    update tracking_job set
+        status = v_status,
+        finished_at = current_timestamp,
+        lease_expires_at = null,
+        lease_token = null,
+        errors = nullif(p_job->>'errors', ''),
+        packages_added = (p_job->>'packages_added')::int,
+        packages_removed = (p_job->>'packages_removed')::int
+    where tracking_job_id = v_tracking_job_id
+    and status = 'running'
+    and lease_token = (p_job->>'lease_token')::uuid
+    returning repository_id into v_repository_id;
+    if not found then
+        raise exception 'tracking job not leased with the token provided';
+    end if;
+
+    update repository set
+        tracking_failures = case when v_status = 'failed' then tracking_failures + 1 else 0 end
+    where repository_id = v_repository_id;
+
+    -- Jobs history is kept for a week
+    delete from tracking_job
+    where repository_id = v_repository_id
+    and finished_at < current_timestamp - '7 days'::interval;
+end
+$$ language plpgsql;
@@ -0,0 +1,46 @@
+-- get_tracking_job returns the requested tracking job of the provided
+-- repository as a json object.
+create or replace function get_tracking_job(
+    p_user_id uuid,
+    p_repository_name text,
+    p_tracking_job_id uuid
+) returns setof json as $$
+declare
+    v_owner_user_id uuid;
+    v_owner_organization_name text;
+begin
+    -- Get user or organization owning the repository
+    select r.user_id, o.name into v_owner_user_id, v_owner_organization_name
+    from repository r
+    left join organization o using (organization_id)
+    where r.name = p_repository_name;
+
+    -- Check if the user doing the request is the owner or belongs to the
+    -- organization which owns it
+    if v_owner_organization_name is not null then
+        if not user_belongs_to_organization(p_user_id, v_owner_organization_name) then
+            raise insufficient_privilege;
+        end if;
+    elsif v_owner_user_id <> p_user_id then
+        raise insufficient_privilege;
+    end if;
+
+    return query
+    select json_strip_nulls(json_build_object(
+        'tracking_job_id', tj.tracking_job_id,
+        'repository_id', tj.repository_id,
+        'status', tj.status,
+        'created_at', floor(extract(epoch from tj.created_at)),
+        'scheduled_at', floor(extract(epoch from tj.scheduled_at)),
+        'started_at', floor(extract(epoch from tj.started_at)),
+        'finished_at', floor(extract(epoch from tj.finished_at)),
+        'errors', tj.errors,
+        'packages_added', tj.packages_added,
+        'packages_removed', tj.packages_removed
+    ))
+    from tracking_job tj
+    join repository r using (repository_id)
+    where r.name = p_repository_name
+    and tj.tracking_job_id = p_tracking_job_id;
+end
+$$ language plpgsql;
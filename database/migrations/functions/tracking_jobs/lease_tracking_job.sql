@@ -0,0 +1,60 @@
+-- lease_tracking_job returns the next tracking job ready to be run if
+-- available, marking it as running for the lease duration provided. Jobs
+-- locked by other transactions are skipped, so several trackers can lease
+-- jobs concurrently. Running jobs whose lease has expired (i.e. the tracker
+-- processing them stopped unexpectedly) can be leased again. Jobs of
+-- repositories that have another job running are not leased until it
+-- finishes. To make sure two jobs of the same repository are never leased at
+-- the same time, leasing is serialized per repository using an advisory lock.
+-- Each lease gets a new token, which must be provided to renew the lease or
+-- to finish the job.
+create or replace function lease_tracking_job(p_lease_duration interval)
+returns setof json as $$
+declare
+    v_job record;
+begin
+    for v_job in
+        select tracking_job_id, repository_id
+        from tracking_job
+        where (
+            (status = 'pending' and scheduled_at <= current_timestamp)
+            or (status = 'running' and lease_expires_at < current_timestamp)
+        )
+        order by scheduled_at asc
+        for update skip locked
+    loop
+        -- Skip job if another tracker is leasing a job of the same repository
+        if not pg_try_advisory_xact_lock(hashtext(v_job.repository_id::text)) then
+            continue;
+        end if;
+
+        -- Skip job if the repository has another job running
+        if exists (
+            select 1
+            from tracking_job rj
+            where rj.repository_id = v_job.repository_id
+            and rj.status = 'running'
+            and rj.lease_expires_at >= current_timestamp
+        ) then
+            continue;
+        end if;
+
+        return query
+        update tracking_job tj set
+            status = 'running',
+            started_at = current_timestamp,
+            lease_expires_at = current_timestamp + p_lease_duration,
+            lease_token = gen_random_uuid()
+        where tj.tracking_job_id = v_job.tracking_job_id
+        returning json_build_object(
+            'tracking_job_id', tj.tracking_job_id,
+            'repository_id', tj.repository_id,
+            'status', tj.status,
+            'scheduled_at', floor(extract(epoch from tj.scheduled_at)),
+            'started_at', floor(extract(epoch from tj.started_at)),
+            'lease_token', tj.lease_token
+        );
+        return;
+    end loop;
+end
+$$ language plpgsql;
@@ -0,0 +1,19 @@
+-- renew_tracking_job_lease extends the lease of the running tracking job
+-- provided for the duration given. The job must still be leased with the
+-- token provided.
+create or replace function renew_tracking_job_lease(
+    p_tracking_job_id uuid,
+    p_lease_token uuid,
+    p_lease_duration interval
+) returns void as $$
+begin
+    update tracking_job set
+        lease_expires_at = current_timestamp + p_lease_duration
+    where tracking_job_id = p_tracking_job_id
+    and status = 'running'
+    and lease_token = p_lease_token;
+    if not found then
+        raise exception 'tracking job not leased with the token provided';
+    end if;
+end
+$$ language plpgsql;
@@ -0,0 +1,32 @@
+-- schedule_tracking_jobs registers a pending tracking job for each of the
+-- enabled repositories that do not have one already. Jobs are scheduled to be
+-- run once the repository's tracking interval (or the default one provided)
+-- has elapsed since the last job finished. When the tracking of a repository
+-- keeps failing, the interval is increased exponentially up to the maximum
+-- backoff provided.
+create or replace function schedule_tracking_jobs(p_default_interval interval, p_max_backoff interval)
+returns void as $$
+    insert into tracking_job (repository_id, scheduled_at)
+    select
+        r.repository_id,
+        coalesce(
+            (
+                select max(tj.finished_at)
+                from tracking_job tj
+                where tj.repository_id = r.repository_id
+            ) + least(
+                coalesce(r.tracking_interval, p_default_interval) * power(2, least(r.tracking_failures, 16)),
+                greatest(coalesce(r.tracking_interval, p_default_interval), p_max_backoff)
+            ),
+            current_timestamp
+        )
+    from repository r
+    where r.disabled = false
+    and not exists (
+        select 1
+        from tracking_job tj
+        where tj.repository_id = r.repository_id
+        and tj.status in ('pending', 'running')
+    )
+    on conflict do nothing;
+$$ language sql;
@@ -0,0 +1,39 @@
+-- approve_session approves the provided session, which must be pending
+-- approval. When a recovery code (hash) is provided, it is removed from the
+-- user's recovery codes so that it cannot be used again. Otherwise, the
+-- passcode time step provided is recorded as the last one used by the user,
+-- so that passcodes cannot be replayed. Nothing is approved if the recovery
+-- code or the passcode time step have already been used. Once approved, the
+-- failed two-factor authentication attempts of the user are reset.
+create or replace function approve_session(p_session_id bytea, p_tfa_step bigint, p_recovery_code text)
+returns void as $$
+declare
+    v_user_id uuid;
+begin
+    update session set approved = true
+    where session_id = p_session_id
+    and approved = false
+    returning user_id into v_user_id;
+    if not found then
+        raise no_data_found;
+    end if;
+
+    if p_recovery_code is not null then
+        update "user" set tfa_recovery_codes = array_remove(tfa_recovery_codes, p_recovery_code)
+        where user_id = v_user_id
+        and p_recovery_code = any(tfa_recovery_codes);
+    else
+        update "user" set tfa_last_used_step = p_tfa_step
+        where user_id = v_user_id
+        and (tfa_last_used_step is null or tfa_last_used_step < p_tfa_step);
+    end if;
+    if not found then
+        raise invalid_password;
+    end if;
+
+    update "user" set
+        tfa_failed_attempts = 0,
+        tfa_locked_until = null
+    where user_id = v_user_id;
+end
+$$ language plpgsql;
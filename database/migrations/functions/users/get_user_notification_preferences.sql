@@ -0,0 +1,28 @@
+-- get_user_notification_preferences returns the notification preferences of
+-- the provided user as a json object. The preferences of all event kinds are
+-- returned, using the default ones for those the user has not set up yet.
+-- Packages notifications are delivered in a digest by default to users who
+-- selected a digest delivery preference.
+create or replace function get_user_notification_preferences(p_user_id uuid)
+returns setof json as $$
+    select json_strip_nulls(json_build_object(
+        'event_kinds', (
+            select json_agg(json_build_object(
+                'event_kind', ek.event_kind_id,
+                'email', coalesce(unp.email, not d.digest),
+                'digest', coalesce(unp.digest, d.digest),
+                'webhook', coalesce(unp.webhook, true)
+            ) order by ek.event_kind_id asc)
+            from event_kind ek
+            cross join lateral (
+                select (u.delivery_preference_id in (1, 2) and ek.event_kind_id in (0, 1)) as digest
+            ) d
+            left join user_notification_preference unp
+                on unp.user_id = u.user_id
+                and unp.event_kind_id = ek.event_kind_id
+        ),
+        'quiet_hours', u.quiet_hours
+    ))
+    from "user" u
+    where u.user_id = p_user_id;
+$$ language sql;
@@ -0,0 +1,23 @@
+-- get_user_sessions returns the active sessions of the provided user as a json
+-- array. Sessions are identified by the sha256 hash of their id, so that the
+-- actual ids are never exposed.
+create or replace function get_user_sessions(
+    p_user_id uuid,
+    p_current_session_id bytea,
+    p_idle_timeout integer,
+    p_absolute_timeout integer
+) returns setof json as $$
+    select coalesce(json_agg(json_build_object(
+        'session_id', encode(digest(session_id, 'sha256'), 'hex'),
+        'ip', host(ip),
+        'user_agent', user_agent,
+        'created_at', floor(extract(epoch from created_at)),
+        'last_seen_at', floor(extract(epoch from last_seen_at)),
+        'current', coalesce(session_id = p_current_session_id, false)
+    ) order by last_seen_at desc), '[]')
+    from session
+    where user_id = p_user_id
+    and approved = true
+    and last_seen_at > current_timestamp - make_interval(secs => p_idle_timeout)
+    and created_at > current_timestamp - make_interval(secs => p_absolute_timeout);
+$$ language sql;
@@ -0,0 +1,14 @@
+-- get_user_tfa_config returns the two-factor authentication configuration of
+-- the provided user as a json object. Users locked out after too many failed
+-- attempts are flagged as such.
+create or replace function get_user_tfa_config(p_user_id uuid)
+returns setof json as $$
+    select json_strip_nulls(json_build_object(
+        'enabled', u.tfa_enabled,
+        'secret', u.tfa_secret,
+        'recovery_codes', u.tfa_recovery_codes,
+        'locked', nullif(u.tfa_locked_until > current_timestamp, false)
+    ))
+    from "user" u
+    where u.user_id = p_user_id;
+$$ language sql;
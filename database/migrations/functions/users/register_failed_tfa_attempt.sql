@@ -0,0 +1,19 @@
+-- register_failed_tfa_attempt registers a failed attempt to approve the
+-- provided session for the user who owns it. Failed attempts are counted per
+-- user (across all their sessions), and once the maximum number of attempts
+-- provided is reached the user is locked out for the duration given (in
+-- seconds). Each subsequent failed attempt extends the lockout.
+create or replace function register_failed_tfa_attempt(
+    p_session_id bytea,
+    p_max_attempts int,
+    p_lockout_duration int
+) returns void as $$
+    update "user" set
+        tfa_failed_attempts = tfa_failed_attempts + 1,
+        tfa_locked_until = case
+            when tfa_failed_attempts + 1 >= p_max_attempts
+                then current_timestamp + make_interval(secs => p_lockout_duration)
+            else tfa_locked_until
+        end
+    where user_id = (select user_id from session where session_id = p_session_id);
+$$ language sql;
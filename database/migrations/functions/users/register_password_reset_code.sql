@@ -0,0 +1,17 @@
+-- register_password_reset_code registers a password reset code for the user
+-- with the provided email, returning the code. Only users with a verified email
+-- and a password (database-backed accounts) can reset their password. When a
+-- code was already registered for the user, it is replaced by a new one.
+create or replace function register_password_reset_code(p_email text)
+returns uuid as $$
+    insert into password_reset_code (user_id)
+    select user_id from "user"
+    where email = p_email
+    and email_verified = true
+    and password is not null
+    on conflict (user_id) do update
+    set
+        password_reset_code_id = gen_random_uuid(),
+        created_at = current_timestamp
+    returning password_reset_code_id;
+$$ language sql;
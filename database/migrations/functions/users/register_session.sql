@@ -1,13 +1,20 @@
--- register_session registers the provided session in the database.
+-- register_session registers the provided session in the database, returning
+-- its id. Sessions of users who have enabled two-factor authentication need to
+-- be approved before they can be used.
 create or replace function register_session(p_session jsonb)
-returns bytea as $$
+returns table (session_id bytea, approved boolean) as $$
     insert into session (
         user_id,
         ip,
-        user_agent
+        user_agent,
+        approved
     ) values (
         (p_session->>'user_id')::uuid,
         nullif(p_session->>'ip', '')::inet,
-        nullif(p_session->>'user_agent', '')
-    ) returning session_id;
+        nullif(p_session->>'user_agent', ''),
+        (
+            select not tfa_enabled from "user"
+            where user_id = (p_session->>'user_id')::uuid
+        )
+    ) returning session_id, approved;
 $$ language sql;
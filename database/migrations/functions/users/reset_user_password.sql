@@ -0,0 +1,30 @@
+-- reset_user_password updates the password of the user the provided password
+-- reset code belongs to, returning true if the password was reset successfully
+-- or false otherwise. Codes are single-use and expire after one hour. All the
+-- user's sessions are deleted once the password has been reset.
+create or replace function reset_user_password(p_code uuid, p_new_password text)
+returns boolean as $$
+declare
+    v_user_id uuid;
+    v_created_at timestamptz;
+begin
+    -- Delete password reset code (even if it has expired)
+    delete from password_reset_code
+    where password_reset_code_id = p_code
+    returning user_id, created_at into v_user_id, v_created_at;
+
+    -- Check if password reset code existed and was not expired
+    if v_user_id is null or v_created_at + '1 hour'::interval < current_timestamp then
+        return false;
+    end if;
+
+    -- Update user password
+    update "user" set password = p_new_password
+    where user_id = v_user_id;
+
+    -- Invalidate all user's sessions
+    delete from session where user_id = v_user_id;
+
+    return true;
+end
+$$ language plpgsql;
@@ -0,0 +1,33 @@
+-- update_user_notification_preferences updates the notification preferences
+-- of the requesting user in the database.
+create or replace function update_user_notification_preferences(
+    p_requesting_user_id uuid,
+    p_preferences jsonb
+) returns void as $$
+begin
+    -- Event kinds preferences
+    insert into user_notification_preference (
+        user_id,
+        event_kind_id,
+        email,
+        digest,
+        webhook
+    )
+    select
+        p_requesting_user_id,
+        (ekp->>'event_kind')::int,
+        coalesce((ekp->>'email')::boolean, false),
+        coalesce((ekp->>'digest')::boolean, false),
+        coalesce((ekp->>'webhook')::boolean, false)
+    from jsonb_array_elements(nullif(p_preferences->'event_kinds', 'null'::jsonb)) ekp
+    on conflict (user_id, event_kind_id) do update set
+        email = excluded.email,
+        digest = excluded.digest,
+        webhook = excluded.webhook;
+
+    -- Quiet hours
+    update "user" set
+        quiet_hours = nullif(p_preferences->'quiet_hours', 'null'::jsonb)
+    where user_id = p_requesting_user_id;
+end
+$$ language plpgsql;
@@ -10,9 +10,10 @@ declare
     v_webhook_id uuid;
     v_event_kind integer;
     v_package jsonb;
+    v_repository jsonb;
 begin
     if p_org_name <> '' then
-        if not user_belongs_to_organization(p_user_id, p_org_name) then
+        if not regular_user_belongs_to_organization(p_user_id, p_org_name) then
             raise insufficient_privilege;
         end if;
         v_owner_organization_id = (select organization_id from organization where name = p_org_name);
@@ -28,6 +29,9 @@ begin
         secret,
         content_type,
         template,
+        legacy_secret_header,
+        webhook_kind_id,
+        filters,
         active,
         user_id,
         organization_id
@@ -38,6 +42,9 @@ begin
         nullif(p_webhook->>'secret', ''),
         nullif(p_webhook->>'content_type', ''),
         nullif(p_webhook->>'template', ''),
+        coalesce((p_webhook->>'legacy_secret_header')::boolean, false),
+        coalesce((p_webhook->>'kind')::int, 0),
+        nullif(p_webhook->'filters', 'null'::jsonb),
         (p_webhook->>'active')::boolean,
         v_owner_user_id,
         v_owner_organization_id
@@ -57,5 +64,20 @@ begin
         insert into webhook__package (webhook_id, package_id)
         values (v_webhook_id, (v_package->>'package_id')::uuid);
     end loop;
+
+    -- Repositories this webhook is interested in (they must belong to the
+    -- user or organization owning the webhook)
+    for v_repository in select * from jsonb_array_elements(nullif(p_webhook->'repositories', 'null'::jsonb))
+    loop
+        if not exists (
+            select 1 from repository
+            where repository_id = (v_repository->>'repository_id')::uuid
+            and (user_id = v_owner_user_id or organization_id = v_owner_organization_id)
+        ) then
+            raise insufficient_privilege;
+        end if;
+        insert into webhook__repository (webhook_id, repository_id)
+        values (v_webhook_id, (v_repository->>'repository_id')::uuid);
+    end loop;
 end
 $$ language plpgsql;
@@ -1,5 +1,5 @@
 -- get_org_webhooks returns the webhooks that belong to the organization
--- provided if the requesting user belongs to it.
+-- provided if the requesting user belongs to it (service accounts excluded).
 create or replace function get_org_webhooks(p_user_id uuid, p_org_name text)
 returns setof json as $$
     select coalesce(json_agg(whJSON), '[]')
@@ -9,9 +9,11 @@ returns setof json as $$
         cross join get_webhook(null::uuid, webhook_id) as whJSON
         join organization o using (organization_id)
         join user__organization uo using (organization_id)
+        join "user" u on u.user_id = uo.user_id
         where o.name = p_org_name
         and uo.user_id = p_user_id
         and uo.confirmed = true
+        and u.service_account = false
         order by wh.name asc
     ) whs;
 $$ language sql;
@@ -1,5 +1,5 @@
 -- get_webhook returns the webhook identified by the id provided as a json
--- object.
+-- object. The webhook secret is never returned, only whether it is set or not.
 create or replace function get_webhook(p_user_id uuid, p_webhook_id uuid)
 returns setof json as $$
 begin
@@ -12,9 +12,12 @@ begin
         'name', wh.name,
         'description', wh.description,
         'url', wh.url,
-        'secret', wh.secret,
+        'secret_set', wh.secret is not null,
         'content_type', wh.content_type,
         'template', wh.template,
+        'legacy_secret_header', wh.legacy_secret_header,
+        'kind', wh.webhook_kind_id,
+        'filters', wh.filters,
         'active', wh.active,
         'event_kinds', (
             select json_agg(event_kind_id)
@@ -32,6 +35,17 @@ begin
             ) wp
             cross join get_package_summary(wp.package_id) as pkgJSON
         ),
+        'repositories', (
+            select json_agg(repoJSON)
+            from (
+                select repository_id
+                from repository r
+                join webhook__repository wr using (repository_id)
+                where wr.webhook_id = wh.webhook_id
+                order by r.name asc
+            ) wr
+            cross join get_repository_summary(wr.repository_id) as repoJSON
+        ),
         'last_notifications', (
             select json_agg(json_build_object(
                 'notification_id', notification_id,
@@ -0,0 +1,28 @@
+-- get_webhook_deliveries returns the last deliveries attempts of the provided
+-- webhook as a json array.
+create or replace function get_webhook_deliveries(p_user_id uuid, p_webhook_id uuid)
+returns setof json as $$
+begin
+    if not user_has_access_to_webhook(p_user_id, p_webhook_id) then
+        raise insufficient_privilege;
+    end if;
+
+    return query select coalesce(json_agg(json_strip_nulls(json_build_object(
+        'webhook_delivery_id', webhook_delivery_id,
+        'notification_id', notification_id,
+        'created_at', floor(extract(epoch from created_at)),
+        'attempt', attempt,
+        'status_code', status_code,
+        'latency_ms', latency_ms,
+        'response', response,
+        'error', error
+    ))), '[]')
+    from (
+        select *
+        from webhook_delivery
+        where webhook_id = p_webhook_id
+        order by created_at desc
+        limit 50
+    ) wd;
+end
+$$ language plpgsql;
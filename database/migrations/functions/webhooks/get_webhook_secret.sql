@@ -0,0 +1,18 @@
+-- get_webhook_secret returns the secret of the webhook identified by the id
+-- provided, provided the user has access to it.
+create or replace function get_webhook_secret(p_user_id uuid, p_webhook_id uuid)
+returns text as $$
+declare
+    v_secret text;
+begin
+    if not user_has_access_to_webhook(p_user_id, p_webhook_id) then
+        raise insufficient_privilege;
+    end if;
+
+    select secret into v_secret
+    from webhook
+    where webhook_id = p_webhook_id;
+
+    return v_secret;
+end
+$$ language plpgsql;
@@ -1,8 +1,15 @@
 -- get_webhooks_subscribed_to_package returns the webhooks subscribed to the
--- event kind and package provided.
+-- event kind and package provided. The user owning each webhook (if any) is
+-- included as well, so that its notification preferences can be applied, as
+-- well as the webhook secret.
 create or replace function get_webhooks_subscribed_to_package(p_event_kind_id integer, p_package_id uuid)
 returns setof json as $$
-    select coalesce(json_agg(wh), '[]')
+    select coalesce(json_agg(json_strip_nulls((
+        wh::jsonb || jsonb_build_object(
+            'user_id', webhook.user_id,
+            'secret', webhook.secret
+        )
+    )::json)), '[]')
     from webhook
     join webhook__event_kind wek using (webhook_id)
     join webhook__package wp using (webhook_id)
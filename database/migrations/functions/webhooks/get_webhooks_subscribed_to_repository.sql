@@ -0,0 +1,28 @@
+-- get_webhooks_subscribed_to_repository returns the webhooks subscribed to the
+-- event kind and repository provided. Webhooks are only notified about the
+-- tracking errors of repositories that still belong to their owner, whereas
+-- ownership claims are notified to the webhooks of the previous owner. The
+-- user owning each webhook (if any) is included as well, so that its
+-- notification preferences can be applied, as well as the webhook secret.
+create or replace function get_webhooks_subscribed_to_repository(p_event_kind_id integer, p_repository_id uuid)
+returns setof json as $$
+    select coalesce(json_agg(json_strip_nulls((
+        wh::jsonb || jsonb_build_object(
+            'user_id', w.user_id,
+            'secret', w.secret
+        )
+    )::json)), '[]')
+    from webhook w
+    join webhook__event_kind wek using (webhook_id)
+    join webhook__repository wr using (webhook_id)
+    join repository r using (repository_id)
+    cross join get_webhook(null::uuid, w.webhook_id) as wh
+    where wek.event_kind_id = p_event_kind_id
+    and wr.repository_id = p_repository_id
+    and w.active = true
+    and (
+        p_event_kind_id = 3
+        or w.user_id = r.user_id
+        or w.organization_id = r.organization_id
+    );
+$$ language sql;
@@ -0,0 +1,27 @@
+-- redeliver_webhook_notification schedules the notification of the provided
+-- webhook delivery to be delivered again.
+create or replace function redeliver_webhook_notification(
+    p_user_id uuid,
+    p_webhook_id uuid,
+    p_webhook_delivery_id uuid
+) returns void as $$
+begin
+    if not user_has_access_to_webhook(p_user_id, p_webhook_id) then
+        raise insufficient_privilege;
+    end if;
+
+    update notification n set
+        processed = false,
+        processed_at = null,
+        attempts = 0,
+        next_attempt_at = null,
+        error = null
+    from webhook_delivery wd
+    where wd.notification_id = n.notification_id
+    and wd.webhook_delivery_id = p_webhook_delivery_id
+    and wd.webhook_id = p_webhook_id;
+    if not found then
+        raise no_data_found;
+    end if;
+end
+$$ language plpgsql;
@@ -1,25 +1,39 @@
--- update_webhook updates the provided webhook in the database.
+-- update_webhook updates the provided webhook in the database. The current
+-- secret is kept when no new one is provided, unless it is explicitly cleared.
 create or replace function update_webhook(p_user_id uuid, p_webhook jsonb)
 returns void as $$
 declare
     v_webhook_id uuid := (p_webhook->>'webhook_id')::uuid;
     v_owner_user_id uuid;
     v_owner_organization_name text;
+    v_owner_organization_id uuid;
     v_event_kind integer;
     v_package jsonb;
+    v_repository jsonb;
 begin
     if not user_has_access_to_webhook(p_user_id, v_webhook_id) then
         raise insufficient_privilege;
     end if;
 
+    -- Get user or organization owning the webhook
+    select user_id, organization_id into v_owner_user_id, v_owner_organization_id
+    from webhook
+    where webhook_id = v_webhook_id;
+
     -- Webhook
     update webhook set
         name = p_webhook->>'name',
         description = nullif(p_webhook->>'description', ''),
         url = p_webhook->>'url',
-        secret = nullif(p_webhook->>'secret', ''),
+        secret = case
+            when (p_webhook->>'clear_secret')::boolean = true then null
+            else coalesce(nullif(p_webhook->>'secret', ''), secret)
+        end,
         content_type = nullif(p_webhook->>'content_type', ''),
         template = nullif(p_webhook->>'template', ''),
+        legacy_secret_header = coalesce((p_webhook->>'legacy_secret_header')::boolean, false),
+        webhook_kind_id = coalesce((p_webhook->>'kind')::int, 0),
+        filters = nullif(p_webhook->'filters', 'null'::jsonb),
         active = (p_webhook->>'active')::boolean
     where webhook_id = v_webhook_id;
 
@@ -54,5 +68,29 @@ begin
         select (value->>'package_id')::uuid
         from jsonb_array_elements(nullif(p_webhook->'packages', 'null'::jsonb))
     );
+
+    -- Bind webhook with repositories if needed (they must belong to the user
+    -- or organization owning the webhook)
+    for v_repository in select * from jsonb_array_elements(nullif(p_webhook->'repositories', 'null'::jsonb))
+    loop
+        if not exists (
+            select 1 from repository
+            where repository_id = (v_repository->>'repository_id')::uuid
+            and (user_id = v_owner_user_id or organization_id = v_owner_organization_id)
+        ) then
+            raise insufficient_privilege;
+        end if;
+        insert into webhook__repository (webhook_id, repository_id)
+        values (v_webhook_id, (v_repository->>'repository_id')::uuid)
+        on conflict do nothing;
+    end loop;
+
+    -- Unbind deleted repositories from webhook
+    delete from webhook__repository
+    where webhook_id = v_webhook_id
+    and repository_id not in (
+        select (value->>'repository_id')::uuid
+        from jsonb_array_elements(nullif(p_webhook->'repositories', 'null'::jsonb))
+    );
 end
 $$ language plpgsql;
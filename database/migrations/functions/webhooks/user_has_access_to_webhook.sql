@@ -1,5 +1,6 @@
 -- user_has_access_to_webhook checks if a user is the owner of the given webhook
--- or belongs to the organization who owns it.
+-- or belongs to the organization who owns it. Service accounts do not have
+-- access to the webhooks of the organization they belong to.
 create or replace function user_has_access_to_webhook(p_user_id uuid, p_webhook_id uuid)
 returns boolean as $$
 declare
@@ -15,7 +16,7 @@ begin
     -- Check if the user doing the request is the owner or belongs to the
     -- organization which owns it
     if v_owner_organization_name is not null then
-        if not user_belongs_to_organization(p_user_id, v_owner_organization_name) then
+        if not regular_user_belongs_to_organization(p_user_id, v_owner_organization_name) then
             return false;
         end if;
     elsif v_owner_user_id <> p_user_id then
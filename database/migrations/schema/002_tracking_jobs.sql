@@ -0,0 +1,25 @@
+alter table repository add column tracking_interval interval check (tracking_interval > '0'::interval);
+alter table repository add column tracking_failures integer not null default 0;
+
+create table if not exists tracking_job (
+    tracking_job_id uuid primary key default gen_random_uuid(),
+    repository_id uuid not null references repository on delete cascade,
+    status text not null default 'pending' check (status in ('pending', 'running', 'succeeded', 'failed')),
+    created_at timestamptz default current_timestamp not null,
+    scheduled_at timestamptz default current_timestamp not null,
+    started_at timestamptz,
+    finished_at timestamptz,
+    lease_expires_at timestamptz,
+    lease_token uuid,
+    errors text
+);
+
+create index tracking_job_repository_id_finished_at_idx on tracking_job (repository_id, finished_at);
+create index tracking_job_scheduled_at_idx on tracking_job (scheduled_at) where status in ('pending', 'running');
+create unique index tracking_job_pending_repository_id_idx on tracking_job (repository_id) where status = 'pending';
+
+---- create above / drop below ----
+
+drop table if exists tracking_job;
+alter table repository drop column tracking_failures;
+alter table repository drop column tracking_interval;
@@ -0,0 +1,7 @@
+alter table tracking_job add column packages_added integer;
+alter table tracking_job add column packages_removed integer;
+
+---- create above / drop below ----
+
+alter table tracking_job drop column packages_removed;
+alter table tracking_job drop column packages_added;
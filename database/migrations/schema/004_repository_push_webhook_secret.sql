@@ -0,0 +1,5 @@
+alter table repository add column push_webhook_secret text check (push_webhook_secret <> '');
+
+---- create above / drop below ----
+
+alter table repository drop column push_webhook_secret;
@@ -0,0 +1,8 @@
+alter table webhook add column legacy_secret_header boolean not null default false;
+
+-- Existing webhooks with a secret keep receiving it in the legacy header
+update webhook set legacy_secret_header = true where secret is not null;
+
+---- create above / drop below ----
+
+alter table webhook drop column legacy_secret_header;
@@ -0,0 +1,23 @@
+alter table notification add column attempts integer not null default 0;
+alter table notification add column next_attempt_at timestamptz;
+
+create table if not exists webhook_delivery (
+    webhook_delivery_id uuid primary key default gen_random_uuid(),
+    created_at timestamptz default current_timestamp not null,
+    attempt integer not null,
+    status_code integer,
+    latency_ms integer not null,
+    response text check (response <> ''),
+    error text check (error <> ''),
+    notification_id uuid not null references notification on delete cascade,
+    webhook_id uuid not null references webhook on delete cascade
+);
+
+create index webhook_delivery_webhook_id_created_at_idx on webhook_delivery (webhook_id, created_at);
+create index webhook_delivery_notification_id_idx on webhook_delivery (notification_id);
+
+---- create above / drop below ----
+
+drop table if exists webhook_delivery;
+alter table notification drop column next_attempt_at;
+alter table notification drop column attempts;
@@ -0,0 +1,18 @@
+create table if not exists delivery_preference (
+    delivery_preference_id integer primary key,
+    name text not null check (name <> '')
+);
+
+insert into delivery_preference values (0, 'Immediate');
+insert into delivery_preference values (1, 'Daily digest');
+insert into delivery_preference values (2, 'Weekly digest');
+
+alter table "user" add column delivery_preference_id integer not null default 0 references delivery_preference on delete restrict;
+
+create index notification_user_id_not_processed_idx on notification (user_id) where processed = 'false';
+
+---- create above / drop below ----
+
+drop index if exists notification_user_id_not_processed_idx;
+alter table "user" drop column delivery_preference_id;
+drop table if exists delivery_preference;
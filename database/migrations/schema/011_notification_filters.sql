@@ -0,0 +1,11 @@
+alter table subscription add column filters jsonb;
+alter table repository_subscription add column filters jsonb;
+alter table organization_subscription add column filters jsonb;
+alter table webhook add column filters jsonb;
+
+---- create above / drop below ----
+
+alter table webhook drop column filters;
+alter table organization_subscription drop column filters;
+alter table repository_subscription drop column filters;
+alter table subscription drop column filters;
@@ -0,0 +1,32 @@
+create or replace function notify_event_created()
+returns trigger as $$
+begin
+    perform pg_notify('event_created', '');
+    return null;
+end
+$$ language plpgsql;
+
+create trigger trigger_event_created
+after insert on event
+for each statement
+execute function notify_event_created();
+
+create or replace function notify_notification_created()
+returns trigger as $$
+begin
+    perform pg_notify('notification_created', '');
+    return null;
+end
+$$ language plpgsql;
+
+create trigger trigger_notification_created
+after insert on notification
+for each statement
+execute function notify_notification_created();
+
+---- create above / drop below ----
+
+drop trigger trigger_notification_created on notification;
+drop function notify_notification_created;
+drop trigger trigger_event_created on event;
+drop function notify_event_created;
@@ -0,0 +1,5 @@
+create index event_created_at_event_id_idx on event (created_at, event_id);
+
+---- create above / drop below ----
+
+drop index event_created_at_event_id_idx;
@@ -0,0 +1,11 @@
+create table if not exists webhook__repository (
+    webhook_id uuid not null references webhook on delete cascade,
+    repository_id uuid not null references repository on delete cascade,
+    primary key (webhook_id, repository_id)
+);
+
+create index webhook__repository_repository_id_idx on webhook__repository (repository_id);
+
+---- create above / drop below ----
+
+drop table if exists webhook__repository;
@@ -0,0 +1,28 @@
+create table if not exists user_notification_preference (
+    user_id uuid not null references "user" on delete cascade,
+    event_kind_id integer not null references event_kind on delete restrict,
+    email boolean not null default true,
+    digest boolean not null default false,
+    webhook boolean not null default true,
+    primary key (user_id, event_kind_id)
+);
+
+alter table "user" add column quiet_hours jsonb;
+
+alter table notification add column digest boolean not null default false;
+
+-- Packages notifications of users who prefer a digest, which are pending to be
+-- delivered, are now flagged as digest notifications
+update notification n set digest = true
+from event e, "user" u
+where n.event_id = e.event_id
+and n.user_id = u.user_id
+and n.processed = false
+and e.event_kind_id in (0, 1)
+and u.delivery_preference_id in (1, 2);
+
+---- create above / drop below ----
+
+alter table notification drop column digest;
+alter table "user" drop column quiet_hours;
+drop table if exists user_notification_preference;
@@ -0,0 +1,15 @@
+alter table "user" add column tfa_enabled boolean not null default false;
+alter table "user" add column tfa_secret text check (tfa_secret <> '');
+alter table "user" add column tfa_recovery_codes text[];
+
+alter table session add column approved boolean not null default true;
+
+alter table organization add column tfa_required boolean not null default false;
+
+---- create above / drop below ----
+
+alter table organization drop column tfa_required;
+alter table session drop column approved;
+alter table "user" drop column tfa_recovery_codes;
+alter table "user" drop column tfa_secret;
+alter table "user" drop column tfa_enabled;
@@ -0,0 +1,9 @@
+alter table api_key add column scopes text[];
+alter table api_key add column expires_at timestamptz;
+alter table api_key add column last_used_at timestamptz;
+
+---- create above / drop below ----
+
+alter table api_key drop column last_used_at;
+alter table api_key drop column expires_at;
+alter table api_key drop column scopes;
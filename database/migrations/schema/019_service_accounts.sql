@@ -0,0 +1,11 @@
+alter table "user" add column service_account boolean not null default false;
+
+alter table api_key add column organization_id uuid references organization on delete cascade;
+create index api_key_organization_id_idx on api_key (organization_id);
+
+---- create above / drop below ----
+
+delete from "user" where service_account = true;
+drop index api_key_organization_id_idx;
+alter table api_key drop column organization_id;
+alter table "user" drop column service_account;
@@ -0,0 +1,7 @@
+alter table session add column last_seen_at timestamptz default current_timestamp not null;
+create index session_user_id_idx on session (user_id);
+
+---- create above / drop below ----
+
+drop index session_user_id_idx;
+alter table session drop column last_seen_at;
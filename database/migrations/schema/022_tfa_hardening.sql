@@ -0,0 +1,14 @@
+alter table "user" add column tfa_last_used_step bigint;
+alter table session add column tfa_attempts integer not null default 0;
+
+-- Recovery codes are stored hashed (bcrypt)
+update "user" set tfa_recovery_codes = (
+    select array_agg(crypt(code, gen_salt('bf', 10)))
+    from unnest(tfa_recovery_codes) as code
+)
+where tfa_recovery_codes is not null;
+
+---- create above / drop below ----
+
+alter table session drop column tfa_attempts;
+alter table "user" drop column tfa_last_used_step;
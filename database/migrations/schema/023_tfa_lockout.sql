@@ -0,0 +1,7 @@
+alter table "user" add column tfa_failed_attempts integer not null default 0;
+alter table "user" add column tfa_locked_until timestamptz;
+
+---- create above / drop below ----
+
+alter table "user" drop column tfa_locked_until;
+alter table "user" drop column tfa_failed_attempts;
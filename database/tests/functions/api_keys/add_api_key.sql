@@ -13,6 +13,8 @@ values (:'user1ID', 'user1', 'user1@email.com');
 select add_api_key('
 {
     "name": "apikey1",
+    "scopes": ["read-only", "manage-webhooks"],
+    "expires_at": 1590753300,
     "user_id": "00000000-0000-0000-0000-000000000001"
 }
 '::jsonb) as apikey \gset
@@ -27,12 +29,16 @@ select results_eq(
     $$
         select
             name,
+            scopes,
+            expires_at,
             user_id
         from api_key
     $$,
     $$
         values (
             'apikey1',
+            '{read-only,manage-webhooks}'::text[],
+            '2020-05-29 13:55:00+02'::timestamptz,
             '00000000-0000-0000-0000-000000000001'::uuid
         )
     $$,
@@ -0,0 +1,89 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user2ID', 'user2', 'user2@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+
+-- User not belonging to the organization tries to add an api key to it
+select throws_ok(
+    $$
+        select add_org_api_key(
+            '00000000-0000-0000-0000-000000000002',
+            'org1',
+            '{"name": "apikey1"}'::jsonb
+        )
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Api key should not be added as requesting user does not belong to the organization'
+);
+
+-- Add api key
+select add_org_api_key(
+    :'user1ID',
+    'org1',
+    '{
+        "name": "apikey1",
+        "scopes": ["manage-repositories"]
+    }'::jsonb
+) as apikey \gset
+
+-- Check if api key was added successfully
+select is(
+    octet_length(:'apikey'::bytea),
+    32,
+    'Key returned should have 32 bytes'
+);
+select results_eq(
+    $$
+        select
+            ak.name,
+            ak.scopes,
+            ak.organization_id,
+            u.service_account,
+            u.alias like 'org1-sa-%'
+        from api_key ak
+        join "user" u using (user_id)
+    $$,
+    $$
+        values (
+            'apikey1',
+            '{manage-repositories}'::text[],
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            true,
+            true
+        )
+    $$,
+    'Api key should exist and be bound to a service account'
+);
+select results_eq(
+    $$
+        select user_belongs_to_organization(ak.user_id, 'org1')
+        from api_key ak
+    $$,
+    $$
+        values (true)
+    $$,
+    'Service account should belong to the organization'
+);
+select is(
+    (select count(*) from "user" where service_account = true),
+    1::bigint,
+    'A single service account should have been created'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
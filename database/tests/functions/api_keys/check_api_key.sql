@@ -0,0 +1,75 @@
+-- Start transaction and plan tests
+begin;
+select plan(7);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set apikey1ID '00000000-0000-0000-0000-000000000001'
+\set apikey2ID '00000000-0000-0000-0000-000000000002'
+\set apikey3ID '00000000-0000-0000-0000-000000000003'
+\set apikey4ID '00000000-0000-0000-0000-000000000004'
+\set apikey5ID '00000000-0000-0000-0000-000000000005'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into api_key (api_key_id, name, key, user_id)
+values (:'apikey1ID', 'apikey1', 'key1', :'user1ID');
+insert into api_key (api_key_id, name, key, scopes, expires_at, user_id)
+values (:'apikey2ID', 'apikey2', 'key2', '{read-only}', current_timestamp + '1 day'::interval, :'user1ID');
+insert into api_key (api_key_id, name, key, expires_at, user_id)
+values (:'apikey3ID', 'apikey3', 'key3', current_timestamp - '1 day'::interval, :'user1ID');
+insert into api_key (api_key_id, name, key, last_used_at, user_id)
+values (:'apikey4ID', 'apikey4', 'key4', current_timestamp - '30 seconds'::interval, :'user1ID');
+insert into api_key (api_key_id, name, key, last_used_at, user_id)
+values (:'apikey5ID', 'apikey5', 'key5', current_timestamp - '2 minutes'::interval, :'user1ID');
+
+-- Run some tests
+select results_eq(
+    $$ select * from check_api_key('key1') $$,
+    $$ values ('00000000-0000-0000-0000-000000000001'::uuid, null::text[]) $$,
+    'Key1 should be valid and have no scopes'
+);
+select results_eq(
+    $$ select * from check_api_key('key2') $$,
+    $$ values ('00000000-0000-0000-0000-000000000001'::uuid, '{read-only}'::text[]) $$,
+    'Key2 should be valid and have the read-only scope'
+);
+select is_empty(
+    $$ select * from check_api_key('key3') $$,
+    'Key3 should not be valid as it has expired'
+);
+select is_empty(
+    $$ select * from check_api_key('key6') $$,
+    'Key6 should not be valid as it does not exist'
+);
+select results_eq(
+    $$
+        select api_key_id
+        from api_key
+        where last_used_at = current_timestamp
+        order by api_key_id asc
+    $$,
+    $$
+        values
+            ('00000000-0000-0000-0000-000000000001'::uuid),
+            ('00000000-0000-0000-0000-000000000002'::uuid)
+    $$,
+    'Last used time should have been registered for keys 1 and 2'
+);
+select check_api_key('key4');
+select check_api_key('key5');
+select results_eq(
+    $$ select last_used_at from api_key where api_key_id = '00000000-0000-0000-0000-000000000004' $$,
+    $$ values (current_timestamp - '30 seconds'::interval) $$,
+    'Last used time of key4 should not have been updated as it was registered less than a minute ago'
+);
+select results_eq(
+    $$ select last_used_at from api_key where api_key_id = '00000000-0000-0000-0000-000000000005' $$,
+    $$ values (current_timestamp) $$,
+    'Last used time of key5 should have been updated'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -21,7 +21,10 @@ select is(
     '{
         "api_key_id": "00000000-0000-0000-0000-000000000001",
         "name": "apikey1",
-        "created_at": 1590753300
+        "scopes": null,
+        "created_at": 1590753300,
+        "expires_at": null,
+        "last_used_at": null
     }'::jsonb,
     'Api key should exist'
 );
@@ -29,12 +29,18 @@ select is(
         {
             "api_key_id": "00000000-0000-0000-0000-000000000001",
             "name": "apikey1",
-            "created_at": 1590753300
+            "scopes": null,
+            "created_at": 1590753300,
+            "expires_at": null,
+            "last_used_at": null
         },
         {
             "api_key_id": "00000000-0000-0000-0000-000000000002",
             "name": "apikey2",
-            "created_at": 1590753300
+            "scopes": null,
+            "created_at": 1590753300,
+            "expires_at": null,
+            "last_used_at": null
         }
     ]'::jsonb,
     'Api keys 1 and 2 should be returned'
@@ -45,7 +51,10 @@ select is(
         {
             "api_key_id": "00000000-0000-0000-0000-000000000003",
             "name": "apikey3",
-            "created_at": 1590753300
+            "scopes": null,
+            "created_at": 1590753300,
+            "expires_at": null,
+            "last_used_at": null
         }
     ]'::jsonb,
     'Api key 3 should be returned'
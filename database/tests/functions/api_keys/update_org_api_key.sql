@@ -0,0 +1,60 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set sa1ID '00000000-0000-0000-0000-000000000003'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set apikey1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user2ID', 'user2', 'user2@email.com');
+insert into "user" (user_id, alias, email, service_account)
+values (:'sa1ID', 'org1-sa-1', 'org1-sa-1@email.com', true);
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into user__organization (user_id, organization_id, confirmed) values(:'sa1ID', :'org1ID', true);
+insert into api_key (api_key_id, name, user_id, organization_id)
+values (:'apikey1ID', 'apikey1', :'sa1ID', :'org1ID');
+
+-- Run some tests
+select throws_ok(
+    $$
+        select update_org_api_key(
+            '00000000-0000-0000-0000-000000000002',
+            'org1',
+            '{
+                "api_key_id": "00000000-0000-0000-0000-000000000001",
+                "name": "apikey1 updated"
+            }'::jsonb
+        )
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Api key should not be updated as requesting user does not belong to the organization'
+);
+select update_org_api_key(:'user1ID', 'org1', '
+{
+    "api_key_id": "00000000-0000-0000-0000-000000000001",
+    "name": "apikey1 updated"
+}
+'::jsonb);
+select results_eq(
+    $$
+        select name from api_key where api_key_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values ('apikey1 updated')
+    $$,
+    'Api key should have been updated'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
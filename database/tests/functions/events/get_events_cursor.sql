@@ -0,0 +1,47 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set event2ID '00000000-0000-0000-0000-000000000002'
+\set event3ID '00000000-0000-0000-0000-000000000003'
+
+-- No events registered yet
+select is(
+    get_events_cursor(null)::jsonb,
+    json_build_object('created_at', current_timestamp)::jsonb,
+    'Current position should be returned when there are no events'
+);
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into event (event_id, created_at, repository_id, event_kind_id)
+values (:'event1ID', '2020-06-16 11:20:34+02', :'repo1ID', 2);
+insert into event (event_id, created_at, repository_id, event_kind_id)
+values (:'event2ID', '2020-06-16 11:20:35+02', :'repo1ID', 2);
+
+-- Run some tests
+select is(
+    (get_events_cursor(:'event1ID')->>'created_at')::timestamptz,
+    '2020-06-16 11:20:34+02'::timestamptz,
+    'Creation time of the event provided should be returned'
+);
+select is(
+    get_events_cursor(:'event1ID')->>'event_id',
+    :'event1ID',
+    'Id of the event provided should be returned'
+);
+select is(
+    get_events_cursor(:'event3ID')->>'event_id',
+    :'event2ID',
+    'Position of the last event should be returned when the event does not exist'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
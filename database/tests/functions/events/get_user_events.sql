@@ -0,0 +1,223 @@
+-- Start transaction and plan tests
+begin;
+select plan(9);
+
+-- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set event2ID '00000000-0000-0000-0000-000000000002'
+\set event3ID '00000000-0000-0000-0000-000000000003'
+\set event4ID '00000000-0000-0000-0000-000000000004'
+\set event5ID '00000000-0000-0000-0000-000000000005'
+
+-- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into user__organization (user_id, organization_id, confirmed) values (:'user1ID', :'org1ID', true);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'package1', '1.0.0', :'repo1ID');
+insert into snapshot (package_id, version, prerelease)
+values (:'package1ID', '1.0.0', false);
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package2ID', 'package2', '2.0.0', :'repo2ID');
+insert into snapshot (package_id, version, prerelease)
+values (:'package2ID', '2.0.0', true);
+insert into subscription (user_id, package_id, event_kind_id)
+values (:'user1ID', :'package1ID', 0);
+insert into organization_subscription (user_id, organization_id, event_kind_id, filters)
+values (:'user1ID', :'org1ID', 0, '{"major_minor_only": true}');
+insert into opt_out (user_id, repository_id, event_kind_id)
+values (:'user1ID', :'repo2ID', 2);
+insert into event (event_id, created_at, package_id, package_version, event_kind_id)
+values (:'event1ID', '2020-06-16 11:20:31+02', :'package1ID', '1.0.0', 0);
+insert into event (event_id, created_at, package_id, package_version, event_kind_id)
+values (:'event2ID', '2020-06-16 11:20:32+02', :'package2ID', '2.0.0', 0);
+insert into event (event_id, created_at, repository_id, event_kind_id)
+values (:'event3ID', '2020-06-16 11:20:33+02', :'repo1ID', 2);
+insert into event (event_id, created_at, package_id, package_version, event_kind_id)
+values (:'event4ID', '2020-06-16 11:20:34+02', :'package1ID', '1.0.0', 1);
+insert into event (event_id, created_at, repository_id, event_kind_id)
+values (:'event5ID', '2020-06-16 11:20:35+02', :'repo2ID', 2);
+
+-- Run some tests
+select results_eq(
+    $$
+        select e->>'event_id'
+        from jsonb_array_elements((
+            select get_user_events(
+                '00000000-0000-0000-0000-000000000001',
+                '{"cursor": {"created_at": "2020-06-16T09:00:00Z"}}'
+            )::jsonb
+        )) e
+    $$,
+    $$
+        values
+            ('00000000-0000-0000-0000-000000000001'),
+            ('00000000-0000-0000-0000-000000000002'),
+            ('00000000-0000-0000-0000-000000000003')
+    $$,
+    'Events user1 is subscribed to or owns should be returned'
+);
+select results_eq(
+    $$
+        select e->>'event_id'
+        from jsonb_array_elements((
+            select get_user_events(
+                '00000000-0000-0000-0000-000000000001',
+                '{
+                    "cursor": {
+                        "created_at": "2020-06-16T09:20:31Z",
+                        "event_id": "00000000-0000-0000-0000-000000000001"
+                    }
+                }'
+            )::jsonb
+        )) e
+    $$,
+    $$
+        values
+            ('00000000-0000-0000-0000-000000000002'),
+            ('00000000-0000-0000-0000-000000000003')
+    $$,
+    'Only events after the cursor should be returned'
+);
+select results_eq(
+    $$
+        select e->>'event_id'
+        from jsonb_array_elements((
+            select get_user_events(
+                '00000000-0000-0000-0000-000000000001',
+                '{
+                    "cursor": {"created_at": "2020-06-16T09:00:00Z"},
+                    "package_id": "00000000-0000-0000-0000-000000000001"
+                }'
+            )::jsonb
+        )) e
+    $$,
+    $$
+        values ('00000000-0000-0000-0000-000000000001')
+    $$,
+    'Only events of package1 should be returned'
+);
+select results_eq(
+    $$
+        select e->>'event_id'
+        from jsonb_array_elements((
+            select get_user_events(
+                '00000000-0000-0000-0000-000000000001',
+                '{
+                    "cursor": {"created_at": "2020-06-16T09:00:00Z"},
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "event_kinds": [2]
+                }'
+            )::jsonb
+        )) e
+    $$,
+    $$
+        values ('00000000-0000-0000-0000-000000000003')
+    $$,
+    'Only tracking errors events of repo1 should be returned'
+);
+select results_eq(
+    $$
+        select e->>'event_id'
+        from jsonb_array_elements((
+            select get_user_events(
+                '00000000-0000-0000-0000-000000000001',
+                '{
+                    "cursor": {"created_at": "2020-06-16T09:00:00Z"},
+                    "limit": 1
+                }'
+            )::jsonb
+        )) e
+    $$,
+    $$
+        values ('00000000-0000-0000-0000-000000000001')
+    $$,
+    'Only the first event should be returned'
+);
+select is(
+    (
+        get_user_events(
+            :'user1ID',
+            '{
+                "cursor": {"created_at": "2020-06-16T09:00:00Z"},
+                "organization_id": "00000000-0000-0000-0000-000000000001"
+            }'
+        )::jsonb->0
+    ) - 'created_at',
+    '{
+        "event_id": "00000000-0000-0000-0000-000000000002",
+        "event_kind": 0,
+        "package_id": "00000000-0000-0000-0000-000000000002",
+        "package_version": "2.0.0",
+        "package": {
+            "package_id": "00000000-0000-0000-0000-000000000002",
+            "name": "package2",
+            "normalized_name": "package2",
+            "version": "2.0.0",
+            "prerelease": true
+        },
+        "repository": {
+            "repository_id": "00000000-0000-0000-0000-000000000002",
+            "kind": 0,
+            "name": "repo2",
+            "organization_id": "00000000-0000-0000-0000-000000000001",
+            "organization_name": "org1"
+        },
+        "subscription_filters": [{"major_minor_only": true}]
+    }'::jsonb,
+    'Event of package2 should be returned including the subscription filters'
+);
+select is(
+    get_user_events(
+        :'user2ID',
+        '{"cursor": {"created_at": "2020-06-16T09:00:00Z"}}'
+    )::jsonb,
+    '[]',
+    'No events should be returned for user2'
+);
+select is(
+    get_user_events(
+        :'user1ID',
+        '{"cursor": {"created_at": "2020-06-16T09:30:00Z"}}'
+    )::jsonb,
+    '[]',
+    'No events should be returned after the last one'
+);
+
+-- Opt out of package2 new releases (subscribed through org1)
+insert into package_opt_out (user_id, package_id, event_kind_id)
+values (:'user1ID', :'package2ID', 0);
+select results_eq(
+    $$
+        select e->>'event_id'
+        from jsonb_array_elements((
+            select get_user_events(
+                '00000000-0000-0000-0000-000000000001',
+                '{"cursor": {"created_at": "2020-06-16T09:00:00Z"}}'
+            )::jsonb
+        )) e
+    $$,
+    $$
+        values
+            ('00000000-0000-0000-0000-000000000001'),
+            ('00000000-0000-0000-0000-000000000003')
+    $$,
+    'Events of packages user1 has opted out of should not be returned'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(3);
+select plan(4);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -92,6 +92,31 @@ select results_eq(
     $$,
     'Notification for event1 and webhook1 should exist'
 );
+select add_notification('
+{
+    "event": {
+        "event_id": "00000000-0000-0000-0000-000000000001"
+    },
+    "user": {
+        "user_id": "00000000-0000-0000-0000-000000000001"
+    },
+    "digest": true,
+    "next_attempt_at": "2020-06-16T11:20:34Z"
+}
+'::jsonb);
+select results_eq(
+    $$
+        select digest, next_attempt_at
+        from notification
+        where event_id = '00000000-0000-0000-0000-000000000001'
+        and user_id = '00000000-0000-0000-0000-000000000001'
+        and digest = true
+    $$,
+    $$
+        values (true, '2020-06-16T11:20:34Z'::timestamptz)
+    $$,
+    'Digest notification for event1 and user1 should exist'
+);
 select throws_ok(
     $$
         select add_notification('
@@ -0,0 +1,74 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set notification1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into webhook (webhook_id, name, url, user_id)
+values (:'webhook1ID', 'webhook1', 'http://webhook1.url', :'user1ID');
+insert into event (event_id, package_version, package_id, event_kind_id)
+values (:'event1ID', '1.0.0', :'package1ID', 0);
+insert into notification (notification_id, event_id, webhook_id)
+values (:'notification1ID', :'event1ID', :'webhook1ID');
+
+-- Add some webhook deliveries
+select add_webhook_delivery('
+{
+    "notification_id": "00000000-0000-0000-0000-000000000001",
+    "webhook_id": "00000000-0000-0000-0000-000000000001",
+    "attempt": 1,
+    "latency_ms": 10,
+    "error": "connection refused"
+}
+');
+select add_webhook_delivery('
+{
+    "notification_id": "00000000-0000-0000-0000-000000000001",
+    "webhook_id": "00000000-0000-0000-0000-000000000001",
+    "attempt": 2,
+    "status_code": 200,
+    "latency_ms": 20,
+    "response": "ok"
+}
+');
+
+-- Run some tests
+select results_eq(
+    $$
+        select attempt, status_code, latency_ms, response, error
+        from webhook_delivery
+        where notification_id = '00000000-0000-0000-0000-000000000001'
+        order by attempt asc
+    $$,
+    $$
+        values
+            (1, null::int, 10, null::text, 'connection refused'),
+            (2, 200, 20, 'ok', null::text)
+    $$,
+    'Webhook deliveries should exist'
+);
+select results_eq(
+    $$
+        select distinct webhook_id from webhook_delivery
+    $$,
+    $$
+        values ('00000000-0000-0000-0000-000000000001'::uuid)
+    $$,
+    'Webhook deliveries should belong to webhook1'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,115 @@
+-- Start transaction and plan tests
+begin;
+select plan(6);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set event2ID '00000000-0000-0000-0000-000000000002'
+\set event3ID '00000000-0000-0000-0000-000000000003'
+\set notification1ID '00000000-0000-0000-0000-000000000001'
+\set notification2ID '00000000-0000-0000-0000-000000000002'
+\set notification3ID '00000000-0000-0000-0000-000000000003'
+\set notification4ID '00000000-0000-0000-0000-000000000004'
+
+-- No pending digests available yet
+select is_empty(
+    $$ select get_pending_digest()::jsonb $$,
+    'Should not return a digest'
+);
+
+-- Seed some data
+insert into "user" (user_id, alias, email, delivery_preference_id)
+values (:'user1ID', 'user1', 'user1@email.com', 1);
+insert into "user" (user_id, alias, email, delivery_preference_id)
+values (:'user2ID', 'user2', 'user2@email.com', 0);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into event (event_id, package_version, package_id, event_kind_id)
+values (:'event1ID', '1.0.0', :'package1ID', 0);
+insert into event (event_id, package_version, package_id, event_kind_id, data)
+values (:'event2ID', '1.0.0', :'package1ID', 1, '{"k": "v"}');
+insert into event (event_id, repository_id, event_kind_id)
+values (:'event3ID', :'repo1ID', 2);
+
+-- Add a recent notification for user1, the digest is not due yet
+insert into notification (notification_id, event_id, user_id, digest, created_at)
+values (:'notification1ID', :'event2ID', :'user1ID', true, current_timestamp - '1 hour'::interval);
+select is_empty(
+    $$ select get_pending_digest()::jsonb $$,
+    'Should not return a digest that is not due yet'
+);
+
+-- Add some older notifications, the digest is due now
+insert into notification (notification_id, event_id, user_id, digest, created_at)
+values (:'notification2ID', :'event1ID', :'user1ID', true, current_timestamp - '25 hours'::interval);
+insert into notification (notification_id, event_id, user_id, created_at)
+values (:'notification3ID', :'event3ID', :'user1ID', current_timestamp - '25 hours'::interval);
+insert into notification (notification_id, event_id, user_id, created_at)
+values (:'notification4ID', :'event1ID', :'user2ID', current_timestamp - '25 hours'::interval);
+select is(
+    get_pending_digest()::jsonb,
+    '{
+        "user": {
+            "user_id": "00000000-0000-0000-0000-000000000001",
+            "email": "user1@email.com",
+            "delivery_preference": 1
+        },
+        "notifications": [
+            {
+                "notification_id": "00000000-0000-0000-0000-000000000002",
+                "attempts": 0,
+                "event": {
+                    "event_id": "00000000-0000-0000-0000-000000000001",
+                    "event_kind": 0,
+                    "package_id": "00000000-0000-0000-0000-000000000001",
+                    "package_version": "1.0.0"
+                }
+            },
+            {
+                "notification_id": "00000000-0000-0000-0000-000000000001",
+                "attempts": 0,
+                "event": {
+                    "event_id": "00000000-0000-0000-0000-000000000002",
+                    "event_kind": 1,
+                    "package_id": "00000000-0000-0000-0000-000000000001",
+                    "package_version": "1.0.0",
+                    "data": {"k": "v"}
+                }
+            }
+        ]
+    }'::jsonb,
+    'A digest for user1 including its pending digest notifications should be returned'
+);
+
+-- Switch user1 to weekly digest, it is not due yet
+update "user" set delivery_preference_id = 2 where user_id = :'user1ID';
+select is_empty(
+    $$ select get_pending_digest()::jsonb $$,
+    'Should not return a weekly digest that is not due yet'
+);
+
+-- Schedule a new delivery attempt of the digest, it is not due yet
+update "user" set delivery_preference_id = 1 where user_id = :'user1ID';
+update notification set next_attempt_at = current_timestamp + '10 minutes'::interval
+where user_id = :'user1ID' and digest = true;
+select is_empty(
+    $$ select get_pending_digest()::jsonb $$,
+    'Should not return a digest whose next delivery attempt is not due yet'
+);
+
+-- Once the notifications are processed, no digest should be returned
+update notification set processed = true where user_id = :'user1ID';
+select is_empty(
+    $$ select get_pending_digest()::jsonb $$,
+    'Should not return a digest when there are no pending notifications'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(3);
+select plan(7);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -10,6 +10,11 @@ select plan(3);
 \set event1ID '00000000-0000-0000-0000-000000000001'
 \set notification1ID '00000000-0000-0000-0000-000000000001'
 \set notification2ID '00000000-0000-0000-0000-000000000002'
+\set notification3ID '00000000-0000-0000-0000-000000000003'
+\set event2ID '00000000-0000-0000-0000-000000000002'
+\set event3ID '00000000-0000-0000-0000-000000000003'
+\set notification4ID '00000000-0000-0000-0000-000000000004'
+\set notification5ID '00000000-0000-0000-0000-000000000005'
 
 -- No pending events available yet
 select is_empty(
@@ -52,6 +57,7 @@ select is(
     get_pending_notification()::jsonb,
     '{
         "notification_id": "00000000-0000-0000-0000-000000000001",
+        "attempts": 0,
         "event": {
             "event_id": "00000000-0000-0000-0000-000000000001",
             "event_kind": 0,
@@ -59,6 +65,7 @@ select is(
             "package_version": "1.0.0"
         },
         "user": {
+            "user_id": "00000000-0000-0000-0000-000000000001",
             "email": "user1@email.com"
         }
 	}'::jsonb,
@@ -73,6 +80,7 @@ select is(
     get_pending_notification()::jsonb,
     '{
         "notification_id": "00000000-0000-0000-0000-000000000002",
+        "attempts": 0,
         "event": {
             "event_id": "00000000-0000-0000-0000-000000000001",
             "event_kind": 0,
@@ -80,15 +88,59 @@ select is(
             "package_version": "1.0.0"
         },
         "webhook": {
+            "webhook_id": "00000000-0000-0000-0000-000000000001",
             "name": "webhook1",
             "url": "http://webhook1.url",
             "secret": "very",
             "content_type": "application/json",
+            "legacy_secret_header": false,
+            "kind": 0,
             "template": "custom payload"
         }
 	}'::jsonb,
     'A notification for webhook1 should be returned'
 );
+update notification set processed=true where notification_id=:'notification2ID';
+
+-- Add notification for webhook1 with a retry scheduled in the future
+insert into event (event_id, package_version, package_id, event_kind_id)
+values (:'event2ID', '1.0.0', :'package1ID', 0);
+insert into notification (notification_id, event_id, webhook_id, attempts, next_attempt_at)
+values (:'notification3ID', :'event2ID', :'webhook1ID', 1, current_timestamp + '1 hour'::interval);
+select is_empty(
+    $$ select get_pending_notification()::jsonb $$,
+    'Should not return a notification with a retry scheduled in the future'
+);
+
+-- Retry is due now, so the notification should be returned
+update notification set next_attempt_at = current_timestamp - '1 minute'::interval
+where notification_id=:'notification3ID';
+select is(
+    (get_pending_notification()::jsonb)->>'attempts',
+    '1',
+    'A notification with a retry due should be returned'
+);
+
+update notification set processed=true where notification_id=:'notification3ID';
+
+-- Notifications to be delivered in a digest should not be returned
+insert into notification (notification_id, event_id, user_id, digest)
+values (:'notification4ID', :'event2ID', :'user1ID', true);
+select is_empty(
+    $$ select get_pending_notification()::jsonb $$,
+    'Should not return a notification to be delivered in a digest'
+);
+
+-- Notifications not flagged as digest are delivered immediately
+insert into event (event_id, repository_id, event_kind_id)
+values (:'event3ID', :'repo1ID', 2);
+insert into notification (notification_id, event_id, user_id)
+values (:'notification5ID', :'event3ID', :'user1ID');
+select is(
+    (get_pending_notification()::jsonb)->>'notification_id',
+    '00000000-0000-0000-0000-000000000005',
+    'A notification not flagged as digest should be returned'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
@@ -0,0 +1,54 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set notification1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into webhook (webhook_id, name, url, user_id)
+values (:'webhook1ID', 'webhook1', 'http://webhook1.url', :'user1ID');
+insert into event (event_id, package_version, package_id, event_kind_id)
+values (:'event1ID', '1.0.0', :'package1ID', 0);
+insert into notification (notification_id, event_id, webhook_id)
+values (:'notification1ID', :'event1ID', :'webhook1ID');
+
+-- Schedule retry twice
+select schedule_notification_retry(:'notification1ID', 60, 'fake error 1');
+select schedule_notification_retry(:'notification1ID', 120, 'fake error 2');
+
+-- Run some tests
+select results_eq(
+    $$
+        select processed, processed_at, attempts, error from notification
+        where notification_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values (false, null::timestamptz, 2, 'fake error 2')
+    $$,
+    'Notification should not be processed and attempts and error should be updated'
+);
+select results_eq(
+    $$
+        select next_attempt_at from notification
+        where notification_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values (current_timestamp + '120 seconds'::interval)
+    $$,
+    'Next attempt should be scheduled using the last delay provided'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -38,11 +38,11 @@ select update_notification_status(:'notification1ID', true, 'fake error');
 -- Run some tests
 select results_eq(
     $$
-        select processed, error from notification
+        select processed, error, attempts, next_attempt_at from notification
         where notification_id = '00000000-0000-0000-0000-000000000001'
     $$,
     $$
-        values (true, 'fake error')
+        values (true, 'fake error', 1, null::timestamptz)
     $$,
     'Notification has been processed'
 );
@@ -1,10 +1,11 @@
 -- Start transaction and plan tests
 begin;
-select plan(2);
+select plan(3);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
 \set user2ID '00000000-0000-0000-0000-000000000002'
+\set sa1ID '00000000-0000-0000-0000-000000000003'
 \set org1ID '00000000-0000-0000-0000-000000000001'
 
 -- Seed user and organization
@@ -13,6 +14,9 @@ insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@e
 insert into organization (organization_id, name, display_name, description, home_url)
 values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
 insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into "user" (user_id, alias, email, service_account) values (:'sa1ID', 'org1-sa-1', 'org1-sa-1@email.com', true);
+insert into user__organization (user_id, organization_id, confirmed) values(:'sa1ID', :'org1ID', true);
+insert into api_key (name, user_id, organization_id) values ('apikey1', :'sa1ID', :'org1ID');
 
 -- User not belonging to an organization tries to delete it
 select throws_ok(
@@ -32,6 +36,12 @@ select is_empty(
     $$,
     'Organization should have been deleted by user who belongs to it'
 );
+select is_empty(
+    $$
+        select user_id from "user" where service_account = true
+    $$,
+    'Organization service accounts should have been deleted'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
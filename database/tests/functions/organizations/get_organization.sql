@@ -18,7 +18,8 @@ select is(
         "display_name": "Organization 1",
         "description": "Description 1",
         "home_url": "https://org1.com",
-        "logo_image_id": "00000000-0000-0000-0000-000000000001"
+        "logo_image_id": "00000000-0000-0000-0000-000000000001",
+        "tfa_required": false
     }
     '::jsonb,
     'Organization1 should exist'
@@ -1,12 +1,13 @@
 -- Start transaction and plan tests
 begin;
-select plan(2);
+select plan(3);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
 \set user2ID '00000000-0000-0000-0000-000000000002'
 \set org1ID '00000000-0000-0000-0000-000000000001'
 \set org2ID '00000000-0000-0000-0000-000000000002'
+\set serviceAccountID '00000000-0000-0000-0000-000000000009'
 
 -- Seed some users and organizations
 insert into "user" (user_id, alias, first_name, last_name, email)
@@ -43,6 +44,17 @@ select throws_ok(
     'User1 should not be able to get organization2 members'
 );
 
+-- Service accounts cannot get the organization members
+insert into "user" (user_id, alias, email, service_account)
+values (:'serviceAccountID', 'org1-sa-00000009', 'org1-sa-00000009@service-account.invalid', true);
+insert into user__organization (user_id, organization_id, confirmed) values(:'serviceAccountID', :'org1ID', true);
+select throws_ok(
+    $$ select get_organization_members('00000000-0000-0000-0000-000000000009', 'org1') $$,
+    42501,
+    'insufficient_privilege',
+    'Service accounts should not be able to get organization members'
+);
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
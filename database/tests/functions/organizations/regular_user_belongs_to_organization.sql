@@ -0,0 +1,45 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email, service_account)
+values (:'user2ID', 'org1-sa-00000002', 'org1-sa-00000002@service-account.invalid', true);
+insert into user__organization (user_id, organization_id) values(:'user1ID', :'org1ID');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user2ID', :'org1ID', true);
+
+-- Run some tests
+select is(
+    regular_user_belongs_to_organization(:'user1ID', 'org1'),
+    false,
+    'User1 does not belong to Org1 as it is not confirmed yet'
+);
+update "user__organization" set confirmed = true
+where user_id = :'user1ID' and organization_id = :'org1ID';
+select is(
+    regular_user_belongs_to_organization(:'user1ID', 'org1'),
+    true,
+    'User1 belongs to Org1'
+);
+select is(
+    regular_user_belongs_to_organization(:'user2ID', 'org1'),
+    false,
+    'Service account does not belong to Org1 as a regular user'
+);
+select is(
+    user_belongs_to_organization(:'user2ID', 'org1'),
+    true,
+    'Service account belongs to Org1'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -19,7 +19,8 @@ select update_organization(:'user1ID', 'org1', '
     "display_name": "Organization 1 updated",
     "description": "Description 1 updated",
     "home_url": "https://org1.com/updated",
-    "logo_image_id": "00000000-0000-0000-0000-000000000001"
+    "logo_image_id": "00000000-0000-0000-0000-000000000001",
+    "tfa_required": true
 }
 '::jsonb);
 
@@ -31,7 +32,8 @@ select results_eq(
             display_name,
             description,
             home_url,
-            logo_image_id
+            logo_image_id,
+            tfa_required
         from organization
     $$,
     $$
@@ -40,7 +42,8 @@ select results_eq(
             'Organization 1 updated',
             'Description 1 updated',
             'https://org1.com/updated',
-            '00000000-0000-0000-0000-000000000001'::uuid
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            true
         )
     $$,
     'Organization should have been updated'
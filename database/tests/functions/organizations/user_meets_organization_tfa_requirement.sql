@@ -0,0 +1,69 @@
+-- Start transaction and plan tests
+begin;
+select plan(7);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set user3ID '00000000-0000-0000-0000-000000000003'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set org2ID '00000000-0000-0000-0000-000000000002'
+\set org3ID '00000000-0000-0000-0000-000000000003'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email, tfa_enabled)
+values (:'user1ID', 'user1', 'user1@email.com', true);
+insert into "user" (user_id, alias, email)
+values (:'user2ID', 'user2', 'user2@email.com');
+insert into "user" (user_id, alias, email, service_account)
+values (:'user3ID', 'user3', 'user3@email.com', true);
+insert into organization (organization_id, name)
+values (:'org1ID', 'org1');
+insert into organization (organization_id, name, tfa_required)
+values (:'org2ID', 'org2', true);
+insert into organization (organization_id, name)
+values (:'org3ID', 'org3');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id, official)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'org3ID', true);
+
+-- Run some tests
+select is(
+    user_meets_organization_tfa_requirement(:'user2ID', 'org1'),
+    true,
+    'Org1 does not require two-factor authentication'
+);
+select is(
+    user_meets_organization_tfa_requirement(:'user1ID', 'org2'),
+    true,
+    'User1 has two-factor authentication enabled as required by org2'
+);
+select is(
+    user_meets_organization_tfa_requirement(:'user2ID', 'org2'),
+    false,
+    'User2 has not enabled two-factor authentication as required by org2'
+);
+select is(
+    user_meets_organization_tfa_requirement(:'user1ID', 'org3'),
+    true,
+    'User1 has two-factor authentication enabled as required by org3'
+);
+select is(
+    user_meets_organization_tfa_requirement(:'user2ID', 'org3'),
+    false,
+    'Org3 requires two-factor authentication as it owns official repositories'
+);
+select is(
+    user_meets_organization_tfa_requirement(:'user3ID', 'org2'),
+    true,
+    'User3 is a service account, not subject to the org2 requirement'
+);
+select is(
+    user_meets_organization_tfa_requirement(:'user2ID', 'org4'),
+    true,
+    'Org4 does not exist'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(6);
+select plan(9);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -67,6 +67,83 @@ select is(security_report_summary, '{
     "low": 10
 }', 'Security report summary should exist')
 from snapshot where package_id = :'package1ID' and version = '1.0.0';
+select is_empty(
+    $$ select * from event $$,
+    'No security alert event should be registered on the first report'
+);
+select update_snapshot_security_report('{
+    "package_id": "00000000-0000-0000-0000-000000000001",
+    "version": "1.0.0",
+    "summary": {
+        "critical": 3,
+        "high": 3,
+        "low": 10
+    },
+    "full": {
+        "quay.io/org/pkg1:1.0.0": [
+            {
+                "Target": "target1",
+                "Vulnerabilities": [
+                    {"VulnerabilityID": "CVE-0000-0002", "Severity": "HIGH"},
+                    {"VulnerabilityID": "CVE-0000-0001", "Severity": "CRITICAL"},
+                    {"VulnerabilityID": "CVE-0000-0003", "Severity": "LOW"}
+                ]
+            }
+        ]
+    }
+}');
+select results_eq(
+    $$
+        select package_id, package_version, event_kind_id, data
+        from event
+    $$,
+    $$
+        values (
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '1.0.0',
+            1,
+            '{
+                "previous_security_report_summary": {
+                    "critical": 2,
+                    "high": 3,
+                    "low": 10
+                },
+                "added_vulnerabilities": [
+                    {"id": "CVE-0000-0001", "severity": "critical"},
+                    {"id": "CVE-0000-0002", "severity": "high"}
+                ]
+            }'::jsonb
+        )
+    $$,
+    'Security alert event should be registered with the vulnerabilities added'
+);
+
+select update_snapshot_security_report('{
+    "package_id": "00000000-0000-0000-0000-000000000001",
+    "version": "1.0.0",
+    "summary": {
+        "critical": 3,
+        "high": 3,
+        "low": 10
+    },
+    "full": {
+        "quay.io/org/pkg1:1.0.0": [
+            {
+                "Target": "target1",
+                "Vulnerabilities": [
+                    {"VulnerabilityID": "CVE-0000-0002", "Severity": "HIGH"},
+                    {"VulnerabilityID": "CVE-0000-0001", "Severity": "CRITICAL"},
+                    {"VulnerabilityID": "CVE-0000-0003", "Severity": "HIGH"}
+                ]
+            }
+        ]
+    }
+}');
+select is(
+    (select count(*) from event),
+    1::bigint,
+    'No new security alert event should be registered when only the severity of a vulnerability changes'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
@@ -1,10 +1,11 @@
 -- Start transaction and plan tests
 begin;
-select plan(3);
+select plan(4);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
 \set user2ID '00000000-0000-0000-0000-000000000002'
+\set serviceAccountID '00000000-0000-0000-0000-000000000009'
 \set org1ID '00000000-0000-0000-0000-000000000001'
 \set org2ID '00000000-0000-0000-0000-000000000002'
 \set repo1ID '00000000-0000-0000-0000-000000000001'
@@ -21,6 +22,9 @@ insert into organization (organization_id, name, display_name, description, home
 values (:'org2ID', 'org2', 'Organization 2', 'Description 2', 'https://org2.com');
 insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
 insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org2ID', true);
+insert into "user" (user_id, alias, email, service_account)
+values (:'serviceAccountID', 'org1-sa-00000009', 'org1-sa-00000009@service-account.invalid', true);
+insert into user__organization (user_id, organization_id, confirmed) values(:'serviceAccountID', :'org1ID', true);
 
 -- No repositories at this point
 select is(
@@ -132,6 +136,11 @@ select is(
     '[]'::jsonb,
     'No repositories are returned as user provided does not belong to the organization'
 );
+select is(
+    get_org_repositories(:'serviceAccountID', 'org1', true)::jsonb,
+    '[]'::jsonb,
+    'No repositories (nor their credentials) are returned to service accounts'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
@@ -0,0 +1,113 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set repo3ID '00000000-0000-0000-0000-000000000003'
+\set repo4ID '00000000-0000-0000-0000-000000000004'
+
+-- No repositories at this point
+select is(
+    get_repositories_by_git_url('https://github.com/org1/repo1', false)::jsonb,
+    '[]'::jsonb,
+    'With no repositories an empty json array is returned'
+);
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://github.com/org1/repo1', 1, :'user1ID');
+insert into repository (repository_id, name, display_name, url, branch, push_webhook_secret, repository_kind_id, user_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://github.com/Org1/repo1/path/', 'main', 'secret2', 2, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo3ID', 'repo3', 'Repo 3', 'https://github.com/org1/repo10', 1, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, disabled)
+values (:'repo4ID', 'repo4', 'Repo 4', 'https://github.com/org1/repo1', 1, :'user1ID', true);
+
+-- Run some tests
+select is(
+    get_repositories_by_git_url('https://github.com/org1/repo1.git', false)::jsonb,
+    '[{
+        "repository_id": "00000000-0000-0000-0000-000000000001",
+        "name": "repo1",
+        "display_name": "Repo 1",
+        "url": "https://github.com/org1/repo1",
+        "kind": 1,
+        "verified_publisher": false,
+        "official": false,
+        "disabled": false,
+        "scanner_disabled": false,
+        "user_alias": "user1"
+    }, {
+        "repository_id": "00000000-0000-0000-0000-000000000002",
+        "name": "repo2",
+        "display_name": "Repo 2",
+        "url": "https://github.com/Org1/repo1/path/",
+        "branch": "main",
+        "kind": 2,
+        "verified_publisher": false,
+        "official": false,
+        "disabled": false,
+        "scanner_disabled": false,
+        "user_alias": "user1"
+    }]'::jsonb,
+    'Repositories 1 and 2 are returned'
+);
+select is(
+    get_repositories_by_git_url('http://github.com/org1/repo1/', true)::jsonb,
+    '[{
+        "repository_id": "00000000-0000-0000-0000-000000000001",
+        "name": "repo1",
+        "display_name": "Repo 1",
+        "url": "https://github.com/org1/repo1",
+        "kind": 1,
+        "verified_publisher": false,
+        "official": false,
+        "disabled": false,
+        "scanner_disabled": false,
+        "user_alias": "user1"
+    }, {
+        "repository_id": "00000000-0000-0000-0000-000000000002",
+        "name": "repo2",
+        "display_name": "Repo 2",
+        "url": "https://github.com/Org1/repo1/path/",
+        "branch": "main",
+        "push_webhook_secret": "secret2",
+        "kind": 2,
+        "verified_publisher": false,
+        "official": false,
+        "disabled": false,
+        "scanner_disabled": false,
+        "user_alias": "user1"
+    }]'::jsonb,
+    'Repositories 1 and 2 are returned including the credentials'
+);
+select is(
+    get_repositories_by_git_url('git@github.com:org1/repo10.git', false)::jsonb,
+    '[{
+        "repository_id": "00000000-0000-0000-0000-000000000003",
+        "name": "repo3",
+        "display_name": "Repo 3",
+        "url": "https://github.com/org1/repo10",
+        "kind": 1,
+        "verified_publisher": false,
+        "official": false,
+        "disabled": false,
+        "scanner_disabled": false,
+        "user_alias": "user1"
+    }]'::jsonb,
+    'Repository 3 is returned when using a ssh url'
+);
+select is(
+    get_repositories_by_git_url('https://github.com/org1/repo2', false)::jsonb,
+    '[]'::jsonb,
+    'No repositories match the url provided'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
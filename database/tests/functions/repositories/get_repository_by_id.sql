@@ -23,11 +23,14 @@ insert into repository (
     branch,
     auth_user,
     auth_pass,
+    ssh_key,
+    push_webhook_secret,
     digest,
     repository_kind_id,
     user_id,
     last_tracking_ts,
-    last_tracking_errors
+    last_tracking_errors,
+    tracking_interval
 )
 values (
     :'repo1ID',
@@ -37,11 +40,14 @@ values (
     'main',
     'user1',
     'pass1',
+    'key1',
+    'secret1',
     'digest',
     0,
     :'user1ID',
     '2020-06-16 11:20:34+02',
-    'error1\nerror2\n'
+    'error1\nerror2\n',
+    '2 hours'
 );
 
 -- One repository has just been seeded
@@ -58,6 +64,7 @@ select is(
         "official": false,
         "disabled": false,
         "scanner_disabled": false,
+        "tracking_interval": 120,
         "digest": "digest",
         "last_tracking_ts": 1592299234,
         "last_tracking_errors": "error1\\nerror2\\n",
@@ -75,11 +82,14 @@ select is(
         "branch": "main",
         "auth_user": "user1",
         "auth_pass": "pass1",
+        "ssh_key": "key1",
+        "push_webhook_secret": "secret1",
         "kind": 0,
         "verified_publisher": false,
         "official": false,
         "disabled": false,
         "scanner_disabled": false,
+        "tracking_interval": 120,
         "digest": "digest",
         "last_tracking_ts": 1592299234,
         "last_tracking_errors": "error1\\nerror2\\n",
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(7);
+select plan(11);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -137,18 +137,21 @@ select update_repository(:'user1ID', '
     "url": "https://repo2.com/updated",
     "auth_user": "user1",
     "auth_pass": "pass1",
+    "ssh_key": "key1",
+    "push_webhook_secret": "secret1",
     "disabled": false,
-    "scanner_disabled": true
+    "scanner_disabled": true,
+    "tracking_interval": 60
 }
 '::jsonb);
 select results_eq(
     $$
-        select name, display_name, url, branch, auth_user, auth_pass, disabled
+        select name, display_name, url, branch, auth_user, auth_pass, ssh_key, push_webhook_secret, disabled, tracking_interval
         from repository
         where name = 'repo2'
     $$,
     $$
-        values ('repo2', 'Repo 2 updated', 'https://repo2.com/updated', null, 'user1', 'pass1', false)
+        values ('repo2', 'Repo 2 updated', 'https://repo2.com/updated', null, 'user1', 'pass1', 'key1', 'secret1', false, '1 hour'::interval)
     $$,
     'Repository should have been updated by user who belongs to owning organization'
 );
@@ -168,6 +171,100 @@ select results_eq(
     'Security reports in packages belonging to repo2 should have been deleted'
 );
 
+-- Update repository without providing the secrets (current ones are kept)
+select update_repository(:'user1ID', '
+{
+    "name": "repo2",
+    "display_name": "Repo 2 updated",
+    "url": "https://repo2.com/updated",
+    "disabled": false,
+    "scanner_disabled": true
+}
+'::jsonb);
+select results_eq(
+    $$
+        select auth_user, auth_pass, ssh_key, push_webhook_secret
+        from repository
+        where name = 'repo2'
+    $$,
+    $$
+        values (null::text, 'pass1', 'key1', 'secret1')
+    $$,
+    'Repository secrets should have been kept when not provided'
+);
+
+-- Update repository clearing the push webhook secret
+select update_repository(:'user1ID', '
+{
+    "name": "repo2",
+    "display_name": "Repo 2 updated",
+    "url": "https://repo2.com/updated",
+    "push_webhook_secret": "secret2",
+    "clear_push_webhook_secret": true,
+    "disabled": false,
+    "scanner_disabled": true
+}
+'::jsonb);
+select results_eq(
+    $$
+        select ssh_key, push_webhook_secret
+        from repository
+        where name = 'repo2'
+    $$,
+    $$
+        values ('key1', null::text)
+    $$,
+    'Repository push webhook secret should have been cleared'
+);
+
+-- Update repository clearing the ssh key
+select update_repository(:'user1ID', '
+{
+    "name": "repo2",
+    "display_name": "Repo 2 updated",
+    "url": "https://repo2.com/updated",
+    "ssh_key": "key2",
+    "clear_ssh_key": true,
+    "disabled": false,
+    "scanner_disabled": true
+}
+'::jsonb);
+select results_eq(
+    $$
+        select ssh_key, push_webhook_secret
+        from repository
+        where name = 'repo2'
+    $$,
+    $$
+        values (null::text, null::text)
+    $$,
+    'Repository ssh key should have been cleared'
+);
+
+-- Update repository clearing the basic auth password
+select update_repository(:'user1ID', '
+{
+    "name": "repo2",
+    "display_name": "Repo 2 updated",
+    "url": "https://repo2.com/updated",
+    "auth_pass": "pass2",
+    "clear_auth_pass": true,
+    "disabled": false,
+    "scanner_disabled": true
+}
+'::jsonb);
+select results_eq(
+    $$
+        select auth_pass
+        from repository
+        where name = 'repo2'
+    $$,
+    $$
+        values (null::text)
+    $$,
+    'Repository basic auth password should have been cleared'
+);
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
@@ -0,0 +1,50 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+\set webhook2ID '00000000-0000-0000-0000-000000000002'
+
+-- No secrets stored yet
+select is(
+    get_unencrypted_secrets('enc:v1:')::jsonb,
+    '[]'::jsonb,
+    'No secrets expected'
+);
+
+-- Seed some data
+insert into "user" (user_id, alias, email, tfa_secret) values (:'user1ID', 'user1', 'user1@email.com', 'tfaSecret1');
+insert into repository (repository_id, name, display_name, url, auth_pass, ssh_key, push_webhook_secret, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 'pass1', 'enc:v1:key1', 'enc:v1:pushSecret1', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, auth_pass, ssh_key, push_webhook_secret, repository_kind_id, user_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 'enc:v1:pass2', 'key2', 'pushSecret2', 0, :'user1ID');
+insert into webhook (webhook_id, name, url, secret, user_id)
+values (:'webhook1ID', 'webhook1', 'http://webhook1.url', 'secret1', :'user1ID');
+insert into webhook (webhook_id, name, url, secret, user_id)
+values (:'webhook2ID', 'webhook2', 'http://webhook2.url', 'enc:v1:secret2', :'user1ID');
+
+-- Run some tests
+select results_eq(
+    $$
+        select kind, id, value
+        from json_to_recordset(get_unencrypted_secrets('enc:v1:')) as s(kind text, id uuid, value text)
+        order by kind
+    $$,
+    $$
+        values
+            ('repository_auth_pass', '00000000-0000-0000-0000-000000000001'::uuid, 'pass1'),
+            ('repository_push_webhook_secret', '00000000-0000-0000-0000-000000000002'::uuid, 'pushSecret2'),
+            ('repository_ssh_key', '00000000-0000-0000-0000-000000000002'::uuid, 'key2'),
+            ('user_tfa_secret', '00000000-0000-0000-0000-000000000001'::uuid, 'tfaSecret1'),
+            ('webhook_secret', '00000000-0000-0000-0000-000000000001'::uuid, 'secret1')
+    $$,
+    'Only secrets not encrypted yet should be returned'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,84 @@
+-- Start transaction and plan tests
+begin;
+select plan(6);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email, tfa_secret) values (:'user1ID', 'user1', 'user1@email.com', 'tfaSecret1');
+insert into repository (repository_id, name, display_name, url, auth_pass, ssh_key, push_webhook_secret, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 'pass1', 'key1', 'pushSecret1', 0, :'user1ID');
+insert into webhook (webhook_id, name, url, secret, user_id)
+values (:'webhook1ID', 'webhook1', 'http://webhook1.url', 'secret1', :'user1ID');
+
+-- Run some tests
+select update_secret('repository_auth_pass', :'repo1ID', 'pass1', 'enc:v1:pass1');
+select update_secret('repository_ssh_key', :'repo1ID', 'key1', 'enc:v1:key1');
+select update_secret('repository_push_webhook_secret', :'repo1ID', 'pushSecret1', 'enc:v1:pushSecret1');
+select update_secret('webhook_secret', :'webhook1ID', 'secret1', 'enc:v1:secret1');
+select update_secret('user_tfa_secret', :'user1ID', 'tfaSecret1', 'enc:v1:tfaSecret1');
+select results_eq(
+    $$
+        select auth_pass, ssh_key, push_webhook_secret
+        from repository
+        where repository_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values ('enc:v1:pass1', 'enc:v1:key1', 'enc:v1:pushSecret1')
+    $$,
+    'Repository secrets should have been updated'
+);
+select results_eq(
+    $$
+        select secret
+        from webhook
+        where webhook_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values ('enc:v1:secret1')
+    $$,
+    'Webhook secret should have been updated'
+);
+select results_eq(
+    $$
+        select tfa_secret
+        from "user"
+        where user_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values ('enc:v1:tfaSecret1')
+    $$,
+    'User two-factor authentication secret should have been updated'
+);
+select update_secret('webhook_secret', :'webhook1ID', 'secret1', 'enc:v1:other');
+select results_eq(
+    $$
+        select secret
+        from webhook
+        where webhook_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values ('enc:v1:secret1')
+    $$,
+    'Webhook secret should not be updated if its value changed since it was read'
+);
+select throws_ok(
+    $$
+        select update_secret('invalid', '00000000-0000-0000-0000-000000000001', 'value', 'enc:v1:value')
+    $$,
+    'invalid secret kind: invalid',
+    'An error should be raised when the secret kind is not valid'
+);
+select lives_ok(
+    $$
+        select update_secret('webhook_secret', '00000000-0000-0000-0000-000000000002', 'value', 'enc:v1:value')
+    $$,
+    'Updating a secret that does not exist should not fail'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,18 +1,23 @@
 -- Start transaction and plan tests
 begin;
-select plan(1);
+select plan(5);
 
 -- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
 \set user1ID '00000000-0000-0000-0000-000000000001'
 \set repo1ID '00000000-0000-0000-0000-000000000001'
 \set package1ID '00000000-0000-0000-0000-000000000001'
 
 -- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
 insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
 insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
 values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
 insert into package (package_id, name, latest_version, repository_id)
 values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into package_opt_out (user_id, package_id, event_kind_id)
+values (:'user1ID', :'package1ID', 0);
 
 -- Add subscription
 select add_subscription('
@@ -41,6 +46,103 @@ select results_eq(
     $$,
     'Subscription should exist'
 );
+select is_empty(
+    $$ select * from package_opt_out $$,
+    'Package opt-out entry should have been removed'
+);
+
+-- Add repository subscription
+select add_subscription('
+{
+    "user_id": "00000000-0000-0000-0000-000000000001",
+    "package_id": "",
+    "repository_id": "00000000-0000-0000-0000-000000000001",
+    "event_kind": 1
+}
+'::jsonb);
+
+-- Check if repository subscription was added successfully
+select results_eq(
+    $$
+        select
+            user_id,
+            repository_id,
+            event_kind_id
+        from repository_subscription
+    $$,
+    $$
+        values (
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            1
+        )
+    $$,
+    'Repository subscription should exist'
+);
+
+-- Add organization subscription
+select add_subscription('
+{
+    "user_id": "00000000-0000-0000-0000-000000000001",
+    "package_id": "",
+    "repository_id": "",
+    "organization_id": "00000000-0000-0000-0000-000000000001",
+    "event_kind": 0
+}
+'::jsonb);
+
+-- Check if organization subscription was added successfully
+select results_eq(
+    $$
+        select
+            user_id,
+            organization_id,
+            event_kind_id
+        from organization_subscription
+    $$,
+    $$
+        values (
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            0
+        )
+    $$,
+    'Organization subscription should exist'
+);
+
+-- Add existing subscription again with some filters
+select add_subscription('
+{
+    "user_id": "00000000-0000-0000-0000-000000000001",
+    "package_id": "00000000-0000-0000-0000-000000000001",
+    "event_kind": 0,
+    "filters": {
+        "major_minor_only": true,
+        "version_constraint": ">=2.0.0 <3"
+    }
+}
+'::jsonb);
+
+-- Check if subscription filters were updated successfully
+select results_eq(
+    $$
+        select
+            user_id,
+            package_id,
+            event_kind_id,
+            filters
+        from subscription
+    $$,
+    $$
+        values (
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            0,
+            '{"major_minor_only": true, "version_constraint": ">=2.0.0 <3"}'::jsonb
+        )
+    $$,
+    'Subscription filters should have been updated'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
@@ -1,13 +1,16 @@
 -- Start transaction and plan tests
 begin;
-select plan(1);
+select plan(3);
 
 -- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
 \set user1ID '00000000-0000-0000-0000-000000000001'
 \set repo1ID '00000000-0000-0000-0000-000000000001'
 \set package1ID '00000000-0000-0000-0000-000000000001'
 
 -- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
 insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
 insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
 values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
@@ -15,6 +18,10 @@ insert into package (package_id, name, latest_version, repository_id)
 values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
 insert into subscription (user_id, package_id, event_kind_id)
 values (:'user1ID', :'package1ID', 0);
+insert into repository_subscription (user_id, repository_id, event_kind_id)
+values (:'user1ID', :'repo1ID', 0);
+insert into organization_subscription (user_id, organization_id, event_kind_id)
+values (:'user1ID', :'org1ID', 0);
 
 -- Delete subscription
 select delete_subscription('
@@ -37,6 +44,48 @@ select is_empty(
     'Subscription should not exist'
 );
 
+-- Delete repository subscription
+select delete_subscription('
+{
+    "user_id": "00000000-0000-0000-0000-000000000001",
+    "repository_id": "00000000-0000-0000-0000-000000000001",
+    "event_kind": 0
+}
+'::jsonb);
+
+-- Check if repository subscription was deleted successfully
+select is_empty(
+    $$
+        select *
+        from repository_subscription
+        where user_id = '00000000-0000-0000-0000-000000000001'
+        and repository_id = '00000000-0000-0000-0000-000000000001'
+        and event_kind_id = 0
+    $$,
+    'Repository subscription should not exist'
+);
+
+-- Delete organization subscription
+select delete_subscription('
+{
+    "user_id": "00000000-0000-0000-0000-000000000001",
+    "organization_id": "00000000-0000-0000-0000-000000000001",
+    "event_kind": 0
+}
+'::jsonb);
+
+-- Check if organization subscription was deleted successfully
+select is_empty(
+    $$
+        select *
+        from organization_subscription
+        where user_id = '00000000-0000-0000-0000-000000000001'
+        and organization_id = '00000000-0000-0000-0000-000000000001'
+        and event_kind_id = 0
+    $$,
+    'Organization subscription should not exist'
+);
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
@@ -1,22 +1,34 @@
 -- Start transaction and plan tests
 begin;
-select plan(2);
+select plan(6);
 
 -- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
 \set user1ID '00000000-0000-0000-0000-000000000001'
 \set user2ID '00000000-0000-0000-0000-000000000002'
 \set user3ID '00000000-0000-0000-0000-000000000003'
+\set user4ID '00000000-0000-0000-0000-000000000004'
+\set user5ID '00000000-0000-0000-0000-000000000005'
 \set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
 \set package1ID '00000000-0000-0000-0000-000000000001'
 \set package2ID '00000000-0000-0000-0000-000000000002'
+\set package3ID '00000000-0000-0000-0000-000000000003'
+\set package4ID '00000000-0000-0000-0000-000000000004'
 
 -- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
 insert into "user" (user_id, alias, email)
 values (:'user1ID', 'user1', 'user1@email.com');
 insert into "user" (user_id, alias, email)
 values (:'user2ID', 'user2', 'user2@email.com');
 insert into "user" (user_id, alias, email)
 values (:'user3ID', 'user3', 'user3@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user4ID', 'user4', 'user4@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user5ID', 'user5', 'user5@email.com');
 insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
 values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
 insert into package (package_id, name, latest_version, repository_id)
@@ -27,6 +39,27 @@ insert into subscription (user_id, package_id, event_kind_id)
 values (:'user2ID', :'package1ID', 0);
 insert into subscription (user_id, package_id, event_kind_id)
 values (:'user3ID', :'package1ID', 1);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package3ID', 'Package 3', '1.0.0', :'repo2ID');
+insert into repository_subscription (user_id, repository_id, event_kind_id)
+values (:'user1ID', :'repo1ID', 0);
+insert into repository_subscription (user_id, repository_id, event_kind_id)
+values (:'user4ID', :'repo2ID', 0);
+insert into organization_subscription (user_id, organization_id, event_kind_id)
+values (:'user4ID', :'org1ID', 0);
+insert into organization_subscription (user_id, organization_id, event_kind_id)
+values (:'user5ID', :'org1ID', 1);
+
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package4ID', 'Package 4', '1.0.0', :'repo2ID');
+insert into subscription (user_id, package_id, event_kind_id, filters)
+values (:'user1ID', :'package4ID', 0, '{"exclude_prereleases": true}');
+insert into subscription (user_id, package_id, event_kind_id, filters)
+values (:'user2ID', :'package4ID', 0, '{"security_updates_only": true}');
+insert into repository_subscription (user_id, repository_id, event_kind_id, filters)
+values (:'user2ID', :'repo2ID', 0, '{"major_minor_only": true}');
 
 -- Run some tests
 select is(
@@ -46,6 +79,63 @@ select is(
     '[]'::jsonb,
     'No subscriptors expected for package2 and kind new releases'
 );
+select is(
+    get_package_subscriptors(:'package3ID', 0)::jsonb,
+    '[
+        {
+            "user_id": "00000000-0000-0000-0000-000000000004"
+        }
+    ]'::jsonb,
+    'One subscriptor expected for package3 and kind new releases (repository and organization subscriptions)'
+);
+select is(
+    get_package_subscriptors(:'package3ID', 1)::jsonb,
+    '[
+        {
+            "user_id": "00000000-0000-0000-0000-000000000005"
+        }
+    ]'::jsonb,
+    'One subscriptor expected for package3 and kind security alerts (organization subscription)'
+);
+
+select is(
+    get_package_subscriptors(:'package4ID', 0)::jsonb,
+    '[
+        {
+            "user_id": "00000000-0000-0000-0000-000000000001",
+            "subscription_filters": [
+                {"exclude_prereleases": true}
+            ]
+        },
+        {
+            "user_id": "00000000-0000-0000-0000-000000000002",
+            "subscription_filters": [
+                {"security_updates_only": true},
+                {"major_minor_only": true}
+            ]
+        },
+        {
+            "user_id": "00000000-0000-0000-0000-000000000004"
+        }
+    ]'::jsonb,
+    'Three subscriptors expected for package4 and kind new releases, two of them with filters'
+);
+insert into package_opt_out (user_id, package_id, event_kind_id)
+values (:'user2ID', :'package4ID', 0);
+insert into package_opt_out (user_id, package_id, event_kind_id)
+values (:'user4ID', :'package4ID', 0);
+select is(
+    get_package_subscriptors(:'package4ID', 0)::jsonb,
+    '[
+        {
+            "user_id": "00000000-0000-0000-0000-000000000001",
+            "subscription_filters": [
+                {"exclude_prereleases": true}
+            ]
+        }
+    ]'::jsonb,
+    'Users who opted out of package4 new releases should not be returned'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
@@ -0,0 +1,52 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set org2ID '00000000-0000-0000-0000-000000000002'
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set image1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url, logo_image_id)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com', :'image1ID');
+insert into organization (organization_id, name)
+values (:'org2ID', 'org2');
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user2ID', 'user2', 'user2@email.com');
+insert into organization_subscription (user_id, organization_id, event_kind_id)
+values (:'user1ID', :'org1ID', 0);
+insert into organization_subscription (user_id, organization_id, event_kind_id)
+values (:'user1ID', :'org1ID', 1);
+insert into organization_subscription (user_id, organization_id, event_kind_id)
+values (:'user1ID', :'org2ID', 1);
+
+-- Run some tests
+select is(
+    get_user_organization_subscriptions(:'user1ID')::jsonb,
+    '[{
+        "organization_id": "00000000-0000-0000-0000-000000000001",
+        "name": "org1",
+        "display_name": "Organization 1",
+        "logo_image_id": "00000000-0000-0000-0000-000000000001",
+        "event_kinds": [0, 1]
+    }, {
+        "organization_id": "00000000-0000-0000-0000-000000000002",
+        "name": "org2",
+        "event_kinds": [1]
+    }]'::jsonb,
+    'Two organizations subscriptions should be returned'
+);
+select is(
+    get_user_organization_subscriptions(:'user2ID')::jsonb,
+    '[]',
+    'No organizations subscriptions expected for user2'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,106 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'org1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into subscription (user_id, package_id, event_kind_id)
+values (:'user1ID', :'package1ID', 0), (:'user1ID', :'package1ID', 1);
+insert into repository_subscription (user_id, repository_id, event_kind_id)
+values (:'user1ID', :'repo1ID', 0);
+insert into organization_subscription (user_id, organization_id, event_kind_id)
+values (:'user1ID', :'org1ID', 0);
+
+-- Unsubscribe from package new releases
+select unsubscribe('
+{
+    "user_id": "00000000-0000-0000-0000-000000000001",
+    "package_id": "00000000-0000-0000-0000-000000000001",
+    "event_kind": 0
+}
+'::jsonb);
+
+-- Run some tests
+select results_eq(
+    $$
+        select event_kind_id
+        from subscription
+        where user_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$ values (1) $$,
+    'Only the package subscription to security alerts should remain'
+);
+select results_eq(
+    $$
+        select
+            (select count(*) from repository_subscription),
+            (select count(*) from organization_subscription)
+    $$,
+    $$ values (1::bigint, 1::bigint) $$,
+    'Repository and organization subscriptions should have been kept'
+);
+select results_eq(
+    $$
+        select user_id, package_id, event_kind_id
+        from package_opt_out
+    $$,
+    $$
+        values (
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            0
+        )
+    $$,
+    'Package opt-out entry should have been added'
+);
+
+-- Unsubscribe from repository tracking errors (twice)
+select unsubscribe('
+{
+    "user_id": "00000000-0000-0000-0000-000000000001",
+    "repository_id": "00000000-0000-0000-0000-000000000001",
+    "event_kind": 2
+}
+'::jsonb);
+select lives_ok(
+    $$
+        select unsubscribe('
+        {
+            "user_id": "00000000-0000-0000-0000-000000000001",
+            "repository_id": "00000000-0000-0000-0000-000000000001",
+            "event_kind": 2
+        }
+        '::jsonb)
+    $$,
+    'Unsubscribing twice from repository tracking errors should succeed'
+);
+select results_eq(
+    $$
+        select user_id, repository_id, event_kind_id
+        from opt_out
+    $$,
+    $$
+        values (
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            2
+        )
+    $$,
+    'Opt-out entry should have been added'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
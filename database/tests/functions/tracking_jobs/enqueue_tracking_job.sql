@@ -0,0 +1,100 @@
+-- Start transaction and plan tests
+begin;
+select plan(9);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set job1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+insert into tracking_job (tracking_job_id, repository_id, scheduled_at)
+values (:'job1ID', :'repo2ID', current_timestamp + '1 hour'::interval);
+
+-- Run some tests
+select throws_ok(
+    $$ select enqueue_tracking_job('00000000-0000-0000-0000-000000000001', 'repo3') $$,
+    'repository not found',
+    'Enqueueing a tracking job for a repository that does not exist should fail'
+);
+select throws_ok(
+    $$ select enqueue_tracking_job('00000000-0000-0000-0000-000000000002', 'repo1') $$,
+    42501,
+    'insufficient_privilege',
+    'Enqueueing a tracking job should fail because requesting user is not the owner'
+);
+select throws_ok(
+    $$ select enqueue_tracking_job('00000000-0000-0000-0000-000000000002', 'repo2') $$,
+    42501,
+    'insufficient_privilege',
+    'Enqueueing a tracking job should fail because requesting user does not belong to owning organization'
+);
+select isnt(
+    enqueue_tracking_job(:'user1ID', 'repo1'),
+    null,
+    'Tracking job should be enqueued by user who owns the repository'
+);
+select results_eq(
+    $$
+        select status, scheduled_at
+        from tracking_job
+        where repository_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values ('pending', current_timestamp)
+    $$,
+    'Tracking job enqueued should be ready to be run'
+);
+select is(
+    enqueue_tracking_job(:'user1ID', 'repo2'),
+    :'job1ID',
+    'Pending tracking job should be returned instead of registering a new one'
+);
+select results_eq(
+    $$
+        select tracking_job_id, scheduled_at
+        from tracking_job
+        where repository_id = '00000000-0000-0000-0000-000000000002'
+    $$,
+    $$
+        values ('00000000-0000-0000-0000-000000000001'::uuid, current_timestamp)
+    $$,
+    'Pending tracking job should have been rescheduled to run now'
+);
+update tracking_job set status = 'running', lease_expires_at = current_timestamp + '1 hour'::interval
+where tracking_job_id = :'job1ID';
+select isnt(
+    enqueue_tracking_job(:'user1ID', 'repo2'),
+    :'job1ID',
+    'A new tracking job should be registered when the existing one is running'
+);
+select results_eq(
+    $$
+        select status, scheduled_at
+        from tracking_job
+        where repository_id = '00000000-0000-0000-0000-000000000002'
+        order by status
+    $$,
+    $$
+        values
+            ('pending', current_timestamp),
+            ('running', current_timestamp)
+    $$,
+    'New tracking job should be pending behind the running one'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,55 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set job1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'user1ID');
+insert into tracking_job (tracking_job_id, repository_id, scheduled_at)
+values (:'job1ID', :'repo2ID', current_timestamp + '1 hour'::interval);
+
+-- Run some tests
+select lives_ok(
+    $$
+        select enqueue_tracking_jobs(array[
+            '00000000-0000-0000-0000-000000000001',
+            '00000000-0000-0000-0000-000000000002'
+        ]::uuid[])
+    $$,
+    'Tracking jobs should be enqueued'
+);
+select results_eq(
+    $$
+        select status, scheduled_at
+        from tracking_job
+        where repository_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values ('pending', current_timestamp)
+    $$,
+    'Tracking job enqueued for repo1 should be ready to be run'
+);
+select results_eq(
+    $$
+        select tracking_job_id, scheduled_at
+        from tracking_job
+        where repository_id = '00000000-0000-0000-0000-000000000002'
+    $$,
+    $$
+        values ('00000000-0000-0000-0000-000000000001'::uuid, current_timestamp)
+    $$,
+    'Pending tracking job for repo2 should have been rescheduled to run now'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,89 @@
+-- Start transaction and plan tests
+begin;
+select plan(7);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set job1ID '00000000-0000-0000-0000-000000000001'
+\set job2ID '00000000-0000-0000-0000-000000000002'
+\set job3ID '00000000-0000-0000-0000-000000000003'
+\set lease2Token '00000000-0000-0000-0000-000000000002'
+\set lease3Token '00000000-0000-0000-0000-000000000003'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, tracking_failures)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID', 2);
+insert into tracking_job (tracking_job_id, repository_id, status, finished_at)
+values (:'job1ID', :'repo1ID', 'succeeded', current_timestamp - '8 days'::interval);
+insert into tracking_job (tracking_job_id, repository_id, status, lease_expires_at, lease_token)
+values (:'job2ID', :'repo1ID', 'running', current_timestamp + '1 hour'::interval, :'lease2Token');
+
+-- Job leased with a different token
+select throws_ok(
+    $$
+        select finish_tracking_job('{
+            "tracking_job_id": "00000000-0000-0000-0000-000000000002",
+            "status": "succeeded",
+            "lease_token": "00000000-0000-0000-0000-000000000009"
+        }')
+    $$,
+    'tracking job not leased with the token provided',
+    'Job leased with a different token should not be finished'
+);
+select is(status, 'running', 'Job leased with a different token should still be running')
+from tracking_job where tracking_job_id = :'job2ID';
+
+-- Failed job
+select finish_tracking_job('{
+    "tracking_job_id": "00000000-0000-0000-0000-000000000002",
+    "status": "failed",
+    "errors": "error cloning repository",
+    "lease_token": "00000000-0000-0000-0000-000000000002"
+}');
+select results_eq(
+    $$
+        select status, finished_at, lease_expires_at, errors
+        from tracking_job
+        where tracking_job_id = '00000000-0000-0000-0000-000000000002'
+    $$,
+    $$
+        values ('failed', current_timestamp, null::timestamptz, 'error cloning repository')
+    $$,
+    'Job should be marked as failed'
+);
+select is(tracking_failures, 3, 'Repository tracking failures should be incremented')
+from repository where repository_id = :'repo1ID';
+select is_empty(
+    $$ select * from tracking_job where tracking_job_id = '00000000-0000-0000-0000-000000000001' $$,
+    'Jobs finished more than a week ago should be deleted'
+);
+
+-- Succeeded job
+insert into tracking_job (tracking_job_id, repository_id, status, lease_token)
+values (:'job3ID', :'repo1ID', 'running', :'lease3Token');
+select finish_tracking_job('{
+    "tracking_job_id": "00000000-0000-0000-0000-000000000003",
+    "status": "succeeded",
+    "packages_added": 2,
+    "packages_removed": 1,
+    "lease_token": "00000000-0000-0000-0000-000000000003"
+}');
+select results_eq(
+    $$
+        select status, errors, packages_added, packages_removed
+        from tracking_job
+        where tracking_job_id = '00000000-0000-0000-0000-000000000003'
+    $$,
+    $$
+        values ('succeeded', null::text, 2, 1)
+    $$,
+    'Job should be marked as succeeded'
+);
+select is(tracking_failures, 0, 'Repository tracking failures should be reset')
+from repository where repository_id = :'repo1ID';
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
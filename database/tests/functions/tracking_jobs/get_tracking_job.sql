@@ -0,0 +1,82 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set job1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into tracking_job (
+    tracking_job_id,
+    repository_id,
+    status,
+    created_at,
+    scheduled_at,
+    started_at,
+    finished_at,
+    errors,
+    packages_added,
+    packages_removed
+) values (
+    :'job1ID',
+    :'repo1ID',
+    'failed',
+    '2020-06-16 11:20:33+02',
+    '2020-06-16 11:20:34+02',
+    '2020-06-16 11:20:35+02',
+    '2020-06-16 11:20:36+02',
+    'error1',
+    2,
+    1
+);
+
+-- Run some tests
+select throws_ok(
+    $$
+        select get_tracking_job(
+            '00000000-0000-0000-0000-000000000002',
+            'repo1',
+            '00000000-0000-0000-0000-000000000001'
+        )
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Getting a tracking job should fail because requesting user is not the owner'
+);
+select is_empty(
+    $$
+        select get_tracking_job(
+            '00000000-0000-0000-0000-000000000001',
+            'repo1',
+            '00000000-0000-0000-0000-000000000002'
+        )
+    $$,
+    'If tracking job requested does not exist no rows are returned'
+);
+select is(
+    get_tracking_job(:'user1ID', 'repo1', :'job1ID')::jsonb,
+    '{
+        "tracking_job_id": "00000000-0000-0000-0000-000000000001",
+        "repository_id": "00000000-0000-0000-0000-000000000001",
+        "status": "failed",
+        "created_at": 1592299233,
+        "scheduled_at": 1592299234,
+        "started_at": 1592299235,
+        "finished_at": 1592299236,
+        "errors": "error1",
+        "packages_added": 2,
+        "packages_removed": 1
+    }'::jsonb,
+    'Tracking job requested should be returned as a json object'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
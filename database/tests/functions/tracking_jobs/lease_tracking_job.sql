@@ -0,0 +1,88 @@
+-- Start transaction and plan tests
+begin;
+select plan(9);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set job1ID '00000000-0000-0000-0000-000000000001'
+\set job2ID '00000000-0000-0000-0000-000000000002'
+\set job3ID '00000000-0000-0000-0000-000000000003'
+
+-- No jobs available yet
+select is_empty(
+    $$ select lease_tracking_job('1 hour')::jsonb $$,
+    'Should not return a job'
+);
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'user1ID');
+insert into tracking_job (tracking_job_id, repository_id, scheduled_at)
+values (:'job1ID', :'repo1ID', '2020-06-16 11:20:34+02');
+insert into tracking_job (tracking_job_id, repository_id, scheduled_at)
+values (:'job2ID', :'repo2ID', current_timestamp + '1 hour'::interval);
+
+-- Run some tests
+select is(
+    lease_tracking_job('1 hour')::jsonb - 'started_at' - 'lease_token',
+    '{
+        "tracking_job_id": "00000000-0000-0000-0000-000000000001",
+        "repository_id": "00000000-0000-0000-0000-000000000001",
+        "status": "running",
+        "scheduled_at": 1592299234
+    }'::jsonb,
+    'Job ready to be run should be returned'
+);
+select results_eq(
+    $$
+        select status, lease_expires_at
+        from tracking_job
+        where tracking_job_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values ('running', current_timestamp + '1 hour'::interval)
+    $$,
+    'Job should be marked as running and leased'
+);
+select isnt(lease_token, null, 'Job lease token should be set')
+from tracking_job where tracking_job_id = :'job1ID';
+select is_empty(
+    $$ select lease_tracking_job('1 hour')::jsonb $$,
+    'Jobs leased or not ready yet should not be returned'
+);
+insert into tracking_job (tracking_job_id, repository_id, scheduled_at)
+values (:'job3ID', :'repo1ID', current_timestamp - '1 minute'::interval);
+select is_empty(
+    $$ select lease_tracking_job('1 hour')::jsonb $$,
+    'Pending jobs of repositories with a job running should not be returned'
+);
+delete from tracking_job where tracking_job_id = :'job3ID';
+update tracking_job set lease_expires_at = current_timestamp - '1 minute'::interval
+where tracking_job_id = :'job1ID';
+select is(
+    (lease_tracking_job('1 hour')::jsonb)->>'tracking_job_id',
+    '00000000-0000-0000-0000-000000000001',
+    'Job whose lease has expired should be returned again'
+);
+update tracking_job set lease_expires_at = current_timestamp - '1 minute'::interval
+where tracking_job_id = :'job1ID';
+insert into tracking_job (tracking_job_id, repository_id, scheduled_at)
+values (:'job3ID', :'repo1ID', current_timestamp - '1 minute'::interval);
+select is(
+    (lease_tracking_job('1 hour')::jsonb)->>'tracking_job_id',
+    '00000000-0000-0000-0000-000000000001',
+    'Oldest job of the repository should be leased first'
+);
+select is_empty(
+    $$ select lease_tracking_job('1 hour')::jsonb $$,
+    'Pending job of a repository whose expired job was just leased should not be returned'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,42 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set job1ID '00000000-0000-0000-0000-000000000001'
+\set lease1Token '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into tracking_job (tracking_job_id, repository_id, status, lease_expires_at, lease_token)
+values (:'job1ID', :'repo1ID', 'running', current_timestamp + '1 minute'::interval, :'lease1Token');
+
+-- Run some tests
+select throws_ok(
+    $$
+        select renew_tracking_job_lease(
+            '00000000-0000-0000-0000-000000000001',
+            '00000000-0000-0000-0000-000000000009',
+            '1 hour'
+        )
+    $$,
+    'tracking job not leased with the token provided',
+    'Job leased with a different token should not be renewed'
+);
+select renew_tracking_job_lease(:'job1ID', :'lease1Token', '1 hour');
+select is(
+    lease_expires_at,
+    current_timestamp + '1 hour'::interval,
+    'Job lease should be extended'
+)
+from tracking_job where tracking_job_id = :'job1ID';
+select is(status, 'running', 'Job should still be running')
+from tracking_job where tracking_job_id = :'job1ID';
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
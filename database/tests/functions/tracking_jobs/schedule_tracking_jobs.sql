@@ -0,0 +1,64 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set repo3ID '00000000-0000-0000-0000-000000000003'
+\set repo4ID '00000000-0000-0000-0000-000000000004'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, tracking_interval)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'user1ID', '1 hour');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, tracking_failures)
+values (:'repo3ID', 'repo3', 'Repo 3', 'https://repo3.com', 0, :'user1ID', 3);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, disabled)
+values (:'repo4ID', 'repo4', 'Repo 4', 'https://repo4.com', 0, :'user1ID', true);
+insert into tracking_job (repository_id, status, finished_at)
+values (:'repo2ID', 'succeeded', current_timestamp - '10 minutes'::interval);
+insert into tracking_job (repository_id, status, finished_at)
+values (:'repo3ID', 'failed', current_timestamp - '10 minutes'::interval);
+
+-- Run some tests
+select schedule_tracking_jobs('30 minutes', '1 hour');
+select results_eq(
+    $$
+        select repository_id, scheduled_at
+        from tracking_job
+        where status = 'pending'
+        order by repository_id asc
+    $$,
+    $$
+        values
+            ('00000000-0000-0000-0000-000000000001'::uuid, current_timestamp),
+            ('00000000-0000-0000-0000-000000000002'::uuid, current_timestamp + '50 minutes'::interval),
+            ('00000000-0000-0000-0000-000000000003'::uuid, current_timestamp + '50 minutes'::interval)
+    $$,
+    'Pending jobs should be scheduled for enabled repositories using their interval and backoff'
+);
+select is_empty(
+    $$ select * from tracking_job where repository_id = '00000000-0000-0000-0000-000000000004' $$,
+    'No jobs should be scheduled for disabled repositories'
+);
+select schedule_tracking_jobs('30 minutes', '1 hour');
+select is(
+    count(*),
+    3::bigint,
+    'No new jobs should be scheduled for repositories with a pending job'
+) from tracking_job where status = 'pending';
+update tracking_job set status = 'running' where repository_id = :'repo1ID' and status = 'pending';
+select schedule_tracking_jobs('30 minutes', '1 hour');
+select is(
+    count(*),
+    1::bigint,
+    'No new jobs should be scheduled for repositories with a running job'
+) from tracking_job where repository_id = :'repo1ID';
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,91 @@
+-- Start transaction and plan tests
+begin;
+select plan(11);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set session1ID '\\x01'
+\set session2ID '\\x02'
+\set session3ID '\\x03'
+
+-- Seed some data
+insert into "user" (user_id, alias, email, tfa_enabled, tfa_secret, tfa_recovery_codes, tfa_failed_attempts)
+values (:'user1ID', 'user1', 'user1@email.com', true, 'secret', '{"hash1", "hash2"}', 3);
+insert into session (session_id, user_id, approved) values (:'session1ID', :'user1ID', false);
+insert into session (session_id, user_id, approved) values (:'session2ID', :'user1ID', false);
+insert into session (session_id, user_id, approved) values (:'session3ID', :'user1ID', false);
+
+-- Approve session using a passcode
+select approve_session(:'session1ID', 100, null);
+select results_eq(
+    $$ select approved from session where session_id = '\x01' $$,
+    $$ values (true) $$,
+    'Session 1 should have been approved'
+);
+select results_eq(
+    $$ select tfa_last_used_step, tfa_recovery_codes from "user" $$,
+    $$ values (100::bigint, '{"hash1", "hash2"}'::text[]) $$,
+    'Passcode step should have been recorded and recovery codes should not have changed'
+);
+select results_eq(
+    $$ select tfa_failed_attempts, tfa_locked_until from "user" $$,
+    $$ values (0, null::timestamptz) $$,
+    'Failed two-factor authentication attempts should have been reset'
+);
+
+-- Try to approve a session replaying a passcode
+select throws_ok(
+    $$ select approve_session('\x02', 100, null) $$,
+    '28P01',
+    'invalid_password',
+    'Passcodes should not be replayed'
+);
+select results_eq(
+    $$ select approved from session where session_id = '\x02' $$,
+    $$ values (false) $$,
+    'Session 2 should not have been approved'
+);
+
+-- Approve session using a recovery code
+select approve_session(:'session2ID', null, 'hash1');
+select results_eq(
+    $$ select approved from session where session_id = '\x02' $$,
+    $$ values (true) $$,
+    'Session 2 should have been approved'
+);
+select results_eq(
+    $$ select tfa_recovery_codes from "user" $$,
+    $$ values ('{"hash2"}'::text[]) $$,
+    'Recovery code used should have been removed'
+);
+
+-- Try to approve a session using a recovery code already used
+select throws_ok(
+    $$ select approve_session('\x03', null, 'hash1') $$,
+    '28P01',
+    'invalid_password',
+    'Recovery codes should only be used once'
+);
+select results_eq(
+    $$ select approved from session where session_id = '\x03' $$,
+    $$ values (false) $$,
+    'Session 3 should not have been approved'
+);
+
+-- Try to approve a session already approved or that does not exist
+select throws_ok(
+    $$ select approve_session('\x01', 101, null) $$,
+    'P0002',
+    'no_data_found',
+    'Sessions already approved cannot be approved again'
+);
+select throws_ok(
+    $$ select approve_session('\x05', 101, null) $$,
+    'P0002',
+    'no_data_found',
+    'Sessions that do not exist cannot be approved'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
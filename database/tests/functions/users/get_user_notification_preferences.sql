@@ -0,0 +1,66 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email, delivery_preference_id)
+values (:'user2ID', 'user2', 'user2@email.com', 2);
+
+-- Run some tests
+select is(
+    get_user_notification_preferences(:'user1ID')::jsonb,
+    '{
+        "event_kinds": [
+            {"event_kind": 0, "email": true, "digest": false, "webhook": true},
+            {"event_kind": 1, "email": true, "digest": false, "webhook": true},
+            {"event_kind": 2, "email": true, "digest": false, "webhook": true},
+            {"event_kind": 3, "email": true, "digest": false, "webhook": true}
+        ]
+    }'::jsonb,
+    'Default notification preferences should be returned for user1'
+);
+select is(
+    get_user_notification_preferences(:'user2ID')::jsonb,
+    '{
+        "event_kinds": [
+            {"event_kind": 0, "email": false, "digest": true, "webhook": true},
+            {"event_kind": 1, "email": false, "digest": true, "webhook": true},
+            {"event_kind": 2, "email": true, "digest": false, "webhook": true},
+            {"event_kind": 3, "email": true, "digest": false, "webhook": true}
+        ]
+    }'::jsonb,
+    'Packages notifications should be delivered in a digest by default for user2'
+);
+
+-- Set up some preferences for user1
+insert into user_notification_preference (user_id, event_kind_id, email, digest, webhook)
+values (:'user1ID', 2, false, false, false);
+update "user" set quiet_hours = '{"start": "22:00", "end": "07:00", "time_zone": "Europe/Madrid"}'
+where user_id = :'user1ID';
+select is(
+    get_user_notification_preferences(:'user1ID')::jsonb,
+    '{
+        "event_kinds": [
+            {"event_kind": 0, "email": true, "digest": false, "webhook": true},
+            {"event_kind": 1, "email": true, "digest": false, "webhook": true},
+            {"event_kind": 2, "email": false, "digest": false, "webhook": false},
+            {"event_kind": 3, "email": true, "digest": false, "webhook": true}
+        ],
+        "quiet_hours": {
+            "start": "22:00",
+            "end": "07:00",
+            "time_zone": "Europe/Madrid"
+        }
+    }'::jsonb,
+    'Notification preferences set up by user1 should be returned'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
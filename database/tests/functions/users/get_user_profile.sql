@@ -32,7 +32,9 @@ select is(
         "first_name": "firstname",
         "last_name": "lastname",
         "email": "user1@email.com",
-        "profile_image_id": "00000000-0000-0000-0000-000000000001"
+        "profile_image_id": "00000000-0000-0000-0000-000000000001",
+        "delivery_preference": 0,
+        "tfa_enabled": false
     }
     '::jsonb,
     'User1 should exist'
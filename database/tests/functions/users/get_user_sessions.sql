@@ -0,0 +1,57 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user2ID', 'user2', 'user2@email.com');
+insert into session (session_id, user_id, ip, user_agent, created_at, last_seen_at)
+values ('session1', :'user1ID', '192.168.1.100', 'Safari 13.0.5', current_timestamp - '2 days'::interval, current_timestamp - '1 hour'::interval);
+insert into session (session_id, user_id, ip, user_agent, created_at, last_seen_at)
+values ('session2', :'user1ID', '192.168.1.101', 'Firefox 75.0', current_timestamp - '1 day'::interval, current_timestamp - '1 minute'::interval);
+insert into session (session_id, user_id, created_at, last_seen_at)
+values ('session3', :'user1ID', current_timestamp - '10 days'::interval, current_timestamp - '8 days'::interval);
+insert into session (session_id, user_id, created_at, last_seen_at)
+values ('session4', :'user1ID', current_timestamp - '40 days'::interval, current_timestamp - '1 minute'::interval);
+insert into session (session_id, user_id, approved)
+values ('session5', :'user1ID', false);
+insert into session (session_id, user_id)
+values ('session6', :'user2ID');
+
+-- Run some tests
+select is(
+    (
+        select jsonb_agg(s - 'created_at' - 'last_seen_at')
+        from jsonb_array_elements(get_user_sessions(:'user1ID', 'session1', 604800, 2592000)::jsonb) s
+    ),
+    jsonb_build_array(
+        jsonb_build_object(
+            'session_id', encode(digest('session2', 'sha256'), 'hex'),
+            'ip', '192.168.1.101',
+            'user_agent', 'Firefox 75.0',
+            'current', false
+        ),
+        jsonb_build_object(
+            'session_id', encode(digest('session1', 'sha256'), 'hex'),
+            'ip', '192.168.1.100',
+            'user_agent', 'Safari 13.0.5',
+            'current', true
+        )
+    ),
+    'Active sessions 2 and 1 should be returned, session 1 being the current one'
+);
+select is(
+    get_user_sessions('00000000-0000-0000-0000-000000000003', null::bytea, 604800, 2592000)::jsonb,
+    '[]'::jsonb,
+    'No sessions should be returned for an unknown user'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
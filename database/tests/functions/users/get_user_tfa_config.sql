@@ -0,0 +1,54 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some users
+insert into "user" (user_id, alias, email, tfa_enabled, tfa_secret, tfa_recovery_codes)
+values (:'user1ID', 'user1', 'user1@email.com', true, 'secret', '{"code1", "code2"}');
+insert into "user" (user_id, alias, email)
+values (:'user2ID', 'user2', 'user2@email.com');
+
+-- Run some tests
+select is(
+    get_user_tfa_config(:'user1ID')::jsonb, '
+    {
+        "enabled": true,
+        "secret": "secret",
+        "recovery_codes": ["code1", "code2"]
+    }
+    '::jsonb,
+    'User1 two-factor authentication config should be returned'
+);
+select is(
+    get_user_tfa_config(:'user2ID')::jsonb, '
+    {
+        "enabled": false
+    }
+    '::jsonb,
+    'User2 two-factor authentication config should be returned'
+);
+update "user" set tfa_locked_until = current_timestamp + '15 minutes'::interval
+where user_id = :'user1ID';
+select is(
+    get_user_tfa_config(:'user1ID')::jsonb, '
+    {
+        "enabled": true,
+        "secret": "secret",
+        "recovery_codes": ["code1", "code2"],
+        "locked": true
+    }
+    '::jsonb,
+    'User1 should be flagged as locked out'
+);
+select is_empty(
+    $$ select get_user_tfa_config('00000000-0000-0000-0000-000000000003')::jsonb $$,
+    'User3 should not exist'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,53 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set session1ID '\\x01'
+\set session2ID '\\x02'
+
+-- Seed some data
+insert into "user" (user_id, alias, email, tfa_enabled, tfa_secret)
+values (:'user1ID', 'user1', 'user1@email.com', true, 'secret');
+insert into session (session_id, user_id, approved) values (:'session1ID', :'user1ID', false);
+insert into session (session_id, user_id, approved) values (:'session2ID', :'user1ID', false);
+
+-- Register some failed attempts using the first session
+select register_failed_tfa_attempt(:'session1ID', 3, 900);
+select register_failed_tfa_attempt(:'session1ID', 3, 900);
+select results_eq(
+    $$ select tfa_failed_attempts, tfa_locked_until is null from "user" $$,
+    $$ values (2, true) $$,
+    'Failed attempts should have been registered and user should not be locked out yet'
+);
+
+-- Register a failed attempt using a new session
+delete from session where session_id = :'session1ID';
+select register_failed_tfa_attempt(:'session2ID', 3, 900);
+select results_eq(
+    $$ select tfa_failed_attempts, tfa_locked_until > current_timestamp from "user" $$,
+    $$ values (3, true) $$,
+    'Failed attempts should be counted across sessions and user should have been locked out'
+);
+
+-- Lockout is extended on subsequent failed attempts
+update "user" set tfa_locked_until = current_timestamp - '1 minute'::interval;
+select register_failed_tfa_attempt(:'session2ID', 3, 900);
+select results_eq(
+    $$ select tfa_failed_attempts, tfa_locked_until > current_timestamp from "user" $$,
+    $$ values (4, true) $$,
+    'User should have been locked out again'
+);
+
+-- Sessions that do not exist are ignored
+select register_failed_tfa_attempt('\x03', 3, 900);
+select results_eq(
+    $$ select tfa_failed_attempts from "user" $$,
+    $$ values (4) $$,
+    'Failed attempts should not change'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,50 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email, email_verified, password)
+values (:'user1ID', 'user1', 'user1@email.com', true, 'password');
+insert into "user" (user_id, alias, email, email_verified)
+values (:'user2ID', 'user2', 'user2@email.com', true);
+
+-- Register password reset code for user with password
+select register_password_reset_code('user1@email.com') as code1 \gset
+select results_eq(
+    $$ select password_reset_code_id, user_id from password_reset_code $$,
+    $$ values (:'code1'::uuid, :'user1ID'::uuid) $$,
+    'Password reset code should be registered for user1'
+);
+
+-- Register a new password reset code for the same user
+select register_password_reset_code('user1@email.com') as code2 \gset
+select results_eq(
+    $$ select password_reset_code_id, user_id from password_reset_code $$,
+    $$ values (:'code2'::uuid, :'user1ID'::uuid) $$,
+    'Previous password reset code should have been replaced'
+);
+select isnt(
+    :'code1',
+    :'code2',
+    'New password reset code should be different'
+);
+
+-- Try to register password reset codes for users without password or unknown
+select is(
+    register_password_reset_code('user2@email.com'),
+    null::uuid,
+    'No code should be registered for users without password'
+);
+select is(
+    register_password_reset_code('unknown@email.com'),
+    null::uuid,
+    'No code should be registered for unknown emails'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,19 +1,21 @@
 -- Start transaction and plan tests
 begin;
-select plan(2);
+select plan(4);
 
--- Seed user
+-- Seed users
 insert into "user" (user_id, alias, email)
 values ('00000000-0000-0000-0000-000000000001', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email, tfa_enabled)
+values ('00000000-0000-0000-0000-000000000002', 'user2', 'user2@email.com', true);
 
 -- Register session
-select register_session('
+select session_id, approved from register_session('
 {
     "user_id": "00000000-0000-0000-0000-000000000001",
     "ip": "192.168.1.100",
     "user_agent": "Safari 13.0.5"
 }
-') as session_id \gset
+') \gset
 
 -- Check if session registration succeeded
 select results_eq(
@@ -40,6 +42,25 @@ select is(
     'Returned session_id returned should be registered'
 )
 from session where user_id = '00000000-0000-0000-0000-000000000001';
+select is(
+    :'approved'::boolean,
+    true,
+    'Session should be approved as user has not enabled two-factor authentication'
+);
+
+-- Register session for user with two-factor authentication enabled
+select * from register_session('
+{
+    "user_id": "00000000-0000-0000-0000-000000000002",
+    "ip": "192.168.1.100",
+    "user_agent": "Safari 13.0.5"
+}
+') \gset
+select results_eq(
+    $$ select approved from session where user_id = '00000000-0000-0000-0000-000000000002' $$,
+    $$ values (false) $$,
+    'Session should not be approved until the two-factor authentication passcode is provided'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
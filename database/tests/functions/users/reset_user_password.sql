@@ -0,0 +1,58 @@
+-- Start transaction and plan tests
+begin;
+select plan(7);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email, email_verified, password)
+values (:'user1ID', 'user1', 'user1@email.com', true, 'password');
+insert into session (user_id) values (:'user1ID');
+insert into session (user_id) values (:'user1ID');
+select register_password_reset_code('user1@email.com') as code \gset
+
+-- Reset password
+select is(
+    reset_user_password(:'code', 'new-password'),
+    true,
+    'Password should be reset successfully'
+);
+select results_eq(
+    $$ select password from "user" where user_id = '00000000-0000-0000-0000-000000000001' $$,
+    $$ values ('new-password') $$,
+    'User password should have been updated'
+);
+select is_empty(
+    $$ select * from session $$,
+    'User sessions should have been deleted'
+);
+select is_empty(
+    $$ select * from password_reset_code $$,
+    'Password reset code should have been deleted'
+);
+select is(
+    reset_user_password(:'code', 'another-password'),
+    false,
+    'Password reset code should not be used twice'
+);
+
+-- Try to reset password using an expired code
+select register_password_reset_code('user1@email.com') as code2 \gset
+update password_reset_code
+set created_at = created_at - '2 hours'::interval
+where password_reset_code_id = :'code2';
+select is(
+    reset_user_password(:'code2', 'another-password'),
+    false,
+    'Password should not be reset as code is expired'
+);
+select results_eq(
+    $$ select password from "user" where user_id = '00000000-0000-0000-0000-000000000001' $$,
+    $$ values ('new-password') $$,
+    'User password should not have been updated'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,77 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed user
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+
+-- Update user notification preferences
+select update_user_notification_preferences(:'user1ID', '
+{
+    "event_kinds": [
+        {"event_kind": 0, "email": false, "digest": true, "webhook": true},
+        {"event_kind": 2, "email": false, "webhook": false}
+    ],
+    "quiet_hours": {
+        "start": "22:00",
+        "end": "07:00",
+        "time_zone": "Europe/Madrid"
+    }
+}
+'::jsonb);
+
+-- Run some tests
+select results_eq(
+    $$
+        select event_kind_id, email, digest, webhook
+        from user_notification_preference
+        where user_id = '00000000-0000-0000-0000-000000000001'
+        order by event_kind_id asc
+    $$,
+    $$
+        values
+            (0, false, true, true),
+            (2, false, false, false)
+    $$,
+    'Event kinds notification preferences should have been registered'
+);
+select is(
+    (select quiet_hours from "user" where user_id = :'user1ID'),
+    '{"start": "22:00", "end": "07:00", "time_zone": "Europe/Madrid"}'::jsonb,
+    'Quiet hours should have been registered'
+);
+
+-- Update them again
+select update_user_notification_preferences(:'user1ID', '
+{
+    "event_kinds": [
+        {"event_kind": 0, "email": true, "digest": false, "webhook": true}
+    ]
+}
+'::jsonb);
+select results_eq(
+    $$
+        select event_kind_id, email, digest, webhook
+        from user_notification_preference
+        where user_id = '00000000-0000-0000-0000-000000000001'
+        order by event_kind_id asc
+    $$,
+    $$
+        values
+            (0, true, false, true),
+            (2, false, false, false)
+    $$,
+    'Event kinds notification preferences should have been updated'
+);
+select is(
+    (select quiet_hours from "user" where user_id = :'user1ID'),
+    null::jsonb,
+    'Quiet hours should have been removed'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
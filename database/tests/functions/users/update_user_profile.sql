@@ -30,7 +30,8 @@ select update_user_profile(:'user1ID', '
     "alias": "user1 updated",
     "first_name": "firstname updated",
     "last_name": "lastname updated",
-    "profile_image_id": "00000000-0000-0000-0000-000000000002"
+    "profile_image_id": "00000000-0000-0000-0000-000000000002",
+    "delivery_preference": 1
 }
 '::jsonb);
 
@@ -43,7 +44,8 @@ select results_eq(
             last_name,
             email,
             password,
-            profile_image_id
+            profile_image_id,
+            delivery_preference_id
         from "user"
     $$,
     $$
@@ -53,7 +55,8 @@ select results_eq(
             'lastname updated',
             'user1@email.com',
             'password',
-            '00000000-0000-0000-0000-000000000002'::uuid
+            '00000000-0000-0000-0000-000000000002'::uuid,
+            1
         )
     $$,
     'User first and last name should have been updated'
@@ -1,9 +1,10 @@
 -- Start transaction and plan tests
 begin;
-select plan(5);
+select plan(8);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
+\set serviceAccountID '00000000-0000-0000-0000-000000000009'
 \set org1ID '00000000-0000-0000-0000-000000000001'
 \set repo1ID '00000000-0000-0000-0000-000000000001'
 \set package1ID '00000000-0000-0000-0000-000000000001'
@@ -14,6 +15,9 @@ values (:'user1ID', 'user1', 'user1@email.com');
 insert into organization (organization_id, name, display_name, description, home_url)
 values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
 insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into "user" (user_id, alias, email, service_account)
+values (:'serviceAccountID', 'org1-sa-00000009', 'org1-sa-00000009@service-account.invalid', true);
+insert into user__organization (user_id, organization_id, confirmed) values(:'serviceAccountID', :'org1ID', true);
 insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
 values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
 insert into package (package_id, name, latest_version, repository_id)
@@ -28,12 +32,22 @@ select add_webhook(:'user1ID', null, '
     "secret": "very",
     "content_type": "application/json",
     "template": "custom payload",
+    "legacy_secret_header": true,
+    "kind": 1,
+    "filters": {
+        "exclude_prereleases": true
+    },
     "active": true,
-    "event_kinds": [0],
+    "event_kinds": [0, 2],
     "packages": [
         {
             "package_id": "00000000-0000-0000-0000-000000000001"
         }
+    ],
+    "repositories": [
+        {
+            "repository_id": "00000000-0000-0000-0000-000000000001"
+        }
     ]
 }
 '::jsonb);
@@ -46,6 +60,9 @@ select results_eq(
             secret,
             content_type,
             template,
+            legacy_secret_header,
+            webhook_kind_id,
+            filters,
             active,
             user_id,
             organization_id
@@ -61,6 +78,9 @@ select results_eq(
             'application/json',
             'custom payload',
             true,
+            1,
+            '{"exclude_prereleases": true}'::jsonb,
+            true,
             '00000000-0000-0000-0000-000000000001'::uuid,
             null::uuid
         )
@@ -75,9 +95,9 @@ select results_eq(
         where w.name = 'webhook1'
     $$,
     $$
-        values (0)
+        values (0), (2)
     $$,
-    'Webhook1 should be linked to new release event'
+    'Webhook1 should be linked to new release and tracking errors events'
 );
 select results_eq(
     $$
@@ -91,6 +111,18 @@ select results_eq(
     $$,
     'Webhook1 should be linked to package1'
 );
+select results_eq(
+    $$
+        select repository_id
+        from webhook__repository wr
+        join webhook w using (webhook_id)
+        where w.name = 'webhook1'
+    $$,
+    $$
+        values ('00000000-0000-0000-0000-000000000001'::uuid)
+    $$,
+    'Webhook1 should be linked to repo1'
+);
 
 -- When an owning user and organization are provided, the organization takes precedence
 select add_webhook(:'user1ID', 'org1', '
@@ -139,6 +171,44 @@ select throws_ok(
     'User not belonging to organization should not be able to webhooks in its name'
 );
 
+-- Add webhook owned by organization using one of its service accounts
+select throws_ok(
+    $$
+        select add_webhook('00000000-0000-0000-0000-000000000009', 'org1', '
+        {
+            "name": "webhook5",
+            "url": "http://webhook5.url",
+            "active": false
+        }
+        '::jsonb)
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Service accounts should not be able to add webhooks in the name of the organization'
+);
+
+-- Add webhook subscribed to a repository not owned by the webhook owner
+select throws_ok(
+    $$
+        select add_webhook('00000000-0000-0000-0000-000000000001', 'org1', '
+        {
+            "name": "webhook4",
+            "url": "http://webhook4.url",
+            "active": true,
+            "event_kinds": [2],
+            "repositories": [
+                {
+                    "repository_id": "00000000-0000-0000-0000-000000000001"
+                }
+            ]
+        }
+        '::jsonb)
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Webhooks should not be able to subscribe to repositories not owned by the webhook owner'
+);
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
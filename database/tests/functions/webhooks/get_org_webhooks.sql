@@ -1,9 +1,10 @@
 -- Start transaction and plan tests
 begin;
-select plan(2);
+select plan(3);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
+\set serviceAccountID '00000000-0000-0000-0000-000000000009'
 \set org1ID '00000000-0000-0000-0000-000000000001'
 \set repo1ID '00000000-0000-0000-0000-000000000001'
 \set package1ID '00000000-0000-0000-0000-000000000001'
@@ -16,6 +17,9 @@ values (:'user1ID', 'user1', 'user1@email.com');
 insert into organization (organization_id, name, display_name, description, home_url)
 values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
 insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into "user" (user_id, alias, email, service_account)
+values (:'serviceAccountID', 'org1-sa-00000009', 'org1-sa-00000009@service-account.invalid', true);
+insert into user__organization (user_id, organization_id, confirmed) values(:'serviceAccountID', :'org1ID', true);
 insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
 values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
 insert into package (
@@ -73,8 +77,10 @@ select is(
             "name": "webhook1",
             "description": "description",
             "url": "http://webhook1.url",
-            "secret": "very",
+            "secret_set": true,
             "content_type": "application/json",
+            "legacy_secret_header": false,
+            "kind": 0,
             "template": "custom payload",
             "active": true,
             "event_kinds": [0],
@@ -109,6 +115,11 @@ select is(
     '[]',
     'No webhooks are expected as user2 does not belong to the owning org'
 );
+select is(
+    get_org_webhooks(:'serviceAccountID', 'org1')::jsonb,
+    '[]',
+    'No webhooks are expected as service accounts do not have access to them'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
@@ -69,8 +69,10 @@ select is(
             "name": "webhook1",
             "description": "description",
             "url": "http://webhook1.url",
-            "secret": "very",
+            "secret_set": true,
             "content_type": "application/json",
+            "legacy_secret_header": false,
+            "kind": 0,
             "template": "custom payload",
             "active": true,
             "event_kinds": [0],
@@ -48,6 +48,7 @@ insert into webhook (
     secret,
     content_type,
     template,
+    filters,
     active,
     user_id
 ) values (
@@ -58,11 +59,13 @@ insert into webhook (
     'very',
     'application/json',
     'custom payload',
+    '{"major_minor_only": true}',
     true,
     :'user1ID'
 );
 insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook1ID', 0);
 insert into webhook__package (webhook_id, package_id) values (:'webhook1ID', :'package1ID');
+insert into webhook__repository (webhook_id, repository_id) values (:'webhook1ID', :'repo1ID');
 insert into event (event_id, package_version, package_id, event_kind_id)
 values (:'event1ID', '1.0.0', :'package1ID', 0);
 insert into event (event_id, package_version, package_id, event_kind_id)
@@ -126,9 +129,14 @@ select is(
         "name": "webhook1",
         "description": "description",
         "url": "http://webhook1.url",
-        "secret": "very",
+        "secret_set": true,
         "content_type": "application/json",
+        "legacy_secret_header": false,
+        "kind": 0,
         "template": "custom payload",
+        "filters": {
+            "major_minor_only": true
+        },
         "active": true,
         "event_kinds": [0],
         "packages": [
@@ -153,6 +161,19 @@ select is(
                 }
             }
         ],
+        "repositories": [
+            {
+                "repository_id": "00000000-0000-0000-0000-000000000001",
+                "name": "repo1",
+                "display_name": "Repo 1",
+                "url": "https://repo1.com",
+                "private": false,
+                "kind": 0,
+                "verified_publisher": false,
+                "official": false,
+                "user_alias": "user1"
+            }
+        ],
         "last_notifications": [
             {
                 "notification_id": "00000000-0000-0000-0000-000000000002",
@@ -0,0 +1,112 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+\set webhook2ID '00000000-0000-0000-0000-000000000002'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set notification1ID '00000000-0000-0000-0000-000000000001'
+\set delivery1ID '00000000-0000-0000-0000-000000000001'
+\set delivery2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into webhook (webhook_id, name, url, user_id)
+values (:'webhook1ID', 'webhook1', 'http://webhook1.url', :'user1ID');
+insert into webhook (webhook_id, name, url, user_id)
+values (:'webhook2ID', 'webhook2', 'http://webhook2.url', :'user1ID');
+insert into event (event_id, package_version, package_id, event_kind_id)
+values (:'event1ID', '1.0.0', :'package1ID', 0);
+insert into notification (notification_id, event_id, webhook_id)
+values (:'notification1ID', :'event1ID', :'webhook1ID');
+insert into webhook_delivery (
+    webhook_delivery_id,
+    created_at,
+    attempt,
+    latency_ms,
+    error,
+    notification_id,
+    webhook_id
+) values (
+    :'delivery1ID',
+    '2020-06-16 11:20:34+02',
+    1,
+    10,
+    'connection refused',
+    :'notification1ID',
+    :'webhook1ID'
+);
+insert into webhook_delivery (
+    webhook_delivery_id,
+    created_at,
+    attempt,
+    status_code,
+    latency_ms,
+    response,
+    notification_id,
+    webhook_id
+) values (
+    :'delivery2ID',
+    '2020-06-16 11:21:34+02',
+    2,
+    200,
+    20,
+    'ok',
+    :'notification1ID',
+    :'webhook1ID'
+);
+
+-- Run some tests
+select throws_ok(
+    $$
+        select get_webhook_deliveries(
+            '00000000-0000-0000-0000-000000000002',
+            '00000000-0000-0000-0000-000000000001'
+        )
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Webhook deliveries get should fail because requesting user is not the owner'
+);
+select is(
+    get_webhook_deliveries(:'user1ID', :'webhook1ID')::jsonb,
+    '[
+        {
+            "webhook_delivery_id": "00000000-0000-0000-0000-000000000002",
+            "notification_id": "00000000-0000-0000-0000-000000000001",
+            "created_at": 1592299294,
+            "attempt": 2,
+            "status_code": 200,
+            "latency_ms": 20,
+            "response": "ok"
+        },
+        {
+            "webhook_delivery_id": "00000000-0000-0000-0000-000000000001",
+            "notification_id": "00000000-0000-0000-0000-000000000001",
+            "created_at": 1592299234,
+            "attempt": 1,
+            "latency_ms": 10,
+            "error": "connection refused"
+        }
+    ]'::jsonb,
+    'Webhook deliveries should be returned, most recent first'
+);
+select is(
+    get_webhook_deliveries(:'user1ID', :'webhook2ID')::jsonb,
+    '[]'::jsonb,
+    'No deliveries expected for webhook2'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,41 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+\set webhook2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into webhook (webhook_id, name, url, secret, user_id)
+values (:'webhook1ID', 'webhook1', 'http://webhook1.url', 'very', :'user1ID');
+insert into webhook (webhook_id, name, url, user_id)
+values (:'webhook2ID', 'webhook2', 'http://webhook2.url', :'user1ID');
+
+-- Run some tests
+select throws_ok(
+    $$
+        select get_webhook_secret('00000000-0000-0000-0000-000000000002', '00000000-0000-0000-0000-000000000001')
+    $$,
+    42501,
+    'insufficient_privilege',
+    'User2 should not be able to get the secret of a webhook owned by user1'
+);
+select is(
+    get_webhook_secret(:'user1ID', :'webhook1ID'),
+    'very',
+    'Webhook1 secret should be returned'
+);
+select is(
+    get_webhook_secret(:'user1ID', :'webhook2ID'),
+    null,
+    'Null should be returned as webhook2 has no secret'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
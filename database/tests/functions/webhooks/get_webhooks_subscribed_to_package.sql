@@ -87,9 +87,13 @@ select is(
             "description": "description",
             "url": "http://webhook1.url",
             "secret": "very",
+            "secret_set": true,
             "content_type": "application/json",
+            "legacy_secret_header": false,
+            "kind": 0,
             "template": "custom payload",
             "active": true,
+            "user_id": "00000000-0000-0000-0000-000000000001",
             "event_kinds": [0],
             "packages": [
                 {
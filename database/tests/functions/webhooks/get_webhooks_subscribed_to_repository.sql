@@ -0,0 +1,132 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+\set webhook2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'user1ID');
+insert into webhook (
+    webhook_id,
+    name,
+    description,
+    url,
+    active,
+    user_id
+) values (
+    :'webhook1ID',
+    'webhook1',
+    'description',
+    'http://webhook1.url',
+    true,
+    :'user1ID'
+);
+insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook1ID', 2);
+insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook1ID', 3);
+insert into webhook__repository (webhook_id, repository_id) values (:'webhook1ID', :'repo1ID');
+insert into webhook__repository (webhook_id, repository_id) values (:'webhook1ID', :'repo2ID');
+insert into webhook (
+    webhook_id,
+    name,
+    url,
+    active,
+    user_id
+) values (
+    :'webhook2ID',
+    'webhook2',
+    'http://webhook2.url',
+    false,
+    :'user1ID'
+);
+insert into webhook__event_kind (webhook_id, event_kind_id) values (:'webhook2ID', 2);
+insert into webhook__repository (webhook_id, repository_id) values (:'webhook2ID', :'repo1ID');
+
+-- Run some tests
+select is(
+    get_webhooks_subscribed_to_repository(2, :'repo1ID')::jsonb,
+    '[
+        {
+            "webhook_id": "00000000-0000-0000-0000-000000000001",
+            "name": "webhook1",
+            "description": "description",
+            "url": "http://webhook1.url",
+            "secret_set": false,
+            "legacy_secret_header": false,
+            "kind": 0,
+            "active": true,
+            "user_id": "00000000-0000-0000-0000-000000000001",
+            "event_kinds": [2, 3],
+            "repositories": [
+                {
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "name": "repo1",
+                    "display_name": "Repo 1",
+                    "url": "https://repo1.com",
+                    "private": false,
+                    "kind": 0,
+                    "verified_publisher": false,
+                    "official": false,
+                    "user_alias": "user1"
+                },
+                {
+                    "repository_id": "00000000-0000-0000-0000-000000000002",
+                    "name": "repo2",
+                    "display_name": "Repo 2",
+                    "url": "https://repo2.com",
+                    "private": false,
+                    "kind": 0,
+                    "verified_publisher": false,
+                    "official": false,
+                    "user_alias": "user1"
+                }
+            ]
+        }
+    ]'::jsonb,
+    'Webhook1 should be returned when asking for kind2 and repo1'
+);
+select is(
+    get_webhooks_subscribed_to_repository(1, :'repo1ID')::jsonb,
+    '[]',
+    'No webhooks should be returned for kind1 and repo1'
+);
+
+-- Transfer repo2 to user2
+update repository set user_id = :'user2ID' where repository_id = :'repo2ID';
+select is(
+    (
+        select json_agg(wh->>'webhook_id')
+        from json_array_elements(get_webhooks_subscribed_to_repository(2, :'repo2ID')) as wh
+    )::jsonb,
+    null::jsonb,
+    'No webhooks should be returned for kind2 and repo2 once it is owned by user2'
+);
+select is(
+    (
+        select json_agg(wh->>'webhook_id')
+        from json_array_elements(get_webhooks_subscribed_to_repository(3, :'repo2ID')) as wh
+    )::jsonb,
+    '["00000000-0000-0000-0000-000000000001"]'::jsonb,
+    'Webhook1 should be returned for kind3 and repo2 as it belonged to user1'
+);
+select is(
+    get_webhooks_subscribed_to_repository(3, :'repo1ID')::jsonb,
+    get_webhooks_subscribed_to_repository(2, :'repo1ID')::jsonb,
+    'Webhook1 should be returned for kind3 and repo1'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,98 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+\set webhook2ID '00000000-0000-0000-0000-000000000002'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set notification1ID '00000000-0000-0000-0000-000000000001'
+\set delivery1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into webhook (webhook_id, name, url, user_id)
+values (:'webhook1ID', 'webhook1', 'http://webhook1.url', :'user1ID');
+insert into webhook (webhook_id, name, url, user_id)
+values (:'webhook2ID', 'webhook2', 'http://webhook2.url', :'user1ID');
+insert into event (event_id, package_version, package_id, event_kind_id)
+values (:'event1ID', '1.0.0', :'package1ID', 0);
+insert into notification (
+    notification_id,
+    processed,
+    processed_at,
+    error,
+    attempts,
+    event_id,
+    webhook_id
+) values (
+    :'notification1ID',
+    true,
+    current_timestamp,
+    'unexpected status code: 500',
+    3,
+    :'event1ID',
+    :'webhook1ID'
+);
+insert into webhook_delivery (webhook_delivery_id, attempt, status_code, latency_ms, notification_id, webhook_id)
+values (:'delivery1ID', 3, 500, 10, :'notification1ID', :'webhook1ID');
+
+-- Run some tests
+select throws_ok(
+    $$
+        select redeliver_webhook_notification(
+            '00000000-0000-0000-0000-000000000002',
+            '00000000-0000-0000-0000-000000000001',
+            '00000000-0000-0000-0000-000000000001'
+        )
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Redeliver should fail because requesting user is not the owner'
+);
+select throws_ok(
+    $$
+        select redeliver_webhook_notification(
+            '00000000-0000-0000-0000-000000000001',
+            '00000000-0000-0000-0000-000000000002',
+            '00000000-0000-0000-0000-000000000001'
+        )
+    $$,
+    'P0002',
+    'no_data_found',
+    'Redeliver should fail because the delivery does not belong to the webhook'
+);
+select redeliver_webhook_notification(:'user1ID', :'webhook1ID', :'delivery1ID');
+select results_eq(
+    $$
+        select processed, processed_at, error, attempts, next_attempt_at from notification
+        where notification_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values (false, null::timestamptz, null::text, 0, null::timestamptz)
+    $$,
+    'Notification should be pending to be delivered again'
+);
+select results_eq(
+    $$
+        select count(*) from webhook_delivery
+        where notification_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values (1::bigint)
+    $$,
+    'Previous deliveries should be kept'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
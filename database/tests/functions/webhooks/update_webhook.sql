@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(6);
+select plan(10);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -73,12 +73,22 @@ select update_webhook('00000000-0000-0000-0000-000000000001', '
     "secret": "very updated",
     "content_type": "text/xml",
     "template": "custom payload updated",
+    "legacy_secret_header": true,
+    "kind": 1,
+    "filters": {
+        "version_constraint": ">=2.0.0 <3"
+    },
     "active": false,
-    "event_kinds": [1],
+    "event_kinds": [1, 2],
     "packages": [
         {
             "package_id": "00000000-0000-0000-0000-000000000002"
         }
+    ],
+    "repositories": [
+        {
+            "repository_id": "00000000-0000-0000-0000-000000000001"
+        }
     ]
 }
 '::jsonb);
@@ -91,6 +101,9 @@ select results_eq(
             secret,
             content_type,
             template,
+            legacy_secret_header,
+            webhook_kind_id,
+            filters,
             active,
             user_id,
             organization_id
@@ -105,6 +118,9 @@ select results_eq(
             'very updated',
             'text/xml',
             'custom payload updated',
+            true,
+            1,
+            '{"version_constraint": ">=2.0.0 <3"}'::jsonb,
             false,
             '00000000-0000-0000-0000-000000000001'::uuid,
             null::uuid
@@ -120,9 +136,9 @@ select results_eq(
         where w.webhook_id = '00000000-0000-0000-0000-000000000001'
     $$,
     $$
-        values (1)
+        values (1), (2)
     $$,
-    'Webhook1 should now be linked to security alert event'
+    'Webhook1 should now be linked to security alert and tracking errors events'
 );
 select results_eq(
     $$
@@ -136,6 +152,18 @@ select results_eq(
     $$,
     'Webhook1 should now be linked to package2'
 );
+select results_eq(
+    $$
+        select repository_id
+        from webhook__repository wr
+        join webhook w using (webhook_id)
+        where w.webhook_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values ('00000000-0000-0000-0000-000000000001'::uuid)
+    $$,
+    'Webhook1 should now be linked to repo1'
+);
 
 -- Update webhook owned by organization (requesting user belongs to organization)
 select update_webhook('00000000-0000-0000-0000-000000000001', '
@@ -162,6 +190,74 @@ select results_eq(
     'Webhook2 owned by org1 should have been updated'
 );
 
+-- Try to subscribe webhook to a repository not owned by the webhook owner
+select throws_ok(
+    $$
+        select update_webhook('00000000-0000-0000-0000-000000000001', '
+        {
+            "webhook_id": "00000000-0000-0000-0000-000000000002",
+            "name": "webhook2 updated",
+            "url": "http://webhook2.url/updated",
+            "event_kinds": [2],
+            "repositories": [
+                {
+                    "repository_id": "00000000-0000-0000-0000-000000000001"
+                }
+            ]
+        }
+        '::jsonb)
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Webhook update should fail because repo1 is not owned by org1'
+);
+
+-- Update webhook without providing a secret (current one is kept)
+select update_webhook('00000000-0000-0000-0000-000000000001', '
+{
+    "webhook_id": "00000000-0000-0000-0000-000000000001",
+    "name": "webhook1 updated",
+    "url": "http://webhook1.url/updated",
+    "active": true,
+    "event_kinds": [1]
+}
+'::jsonb);
+select results_eq(
+    $$
+        select secret
+        from webhook
+        where webhook_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values ('very updated')
+    $$,
+    'Webhook1 secret should have been kept when not provided'
+);
+
+-- Update webhook clearing its secret
+select update_webhook('00000000-0000-0000-0000-000000000001', '
+{
+    "webhook_id": "00000000-0000-0000-0000-000000000001",
+    "name": "webhook1 updated",
+    "url": "http://webhook1.url/updated",
+    "secret": "ignored",
+    "clear_secret": true,
+    "active": true,
+    "event_kinds": [1]
+}
+'::jsonb);
+select results_eq(
+    $$
+        select secret
+        from webhook
+        where webhook_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values (null::text)
+    $$,
+    'Webhook1 secret should have been cleared'
+);
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
@@ -1,11 +1,12 @@
 -- Start transaction and plan tests
 begin;
-select plan(4);
+select plan(5);
 
 -- Declare some variables
 \set org1ID '00000000-0000-0000-0000-000000000001'
 \set user1ID '00000000-0000-0000-0000-000000000001'
 \set user2ID '00000000-0000-0000-0000-000000000002'
+\set serviceAccountID '00000000-0000-0000-0000-000000000009'
 \set webhook1ID '00000000-0000-0000-0000-000000000001'
 \set webhook2ID '00000000-0000-0000-0000-000000000002'
 
@@ -14,6 +15,9 @@ insert into organization (organization_id, name, display_name, description, home
 values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
 insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
 insert into user__organization (user_id, organization_id) values(:'user1ID', :'org1ID');
+insert into "user" (user_id, alias, email, service_account)
+values (:'serviceAccountID', 'org1-sa-00000009', 'org1-sa-00000009@service-account.invalid', true);
+insert into user__organization (user_id, organization_id, confirmed) values(:'serviceAccountID', :'org1ID', true);
 insert into webhook (webhook_id, name, url, user_id)
 values (:'webhook1ID', 'webhook1', 'http://webhook1.url', :'user1ID');
 insert into webhook (webhook_id, name, url, organization_id)
@@ -42,6 +46,11 @@ select is(
     false,
     'No, as user1 is the owner '
 );
+select is(
+    user_has_access_to_webhook(:'serviceAccountID', :'webhook2ID'),
+    false,
+    'No, as service accounts do not have access to the organization webhooks'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
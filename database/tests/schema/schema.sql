@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(133);
+select plan(196);
 
 -- Check default_text_search_config is correct
 select results_eq(
@@ -15,6 +15,7 @@ select has_extension('pgcrypto');
 -- Check expected tables exist
 select tables_are(array[
     'api_key',
+    'delivery_preference',
     'email_verification_code',
     'event',
     'event_kind',
@@ -24,21 +25,30 @@ select tables_are(array[
     'notification',
     'opt_out',
     'organization',
+    'organization_subscription',
     'package',
     'package__maintainer',
+    'package_opt_out',
+    'password_reset_code',
     'repository',
     'repository_kind',
+    'repository_subscription',
     'session',
     'snapshot',
     'subscription',
+    'tracking_job',
     'user',
+    'user_notification_preference',
     'user_starred_package',
     'user__organization',
     'version_functions',
     'version_schema',
     'webhook',
     'webhook__event_kind',
-    'webhook__package'
+    'webhook__package',
+    'webhook__repository',
+    'webhook_delivery',
+    'webhook_kind'
 ]);
 
 -- Check tables have expected columns
@@ -47,7 +57,15 @@ select columns_are('api_key', array[
     'name',
     'key',
     'user_id',
-    'created_at'
+    'created_at',
+    'scopes',
+    'expires_at',
+    'last_used_at',
+    'organization_id'
+]);
+select columns_are('delivery_preference', array[
+    'delivery_preference_id',
+    'name'
 ]);
 select columns_are('email_verification_code', array[
     'email_verification_code_id',
@@ -91,7 +109,10 @@ select columns_are('notification', array[
     'error',
     'event_id',
     'user_id',
-    'webhook_id'
+    'webhook_id',
+    'attempts',
+    'next_attempt_at',
+    'digest'
 ]);
 select columns_are('opt_out', array[
     'opt_out_id',
@@ -110,7 +131,14 @@ select columns_are('organization', array[
     'authorization_enabled',
     'predefined_policy',
     'custom_policy',
-    'policy_data'
+    'policy_data',
+    'tfa_required'
+]);
+select columns_are('organization_subscription', array[
+    'user_id',
+    'organization_id',
+    'event_kind_id',
+    'filters'
 ]);
 select columns_are('package', array[
     'package_id',
@@ -130,6 +158,17 @@ select columns_are('package__maintainer', array[
     'package_id',
     'maintainer_id'
 ]);
+select columns_are('package_opt_out', array[
+    'user_id',
+    'package_id',
+    'event_kind_id',
+    'created_at'
+]);
+select columns_are('password_reset_code', array[
+    'password_reset_code_id',
+    'user_id',
+    'created_at'
+]);
 select columns_are('repository', array[
     'repository_id',
     'name',
@@ -147,18 +186,31 @@ select columns_are('repository', array[
     'digest',
     'repository_kind_id',
     'user_id',
-    'organization_id'
+    'organization_id',
+    'tracking_interval',
+    'tracking_failures',
+    'push_webhook_secret',
+    'ssh_key'
 ]);
 select columns_are('repository_kind', array[
     'repository_kind_id',
     'name'
 ]);
+select columns_are('repository_subscription', array[
+    'user_id',
+    'repository_id',
+    'event_kind_id',
+    'filters'
+]);
 select columns_are('session', array[
     'session_id',
     'user_id',
     'ip',
     'user_agent',
-    'created_at'
+    'created_at',
+    'approved',
+    'last_seen_at',
+    'tfa_attempts'
 ]);
 select columns_are('snapshot', array[
     'package_id',
@@ -194,7 +246,22 @@ select columns_are('snapshot', array[
 select columns_are('subscription', array[
     'user_id',
     'package_id',
-    'event_kind_id'
+    'event_kind_id',
+    'filters'
+]);
+select columns_are('tracking_job', array[
+    'tracking_job_id',
+    'repository_id',
+    'status',
+    'created_at',
+    'scheduled_at',
+    'started_at',
+    'finished_at',
+    'lease_expires_at',
+    'lease_token',
+    'errors',
+    'packages_added',
+    'packages_removed'
 ]);
 select columns_are('user', array[
     'user_id',
@@ -205,7 +272,23 @@ select columns_are('user', array[
     'email_verified',
     'password',
     'profile_image_id',
-    'created_at'
+    'created_at',
+    'delivery_preference_id',
+    'quiet_hours',
+    'tfa_enabled',
+    'tfa_secret',
+    'tfa_recovery_codes',
+    'service_account',
+    'tfa_last_used_step',
+    'tfa_failed_attempts',
+    'tfa_locked_until'
+]);
+select columns_are('user_notification_preference', array[
+    'user_id',
+    'event_kind_id',
+    'email',
+    'digest',
+    'webhook'
 ]);
 select columns_are('user_starred_package', array[
     'user_id',
@@ -234,7 +317,10 @@ select columns_are('webhook', array[
     'created_at',
     'updated_at',
     'user_id',
-    'organization_id'
+    'organization_id',
+    'legacy_secret_header',
+    'webhook_kind_id',
+    'filters'
 ]);
 select columns_are('webhook__event_kind', array[
     'webhook_id',
@@ -244,11 +330,34 @@ select columns_are('webhook__package', array[
     'webhook_id',
     'package_id'
 ]);
+select columns_are('webhook__repository', array[
+    'webhook_id',
+    'repository_id'
+]);
+select columns_are('webhook_delivery', array[
+    'webhook_delivery_id',
+    'created_at',
+    'attempt',
+    'status_code',
+    'latency_ms',
+    'response',
+    'error',
+    'notification_id',
+    'webhook_id'
+]);
+select columns_are('webhook_kind', array[
+    'webhook_kind_id',
+    'name'
+]);
 
 -- Check tables have expected indexes
 select indexes_are('api_key', array[
     'api_key_pkey',
-    'api_key_user_id_idx'
+    'api_key_user_id_idx',
+    'api_key_organization_id_idx'
+]);
+select indexes_are('delivery_preference', array[
+    'delivery_preference_pkey'
 ]);
 select indexes_are('email_verification_code', array[
     'email_verification_code_pkey',
@@ -256,7 +365,8 @@ select indexes_are('email_verification_code', array[
 ]);
 select indexes_are('event', array[
     'event_pkey',
-    'event_not_processed_idx'
+    'event_not_processed_idx',
+    'event_created_at_event_id_idx'
 ]);
 select indexes_are('image', array[
     'image_pkey',
@@ -274,7 +384,8 @@ select indexes_are('notification', array[
     'notification_not_processed_idx',
     'notification_event_id_user_id_key',
     'notification_event_id_webhook_id_key',
-    'notification_webhook_id_created_at_idx'
+    'notification_webhook_id_created_at_idx',
+    'notification_user_id_not_processed_idx'
 ]);
 select indexes_are('opt_out', array[
     'opt_out_pkey',
@@ -284,6 +395,10 @@ select indexes_are('organization', array[
     'organization_pkey',
     'organization_name_key'
 ]);
+select indexes_are('organization_subscription', array[
+    'organization_subscription_pkey',
+    'organization_subscription_organization_id_idx'
+]);
 select indexes_are('package', array[
     'package_pkey',
     'package_tsdoc_idx',
@@ -294,6 +409,14 @@ select indexes_are('package', array[
 select indexes_are('package__maintainer', array[
     'package__maintainer_pkey'
 ]);
+select indexes_are('package_opt_out', array[
+    'package_opt_out_pkey',
+    'package_opt_out_package_id_idx'
+]);
+select indexes_are('password_reset_code', array[
+    'password_reset_code_pkey',
+    'password_reset_code_user_id_key'
+]);
 select indexes_are('repository', array[
     'repository_pkey',
     'repository_name_key',
@@ -305,8 +428,13 @@ select indexes_are('repository', array[
 select indexes_are('repository_kind', array[
     'repository_kind_pkey'
 ]);
+select indexes_are('repository_subscription', array[
+    'repository_subscription_pkey',
+    'repository_subscription_repository_id_idx'
+]);
 select indexes_are('session', array[
-    'session_pkey'
+    'session_pkey',
+    'session_user_id_idx'
 ]);
 select indexes_are('snapshot', array[
     'snapshot_pkey',
@@ -316,6 +444,12 @@ select indexes_are('snapshot', array[
 select indexes_are('subscription', array[
     'subscription_pkey'
 ]);
+select indexes_are('tracking_job', array[
+    'tracking_job_pkey',
+    'tracking_job_repository_id_finished_at_idx',
+    'tracking_job_scheduled_at_idx',
+    'tracking_job_pending_repository_id_idx'
+]);
 select indexes_are('user', array[
     'user_pkey',
     'user_alias_key',
@@ -324,6 +458,9 @@ select indexes_are('user', array[
 select indexes_are('user__organization', array[
     'user__organization_pkey'
 ]);
+select indexes_are('user_notification_preference', array[
+    'user_notification_preference_pkey'
+]);
 select indexes_are('user_starred_package', array[
     'user_starred_package_pkey'
 ]);
@@ -338,24 +475,49 @@ select indexes_are('webhook__event_kind', array[
 select indexes_are('webhook__package', array[
     'webhook__package_pkey'
 ]);
+select indexes_are('webhook__repository', array[
+    'webhook__repository_pkey',
+    'webhook__repository_repository_id_idx'
+]);
+select indexes_are('webhook_delivery', array[
+    'webhook_delivery_pkey',
+    'webhook_delivery_webhook_id_created_at_idx',
+    'webhook_delivery_notification_id_idx'
+]);
+select indexes_are('webhook_kind', array[
+    'webhook_kind_pkey'
+]);
 
 -- Check expected functions exist
 -- API keys
 select has_function('add_api_key');
+select has_function('add_org_api_key');
+select has_function('check_api_key');
 select has_function('delete_api_key');
+select has_function('delete_org_api_key');
 select has_function('get_api_key');
+select has_function('get_org_api_key');
+select has_function('get_org_api_keys');
 select has_function('get_user_api_keys');
 select has_function('update_api_key');
+select has_function('update_org_api_key');
 -- Authz
 select has_function('notify_authorization_policies_updates');
 -- Events
+select has_function('get_events_cursor');
 select has_function('get_pending_event');
+select has_function('get_user_events');
+select has_function('notify_event_created');
 -- Images
 select has_function('get_image');
 select has_function('register_image');
 -- Notifications
 select has_function('add_notification');
+select has_function('add_webhook_delivery');
+select has_function('get_pending_digest');
 select has_function('get_pending_notification');
+select has_function('notify_notification_created');
+select has_function('schedule_notification_retry');
 select has_function('update_notification_status');
 -- Organizations
 select has_function('add_organization');
@@ -368,9 +530,11 @@ select has_function('get_authorization_policy');
 select has_function('get_organization');
 select has_function('get_organization_members');
 select has_function('get_user_organizations');
+select has_function('regular_user_belongs_to_organization');
 select has_function('update_authorization_policy');
 select has_function('update_organization');
 select has_function('user_belongs_to_organization');
+select has_function('user_meets_organization_tfa_requirement');
 -- Packages
 select has_function('generate_package_tsdoc');
 select has_function('get_harbor_replication_dump');
@@ -394,6 +558,7 @@ select has_function('unregister_package');
 select has_function('add_repository');
 select has_function('delete_repository');
 select has_function('get_all_repositories');
+select has_function('get_repositories_by_git_url');
 select has_function('get_repositories_by_kind');
 select has_function('get_repository_by_id');
 select has_function('get_repository_by_name');
@@ -401,10 +566,14 @@ select has_function('get_repository_packages_digest');
 select has_function('get_repository_summary');
 select has_function('get_org_repositories');
 select has_function('get_user_repositories');
+select has_function('normalize_git_url');
 select has_function('set_last_tracking_results');
 select has_function('set_verified_publisher');
 select has_function('transfer_repository');
 select has_function('update_repository');
+-- Secrets
+select has_function('get_unencrypted_secrets');
+select has_function('update_secret');
 -- Subscriptions
 select has_function('add_opt_out');
 select has_function('add_subscription');
@@ -413,13 +582,32 @@ select has_function('delete_subscription');
 select has_function('get_package_subscriptors');
 select has_function('get_repository_subscriptors');
 select has_function('get_user_opt_out_entries');
+select has_function('get_user_organization_subscriptions');
 select has_function('get_user_package_subscriptions');
+select has_function('get_user_repository_subscriptions');
 select has_function('get_user_subscriptions');
+select has_function('unsubscribe');
+-- Tracking jobs
+select has_function('enqueue_tracking_job');
+select has_function('enqueue_tracking_jobs');
+select has_function('finish_tracking_job');
+select has_function('get_tracking_job');
+select has_function('lease_tracking_job');
+select has_function('renew_tracking_job_lease');
+select has_function('schedule_tracking_jobs');
 -- Users
+select has_function('approve_session');
 select has_function('check_user_alias_availability');
+select has_function('get_user_notification_preferences');
 select has_function('get_user_profile');
+select has_function('get_user_sessions');
+select has_function('get_user_tfa_config');
+select has_function('register_failed_tfa_attempt');
+select has_function('register_password_reset_code');
 select has_function('register_session');
 select has_function('register_user');
+select has_function('reset_user_password');
+select has_function('update_user_notification_preferences');
 select has_function('update_user_password');
 select has_function('update_user_profile');
 select has_function('verify_email');
@@ -429,7 +617,11 @@ select has_function('delete_webhook');
 select has_function('get_webhook');
 select has_function('get_org_webhooks');
 select has_function('get_user_webhooks');
+select has_function('get_webhook_deliveries');
+select has_function('get_webhook_secret');
 select has_function('get_webhooks_subscribed_to_package');
+select has_function('get_webhooks_subscribed_to_repository');
+select has_function('redeliver_webhook_notification');
 select has_function('update_webhook');
 select has_function('user_has_access_to_webhook');
 
@@ -461,6 +653,29 @@ select results_eq(
     'Event kinds should exist'
 );
 
+-- Check delivery preferences exist
+select results_eq(
+    'select * from delivery_preference',
+    $$ values
+        (0, 'Immediate'),
+        (1, 'Daily digest'),
+        (2, 'Weekly digest')
+    $$,
+    'Delivery preferences should exist'
+);
+
+-- Check webhook kinds exist
+select results_eq(
+    'select * from webhook_kind',
+    $$ values
+        (0, 'Generic'),
+        (1, 'Slack'),
+        (2, 'Microsoft Teams'),
+        (3, 'Discord')
+    $$,
+    'Webhook kinds should exist'
+);
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
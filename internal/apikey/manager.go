@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/util"
@@ -12,43 +13,70 @@ import (
 
 const (
 	// Database queries
-	addAPIKeyDBQ      = `select add_api_key($1::jsonb)`
-	deleteAPIKeyDBQ   = `select delete_api_key($1::uuid, $2::uuid)`
-	getAPIKeyDBQ      = `select get_api_key($1::uuid, $2::uuid)`
-	getUserAPIKeysDBQ = `select get_user_api_keys($1::uuid)`
-	updateAPIKeyDBQ   = `select update_api_key($1::jsonb)`
+	addAPIKeyDBQ       = `select add_api_key($1::jsonb)`
+	addOrgAPIKeyDBQ    = `select add_org_api_key($1::uuid, $2::text, $3::jsonb)`
+	deleteAPIKeyDBQ    = `select delete_api_key($1::uuid, $2::uuid)`
+	deleteOrgAPIKeyDBQ = `select delete_org_api_key($1::uuid, $2::text, $3::uuid)`
+	getAPIKeyDBQ       = `select get_api_key($1::uuid, $2::uuid)`
+	getOrgAPIKeyDBQ    = `select get_org_api_key($1::uuid, $2::text, $3::uuid)`
+	getOrgAPIKeysDBQ   = `select get_org_api_keys($1::uuid, $2::text)`
+	getUserAPIKeysDBQ  = `select get_user_api_keys($1::uuid)`
+	updateAPIKeyDBQ    = `select update_api_key($1::jsonb)`
+	updateOrgAPIKeyDBQ = `select update_org_api_key($1::uuid, $2::text, $3::jsonb)`
 )
 
 // Manager provides an API to manage api keys.
 type Manager struct {
 	db hub.DB
+	az hub.Authorizer
 }
 
 // NewManager creates a new Manager instance.
-func NewManager(db hub.DB) *Manager {
+func NewManager(db hub.DB, az hub.Authorizer) *Manager {
 	return &Manager{
 		db: db,
+		az: az,
 	}
 }
 
-// Add adds the provided api key to the database.
-func (m *Manager) Add(ctx context.Context, ak *hub.APIKey) ([]byte, error) {
+// Add adds the provided api key to the database. When an organization name is
+// provided, the api key will be owned by the organization.
+func (m *Manager) Add(ctx context.Context, orgName string, ak *hub.APIKey) ([]byte, error) {
 	ak.UserID = ctx.Value(hub.UserIDKey).(string)
 
 	// Validate input
 	if ak.Name == "" {
 		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
 	}
+	for _, scope := range ak.Scopes {
+		if !isValidScope(scope) {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid scope")
+		}
+	}
+	if ak.ExpiresAt != 0 && ak.ExpiresAt <= time.Now().Unix() {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "expiration date must be in the future")
+	}
 
 	// Add api key to the database
 	akJSON, _ := json.Marshal(ak)
 	var key []byte
-	err := m.db.QueryRow(ctx, addAPIKeyDBQ, akJSON).Scan(&key)
+	var err error
+	if orgName != "" {
+		if err := m.authorize(ctx, orgName); err != nil {
+			return nil, err
+		}
+		err = m.db.QueryRow(ctx, addOrgAPIKeyDBQ, ak.UserID, orgName, akJSON).Scan(&key)
+	} else {
+		err = m.db.QueryRow(ctx, addAPIKeyDBQ, akJSON).Scan(&key)
+	}
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return nil, hub.ErrInsufficientPrivilege
+	}
 	return key, err
 }
 
 // Delete deletes the provided api key from the database.
-func (m *Manager) Delete(ctx context.Context, apiKeyID string) error {
+func (m *Manager) Delete(ctx context.Context, orgName, apiKeyID string) error {
 	userID := ctx.Value(hub.UserIDKey).(string)
 
 	// Validate input
@@ -57,12 +85,23 @@ func (m *Manager) Delete(ctx context.Context, apiKeyID string) error {
 	}
 
 	// Delete api key from database
-	_, err := m.db.Exec(ctx, deleteAPIKeyDBQ, userID, apiKeyID)
+	var err error
+	if orgName != "" {
+		if err := m.authorize(ctx, orgName); err != nil {
+			return err
+		}
+		_, err = m.db.Exec(ctx, deleteOrgAPIKeyDBQ, userID, orgName, apiKeyID)
+	} else {
+		_, err = m.db.Exec(ctx, deleteAPIKeyDBQ, userID, apiKeyID)
+	}
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
 	return err
 }
 
 // GetJSON returns the requested api key as a json object.
-func (m *Manager) GetJSON(ctx context.Context, apiKeyID string) ([]byte, error) {
+func (m *Manager) GetJSON(ctx context.Context, orgName, apiKeyID string) ([]byte, error) {
 	userID := ctx.Value(hub.UserIDKey).(string)
 
 	// Validate input
@@ -71,9 +110,34 @@ func (m *Manager) GetJSON(ctx context.Context, apiKeyID string) ([]byte, error)
 	}
 
 	// Get api key from database
+	if orgName != "" {
+		if err := m.authorize(ctx, orgName); err != nil {
+			return nil, err
+		}
+		return util.DBQueryJSON(ctx, m.db, getOrgAPIKeyDBQ, userID, orgName, apiKeyID)
+	}
 	return util.DBQueryJSON(ctx, m.db, getAPIKeyDBQ, userID, apiKeyID)
 }
 
+// GetOwnedByOrgJSON returns the api keys belonging to the organization
+// provided as a json array.
+func (m *Manager) GetOwnedByOrgJSON(ctx context.Context, orgName string) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if orgName == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "organization name not provided")
+	}
+
+	// Authorize action
+	if err := m.authorize(ctx, orgName); err != nil {
+		return nil, err
+	}
+
+	// Get api keys from database
+	return util.DBQueryJSON(ctx, m.db, getOrgAPIKeysDBQ, userID, orgName)
+}
+
 // GetOwnedByUserJSON returns the api keys belonging to the requesting user as
 // a json array.
 func (m *Manager) GetOwnedByUserJSON(ctx context.Context) ([]byte, error) {
@@ -84,7 +148,7 @@ func (m *Manager) GetOwnedByUserJSON(ctx context.Context) ([]byte, error) {
 }
 
 // Update updates the provided api key in the database.
-func (m *Manager) Update(ctx context.Context, ak *hub.APIKey) error {
+func (m *Manager) Update(ctx context.Context, orgName string, ak *hub.APIKey) error {
 	ak.UserID = ctx.Value(hub.UserIDKey).(string)
 
 	// Validate input
@@ -97,6 +161,38 @@ func (m *Manager) Update(ctx context.Context, ak *hub.APIKey) error {
 
 	// Update api key in database
 	akJSON, _ := json.Marshal(ak)
-	_, err := m.db.Exec(ctx, updateAPIKeyDBQ, akJSON)
+	var err error
+	if orgName != "" {
+		if err := m.authorize(ctx, orgName); err != nil {
+			return err
+		}
+		_, err = m.db.Exec(ctx, updateOrgAPIKeyDBQ, ak.UserID, orgName, akJSON)
+	} else {
+		_, err = m.db.Exec(ctx, updateAPIKeyDBQ, akJSON)
+	}
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
 	return err
 }
+
+// authorize checks if the requesting user is allowed to manage the api keys
+// of the organization provided.
+func (m *Manager) authorize(ctx context.Context, orgName string) error {
+	return m.az.Authorize(ctx, &hub.AuthorizeInput{
+		OrganizationName: orgName,
+		UserID:           ctx.Value(hub.UserIDKey).(string),
+		Action:           hub.ManageOrganizationAPIKeys,
+	})
+}
+
+// isValidScope checks if the scope provided is one of the api key scopes
+// supported.
+func isValidScope(scope hub.APIKeyScope) bool {
+	for _, validScope := range hub.APIKeyScopes {
+		if scope == validScope {
+			return true
+		}
+	}
+	return false
+}
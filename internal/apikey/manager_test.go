@@ -5,26 +5,35 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/artifacthub/hub/internal/authz"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/stretchr/testify/assert"
 )
 
 const apiKeyID = "00000000-0000-0000-0000-000000000001"
 
+var manageOrgAPIKeysInput = &hub.AuthorizeInput{
+	OrganizationName: "orgName",
+	UserID:           "userID",
+	Action:           hub.ManageOrganizationAPIKeys,
+}
+
 func TestAdd(t *testing.T) {
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
 
 	t.Run("user id not found in ctx", func(t *testing.T) {
 		t.Parallel()
-		m := NewManager(nil)
+		m := NewManager(nil, nil)
 		assert.Panics(t, func() {
 			ak := &hub.APIKey{
 				Name:   "apikey1",
 				UserID: "userID",
 			}
-			_, _ = m.Add(context.Background(), ak)
+			_, _ = m.Add(context.Background(), "", ak)
 		})
 	})
 
@@ -39,14 +48,28 @@ func TestAdd(t *testing.T) {
 					Name: "",
 				},
 			},
+			{
+				"invalid scope",
+				&hub.APIKey{
+					Name:   "apikey1",
+					Scopes: []hub.APIKeyScope{hub.ReadOnlyScope, "invalid"},
+				},
+			},
+			{
+				"expiration date must be in the future",
+				&hub.APIKey{
+					Name:      "apikey1",
+					ExpiresAt: time.Now().Add(-1 * time.Hour).Unix(),
+				},
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
 			t.Run(tc.errMsg, func(t *testing.T) {
 				t.Parallel()
-				m := NewManager(nil)
+				m := NewManager(nil, nil)
 
-				dataJSON, err := m.Add(ctx, tc.ak)
+				dataJSON, err := m.Add(ctx, "", tc.ak)
 				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
 				assert.Contains(t, err.Error(), tc.errMsg)
 				assert.Nil(t, dataJSON)
@@ -63,28 +86,101 @@ func TestAdd(t *testing.T) {
 		akJSON, _ := json.Marshal(ak)
 		db := &tests.DBMock{}
 		db.On("QueryRow", ctx, addAPIKeyDBQ, akJSON).Return(nil, tests.ErrFakeDB)
-		m := NewManager(db)
+		m := NewManager(db, nil)
 
-		dataJSON, err := m.Add(ctx, ak)
+		dataJSON, err := m.Add(ctx, "", ak)
 		assert.Equal(t, tests.ErrFakeDB, err)
 		assert.Nil(t, dataJSON)
 		db.AssertExpectations(t)
 	})
 
 	t.Run("add api key succeeded", func(t *testing.T) {
+		t.Parallel()
+		ak := &hub.APIKey{
+			Name:      "apikey1",
+			Scopes:    []hub.APIKeyScope{hub.ReadOnlyScope, hub.ManageWebhooksScope},
+			ExpiresAt: time.Now().Add(24 * time.Hour).Unix(),
+			UserID:    "userID",
+		}
+		akJSON, _ := json.Marshal(ak)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, addAPIKeyDBQ, akJSON).Return([]byte("key"), nil)
+		m := NewManager(db, nil)
+
+		dataJSON, err := m.Add(ctx, "", ak)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("key"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("org api key: authorization failed", func(t *testing.T) {
+		t.Parallel()
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, manageOrgAPIKeysInput).Return(tests.ErrFake)
+		m := NewManager(nil, az)
+
+		dataJSON, err := m.Add(ctx, "orgName", &hub.APIKey{Name: "apikey1"})
+		assert.Equal(t, tests.ErrFake, err)
+		assert.Nil(t, dataJSON)
+		az.AssertExpectations(t)
+	})
+
+	t.Run("org api key: database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				ak := &hub.APIKey{
+					Name:   "apikey1",
+					UserID: "userID",
+				}
+				akJSON, _ := json.Marshal(ak)
+				az := &authz.AuthorizerMock{}
+				az.On("Authorize", ctx, manageOrgAPIKeysInput).Return(nil)
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, addOrgAPIKeyDBQ, "userID", "orgName", akJSON).Return(nil, tc.dbErr)
+				m := NewManager(db, az)
+
+				dataJSON, err := m.Add(ctx, "orgName", ak)
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
+				az.AssertExpectations(t)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("add org api key succeeded", func(t *testing.T) {
 		t.Parallel()
 		ak := &hub.APIKey{
 			Name:   "apikey1",
+			Scopes: []hub.APIKeyScope{hub.ManageRepositoriesScope},
 			UserID: "userID",
 		}
 		akJSON, _ := json.Marshal(ak)
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, manageOrgAPIKeysInput).Return(nil)
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, addAPIKeyDBQ, akJSON).Return([]byte("key"), nil)
-		m := NewManager(db)
+		db.On("QueryRow", ctx, addOrgAPIKeyDBQ, "userID", "orgName", akJSON).Return([]byte("key"), nil)
+		m := NewManager(db, az)
 
-		dataJSON, err := m.Add(ctx, ak)
+		dataJSON, err := m.Add(ctx, "orgName", ak)
 		assert.NoError(t, err)
 		assert.Equal(t, []byte("key"), dataJSON)
+		az.AssertExpectations(t)
 		db.AssertExpectations(t)
 	})
 }
@@ -94,16 +190,16 @@ func TestDelete(t *testing.T) {
 
 	t.Run("user id not found in ctx", func(t *testing.T) {
 		t.Parallel()
-		m := NewManager(nil)
+		m := NewManager(nil, nil)
 		assert.Panics(t, func() {
-			_ = m.Delete(context.Background(), apiKeyID)
+			_ = m.Delete(context.Background(), "", apiKeyID)
 		})
 	})
 
 	t.Run("invalid input", func(t *testing.T) {
 		t.Parallel()
-		m := NewManager(nil)
-		err := m.Delete(ctx, "")
+		m := NewManager(nil, nil)
+		err := m.Delete(ctx, "", "")
 		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
 	})
 
@@ -111,9 +207,9 @@ func TestDelete(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
 		db.On("Exec", ctx, deleteAPIKeyDBQ, "userID", apiKeyID).Return(tests.ErrFakeDB)
-		m := NewManager(db)
+		m := NewManager(db, nil)
 
-		err := m.Delete(ctx, apiKeyID)
+		err := m.Delete(ctx, "", apiKeyID)
 		assert.Equal(t, tests.ErrFakeDB, err)
 		db.AssertExpectations(t)
 	})
@@ -122,12 +218,37 @@ func TestDelete(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
 		db.On("Exec", ctx, deleteAPIKeyDBQ, "userID", apiKeyID).Return(nil)
-		m := NewManager(db)
+		m := NewManager(db, nil)
 
-		err := m.Delete(ctx, apiKeyID)
+		err := m.Delete(ctx, "", apiKeyID)
 		assert.NoError(t, err)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("org api key: authorization failed", func(t *testing.T) {
+		t.Parallel()
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, manageOrgAPIKeysInput).Return(tests.ErrFake)
+		m := NewManager(nil, az)
+
+		err := m.Delete(ctx, "orgName", apiKeyID)
+		assert.Equal(t, tests.ErrFake, err)
+		az.AssertExpectations(t)
+	})
+
+	t.Run("delete org api key succeeded", func(t *testing.T) {
+		t.Parallel()
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, manageOrgAPIKeysInput).Return(nil)
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, deleteOrgAPIKeyDBQ, "userID", "orgName", apiKeyID).Return(nil)
+		m := NewManager(db, az)
+
+		err := m.Delete(ctx, "orgName", apiKeyID)
+		assert.NoError(t, err)
+		az.AssertExpectations(t)
+		db.AssertExpectations(t)
+	})
 }
 
 func TestGetJSON(t *testing.T) {
@@ -135,16 +256,16 @@ func TestGetJSON(t *testing.T) {
 
 	t.Run("user id not found in ctx", func(t *testing.T) {
 		t.Parallel()
-		m := NewManager(nil)
+		m := NewManager(nil, nil)
 		assert.Panics(t, func() {
-			_, _ = m.GetJSON(context.Background(), apiKeyID)
+			_, _ = m.GetJSON(context.Background(), "", apiKeyID)
 		})
 	})
 
 	t.Run("invalid input", func(t *testing.T) {
 		t.Parallel()
-		m := NewManager(nil)
-		_, err := m.GetJSON(ctx, "")
+		m := NewManager(nil, nil)
+		_, err := m.GetJSON(ctx, "", "")
 		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
 	})
 
@@ -152,9 +273,9 @@ func TestGetJSON(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
 		db.On("QueryRow", ctx, getAPIKeyDBQ, "userID", apiKeyID).Return(nil, tests.ErrFakeDB)
-		m := NewManager(db)
+		m := NewManager(db, nil)
 
-		dataJSON, err := m.GetJSON(ctx, apiKeyID)
+		dataJSON, err := m.GetJSON(ctx, "", apiKeyID)
 		assert.Equal(t, tests.ErrFakeDB, err)
 		assert.Nil(t, dataJSON)
 		db.AssertExpectations(t)
@@ -164,13 +285,101 @@ func TestGetJSON(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
 		db.On("QueryRow", ctx, getAPIKeyDBQ, "userID", apiKeyID).Return([]byte("dataJSON"), nil)
-		m := NewManager(db)
+		m := NewManager(db, nil)
 
-		dataJSON, err := m.GetJSON(ctx, apiKeyID)
+		dataJSON, err := m.GetJSON(ctx, "", apiKeyID)
 		assert.NoError(t, err)
 		assert.Equal(t, []byte("dataJSON"), dataJSON)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("org api key: authorization failed", func(t *testing.T) {
+		t.Parallel()
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, manageOrgAPIKeysInput).Return(tests.ErrFake)
+		m := NewManager(nil, az)
+
+		dataJSON, err := m.GetJSON(ctx, "orgName", apiKeyID)
+		assert.Equal(t, tests.ErrFake, err)
+		assert.Nil(t, dataJSON)
+		az.AssertExpectations(t)
+	})
+
+	t.Run("org api key data returned successfully", func(t *testing.T) {
+		t.Parallel()
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, manageOrgAPIKeysInput).Return(nil)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getOrgAPIKeyDBQ, "userID", "orgName", apiKeyID).Return([]byte("dataJSON"), nil)
+		m := NewManager(db, az)
+
+		dataJSON, err := m.GetJSON(ctx, "orgName", apiKeyID)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		az.AssertExpectations(t)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetOwnedByOrgJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetOwnedByOrgJSON(context.Background(), "orgName")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil, nil)
+		_, err := m.GetOwnedByOrgJSON(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("authorization failed", func(t *testing.T) {
+		t.Parallel()
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, manageOrgAPIKeysInput).Return(tests.ErrFake)
+		m := NewManager(nil, az)
+
+		dataJSON, err := m.GetOwnedByOrgJSON(ctx, "orgName")
+		assert.Equal(t, tests.ErrFake, err)
+		assert.Nil(t, dataJSON)
+		az.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, manageOrgAPIKeysInput).Return(nil)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getOrgAPIKeysDBQ, "userID", "orgName").Return(nil, tests.ErrFakeDB)
+		m := NewManager(db, az)
+
+		dataJSON, err := m.GetOwnedByOrgJSON(ctx, "orgName")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, dataJSON)
+		az.AssertExpectations(t)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("org api keys data returned successfully", func(t *testing.T) {
+		t.Parallel()
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, manageOrgAPIKeysInput).Return(nil)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getOrgAPIKeysDBQ, "userID", "orgName").Return([]byte("dataJSON"), nil)
+		m := NewManager(db, az)
+
+		dataJSON, err := m.GetOwnedByOrgJSON(ctx, "orgName")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		az.AssertExpectations(t)
+		db.AssertExpectations(t)
+	})
 }
 
 func TestGetOwnedByUserJSON(t *testing.T) {
@@ -178,7 +387,7 @@ func TestGetOwnedByUserJSON(t *testing.T) {
 
 	t.Run("user id not found in ctx", func(t *testing.T) {
 		t.Parallel()
-		m := NewManager(nil)
+		m := NewManager(nil, nil)
 		assert.Panics(t, func() {
 			_, _ = m.GetOwnedByUserJSON(context.Background())
 		})
@@ -188,7 +397,7 @@ func TestGetOwnedByUserJSON(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
 		db.On("QueryRow", ctx, getUserAPIKeysDBQ, "userID").Return(nil, tests.ErrFakeDB)
-		m := NewManager(db)
+		m := NewManager(db, nil)
 
 		dataJSON, err := m.GetOwnedByUserJSON(ctx)
 		assert.Equal(t, tests.ErrFakeDB, err)
@@ -200,7 +409,7 @@ func TestGetOwnedByUserJSON(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
 		db.On("QueryRow", ctx, getUserAPIKeysDBQ, "userID").Return([]byte("dataJSON"), nil)
-		m := NewManager(db)
+		m := NewManager(db, nil)
 
 		dataJSON, err := m.GetOwnedByUserJSON(ctx)
 		assert.NoError(t, err)
@@ -214,14 +423,14 @@ func TestUpdate(t *testing.T) {
 
 	t.Run("user id not found in ctx", func(t *testing.T) {
 		t.Parallel()
-		m := NewManager(nil)
+		m := NewManager(nil, nil)
 		assert.Panics(t, func() {
 			ak := &hub.APIKey{
 				APIKeyID: apiKeyID,
 				Name:     "apikey1-updated",
 				UserID:   "userID",
 			}
-			_ = m.Update(context.Background(), ak)
+			_ = m.Update(context.Background(), "", ak)
 		})
 	})
 
@@ -248,9 +457,9 @@ func TestUpdate(t *testing.T) {
 			tc := tc
 			t.Run(tc.errMsg, func(t *testing.T) {
 				t.Parallel()
-				m := NewManager(nil)
+				m := NewManager(nil, nil)
 
-				err := m.Update(ctx, tc.ak)
+				err := m.Update(ctx, "", tc.ak)
 				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
 				assert.Contains(t, err.Error(), tc.errMsg)
 			})
@@ -267,9 +476,9 @@ func TestUpdate(t *testing.T) {
 		akJSON, _ := json.Marshal(ak)
 		db := &tests.DBMock{}
 		db.On("Exec", ctx, updateAPIKeyDBQ, akJSON).Return(tests.ErrFakeDB)
-		m := NewManager(db)
+		m := NewManager(db, nil)
 
-		err := m.Update(ctx, ak)
+		err := m.Update(ctx, "", ak)
 		assert.Equal(t, tests.ErrFakeDB, err)
 		db.AssertExpectations(t)
 	})
@@ -284,10 +493,44 @@ func TestUpdate(t *testing.T) {
 		akJSON, _ := json.Marshal(ak)
 		db := &tests.DBMock{}
 		db.On("Exec", ctx, updateAPIKeyDBQ, akJSON).Return(nil)
-		m := NewManager(db)
+		m := NewManager(db, nil)
+
+		err := m.Update(ctx, "", ak)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("org api key: authorization failed", func(t *testing.T) {
+		t.Parallel()
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, manageOrgAPIKeysInput).Return(tests.ErrFake)
+		m := NewManager(nil, az)
+
+		err := m.Update(ctx, "orgName", &hub.APIKey{
+			APIKeyID: apiKeyID,
+			Name:     "apikey1-updated",
+		})
+		assert.Equal(t, tests.ErrFake, err)
+		az.AssertExpectations(t)
+	})
+
+	t.Run("update org api key succeeded", func(t *testing.T) {
+		t.Parallel()
+		ak := &hub.APIKey{
+			APIKeyID: apiKeyID,
+			Name:     "apikey1-updated",
+			UserID:   "userID",
+		}
+		akJSON, _ := json.Marshal(ak)
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, manageOrgAPIKeysInput).Return(nil)
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, updateOrgAPIKeyDBQ, "userID", "orgName", akJSON).Return(nil)
+		m := NewManager(db, az)
 
-		err := m.Update(ctx, ak)
+		err := m.Update(ctx, "orgName", ak)
 		assert.NoError(t, err)
+		az.AssertExpectations(t)
 		db.AssertExpectations(t)
 	})
 }
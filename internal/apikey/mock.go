@@ -13,18 +13,25 @@ type ManagerMock struct {
 }
 
 // Add implements the APIKeyManager interface.
-func (m *ManagerMock) Add(ctx context.Context, ak *hub.APIKey) ([]byte, error) {
-	args := m.Called(ctx, ak)
+func (m *ManagerMock) Add(ctx context.Context, orgName string, ak *hub.APIKey) ([]byte, error) {
+	args := m.Called(ctx, orgName, ak)
 	data, _ := args.Get(0).([]byte)
 	return data, args.Error(1)
 }
 
 // Delete implements the APIKeyManager interface.
-func (m *ManagerMock) Delete(ctx context.Context, apiKeyID string) error {
-	args := m.Called(ctx, apiKeyID)
+func (m *ManagerMock) Delete(ctx context.Context, orgName, apiKeyID string) error {
+	args := m.Called(ctx, orgName, apiKeyID)
 	return args.Error(0)
 }
 
+// GetOwnedByOrgJSON implements the APIKeyManager interface.
+func (m *ManagerMock) GetOwnedByOrgJSON(ctx context.Context, orgName string) ([]byte, error) {
+	args := m.Called(ctx, orgName)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // GetOwnedByUserJSON implements the APIKeyManager interface.
 func (m *ManagerMock) GetOwnedByUserJSON(ctx context.Context) ([]byte, error) {
 	args := m.Called(ctx)
@@ -33,14 +40,14 @@ func (m *ManagerMock) GetOwnedByUserJSON(ctx context.Context) ([]byte, error) {
 }
 
 // GetJSON implements the APIKeyManager interface.
-func (m *ManagerMock) GetJSON(ctx context.Context, apiKeyID string) ([]byte, error) {
-	args := m.Called(ctx, apiKeyID)
+func (m *ManagerMock) GetJSON(ctx context.Context, orgName, apiKeyID string) ([]byte, error) {
+	args := m.Called(ctx, orgName, apiKeyID)
 	data, _ := args.Get(0).([]byte)
 	return data, args.Error(1)
 }
 
 // Update implements the APIKeyManager interface.
-func (m *ManagerMock) Update(ctx context.Context, ak *hub.APIKey) error {
-	args := m.Called(ctx, ak)
+func (m *ManagerMock) Update(ctx context.Context, orgName string, ak *hub.APIKey) error {
+	args := m.Called(ctx, orgName, ak)
 	return args.Error(0)
 }
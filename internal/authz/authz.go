@@ -25,8 +25,10 @@ const (
 	AllowedActionsQuery = "data.artifacthub.authz.allowed_actions"
 
 	// Database queries
+	checkUserTFAReqDBQ  = `select user_meets_organization_tfa_requirement($1::uuid, $2::text)`
 	getAuthzPoliciesDBQ = `select get_authorization_policies()`
 	getUserAliasDBQ     = `select alias from "user" where user_id = $1`
+	isServiceAccountDBQ = `select service_account from "user" where user_id = $1`
 
 	pauseOnError = 10 * time.Second
 )
@@ -149,8 +151,17 @@ func (a *Authorizer) listenForPoliciesUpdates() {
 // Authorize allows or denies if an action can be performed based on the input
 // provided and the organization authorization policy. It queries the policy
 // for all the actions the user is allowed to perform and checks if the action
-// provided in the input is in that list.
+// provided in the input is in that list. Users must also meet the organization
+// two-factor authentication requirement.
 func (a *Authorizer) Authorize(ctx context.Context, input *hub.AuthorizeInput) error {
+	var tfaRequirementMet bool
+	err := a.db.QueryRow(ctx, checkUserTFAReqDBQ, input.UserID, input.OrganizationName).Scan(&tfaRequirementMet)
+	if err != nil {
+		return fmt.Errorf("%w: error checking tfa requirement: %s", hub.ErrInsufficientPrivilege, err.Error())
+	}
+	if !tfaRequirementMet {
+		return fmt.Errorf("%w: %s", hub.ErrInsufficientPrivilege, "two-factor authentication required by organization")
+	}
 	allowedActions, err := a.GetAllowedActions(ctx, input.UserID, input.OrganizationName)
 	if err != nil {
 		return fmt.Errorf("%w: error getting allowed actions: %s", hub.ErrInsufficientPrivilege, err.Error())
@@ -171,8 +182,16 @@ func (a *Authorizer) GetAllowedActions(ctx context.Context, userID, orgName stri
 	if !ok {
 		// If the organization hasn't defined an authorization policy yet, the
 		// user is allowed to perform all actions available in the organizations
-		// he belongs to.
+		// he belongs to. Service accounts are not granted any action implicitly,
+		// they can only perform the ones explicitly allowed by the policy.
 		a.mu.RUnlock()
+		var serviceAccount bool
+		if err := a.db.QueryRow(ctx, isServiceAccountDBQ, userID).Scan(&serviceAccount); err != nil {
+			return nil, err
+		}
+		if serviceAccount {
+			return []hub.Action{}, nil
+		}
 		return []hub.Action{"all"}, nil
 	}
 	a.mu.RUnlock()
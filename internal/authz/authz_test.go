@@ -12,6 +12,7 @@ import (
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -114,10 +115,16 @@ func TestNewAuthorizer(t *testing.T) {
 func TestAuthorize(t *testing.T) {
 	db := &tests.DBMock{}
 	db.On("QueryRow", context.Background(), getAuthzPoliciesDBQ).Return(testsAuthorizationPoliciesJSON, nil)
+	db.On("QueryRow", context.Background(), checkUserTFAReqDBQ, mock.Anything, mock.Anything).Return(true, nil).Maybe()
 	db.On("QueryRow", context.Background(), getUserAliasDBQ, user1ID).Return(user1Alias, nil).Maybe()
 	db.On("QueryRow", context.Background(), getUserAliasDBQ, user2ID).Return(user2Alias, nil).Maybe()
 	db.On("QueryRow", context.Background(), getUserAliasDBQ, user3ID).Return(user3Alias, nil).Maybe()
 	db.On("QueryRow", context.Background(), getUserAliasDBQ, user5ID).Return("", tests.ErrFakeDB).Maybe()
+	db.On("QueryRow", context.Background(), isServiceAccountDBQ, user1ID).Return(false, nil).Maybe()
+	db.On("QueryRow", context.Background(), isServiceAccountDBQ, user2ID).Return(false, nil).Maybe()
+	db.On("QueryRow", context.Background(), isServiceAccountDBQ, user3ID).Return(false, nil).Maybe()
+	db.On("QueryRow", context.Background(), isServiceAccountDBQ, user4ID).Return(true, nil).Maybe()
+	db.On("QueryRow", context.Background(), isServiceAccountDBQ, user5ID).Return(false, tests.ErrFakeDB).Maybe()
 	db.On("Acquire", context.Background()).Return(nil, tests.ErrFakeDB).Maybe()
 	az, err := NewAuthorizer(db)
 	require.NoError(t, err)
@@ -230,6 +237,14 @@ func TestAuthorize(t *testing.T) {
 			},
 			true,
 		},
+		{
+			&hub.AuthorizeInput{
+				OrganizationName: org3Name,
+				UserID:           user4ID,
+				Action:           hub.AddOrganizationMember,
+			},
+			false,
+		},
 	}
 	for i, tc := range testCases {
 		tc := tc
@@ -247,6 +262,43 @@ func TestAuthorize(t *testing.T) {
 	db.AssertExpectations(t)
 }
 
+func TestAuthorizeTFARequirement(t *testing.T) {
+	input := &hub.AuthorizeInput{
+		OrganizationName: org1Name,
+		UserID:           user1ID,
+		Action:           hub.AddOrganizationMember,
+	}
+
+	t.Run("database error checking tfa requirement", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", context.Background(), getAuthzPoliciesDBQ).Return(testsAuthorizationPoliciesJSON, nil)
+		db.On("QueryRow", context.Background(), checkUserTFAReqDBQ, user1ID, org1Name).Return(false, tests.ErrFakeDB)
+		db.On("Acquire", context.Background()).Return(nil, tests.ErrFakeDB).Maybe()
+		az, err := NewAuthorizer(db)
+		require.NoError(t, err)
+
+		err = az.Authorize(context.Background(), input)
+		assert.True(t, errors.Is(err, hub.ErrInsufficientPrivilege))
+		db.AssertExpectations(t)
+	})
+
+	t.Run("tfa requirement not met", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", context.Background(), getAuthzPoliciesDBQ).Return(testsAuthorizationPoliciesJSON, nil)
+		db.On("QueryRow", context.Background(), checkUserTFAReqDBQ, user1ID, org1Name).Return(false, nil)
+		db.On("Acquire", context.Background()).Return(nil, tests.ErrFakeDB).Maybe()
+		az, err := NewAuthorizer(db)
+		require.NoError(t, err)
+
+		err = az.Authorize(context.Background(), input)
+		assert.True(t, errors.Is(err, hub.ErrInsufficientPrivilege))
+		assert.Contains(t, err.Error(), "two-factor authentication required")
+		db.AssertExpectations(t)
+	})
+}
+
 func TestGetAllowedActions(t *testing.T) {
 	db := &tests.DBMock{}
 	db.On("QueryRow", context.Background(), getAuthzPoliciesDBQ).Return(testsAuthorizationPoliciesJSON, nil)
@@ -255,6 +307,11 @@ func TestGetAllowedActions(t *testing.T) {
 	db.On("QueryRow", context.Background(), getUserAliasDBQ, user3ID).Return(user3Alias, nil).Maybe()
 	db.On("QueryRow", context.Background(), getUserAliasDBQ, user4ID).Return(user4Alias, nil).Maybe()
 	db.On("QueryRow", context.Background(), getUserAliasDBQ, user5ID).Return("", tests.ErrFakeDB).Maybe()
+	db.On("QueryRow", context.Background(), isServiceAccountDBQ, user1ID).Return(false, nil).Maybe()
+	db.On("QueryRow", context.Background(), isServiceAccountDBQ, user2ID).Return(false, nil).Maybe()
+	db.On("QueryRow", context.Background(), isServiceAccountDBQ, user3ID).Return(false, nil).Maybe()
+	db.On("QueryRow", context.Background(), isServiceAccountDBQ, user4ID).Return(true, nil).Maybe()
+	db.On("QueryRow", context.Background(), isServiceAccountDBQ, user5ID).Return(false, tests.ErrFakeDB).Maybe()
 	db.On("Acquire", context.Background()).Return(nil, tests.ErrFakeDB).Maybe()
 	az, err := NewAuthorizer(db)
 	require.NoError(t, err)
@@ -320,6 +377,16 @@ func TestGetAllowedActions(t *testing.T) {
 				hub.Action("all"),
 			},
 		},
+		{
+			user4ID,
+			org3Name,
+			[]hub.Action{},
+		},
+		{
+			user5ID,
+			org3Name,
+			nil,
+		},
 	}
 	for i, tc := range testCases {
 		tc := tc
@@ -20,6 +20,7 @@ type Data struct {
 	To      string
 	Subject string
 	Body    []byte
+	Headers map[string]string
 }
 
 // Sender is in charge of sending emails.
@@ -71,6 +72,9 @@ func (s *Sender) SendEmail(d *Data) error {
 	email.ReplyTo(s.replyTo)
 	email.To(d.To)
 	email.Subject(d.Subject)
+	for name, value := range d.Headers {
+		email.AddHeader(name, value)
+	}
 	if _, err := email.HTML().Write(d.Body); err != nil {
 		return err
 	}
@@ -0,0 +1,177 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// encryptedPrefix represents the prefix used in all values encrypted by
+	// the Encrypter, allowing to distinguish them from plaintext ones.
+	encryptedPrefix = "enc:v1:"
+
+	// dataKeySize represents the size of the data keys generated to encrypt
+	// each value (AES-256).
+	dataKeySize = 32
+)
+
+var (
+	// ErrKeyNotConfigured indicates that the encryption key has not been
+	// provided in the configuration.
+	ErrKeyNotConfigured = errors.New("encryption key not configured")
+
+	// ErrInvalidCiphertext indicates that the ciphertext provided is not
+	// valid, so it cannot be decrypted.
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+// KeyProvider defines the methods a KeyProvider implementation must provide.
+// A key provider (i.e. a KMS) is in charge of encrypting and decrypting the
+// data keys used by the Encrypter, using a master key that never leaves it.
+type KeyProvider interface {
+	EncryptKey(ctx context.Context, key []byte) ([]byte, error)
+	DecryptKey(ctx context.Context, encryptedKey []byte) ([]byte, error)
+}
+
+// Encrypter provides an API to encrypt and decrypt sensitive data (i.e.
+// credentials or secrets) using envelope encryption. Each value is encrypted
+// using a new data key, which is encrypted by the key provider and stored
+// along with the data.
+type Encrypter struct {
+	kp KeyProvider
+}
+
+// NewEncrypter creates a new Encrypter instance. When no key provider is
+// provided, values will be neither encrypted nor decrypted.
+func NewEncrypter(kp KeyProvider) *Encrypter {
+	return &Encrypter{
+		kp: kp,
+	}
+}
+
+// Enabled returns whether the encrypter has a key provider configured, so
+// values will actually be encrypted.
+func (e *Encrypter) Enabled() bool {
+	return e.kp != nil
+}
+
+// Encrypt encrypts the plaintext provided. The result includes the encrypted
+// data key and the data encrypted with it (AES-GCM), both base64 encoded. When
+// the encrypter is not enabled, the plaintext is returned as is.
+func (e *Encrypter) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	if !e.Enabled() {
+		return plaintext, nil
+	}
+
+	// Generate a new data key and encrypt it using the key provider
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+	encryptedDataKey, err := e.kp.EncryptKey(ctx, dataKey)
+	if err != nil {
+		return "", fmt.Errorf("error encrypting data key: %w", err)
+	}
+
+	// Encrypt data using the data key
+	ciphertext, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	return encryptedPrefix +
+		base64.StdEncoding.EncodeToString(encryptedDataKey) + ":" +
+		base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt decrypts the ciphertext provided, previously encrypted with the
+// Encrypt method. Values that were not encrypted (i.e. stored before
+// encryption was enabled) are returned as is.
+func (e *Encrypter) Decrypt(ctx context.Context, ciphertext string) (string, error) {
+	if !IsEncrypted(ciphertext) {
+		return ciphertext, nil
+	}
+	if !e.Enabled() {
+		return "", ErrKeyNotConfigured
+	}
+
+	// Parse encrypted data key and data
+	parts := strings.Split(strings.TrimPrefix(ciphertext, encryptedPrefix), ":")
+	if len(parts) != 2 {
+		return "", ErrInvalidCiphertext
+	}
+	encryptedDataKey, err := base64.StdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	data, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	// Decrypt data key using the key provider and then the data
+	dataKey, err := e.kp.DecryptKey(ctx, encryptedDataKey)
+	if err != nil {
+		return "", fmt.Errorf("error decrypting data key: %w", err)
+	}
+	plaintext, err := open(dataKey, data)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// IsEncrypted checks if the value provided has been encrypted by an
+// Encrypter.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// seal encrypts the data provided using AES-GCM and the key provided. The
+// nonce used is prepended to the ciphertext returned.
+func seal(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+// open decrypts the data provided, previously encrypted with seal, using the
+// key provided.
+func open(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce, data := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, data, nil)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
+
+// newGCM returns an AES cipher wrapped in Galois Counter Mode using the key
+// provided.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
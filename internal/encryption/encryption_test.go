@@ -0,0 +1,134 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncrypter(t *testing.T) {
+	ctx := context.Background()
+	enc := NewEncrypter(NewLocalKeyProvider("key"))
+
+	t.Run("encrypted data can be decrypted with the same key", func(t *testing.T) {
+		t.Parallel()
+		ciphertext, err := enc.Encrypt(ctx, "plaintext")
+		require.NoError(t, err)
+		assert.True(t, IsEncrypted(ciphertext))
+		assert.NotContains(t, ciphertext, "plaintext")
+		plaintext, err := enc.Decrypt(ctx, ciphertext)
+		assert.NoError(t, err)
+		assert.Equal(t, "plaintext", plaintext)
+	})
+
+	t.Run("encrypting the same data twice produces different ciphertexts", func(t *testing.T) {
+		t.Parallel()
+		ciphertext1, err := enc.Encrypt(ctx, "plaintext")
+		require.NoError(t, err)
+		ciphertext2, err := enc.Encrypt(ctx, "plaintext")
+		require.NoError(t, err)
+		assert.NotEqual(t, ciphertext1, ciphertext2)
+	})
+
+	t.Run("decrypting with a different key fails", func(t *testing.T) {
+		t.Parallel()
+		ciphertext, err := enc.Encrypt(ctx, "plaintext")
+		require.NoError(t, err)
+		otherEnc := NewEncrypter(NewLocalKeyProvider("other"))
+		_, err = otherEnc.Decrypt(ctx, ciphertext)
+		assert.True(t, errors.Is(err, ErrInvalidCiphertext))
+	})
+
+	t.Run("invalid ciphertext", func(t *testing.T) {
+		testCases := []string{
+			"enc:v1:",
+			"enc:v1:not-base64:not-base64",
+			"enc:v1:c2hvcnQ=:c2hvcnQ=",
+		}
+		for _, ciphertext := range testCases {
+			ciphertext := ciphertext
+			t.Run(ciphertext, func(t *testing.T) {
+				t.Parallel()
+				_, err := enc.Decrypt(ctx, ciphertext)
+				assert.True(t, errors.Is(err, ErrInvalidCiphertext))
+			})
+		}
+	})
+
+	t.Run("values not encrypted are returned as is", func(t *testing.T) {
+		t.Parallel()
+		plaintext, err := enc.Decrypt(ctx, "plaintext")
+		assert.NoError(t, err)
+		assert.Equal(t, "plaintext", plaintext)
+	})
+
+	t.Run("encrypter not enabled", func(t *testing.T) {
+		t.Parallel()
+		enc := NewEncrypter(nil)
+		assert.False(t, enc.Enabled())
+
+		ciphertext, err := enc.Encrypt(ctx, "plaintext")
+		assert.NoError(t, err)
+		assert.Equal(t, "plaintext", ciphertext)
+
+		_, err = enc.Decrypt(ctx, "enc:v1:key:data")
+		assert.Equal(t, ErrKeyNotConfigured, err)
+	})
+
+	t.Run("key provider error", func(t *testing.T) {
+		t.Parallel()
+		kp := &keyProviderMock{}
+		kp.On("EncryptKey", ctx, mock.Anything).Return(nil, tests.ErrFake)
+		enc := NewEncrypter(kp)
+
+		_, err := enc.Encrypt(ctx, "plaintext")
+		assert.True(t, errors.Is(err, tests.ErrFake))
+		kp.AssertExpectations(t)
+	})
+
+	t.Run("data key is encrypted using the key provider", func(t *testing.T) {
+		t.Parallel()
+		var dataKey []byte
+		kp := &keyProviderMock{}
+		kp.On("EncryptKey", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			dataKey = args.Get(1).([]byte)
+		}).Return([]byte("encryptedDataKey"), nil)
+		kp.On("DecryptKey", ctx, []byte("encryptedDataKey")).Return(func() []byte {
+			return dataKey
+		}, nil)
+		enc := NewEncrypter(kp)
+
+		ciphertext, err := enc.Encrypt(ctx, "plaintext")
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(ciphertext, "enc:v1:ZW5jcnlwdGVkRGF0YUtleQ==:"))
+		plaintext, err := enc.Decrypt(ctx, ciphertext)
+		assert.NoError(t, err)
+		assert.Equal(t, "plaintext", plaintext)
+		kp.AssertExpectations(t)
+	})
+}
+
+type keyProviderMock struct {
+	mock.Mock
+}
+
+func (m *keyProviderMock) EncryptKey(ctx context.Context, key []byte) ([]byte, error) {
+	args := m.Called(ctx, key)
+	encryptedKey, _ := args.Get(0).([]byte)
+	return encryptedKey, args.Error(1)
+}
+
+func (m *keyProviderMock) DecryptKey(ctx context.Context, encryptedKey []byte) ([]byte, error) {
+	args := m.Called(ctx, encryptedKey)
+	if f, ok := args.Get(0).(func() []byte); ok {
+		return f(), args.Error(1)
+	}
+	key, _ := args.Get(0).([]byte)
+	return key, args.Error(1)
+}
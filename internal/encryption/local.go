@@ -0,0 +1,31 @@
+package encryption
+
+import (
+	"context"
+	"crypto/sha256"
+)
+
+// LocalKeyProvider is a KeyProvider implementation that uses a master key
+// provided in the configuration to encrypt and decrypt the data keys.
+type LocalKeyProvider struct {
+	key []byte
+}
+
+// NewLocalKeyProvider creates a new LocalKeyProvider instance. The key
+// provided is hashed to obtain a master key suitable for AES-256.
+func NewLocalKeyProvider(key string) *LocalKeyProvider {
+	h := sha256.Sum256([]byte(key))
+	return &LocalKeyProvider{
+		key: h[:],
+	}
+}
+
+// EncryptKey implements the KeyProvider interface.
+func (kp *LocalKeyProvider) EncryptKey(ctx context.Context, key []byte) ([]byte, error) {
+	return seal(kp.key, key)
+}
+
+// DecryptKey implements the KeyProvider interface.
+func (kp *LocalKeyProvider) DecryptKey(ctx context.Context, encryptedKey []byte) ([]byte, error) {
+	return open(kp.key, encryptedKey)
+}
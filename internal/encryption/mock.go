@@ -0,0 +1,24 @@
+package encryption
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// EncrypterMock is a mock implementation of the Encrypter interface.
+type EncrypterMock struct {
+	mock.Mock
+}
+
+// Decrypt implements the Encrypter interface.
+func (m *EncrypterMock) Decrypt(ctx context.Context, ciphertext string) (string, error) {
+	args := m.Called(ctx, ciphertext)
+	return args.String(0), args.Error(1)
+}
+
+// Encrypt implements the Encrypter interface.
+func (m *EncrypterMock) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	args := m.Called(ctx, plaintext)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,57 @@
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+const (
+	// Database queries
+	getUnencryptedSecretsDBQ = `select get_unencrypted_secrets($1::text)`
+	updateSecretDBQ          = `select update_secret($1::text, $2::uuid, $3::text, $4::text)`
+)
+
+// secret represents a secret stored in the database (i.e. a repository
+// password, a webhook secret or a user two-factor authentication secret).
+type secret struct {
+	Kind  string `json:"kind"`
+	ID    string `json:"id"`
+	Value string `json:"value"`
+}
+
+// EncryptStoredSecrets encrypts the secrets stored in the database that have
+// not been encrypted yet (i.e. stored before encryption was enabled). The
+// number of secrets encrypted is returned. Nothing is done when the encrypter
+// provided is not enabled.
+func EncryptStoredSecrets(ctx context.Context, db hub.DB, enc *Encrypter) (int, error) {
+	if !enc.Enabled() {
+		return 0, nil
+	}
+
+	// Get secrets not encrypted yet
+	var dataJSON []byte
+	if err := db.QueryRow(ctx, getUnencryptedSecretsDBQ, encryptedPrefix).Scan(&dataJSON); err != nil {
+		return 0, err
+	}
+	var secrets []*secret
+	if err := json.Unmarshal(dataJSON, &secrets); err != nil {
+		return 0, err
+	}
+
+	// Encrypt them and update their values in the database
+	for i, s := range secrets {
+		encryptedValue, err := enc.Encrypt(ctx, s.Value)
+		if err != nil {
+			return i, fmt.Errorf("error encrypting secret (%s %s): %w", s.Kind, s.ID, err)
+		}
+		_, err = db.Exec(ctx, updateSecretDBQ, s.Kind, s.ID, s.Value, encryptedValue)
+		if err != nil {
+			return i, fmt.Errorf("error updating secret (%s %s): %w", s.Kind, s.ID, err)
+		}
+	}
+
+	return len(secrets), nil
+}
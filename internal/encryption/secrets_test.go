@@ -0,0 +1,77 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEncryptStoredSecrets(t *testing.T) {
+	ctx := context.Background()
+	enc := NewEncrypter(NewLocalKeyProvider("key"))
+	secretsJSON := []byte(`[
+		{"kind": "repository_auth_pass", "id": "repo1", "value": "pass1"},
+		{"kind": "webhook_secret", "id": "webhook1", "value": "secret1"}
+	]`)
+	decryptsTo := func(plaintext string) interface{} {
+		return mock.MatchedBy(func(ciphertext string) bool {
+			v, err := enc.Decrypt(ctx, ciphertext)
+			return err == nil && v == plaintext
+		})
+	}
+
+	t.Run("encrypter not enabled", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+
+		n, err := EncryptStoredSecrets(ctx, db, NewEncrypter(nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("error getting unencrypted secrets", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUnencryptedSecretsDBQ, encryptedPrefix).Return(nil, tests.ErrFakeDB)
+
+		n, err := EncryptStoredSecrets(ctx, db, enc)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Equal(t, 0, n)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("error updating secret", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUnencryptedSecretsDBQ, encryptedPrefix).Return(secretsJSON, nil)
+		db.On("Exec", ctx, updateSecretDBQ, "repository_auth_pass", "repo1", "pass1", decryptsTo("pass1")).
+			Return(nil)
+		db.On("Exec", ctx, updateSecretDBQ, "webhook_secret", "webhook1", "secret1", decryptsTo("secret1")).
+			Return(tests.ErrFakeDB)
+
+		n, err := EncryptStoredSecrets(ctx, db, enc)
+		assert.True(t, errors.Is(err, tests.ErrFakeDB))
+		assert.Equal(t, 1, n)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("secrets encrypted successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUnencryptedSecretsDBQ, encryptedPrefix).Return(secretsJSON, nil)
+		db.On("Exec", ctx, updateSecretDBQ, "repository_auth_pass", "repo1", "pass1", decryptsTo("pass1")).
+			Return(nil)
+		db.On("Exec", ctx, updateSecretDBQ, "webhook_secret", "webhook1", "secret1", decryptsTo("secret1")).
+			Return(nil)
+
+		n, err := EncryptStoredSecrets(ctx, db, enc)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		db.AssertExpectations(t)
+	})
+}
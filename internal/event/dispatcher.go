@@ -5,10 +5,15 @@ import (
 	"sync"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
 )
 
 const (
 	defaultNumWorkers = 2
+
+	// eventCreatedChannel represents the database channel where notifications
+	// are sent when new events are registered.
+	eventCreatedChannel = "event_created"
 )
 
 // Services is a wrapper around several internal services used to handle
@@ -19,11 +24,14 @@ type Services struct {
 	SubscriptionManager hub.SubscriptionManager
 	WebhookManager      hub.WebhookManager
 	NotificationManager hub.NotificationManager
+	PackageManager      hub.PackageManager
+	UserManager         hub.UserManager
 }
 
 // Dispatcher handles a group of workers in charge of processing events that
 // happen in the Hub.
 type Dispatcher struct {
+	db         hub.DB
 	numWorkers int
 	workers    []*Worker
 }
@@ -31,6 +39,7 @@ type Dispatcher struct {
 // NewDispatcher creates a new Dispatcher instance.
 func NewDispatcher(svc *Services, opts ...func(d *Dispatcher)) *Dispatcher {
 	d := &Dispatcher{
+		db:         svc.DB,
 		numWorkers: defaultNumWorkers,
 	}
 	for _, o := range opts {
@@ -64,8 +73,21 @@ func (d *Dispatcher) Run(ctx context.Context, wg *sync.WaitGroup) {
 		go w.Run(wctx, wwg)
 	}
 
+	// Wake workers up when new events are registered in the database, so
+	// that they don't have to wait until the queue is polled again
+	if len(d.workers) > 0 {
+		go util.DBListen(wctx, d.db, eventCreatedChannel, d.wakeUpWorkers)
+	}
+
 	// Stop workers when dispatcher is asked to stop
 	<-ctx.Done()
 	stopWorkers()
 	wwg.Wait()
 }
+
+// wakeUpWorkers wakes up all the dispatcher's workers.
+func (d *Dispatcher) wakeUpWorkers() {
+	for _, w := range d.workers {
+		w.wakeUp()
+	}
+}
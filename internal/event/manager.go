@@ -3,22 +3,46 @@ package event
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/jackc/pgx/v4"
+	"github.com/satori/uuid"
 )
 
 const (
 	// Database queries
+	getEventsCursorDBQ = `select get_events_cursor($1::uuid)`
 	getPendingEventDBQ = `select get_pending_event()`
+	getUserEventsDBQ   = `select get_user_events($1::uuid, $2::jsonb)`
 )
 
 // Manager provides an API to manage events.
-type Manager struct{}
+type Manager struct {
+	db hub.DB
+}
 
 // NewManager creates a new Manager instance.
-func NewManager() *Manager {
-	return &Manager{}
+func NewManager(db hub.DB) *Manager {
+	return &Manager{
+		db: db,
+	}
+}
+
+// GetCursor returns the position in the events stream of the event provided.
+// When the event id provided is not valid or the event does not exist, the
+// position of the last event registered is returned.
+func (m *Manager) GetCursor(ctx context.Context, lastEventID string) (*hub.EventsCursor, error) {
+	var eventID interface{}
+	if _, err := uuid.FromString(lastEventID); err == nil {
+		eventID = lastEventID
+	}
+	c := &hub.EventsCursor{}
+	if err := util.DBQueryUnmarshal(ctx, m.db, c, getEventsCursorDBQ, eventID); err != nil {
+		return nil, err
+	}
+	return c, nil
 }
 
 // GetPending returns a pending event to be processed if available.
@@ -33,3 +57,55 @@ func (m *Manager) GetPending(ctx context.Context, tx pgx.Tx) (*hub.Event, error)
 	}
 	return e, nil
 }
+
+// GetUserEvents returns the events created after the cursor provided that
+// the user doing the request can receive, applying the filters provided.
+func (m *Manager) GetUserEvents(ctx context.Context, input *hub.GetUserEventsInput) ([]*hub.UserEvent, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if input.Cursor == nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "cursor not provided")
+	}
+	if input.PackageID != "" {
+		if _, err := uuid.FromString(input.PackageID); err != nil {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+		}
+	}
+	if input.RepositoryID != "" {
+		if _, err := uuid.FromString(input.RepositoryID); err != nil {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+		}
+	}
+	if input.OrganizationID != "" {
+		if _, err := uuid.FromString(input.OrganizationID); err != nil {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid organization id")
+		}
+	}
+	for _, kind := range input.EventKinds {
+		if !isValidEventKind(kind) {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid event kind")
+		}
+	}
+	if input.Limit < 0 {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid limit")
+	}
+
+	// Get events from database
+	inputJSON, _ := json.Marshal(input)
+	var events []*hub.UserEvent
+	if err := util.DBQueryUnmarshal(ctx, m.db, &events, getUserEventsDBQ, userID, inputJSON); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// isValidEventKind checks if the event kind provided is valid.
+func isValidEventKind(kind hub.EventKind) bool {
+	switch kind {
+	case hub.NewRelease, hub.SecurityAlert, hub.RepositoryTrackingErrors, hub.RepositoryOwnershipClaim:
+		return true
+	default:
+		return false
+	}
+}
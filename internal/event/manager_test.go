@@ -2,13 +2,17 @@ package event
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -17,6 +21,58 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
+func TestGetCursor(t *testing.T) {
+	ctx := context.Background()
+	eventID := "00000000-0000-0000-0000-000000000001"
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getEventsCursorDBQ, eventID).Return(nil, tests.ErrFakeDB)
+		m := NewManager(db)
+
+		c, err := m.GetCursor(ctx, eventID)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, c)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("invalid event id provided", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getEventsCursorDBQ, nil).Return([]byte(`
+		{
+			"created_at": "2020-06-16T09:20:34.123456+00:00"
+		}
+		`), nil)
+		m := NewManager(db)
+
+		c, err := m.GetCursor(ctx, "invalid")
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2020, 6, 16, 9, 20, 34, 123456000, time.UTC), c.CreatedAt.UTC())
+		assert.Empty(t, c.EventID)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getEventsCursorDBQ, eventID).Return([]byte(`
+		{
+			"created_at": "2020-06-16T09:20:34.123456+00:00",
+			"event_id": "00000000-0000-0000-0000-000000000001"
+		}
+		`), nil)
+		m := NewManager(db)
+
+		c, err := m.GetCursor(ctx, eventID)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2020, 6, 16, 9, 20, 34, 123456000, time.UTC), c.CreatedAt.UTC())
+		assert.Equal(t, eventID, c.EventID)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestGetPending(t *testing.T) {
 	ctx := context.Background()
 
@@ -24,7 +80,7 @@ func TestGetPending(t *testing.T) {
 		t.Parallel()
 		tx := &tests.TXMock{}
 		tx.On("QueryRow", ctx, getPendingEventDBQ).Return(nil, tests.ErrFakeDB)
-		m := NewManager()
+		m := NewManager(nil)
 
 		dataJSON, err := m.GetPending(ctx, tx)
 		assert.Equal(t, tests.ErrFakeDB, err)
@@ -50,7 +106,7 @@ func TestGetPending(t *testing.T) {
 			"event_kind": 0
 		}
 		`), nil)
-		m := NewManager()
+		m := NewManager(nil)
 
 		e, err := m.GetPending(ctx, tx)
 		require.NoError(t, err)
@@ -58,3 +114,115 @@ func TestGetPending(t *testing.T) {
 		tx.AssertExpectations(t)
 	})
 }
+
+func TestGetUserEvents(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	cursor := &hub.EventsCursor{
+		CreatedAt: time.Date(2020, 6, 16, 9, 20, 34, 0, time.UTC),
+		EventID:   "00000000-0000-0000-0000-000000000001",
+	}
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetUserEvents(context.Background(), &hub.GetUserEventsInput{Cursor: cursor})
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			input  *hub.GetUserEventsInput
+		}{
+			{
+				"cursor not provided",
+				&hub.GetUserEventsInput{},
+			},
+			{
+				"invalid package id",
+				&hub.GetUserEventsInput{Cursor: cursor, PackageID: "invalid"},
+			},
+			{
+				"invalid repository id",
+				&hub.GetUserEventsInput{Cursor: cursor, RepositoryID: "invalid"},
+			},
+			{
+				"invalid organization id",
+				&hub.GetUserEventsInput{Cursor: cursor, OrganizationID: "invalid"},
+			},
+			{
+				"invalid event kind",
+				&hub.GetUserEventsInput{Cursor: cursor, EventKinds: []hub.EventKind{9}},
+			},
+			{
+				"invalid limit",
+				&hub.GetUserEventsInput{Cursor: cursor, Limit: -1},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(nil)
+				events, err := m.GetUserEvents(ctx, tc.input)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+				assert.Nil(t, events)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserEventsDBQ, "userID", mock.Anything).Return(nil, tests.ErrFakeDB)
+		m := NewManager(db)
+
+		events, err := m.GetUserEvents(ctx, &hub.GetUserEventsInput{Cursor: cursor})
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, events)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		input := &hub.GetUserEventsInput{
+			Cursor:     cursor,
+			PackageID:  "00000000-0000-0000-0000-000000000001",
+			EventKinds: []hub.EventKind{hub.NewRelease},
+		}
+		inputJSON, _ := json.Marshal(input)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserEventsDBQ, "userID", inputJSON).Return([]byte(`
+		[
+			{
+				"event_id": "00000000-0000-0000-0000-000000000002",
+				"event_kind": 0,
+				"created_at": "2020-06-16T09:20:35+00:00",
+				"package_id": "00000000-0000-0000-0000-000000000001",
+				"package_version": "1.0.0",
+				"package": {
+					"name": "package1",
+					"version": "1.0.0"
+				},
+				"repository": {
+					"name": "repo1"
+				},
+				"subscription_filters": [{"exclude_prereleases": true}]
+			}
+		]
+		`), nil)
+		m := NewManager(db)
+
+		events, err := m.GetUserEvents(ctx, input)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "00000000-0000-0000-0000-000000000002", events[0].EventID)
+		assert.Equal(t, "1.0.0", events[0].PackageVersion)
+		assert.Equal(t, "package1", events[0].Package.Name)
+		assert.Equal(t, "repo1", events[0].Repository.Name)
+		assert.Equal(t, []*hub.NotificationFilters{{ExcludePrereleases: true}}, events[0].SubscriptionFilters)
+		db.AssertExpectations(t)
+	})
+}
@@ -13,9 +13,23 @@ type ManagerMock struct {
 	mock.Mock
 }
 
+// GetCursor implements the EventManager interface.
+func (m *ManagerMock) GetCursor(ctx context.Context, lastEventID string) (*hub.EventsCursor, error) {
+	args := m.Called(ctx, lastEventID)
+	data, _ := args.Get(0).(*hub.EventsCursor)
+	return data, args.Error(1)
+}
+
 // GetPending implements the EventManager interface.
 func (m *ManagerMock) GetPending(ctx context.Context, tx pgx.Tx) (*hub.Event, error) {
 	args := m.Called(ctx, tx)
 	data, _ := args.Get(0).(*hub.Event)
 	return data, args.Error(1)
 }
+
+// GetUserEvents implements the EventManager interface.
+func (m *ManagerMock) GetUserEvents(ctx context.Context, input *hub.GetUserEventsInput) ([]*hub.UserEvent, error) {
+	args := m.Called(ctx, input)
+	data, _ := args.Get(0).([]*hub.UserEvent)
+	return data, args.Error(1)
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/subscription"
+	"github.com/artifacthub/hub/internal/user"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/jackc/pgx/v4"
 	"github.com/rs/zerolog/log"
@@ -19,18 +21,21 @@ const (
 
 // Worker is in charge of handling events that happen in the Hub.
 type Worker struct {
-	svc *Services
+	svc      *Services
+	wakeUpCh chan struct{}
 }
 
 // NewWorker creates a new Worker instance.
 func NewWorker(svc *Services) *Worker {
 	return &Worker{
-		svc: svc,
+		svc:      svc,
+		wakeUpCh: make(chan struct{}, 1),
 	}
 }
 
 // Run is the main loop of the worker. It calls processEvent periodically until
-// it's asked to stop via the context provided.
+// it's asked to stop via the context provided. When there are no pending events
+// to process, it waits until it's woken up or the queue is polled again.
 func (w *Worker) Run(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 
@@ -45,6 +50,7 @@ func (w *Worker) Run(ctx context.Context, wg *sync.WaitGroup) {
 			}
 		case errors.Is(err, pgx.ErrNoRows):
 			select {
+			case <-w.wakeUpCh:
 			case <-time.After(pauseOnEmptyQueue):
 			case <-ctx.Done():
 				return
@@ -59,6 +65,15 @@ func (w *Worker) Run(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+// wakeUp wakes the worker up if it's waiting for new events to be registered.
+// If the worker is busy, it'll check the queue again as soon as it's done.
+func (w *Worker) wakeUp() {
+	select {
+	case w.wakeUpCh <- struct{}{}:
+	default:
+	}
+}
+
 // processEvent gets a pending event from the database and processes it.
 func (w *Worker) processEvent(ctx context.Context) error {
 	return util.DBTransact(ctx, w.svc.DB, func(tx pgx.Tx) error {
@@ -71,6 +86,53 @@ func (w *Worker) processEvent(ctx context.Context) error {
 			return err
 		}
 
+		// Filters are applied to new release events using the package
+		// version released, which is fetched only if needed
+		var p *hub.Package
+		passesFilters := func(filters ...*hub.NotificationFilters) (bool, error) {
+			if e.EventKind != hub.NewRelease || len(filters) == 0 {
+				return true, nil
+			}
+			for _, f := range filters {
+				if f == nil {
+					return true, nil
+				}
+			}
+			if p == nil {
+				var err error
+				p, err = w.svc.PackageManager.Get(ctx, &hub.GetPackageInput{
+					PackageID: e.PackageID,
+					Version:   e.PackageVersion,
+				})
+				if err != nil {
+					log.Error().Err(err).Msg("error getting package")
+					return false, err
+				}
+			}
+			for _, f := range filters {
+				if subscription.MatchFilters(f, p) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+
+		// Users notification preferences are fetched only if needed, and
+		// reused for all the notifications of the event
+		preferences := make(map[string]*hub.NotificationPreferences)
+		getPreferences := func(userID string) (*hub.NotificationPreferences, error) {
+			if np, ok := preferences[userID]; ok {
+				return np, nil
+			}
+			np, err := w.svc.UserManager.GetNotificationPreferences(ctx, userID)
+			if err != nil {
+				log.Error().Err(err).Msg("error getting notification preferences")
+				return nil, err
+			}
+			preferences[userID] = np
+			return np, nil
+		}
+
 		// Register event notifications
 		// Email notifications
 		users, err := w.svc.SubscriptionManager.GetSubscriptors(ctx, e)
@@ -79,9 +141,30 @@ func (w *Worker) processEvent(ctx context.Context) error {
 			return err
 		}
 		for _, u := range users {
+			pass, err := passesFilters(u.SubscriptionFilters...)
+			if err != nil {
+				return err
+			}
+			if !pass {
+				continue
+			}
+			np, err := getPreferences(u.UserID)
+			if err != nil {
+				return err
+			}
+			ekp := getEventKindPreference(np, e.EventKind)
+			if !ekp.Email && !ekp.Digest {
+				continue
+			}
 			n := &hub.Notification{
-				Event: e,
-				User:  u,
+				Event:  e,
+				User:   u,
+				Digest: ekp.Digest,
+			}
+			if !n.Digest {
+				if end, ok := user.QuietHoursEnd(np.QuietHours, time.Now()); ok {
+					n.NextAttemptAt = &end
+				}
 			}
 			if err := w.svc.NotificationManager.Add(ctx, tx, n); err != nil {
 				log.Error().Err(err).Msg("error adding notification")
@@ -95,12 +178,27 @@ func (w *Worker) processEvent(ctx context.Context) error {
 			return err
 		}
 		for _, wh := range webhooks {
+			pass, err := passesFilters(wh.Filters)
+			if err != nil {
+				return err
+			}
+			if !pass {
+				continue
+			}
+			if wh.UserID != "" {
+				np, err := getPreferences(wh.UserID)
+				if err != nil {
+					return err
+				}
+				if !getEventKindPreference(np, e.EventKind).Webhook {
+					continue
+				}
+			}
 			n := &hub.Notification{
 				Event:   e,
 				Webhook: wh,
 			}
-			err := w.svc.NotificationManager.Add(ctx, tx, n)
-			if err != nil {
+			if err := w.svc.NotificationManager.Add(ctx, tx, n); err != nil {
 				log.Error().Err(err).Msg("error adding notification")
 				return err
 			}
@@ -109,3 +207,22 @@ func (w *Worker) processEvent(ctx context.Context) error {
 		return nil
 	})
 }
+
+// getEventKindPreference returns the notification preference for the event
+// kind provided. When the user has not set it up, notifications are delivered
+// immediately via email and personal webhooks.
+func getEventKindPreference(
+	np *hub.NotificationPreferences,
+	kind hub.EventKind,
+) *hub.EventKindNotificationPreference {
+	for _, ekp := range np.EventKinds {
+		if ekp.EventKind == kind {
+			return ekp
+		}
+	}
+	return &hub.EventKindNotificationPreference{
+		EventKind: kind,
+		Email:     true,
+		Webhook:   true,
+	}
+}
@@ -8,10 +8,14 @@ import (
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/notification"
+	"github.com/artifacthub/hub/internal/pkg"
 	"github.com/artifacthub/hub/internal/subscription"
 	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/user"
 	"github.com/artifacthub/hub/internal/webhook"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestWorker(t *testing.T) {
@@ -32,6 +36,28 @@ func TestWorker(t *testing.T) {
 	wh2 := &hub.Webhook{
 		WebhookID: "webhook2ID",
 	}
+	u3 := &hub.User{
+		UserID: "user3ID",
+		SubscriptionFilters: []*hub.NotificationFilters{
+			{MajorMinorOnly: true},
+		},
+	}
+	u4 := &hub.User{
+		UserID: "user4ID",
+		SubscriptionFilters: []*hub.NotificationFilters{
+			{ExcludePrereleases: true},
+			{SecurityUpdatesOnly: true},
+		},
+	}
+	wh3 := &hub.Webhook{
+		WebhookID: "webhook3ID",
+		Filters:   &hub.NotificationFilters{VersionConstraint: ">=2.0.0"},
+	}
+	wh4 := &hub.Webhook{
+		WebhookID: "webhook4ID",
+		UserID:    "user1ID",
+	}
+	np := &hub.NotificationPreferences{}
 
 	t.Run("error getting pending event", func(t *testing.T) {
 		t.Parallel()
@@ -45,6 +71,29 @@ func TestWorker(t *testing.T) {
 		sw.assertExpectations(t)
 	})
 
+	t.Run("worker woken up while waiting for new events", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		w := NewWorker(sw.svc)
+		processed := make(chan struct{})
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil).Twice()
+		sw.em.On("GetPending", sw.ctx, sw.tx).Run(func(args mock.Arguments) {
+			w.wakeUp()
+		}).Return(nil, pgx.ErrNoRows).Once()
+		sw.em.On("GetPending", sw.ctx, sw.tx).Run(func(args mock.Arguments) {
+			close(processed)
+		}).Return(nil, pgx.ErrNoRows).Once()
+		sw.tx.On("Rollback", sw.ctx).Return(nil).Twice()
+
+		go w.Run(sw.ctx, sw.wg)
+		select {
+		case <-processed:
+		case <-time.After(2 * time.Second):
+			t.Error("worker was not woken up")
+		}
+		sw.assertExpectations(t)
+	})
+
 	t.Run("error getting subscriptors", func(t *testing.T) {
 		t.Parallel()
 		sw := newServicesWrapper()
@@ -78,6 +127,7 @@ func TestWorker(t *testing.T) {
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
 		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
 		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.User{u1}, nil)
+		sw.um.On("GetNotificationPreferences", sw.ctx, "user1ID").Return(np, nil)
 		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, User: u1}).Return(tests.ErrFake)
 		sw.tx.On("Rollback", sw.ctx).Return(nil)
 
@@ -92,6 +142,7 @@ func TestWorker(t *testing.T) {
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
 		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
 		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.User{u1}, nil)
+		sw.um.On("GetNotificationPreferences", sw.ctx, "user1ID").Return(np, nil)
 		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, User: u1}).Return(nil)
 		sw.wm.On("GetSubscribedTo", sw.ctx, e).Return([]*hub.Webhook{}, nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
@@ -107,7 +158,9 @@ func TestWorker(t *testing.T) {
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
 		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
 		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.User{u1, u2}, nil)
+		sw.um.On("GetNotificationPreferences", sw.ctx, "user1ID").Return(np, nil)
 		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, User: u1}).Return(nil)
+		sw.um.On("GetNotificationPreferences", sw.ctx, "user2ID").Return(np, nil)
 		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, User: u2}).Return(nil)
 		sw.wm.On("GetSubscribedTo", sw.ctx, e).Return([]*hub.Webhook{}, nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
@@ -162,6 +215,109 @@ func TestWorker(t *testing.T) {
 		go w.Run(sw.ctx, sw.wg)
 		sw.assertExpectations(t)
 	})
+
+	t.Run("error getting package to apply filters", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
+		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.User{u3}, nil)
+		sw.pm.On("Get", sw.ctx, &hub.GetPackageInput{PackageID: "packageID"}).Return(nil, tests.ErrFake)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("only notifications passing filters are added", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
+		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.User{u1, u3, u4}, nil)
+		sw.pm.On("Get", sw.ctx, &hub.GetPackageInput{PackageID: "packageID"}).Return(&hub.Package{
+			Version:    "1.1.0",
+			Prerelease: true,
+		}, nil).Once()
+		sw.um.On("GetNotificationPreferences", sw.ctx, "user1ID").Return(np, nil)
+		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, User: u1}).Return(nil)
+		sw.um.On("GetNotificationPreferences", sw.ctx, "user3ID").Return(np, nil)
+		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, User: u3}).Return(nil)
+		sw.wm.On("GetSubscribedTo", sw.ctx, e).Return([]*hub.Webhook{wh1, wh3}, nil)
+		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, Webhook: wh1}).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("error getting notification preferences", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
+		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.User{u1}, nil)
+		sw.um.On("GetNotificationPreferences", sw.ctx, "user1ID").Return(nil, tests.ErrFake)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("notifications are added following users preferences", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
+		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.User{u1, u2}, nil)
+		sw.um.On("GetNotificationPreferences", sw.ctx, "user1ID").Return(&hub.NotificationPreferences{
+			EventKinds: []*hub.EventKindNotificationPreference{
+				{EventKind: hub.NewRelease, Email: false, Digest: false, Webhook: false},
+			},
+		}, nil).Once()
+		sw.um.On("GetNotificationPreferences", sw.ctx, "user2ID").Return(&hub.NotificationPreferences{
+			EventKinds: []*hub.EventKindNotificationPreference{
+				{EventKind: hub.NewRelease, Email: false, Digest: true, Webhook: true},
+			},
+		}, nil)
+		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, User: u2, Digest: true}).Return(nil)
+		sw.wm.On("GetSubscribedTo", sw.ctx, e).Return([]*hub.Webhook{wh1, wh4}, nil)
+		sw.nm.On("Add", sw.ctx, sw.tx, &hub.Notification{Event: e, Webhook: wh1}).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("email notifications are deferred during quiet hours", func(t *testing.T) {
+		t.Parallel()
+		now := time.Now().UTC()
+		quietHoursEnd := now.Add(1 * time.Hour).Truncate(time.Minute)
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.em.On("GetPending", sw.ctx, sw.tx).Return(e, nil)
+		sw.sm.On("GetSubscriptors", sw.ctx, e).Return([]*hub.User{u1}, nil)
+		sw.um.On("GetNotificationPreferences", sw.ctx, "user1ID").Return(&hub.NotificationPreferences{
+			QuietHours: &hub.QuietHours{
+				Start:    now.Add(-1 * time.Hour).Format("15:04"),
+				End:      quietHoursEnd.Format("15:04"),
+				TimeZone: "UTC",
+			},
+		}, nil)
+		sw.nm.On("Add", sw.ctx, sw.tx, mock.MatchedBy(func(n *hub.Notification) bool {
+			return n.User == u1 && n.NextAttemptAt != nil && n.NextAttemptAt.Equal(quietHoursEnd)
+		})).Return(nil)
+		sw.wm.On("GetSubscribedTo", sw.ctx, e).Return([]*hub.Webhook{}, nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
 }
 
 type servicesWrapper struct {
@@ -174,6 +330,8 @@ type servicesWrapper struct {
 	sm         *subscription.ManagerMock
 	wm         *webhook.ManagerMock
 	nm         *notification.ManagerMock
+	pm         *pkg.ManagerMock
+	um         *user.ManagerMock
 	svc        *Services
 }
 
@@ -189,6 +347,8 @@ func newServicesWrapper() *servicesWrapper {
 	sm := &subscription.ManagerMock{}
 	wm := &webhook.ManagerMock{}
 	nm := &notification.ManagerMock{}
+	pm := &pkg.ManagerMock{}
+	um := &user.ManagerMock{}
 
 	return &servicesWrapper{
 		ctx:        ctx,
@@ -200,12 +360,16 @@ func newServicesWrapper() *servicesWrapper {
 		sm:         sm,
 		wm:         wm,
 		nm:         nm,
+		pm:         pm,
+		um:         um,
 		svc: &Services{
 			DB:                  db,
 			EventManager:        em,
 			SubscriptionManager: sm,
 			WebhookManager:      wm,
 			NotificationManager: nm,
+			PackageManager:      pm,
+			UserManager:         um,
 		},
 	}
 }
@@ -223,4 +387,6 @@ func (sw *servicesWrapper) assertExpectations(t *testing.T) {
 	sw.sm.AssertExpectations(t)
 	sw.wm.AssertExpectations(t)
 	sw.nm.AssertExpectations(t)
+	sw.pm.AssertExpectations(t)
+	sw.um.AssertExpectations(t)
 }
@@ -2,20 +2,65 @@ package hub
 
 import "context"
 
-// APIKey represents a key used to interact with the HTTP API.
+// APIKeyScope represents a scope that can be granted to an api key, limiting
+// the operations that can be performed using it.
+type APIKeyScope string
+
+const (
+	// ReadOnlyScope grants access to all the read-only operations.
+	ReadOnlyScope APIKeyScope = "read-only"
+
+	// ManageRepositoriesScope grants access to all the operations related to
+	// repositories.
+	ManageRepositoriesScope APIKeyScope = "manage-repositories"
+
+	// ManageSubscriptionsScope grants access to all the operations related to
+	// subscriptions.
+	ManageSubscriptionsScope APIKeyScope = "manage-subscriptions"
+
+	// ManageWebhooksScope grants access to all the operations related to
+	// webhooks.
+	ManageWebhooksScope APIKeyScope = "manage-webhooks"
+)
+
+// APIKeyScopes represents all the scopes that can be granted to an api key.
+var APIKeyScopes = []APIKeyScope{
+	ReadOnlyScope,
+	ManageRepositoriesScope,
+	ManageSubscriptionsScope,
+	ManageWebhooksScope,
+}
+
+type apiKeyScopesKey struct{}
+
+// APIKeyScopesKey represents the key used for the api key scopes value inside a
+// context. It is only set when the request was authenticated using a key that
+// has been granted some scopes.
+var APIKeyScopesKey = apiKeyScopesKey{}
+
+// APIKey represents a key used to interact with the HTTP API. Keys with no
+// scopes are granted the full permissions of the user they belong to. Keys
+// owned by an organization belong to a service account that is a member of
+// it, so their permissions are resolved through the organization
+// authorization policy.
 type APIKey struct {
-	APIKeyID  string `json:"api_key_id"`
-	Name      string `json:"name"`
-	CreatedAt int64  `json:"created_at"`
-	UserID    string `json:"user_id"`
+	APIKeyID            string        `json:"api_key_id"`
+	Name                string        `json:"name"`
+	Scopes              []APIKeyScope `json:"scopes"`
+	CreatedAt           int64         `json:"created_at"`
+	ExpiresAt           int64         `json:"expires_at,omitempty"`
+	LastUsedAt          int64         `json:"last_used_at,omitempty"`
+	ServiceAccountAlias string        `json:"service_account_alias,omitempty"`
+	UserID              string        `json:"user_id"`
 }
 
 // APIKeyManager describes the methods an APIKeyManager implementation must
 // provide.
 type APIKeyManager interface {
-	Add(ctx context.Context, ak *APIKey) ([]byte, error)
-	Delete(ctx context.Context, apiKeyID string) error
-	GetJSON(ctx context.Context, apiKeyID string) ([]byte, error)
+	Add(ctx context.Context, orgName string, ak *APIKey) ([]byte, error)
+	Delete(ctx context.Context, orgName, apiKeyID string) error
+	GetJSON(ctx context.Context, orgName, apiKeyID string) ([]byte, error)
+	GetOwnedByOrgJSON(ctx context.Context, orgName string) ([]byte, error)
 	GetOwnedByUserJSON(ctx context.Context) ([]byte, error)
-	Update(ctx context.Context, ak *APIKey) error
+	Update(ctx context.Context, orgName string, ak *APIKey) error
 }
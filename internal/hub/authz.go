@@ -32,6 +32,14 @@ const (
 	// authorization policy.
 	GetAuthorizationPolicy Action = "getAuthorizationPolicy"
 
+	// ManageOrganizationAPIKeys represents the action of adding, updating or
+	// deleting the api keys that belong to an organization.
+	ManageOrganizationAPIKeys Action = "manageOrganizationAPIKeys"
+
+	// TrackOrganizationRepository represents the action of requesting the
+	// tracking of a repository that belongs to an organization.
+	TrackOrganizationRepository Action = "trackOrganizationRepository"
+
 	// TransferOrganizationRepository represents the action of transferring a
 	// repository that belongs to an organization.
 	TransferOrganizationRepository Action = "transferOrganizationRepository"
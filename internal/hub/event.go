@@ -2,6 +2,7 @@ package hub
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v4"
 )
@@ -35,8 +36,39 @@ const (
 	RepositoryOwnershipClaim EventKind = 3
 )
 
+// EventsCursor represents a position in the events stream. Events created
+// after it (using the event id to break ties) are the ones coming next.
+type EventsCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	EventID   string    `json:"event_id,omitempty"`
+}
+
+// GetUserEventsInput represents the input used to get the events a user can
+// receive in the events stream.
+type GetUserEventsInput struct {
+	Cursor         *EventsCursor `json:"cursor"`
+	PackageID      string        `json:"package_id,omitempty"`
+	RepositoryID   string        `json:"repository_id,omitempty"`
+	OrganizationID string        `json:"organization_id,omitempty"`
+	EventKinds     []EventKind   `json:"event_kinds,omitempty"`
+	Limit          int           `json:"limit,omitempty"`
+}
+
+// UserEvent represents an event a user can receive in the events stream. It
+// includes some details about the package and repository it refers to, as well
+// as the filters of the subscriptions that made the user receive it.
+type UserEvent struct {
+	Event
+	CreatedAt           time.Time              `json:"created_at"`
+	Package             *Package               `json:"package"`
+	Repository          *Repository            `json:"repository"`
+	SubscriptionFilters []*NotificationFilters `json:"subscription_filters"`
+}
+
 // EventManager describes the methods an EventManager implementation must
 // provide.
 type EventManager interface {
+	GetCursor(ctx context.Context, lastEventID string) (*EventsCursor, error)
 	GetPending(ctx context.Context, tx pgx.Tx) (*Event, error)
+	GetUserEvents(ctx context.Context, input *GetUserEventsInput) ([]*UserEvent, error)
 }
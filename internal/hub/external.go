@@ -21,3 +21,9 @@ type DB interface {
 type EmailSender interface {
 	SendEmail(data *email.Data) error
 }
+
+// Encrypter defines the methods an Encrypter implementation must provide.
+type Encrypter interface {
+	Decrypt(ctx context.Context, ciphertext string) (string, error)
+	Encrypt(ctx context.Context, plaintext string) (string, error)
+}
@@ -2,23 +2,43 @@ package hub
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v4"
 )
 
 // Notification represents the details of a notification pending to be delivered.
 type Notification struct {
-	NotificationID string   `json:"notification_id"`
-	Event          *Event   `json:"event"`
-	User           *User    `json:"user"`
-	Webhook        *Webhook `json:"webhook"`
+	NotificationID string     `json:"notification_id"`
+	Event          *Event     `json:"event"`
+	User           *User      `json:"user"`
+	Webhook        *Webhook   `json:"webhook"`
+	Attempts       int        `json:"attempts"`
+	Digest         bool       `json:"digest,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+}
+
+// NotificationDigest represents a set of pending digest notifications for
+// a given user that will be delivered together in a single email.
+type NotificationDigest struct {
+	User          *User           `json:"user"`
+	Notifications []*Notification `json:"notifications"`
 }
 
 // NotificationManager describes the methods an NotificationManager
 // implementation must provide.
 type NotificationManager interface {
 	Add(ctx context.Context, tx pgx.Tx, n *Notification) error
+	AddWebhookDelivery(ctx context.Context, tx pgx.Tx, d *WebhookDelivery) error
 	GetPending(ctx context.Context, tx pgx.Tx) (*Notification, error)
+	GetPendingDigest(ctx context.Context, tx pgx.Tx) (*NotificationDigest, error)
+	ScheduleRetry(
+		ctx context.Context,
+		tx pgx.Tx,
+		notificationID string,
+		delay time.Duration,
+		deliveryErr error,
+	) error
 	UpdateStatus(
 		ctx context.Context,
 		tx pgx.Tx,
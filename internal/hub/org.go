@@ -13,6 +13,7 @@ type Organization struct {
 	Description    string `json:"description"`
 	HomeURL        string `json:"home_url"`
 	LogoImageID    string `json:"logo_image_id"`
+	TFARequired    *bool  `json:"tfa_required,omitempty"`
 }
 
 // OrganizationManager describes the methods an OrganizationManager
@@ -144,10 +144,37 @@ type PackageMetadata struct {
 // SnapshotSecurityReport represents some information about the security
 // vulnerabilities the images used by a given package's snapshot may have.
 type SnapshotSecurityReport struct {
-	PackageID string                   `json:"package_id"`
-	Version   string                   `json:"version"`
-	Summary   *SecurityReportSummary   `json:"summary"`
-	Full      map[string][]interface{} `json:"full"`
+	PackageID string                             `json:"package_id"`
+	Version   string                             `json:"version"`
+	Summary   *SecurityReportSummary             `json:"summary"`
+	Full      map[string][]*SecurityReportTarget `json:"full"`
+}
+
+// SecurityReportTarget represents a target (i.e. operating system packages or
+// some language specific dependencies) in the security report of an image. It
+// is the normalized format used for security reports, regardless of the
+// scanner backend used to generate them.
+type SecurityReportTarget struct {
+	Target          string           `json:"Target"`
+	Type            string           `json:"Type"`
+	Vulnerabilities []*Vulnerability `json:"Vulnerabilities"`
+}
+
+// Vulnerability represents a vulnerability found in a security report target.
+type Vulnerability struct {
+	VulnerabilityID  string                 `json:"VulnerabilityID"`
+	PkgName          string                 `json:"PkgName"`
+	InstalledVersion string                 `json:"InstalledVersion"`
+	FixedVersion     string                 `json:"FixedVersion,omitempty"`
+	Severity         string                 `json:"Severity"`
+	SeveritySource   string                 `json:"SeveritySource,omitempty"`
+	Title            string                 `json:"Title,omitempty"`
+	Description      string                 `json:"Description,omitempty"`
+	CweIDs           []string               `json:"CweIDs,omitempty"`
+	CVSS             map[string]interface{} `json:"CVSS,omitempty"`
+	References       []string               `json:"References,omitempty"`
+	PublishedDate    string                 `json:"PublishedDate,omitempty"`
+	LastModifiedDate string                 `json:"LastModifiedDate,omitempty"`
 }
 
 // SecurityReportSummary represents a summary of the security report.
@@ -3,6 +3,7 @@ package hub
 import (
 	"context"
 	"errors"
+	"net/http"
 
 	helmrepo "helm.sh/helm/v3/pkg/repo"
 )
@@ -130,6 +131,11 @@ type Repository struct {
 	Private                 bool           `json:"private"`
 	AuthUser                string         `json:"auth_user"`
 	AuthPass                string         `json:"auth_pass"`
+	ClearAuthPass           bool           `json:"clear_auth_pass"`
+	SSHKey                  string         `json:"ssh_key"`
+	ClearSSHKey             bool           `json:"clear_ssh_key"`
+	PushWebhookSecret       string         `json:"push_webhook_secret"`
+	ClearPushWebhookSecret  bool           `json:"clear_push_webhook_secret"`
 	Digest                  string         `json:"digest"`
 	Kind                    RepositoryKind `json:"kind"`
 	UserID                  string         `json:"user_id"`
@@ -142,6 +148,7 @@ type Repository struct {
 	Official                bool           `json:"official"`
 	Disabled                bool           `json:"disabled"`
 	ScannerDisabled         bool           `json:"scanner_disabled"`
+	TrackingInterval        int            `json:"tracking_interval"`
 }
 
 // RepositoryCloner describes the methods a RepositoryCloner implementation
@@ -172,13 +179,25 @@ type RepositoryManager interface {
 	GetOwnedByOrgJSON(ctx context.Context, orgName string, includeCredentials bool) ([]byte, error)
 	GetOwnedByUserJSON(ctx context.Context, includeCredentials bool) ([]byte, error)
 	GetRemoteDigest(ctx context.Context, r *Repository) (string, error)
+	GetTrackingJobJSON(ctx context.Context, name, trackingJobID string) ([]byte, error)
 	SetLastTrackingResults(ctx context.Context, repositoryID, errs string) error
 	SetVerifiedPublisher(ctx context.Context, repositorID string, verified bool) error
+	Track(ctx context.Context, name string) (string, error)
+	TrackPushed(ctx context.Context, e *RepositoryPushEvent) ([]string, error)
 	Transfer(ctx context.Context, name, orgName string, ownershipClaim bool) error
 	Update(ctx context.Context, r *Repository) error
 	UpdateDigest(ctx context.Context, repositorID, digest string) error
 }
 
+// RepositoryPushEvent represents a push event sent by a git provider (GitHub,
+// GitLab, Gitea) to notify that some changes have been pushed to a git
+// repository.
+type RepositoryPushEvent struct {
+	Provider string
+	Header   http.Header
+	Body     []byte
+}
+
 // RepositoryMetadata represents some metadata about a given repository. It's
 // usually provided by repositories publishers, to provide some extra context
 // about the repository they'd like to publish.
@@ -12,11 +12,38 @@ type OptOut struct {
 }
 
 // Subscription represents a user's subscription to receive notifications about
-// a given package and event kind.
+// a given package and event kind. Users can also subscribe to all the packages
+// in a repository or in the repositories of an organization, including those
+// added later, by providing a repository or organization id instead.
 type Subscription struct {
-	UserID    string    `json:"user_id"`
-	PackageID string    `json:"package_id"`
-	EventKind EventKind `json:"event_kind"`
+	UserID         string               `json:"user_id"`
+	PackageID      string               `json:"package_id"`
+	RepositoryID   string               `json:"repository_id"`
+	OrganizationID string               `json:"organization_id"`
+	EventKind      EventKind            `json:"event_kind"`
+	Filters        *NotificationFilters `json:"filters,omitempty"`
+}
+
+// NotificationFilters represents some filters that can be applied to
+// subscriptions and webhooks to limit the new releases they are notified
+// about.
+type NotificationFilters struct {
+	MajorMinorOnly      bool   `json:"major_minor_only,omitempty"`
+	ExcludePrereleases  bool   `json:"exclude_prereleases,omitempty"`
+	SecurityUpdatesOnly bool   `json:"security_updates_only,omitempty"`
+	VersionConstraint   string `json:"version_constraint,omitempty"`
+}
+
+// UnsubscribeToken represents the information included in the signed tokens
+// embedded in notifications emails, which allow users to stop receiving them
+// without logging in. Tokens refer to a package for packages events and to a
+// repository for repositories events.
+type UnsubscribeToken struct {
+	UserID       string    `json:"user_id"`
+	EventKind    EventKind `json:"event_kind"`
+	PackageID    string    `json:"package_id,omitempty"`
+	RepositoryID string    `json:"repository_id,omitempty"`
+	ExpiresAt    int64     `json:"expires_at"`
 }
 
 // SubscriptionManager describes the methods a SubscriptionManager
@@ -29,5 +56,8 @@ type SubscriptionManager interface {
 	GetByPackageJSON(ctx context.Context, packageID string) ([]byte, error)
 	GetByUserJSON(ctx context.Context) ([]byte, error)
 	GetOptOutListJSON(ctx context.Context) ([]byte, error)
+	GetOrganizationsByUserJSON(ctx context.Context) ([]byte, error)
+	GetRepositoriesByUserJSON(ctx context.Context) ([]byte, error)
 	GetSubscriptors(ctx context.Context, e *Event) ([]*User, error)
+	Unsubscribe(ctx context.Context, t *UnsubscribeToken) error
 }
@@ -3,6 +3,7 @@ package hub
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/artifacthub/hub/internal/img"
 	"github.com/rs/zerolog"
@@ -34,6 +35,7 @@ type TrackerServices struct {
 	Oe                 OLMOCIExporter
 	Ec                 ErrorsCollector
 	Hc                 HTTPClient
+	Enc                Encrypter
 	Is                 img.Store
 	GithubRL           *rate.Limiter
 	SetupTrackerSource TrackerSourceLoader
@@ -75,3 +77,44 @@ type TrackerSourceServices struct {
 	Logger   zerolog.Logger
 	GithubRL *rate.Limiter
 }
+
+// TrackingJobStatus represents the status of a tracking job.
+type TrackingJobStatus string
+
+const (
+	// TrackingJobPending represents a tracking job waiting to be run.
+	TrackingJobPending TrackingJobStatus = "pending"
+
+	// TrackingJobRunning represents a tracking job that is being run.
+	TrackingJobRunning TrackingJobStatus = "running"
+
+	// TrackingJobSucceeded represents a tracking job that was run successfully.
+	TrackingJobSucceeded TrackingJobStatus = "succeeded"
+
+	// TrackingJobFailed represents a tracking job that failed.
+	TrackingJobFailed TrackingJobStatus = "failed"
+)
+
+// TrackingJob represents a job in charge of tracking a given repository.
+type TrackingJob struct {
+	TrackingJobID   string            `json:"tracking_job_id"`
+	RepositoryID    string            `json:"repository_id"`
+	Status          TrackingJobStatus `json:"status"`
+	CreatedAt       int64             `json:"created_at"`
+	ScheduledAt     int64             `json:"scheduled_at"`
+	StartedAt       int64             `json:"started_at"`
+	FinishedAt      int64             `json:"finished_at"`
+	Errors          string            `json:"errors"`
+	PackagesAdded   int               `json:"packages_added"`
+	PackagesRemoved int               `json:"packages_removed"`
+	LeaseToken      string            `json:"lease_token"`
+}
+
+// TrackingJobManager describes the methods a TrackingJobManager
+// implementation must provide.
+type TrackingJobManager interface {
+	Finish(ctx context.Context, j *TrackingJob) error
+	Lease(ctx context.Context, leaseDuration time.Duration) (*TrackingJob, error)
+	RenewLease(ctx context.Context, j *TrackingJob, leaseDuration time.Duration) error
+	Schedule(ctx context.Context, defaultInterval, maxBackoff time.Duration) error
+}
@@ -1,14 +1,12 @@
 package hub
 
-import (
-	"context"
-	"time"
-)
+import "context"
 
 // CheckAPIKeyOutput represents the output returned by the CheckApiKey method.
 type CheckAPIKeyOutput struct {
-	Valid  bool   `json:"valid"`
-	UserID string `json:"user_id"`
+	Valid  bool          `json:"valid"`
+	UserID string        `json:"user_id"`
+	Scopes []APIKeyScope `json:"scopes"`
 }
 
 // CheckCredentialsOutput represents the output returned by the
@@ -24,6 +22,38 @@ type CheckSessionOutput struct {
 	UserID string `json:"user_id"`
 }
 
+// EventKindNotificationPreference represents the channels through which a
+// user prefers to be notified about events of a given kind.
+type EventKindNotificationPreference struct {
+	EventKind EventKind `json:"event_kind"`
+	Email     bool      `json:"email"`
+	Digest    bool      `json:"digest"`
+	Webhook   bool      `json:"webhook"`
+}
+
+// NotificationPreferences represents the notification preferences of a user.
+type NotificationPreferences struct {
+	EventKinds []*EventKindNotificationPreference `json:"event_kinds"`
+	QuietHours *QuietHours                        `json:"quiet_hours,omitempty"`
+}
+
+// QuietHours represents a daily period of time during which a user prefers
+// not to receive emails notifications immediately. Start and end times use
+// the 15:04 format and are relative to the time zone provided.
+type QuietHours struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	TimeZone string `json:"time_zone"`
+}
+
+// RegisterSessionOutput represents the output returned by the RegisterSession
+// method. Sessions of users who have enabled two-factor authentication are not
+// approved until a valid passcode is provided.
+type RegisterSessionOutput struct {
+	SessionID []byte `json:"session_id"`
+	Approved  bool   `json:"approved"`
+}
+
 // Session represents some information about a user session.
 type Session struct {
 	SessionID string `json:"session_id"`
@@ -32,18 +62,51 @@ type Session struct {
 	UserAgent string `json:"user_agent"`
 }
 
+// TFASetup represents the information needed to enable two-factor
+// authentication in an authenticator app.
+type TFASetup struct {
+	ProvisioningURI string   `json:"provisioning_uri"`
+	Secret          string   `json:"secret"`
+	RecoveryCodes   []string `json:"recovery_codes"`
+}
+
 // User represents a Hub user.
 type User struct {
-	UserID         string `json:"user_id"`
-	Alias          string `json:"alias"`
-	FirstName      string `json:"first_name"`
-	LastName       string `json:"last_name"`
-	Email          string `json:"email"`
-	EmailVerified  bool   `json:"email_verified"`
-	Password       string `json:"password"`
-	ProfileImageID string `json:"profile_image_id"`
+	UserID             string              `json:"user_id"`
+	Alias              string              `json:"alias"`
+	FirstName          string              `json:"first_name"`
+	LastName           string              `json:"last_name"`
+	Email              string              `json:"email"`
+	EmailVerified      bool                `json:"email_verified"`
+	Password           string              `json:"password"`
+	ProfileImageID     string              `json:"profile_image_id"`
+	DeliveryPreference *DeliveryPreference `json:"delivery_preference,omitempty"`
+	TFAEnabled         bool                `json:"tfa_enabled"`
+
+	// SubscriptionFilters contains the filters of the subscriptions that
+	// made the user a subscriptor of a given event, when all of them have
+	// filters defined.
+	SubscriptionFilters []*NotificationFilters `json:"subscription_filters,omitempty"`
 }
 
+// DeliveryPreference represents how a user prefers to receive the packages
+// notifications delivered via email.
+type DeliveryPreference int64
+
+const (
+	// ImmediateDelivery represents the preference of receiving an email for
+	// each notification as soon as it is available.
+	ImmediateDelivery DeliveryPreference = 0
+
+	// DailyDigestDelivery represents the preference of receiving a digest
+	// email summarizing the notifications once a day.
+	DailyDigestDelivery DeliveryPreference = 1
+
+	// WeeklyDigestDelivery represents the preference of receiving a digest
+	// email summarizing the notifications once a week.
+	WeeklyDigestDelivery DeliveryPreference = 2
+)
+
 type userIDKey struct{}
 
 // UserIDKey represents the key used for the userID value inside a context.
@@ -51,16 +114,28 @@ var UserIDKey = userIDKey{}
 
 // UserManager describes the methods a UserManager implementation must provide.
 type UserManager interface {
+	ApproveSession(ctx context.Context, sessionID []byte, passcode string) error
 	CheckAPIKey(ctx context.Context, key []byte) (*CheckAPIKeyOutput, error)
 	CheckAvailability(ctx context.Context, resourceKind, value string) (bool, error)
 	CheckCredentials(ctx context.Context, email, password string) (*CheckCredentialsOutput, error)
-	CheckSession(ctx context.Context, sessionID []byte, duration time.Duration) (*CheckSessionOutput, error)
+	CheckSession(ctx context.Context, sessionID []byte) (*CheckSessionOutput, error)
 	DeleteSession(ctx context.Context, sessionID []byte) error
+	DisableTFA(ctx context.Context, passcode string) error
+	EnableTFA(ctx context.Context, passcode string) error
+	GetNotificationPreferences(ctx context.Context, userID string) (*NotificationPreferences, error)
+	GetNotificationPreferencesJSON(ctx context.Context) ([]byte, error)
 	GetProfile(ctx context.Context) (*User, error)
 	GetProfileJSON(ctx context.Context) ([]byte, error)
+	GetSessionsJSON(ctx context.Context, currentSessionID []byte) ([]byte, error)
 	GetUserID(ctx context.Context, email string) (string, error)
-	RegisterSession(ctx context.Context, session *Session) ([]byte, error)
+	RegisterPasswordResetCode(ctx context.Context, email, baseURL string) error
+	RegisterSession(ctx context.Context, session *Session) (*RegisterSessionOutput, error)
 	RegisterUser(ctx context.Context, user *User, baseURL string) error
+	ResetPassword(ctx context.Context, code, newPassword string) error
+	RevokeOtherSessions(ctx context.Context, currentSessionID []byte) error
+	RevokeSession(ctx context.Context, sessionID string) error
+	SetupTFA(ctx context.Context) ([]byte, error)
+	UpdateNotificationPreferences(ctx context.Context, p *NotificationPreferences) error
 	UpdatePassword(ctx context.Context, old, new string) error
 	UpdateProfile(ctx context.Context, user *User) error
 	VerifyEmail(ctx context.Context, code string) (bool, error)
@@ -5,16 +5,74 @@ import "context"
 // Webhook represents the configuration of a webhook where notifications will
 // be posted to.
 type Webhook struct {
-	WebhookID   string      `json:"webhook_id"`
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	URL         string      `json:"url"`
-	Secret      string      `json:"secret"`
-	ContentType string      `json:"content_type"`
-	Template    string      `json:"template"`
-	Active      bool        `json:"active"`
-	EventKinds  []EventKind `json:"event_kinds"`
-	Packages    []*Package  `json:"packages"`
+	WebhookID          string               `json:"webhook_id"`
+	Name               string               `json:"name"`
+	Description        string               `json:"description"`
+	URL                string               `json:"url"`
+	Secret             string               `json:"secret"`
+	ContentType        string               `json:"content_type"`
+	Template           string               `json:"template"`
+	LegacySecretHeader bool                 `json:"legacy_secret_header"`
+	Kind               WebhookKind          `json:"kind"`
+	Filters            *NotificationFilters `json:"filters,omitempty"`
+	Active             bool                 `json:"active"`
+	EventKinds         []EventKind          `json:"event_kinds"`
+	Packages           []*Package           `json:"packages"`
+	Repositories       []*Repository        `json:"repositories"`
+
+	// SecretSet indicates whether the webhook has a secret or not. The secret
+	// itself is never returned to clients.
+	SecretSet bool `json:"secret_set"`
+
+	// ClearSecret allows removing the secret of a webhook when updating it. An
+	// empty secret keeps the current one.
+	ClearSecret bool `json:"clear_secret"`
+
+	// UserID contains the id of the user owning the webhook. It is only set
+	// when getting the webhooks subscribed to a given event.
+	UserID string `json:"user_id,omitempty"`
+}
+
+// WebhookKind represents the kind of a webhook. Webhooks of a kind other than
+// generic post notifications to chat services, using built-in payloads.
+type WebhookKind int64
+
+const (
+	// GenericWebhook represents a webhook that posts notifications using the
+	// default or a custom template.
+	GenericWebhook WebhookKind = 0
+
+	// SlackWebhook represents a Slack incoming webhook.
+	SlackWebhook WebhookKind = 1
+
+	// MicrosoftTeamsWebhook represents a Microsoft Teams incoming webhook.
+	MicrosoftTeamsWebhook WebhookKind = 2
+
+	// DiscordWebhook represents a Discord webhook.
+	DiscordWebhook WebhookKind = 3
+)
+
+// WebhookDelivery represents the details of an attempt to deliver a
+// notification to a webhook.
+type WebhookDelivery struct {
+	WebhookDeliveryID string `json:"webhook_delivery_id"`
+	NotificationID    string `json:"notification_id"`
+	WebhookID         string `json:"webhook_id"`
+	Attempt           int    `json:"attempt"`
+	StatusCode        int    `json:"status_code"`
+	LatencyMS         int64  `json:"latency_ms"`
+	Response          string `json:"response"`
+	Error             string `json:"error"`
+}
+
+// WebhookTemplatePreviewInput represents the input used to render a webhook
+// template for preview. When a package is provided, its details are used to
+// render the template instead of some sample data.
+type WebhookTemplatePreviewInput struct {
+	Template       string    `json:"template"`
+	EventKind      EventKind `json:"event_kind"`
+	PackageID      string    `json:"package_id"`
+	PackageVersion string    `json:"package_version"`
 }
 
 // WebhookManager describes the methods a WebhookManager implementation must
@@ -22,9 +80,12 @@ type Webhook struct {
 type WebhookManager interface {
 	Add(ctx context.Context, orgName string, wh *Webhook) error
 	Delete(ctx context.Context, webhookID string) error
+	GetDeliveriesJSON(ctx context.Context, webhookID string) ([]byte, error)
 	GetJSON(ctx context.Context, webhookID string) ([]byte, error)
 	GetOwnedByOrgJSON(ctx context.Context, orgName string) ([]byte, error)
 	GetOwnedByUserJSON(ctx context.Context) ([]byte, error)
+	GetSecret(ctx context.Context, webhookID string) (string, error)
 	GetSubscribedTo(ctx context.Context, e *Event) ([]*Webhook, error)
+	Redeliver(ctx context.Context, webhookID, webhookDeliveryID string) error
 	Update(ctx context.Context, wh *Webhook) error
 }
@@ -0,0 +1,312 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+const (
+	// chatDefaultColor represents the color used to highlight chat messages.
+	chatDefaultColor = 0x417598
+
+	// chatAlertColor represents the color used to highlight chat messages
+	// that require attention (i.e. security alerts or tracking errors).
+	chatAlertColor = 0xC00000
+
+	// chatMaxLines represents the maximum number of list items (i.e. changes
+	// or tracking errors) included in a chat message.
+	chatMaxLines = 10
+
+	// chatMaxLineLength represents the maximum length of each of the list
+	// items included in a chat message.
+	chatMaxLineLength = 300
+)
+
+// chatMessage represents a notification message to be posted to a chat
+// service. It is built from the notification data and then rendered using
+// the payload format expected by each of the services supported.
+type chatMessage struct {
+	title  string
+	url    string
+	text   string
+	lines  []string
+	fields []*chatField
+	color  int
+}
+
+// chatField represents a field included in a chat message.
+type chatField struct {
+	name  string
+	value string
+}
+
+// newPkgChatMessage creates a new chat message from the package notification
+// template data provided.
+func newPkgChatMessage(d *hub.PackageNotificationTemplateData) *chatMessage {
+	p := d.Package
+	r, _ := p["repository"].(map[string]interface{})
+	m := &chatMessage{
+		url:   toString(p["url"]),
+		color: chatDefaultColor,
+		fields: []*chatField{
+			{name: "Repository", value: fmt.Sprintf("%s (%s)", r["name"], r["kind"])},
+			{name: "Publisher", value: toString(r["publisher"])},
+		},
+	}
+
+	switch d.Event["kind"] {
+	case "package.security-alert":
+		m.title = fmt.Sprintf("%s version %s security alert", p["name"], p["version"])
+		m.text = "Some vulnerabilities have been detected in this version."
+		m.color = chatAlertColor
+		vulnerabilities, _ := d.Event["addedVulnerabilities"].([]interface{})
+		for _, v := range vulnerabilities {
+			v, _ := v.(map[string]interface{})
+			m.lines = append(m.lines, fmt.Sprintf("%s (%s)", v["id"], v["severity"]))
+		}
+		summary, _ := p["securityReportSummary"].(map[string]interface{})
+		for _, severity := range []string{"Critical", "High", "Medium", "Low", "Unknown"} {
+			if n, ok := summary[strings.ToLower(severity)].(int); ok && n > 0 {
+				m.fields = append(m.fields, &chatField{
+					name:  severity,
+					value: fmt.Sprintf("%d", n),
+				})
+			}
+		}
+	default:
+		m.title = fmt.Sprintf("%s version %s released", p["name"], p["version"])
+		m.text = fmt.Sprintf("Version %s of %s has been released.", p["version"], p["name"])
+		m.lines, _ = p["changes"].([]string)
+		if prerelease, _ := p["prerelease"].(bool); prerelease {
+			m.fields = append(m.fields, &chatField{name: "Pre-release", value: "Yes"})
+		}
+		if securityUpdates, _ := p["containsSecurityUpdates"].(bool); securityUpdates {
+			m.fields = append(m.fields, &chatField{name: "Contains security updates", value: "Yes"})
+		}
+	}
+
+	return m
+}
+
+// newRepoChatMessage creates a new chat message from the repository
+// notification template data provided.
+func newRepoChatMessage(d *hub.RepositoryNotificationTemplateData) *chatMessage {
+	r := d.Repository
+	m := &chatMessage{
+		color: chatDefaultColor,
+		fields: []*chatField{
+			{name: "Repository", value: fmt.Sprintf("%s (%s)", r["name"], r["kind"])},
+		},
+	}
+
+	switch d.Event["kind"] {
+	case "repository.tracking-errors":
+		m.title = fmt.Sprintf("Something went wrong tracking repository %s", r["name"])
+		m.text = "We encountered some errors while tracking this repository."
+		m.url = fmt.Sprintf("%s/control-panel/repositories?user-alias=%s&org-name=%s&repo-name=%s",
+			d.BaseURL,
+			r["userAlias"],
+			r["organizationName"],
+			r["name"],
+		)
+		m.color = chatAlertColor
+		errs, _ := r["lastTrackingErrors"].([]string)
+		for _, err := range errs {
+			if err != "" {
+				m.lines = append(m.lines, err)
+			}
+		}
+	case "repository.ownership-claim":
+		m.title = fmt.Sprintf("%s repository ownership has been claimed", r["name"])
+		owner := fmt.Sprintf("user %s", r["userAlias"])
+		if toString(r["userAlias"]) == "" {
+			owner = fmt.Sprintf("organization %s", r["organizationName"])
+		}
+		m.text = fmt.Sprintf("The %s repository has been transferred to %s, who claimed its ownership.", r["name"], owner)
+		m.url = d.BaseURL
+	}
+
+	return m
+}
+
+// BuildPkgChatPayload builds the payload of a chat notification about the
+// package notification data provided, using the format expected by the chat
+// service the webhook kind provided refers to.
+func BuildPkgChatPayload(kind hub.WebhookKind, d *hub.PackageNotificationTemplateData) ([]byte, error) {
+	return buildChatPayload(kind, newPkgChatMessage(d))
+}
+
+// BuildRepoChatPayload builds the payload of a chat notification about the
+// repository notification data provided, using the format expected by the
+// chat service the webhook kind provided refers to.
+func BuildRepoChatPayload(kind hub.WebhookKind, d *hub.RepositoryNotificationTemplateData) ([]byte, error) {
+	return buildChatPayload(kind, newRepoChatMessage(d))
+}
+
+// IsChatWebhook checks if the webhook kind provided refers to a chat service,
+// so that its payload is built in instead of using a template.
+func IsChatWebhook(kind hub.WebhookKind) bool {
+	switch kind {
+	case hub.SlackWebhook, hub.MicrosoftTeamsWebhook, hub.DiscordWebhook:
+		return true
+	default:
+		return false
+	}
+}
+
+// buildChatPayload renders the chat message provided using the payload
+// format expected by the chat service the webhook kind provided refers to.
+func buildChatPayload(kind hub.WebhookKind, m *chatMessage) ([]byte, error) {
+	switch kind {
+	case hub.SlackWebhook:
+		return buildSlackPayload(m)
+	case hub.MicrosoftTeamsWebhook:
+		return buildMicrosoftTeamsPayload(m)
+	case hub.DiscordWebhook:
+		return buildDiscordPayload(m)
+	default:
+		return nil, fmt.Errorf("unsupported webhook kind: %d", kind)
+	}
+}
+
+// buildSlackPayload renders the chat message provided as a Slack incoming
+// webhook payload, using Block Kit.
+func buildSlackPayload(m *chatMessage) ([]byte, error) {
+	e := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
+	title := fmt.Sprintf("*%s*", e(m.title))
+	if m.url != "" {
+		title = fmt.Sprintf("*<%s|%s>*", m.url, e(m.title))
+	}
+	text := title + "\n" + e(m.text)
+	for _, line := range truncateLines(m.lines) {
+		text += "\n• " + e(line)
+	}
+	blocks := []map[string]interface{}{
+		{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": text},
+		},
+	}
+	if len(m.fields) > 0 {
+		fields := make([]map[string]interface{}, 0, len(m.fields))
+		for _, f := range m.fields {
+			fields = append(fields, map[string]interface{}{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("*%s*\n%s", e(f.name), e(f.value)),
+			})
+		}
+		blocks = append(blocks, map[string]interface{}{
+			"type":   "section",
+			"fields": fields,
+		})
+	}
+	return json.Marshal(map[string]interface{}{
+		"text":   m.title,
+		"blocks": blocks,
+	})
+}
+
+// buildMicrosoftTeamsPayload renders the chat message provided as a Microsoft
+// Teams incoming webhook payload, using a message card.
+func buildMicrosoftTeamsPayload(m *chatMessage) ([]byte, error) {
+	text := m.text
+	for _, line := range truncateLines(m.lines) {
+		text += "\n\n- " + line
+	}
+	facts := make([]map[string]interface{}, 0, len(m.fields))
+	for _, f := range m.fields {
+		facts = append(facts, map[string]interface{}{
+			"name":  f.name,
+			"value": f.value,
+		})
+	}
+	card := map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    m.title,
+		"themeColor": fmt.Sprintf("%06X", m.color),
+		"title":      m.title,
+		"text":       text,
+		"sections": []map[string]interface{}{
+			{"facts": facts},
+		},
+	}
+	if m.url != "" {
+		card["potentialAction"] = []map[string]interface{}{
+			{
+				"@type": "OpenUri",
+				"name":  "View in Artifact Hub",
+				"targets": []map[string]interface{}{
+					{"os": "default", "uri": m.url},
+				},
+			},
+		}
+	}
+	return json.Marshal(card)
+}
+
+// buildDiscordPayload renders the chat message provided as a Discord webhook
+// payload, using an embed.
+func buildDiscordPayload(m *chatMessage) ([]byte, error) {
+	description := m.text
+	for _, line := range truncateLines(m.lines) {
+		description += "\n• " + line
+	}
+	fields := make([]map[string]interface{}, 0, len(m.fields))
+	for _, f := range m.fields {
+		fields = append(fields, map[string]interface{}{
+			"name":   f.name,
+			"value":  f.value,
+			"inline": true,
+		})
+	}
+	embed := map[string]interface{}{
+		"title":       truncate(m.title, 256),
+		"description": description,
+		"color":       m.color,
+		"fields":      fields,
+	}
+	if m.url != "" {
+		embed["url"] = m.url
+	}
+	return json.Marshal(map[string]interface{}{
+		"username": "Artifact Hub",
+		"embeds":   []map[string]interface{}{embed},
+	})
+}
+
+// truncateLines limits the number and length of the lines provided so that
+// they fit in the chat services messages.
+func truncateLines(lines []string) []string {
+	truncated := make([]string, 0, len(lines))
+	for i, line := range lines {
+		if i == chatMaxLines {
+			truncated = append(truncated, fmt.Sprintf("(%d more)", len(lines)-chatMaxLines))
+			break
+		}
+		truncated = append(truncated, truncate(line, chatMaxLineLength))
+	}
+	return truncated
+}
+
+// truncate truncates the string provided to the maximum number of characters
+// provided, adding an ellipsis when needed.
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max-1]) + "…"
+}
+
+// toString returns the string representation of the value provided, or an
+// empty string if it is nil.
+func toString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,173 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildChatPayload(t *testing.T) {
+	pkgData := &hub.PackageNotificationTemplateData{
+		BaseURL: "http://baseURL",
+		Event: map[string]interface{}{
+			"id":   "eventID",
+			"kind": "package.new-release",
+		},
+		Package: map[string]interface{}{
+			"name":                    "package1",
+			"version":                 "1.0.0",
+			"url":                     "http://baseURL/packages/helm/repo1/package1/1.0.0",
+			"changes":                 []string{"Cool feature", "Bug <fixed>"},
+			"containsSecurityUpdates": true,
+			"prerelease":              false,
+			"repository": map[string]interface{}{
+				"kind":      "helm",
+				"name":      "repo1",
+				"publisher": "org1",
+			},
+		},
+	}
+	repoData := &hub.RepositoryNotificationTemplateData{
+		BaseURL: "http://baseURL",
+		Event: map[string]interface{}{
+			"id":   "eventID",
+			"kind": "repository.tracking-errors",
+		},
+		Repository: map[string]interface{}{
+			"kind":               "helm",
+			"name":               "repo1",
+			"userAlias":          "user1",
+			"organizationName":   "",
+			"lastTrackingErrors": []string{"error 1", "error 2"},
+		},
+	}
+
+	t.Run("slack", func(t *testing.T) {
+		t.Parallel()
+		payload, err := BuildPkgChatPayload(hub.SlackWebhook, pkgData)
+		require.NoError(t, err)
+		var p struct {
+			Text   string `json:"text"`
+			Blocks []struct {
+				Type string `json:"type"`
+				Text struct {
+					Text string `json:"text"`
+				} `json:"text"`
+				Fields []struct {
+					Text string `json:"text"`
+				} `json:"fields"`
+			} `json:"blocks"`
+		}
+		require.NoError(t, json.Unmarshal(payload, &p))
+		assert.Equal(t, "package1 version 1.0.0 released", p.Text)
+		require.Len(t, p.Blocks, 2)
+		assert.Equal(t, "*<http://baseURL/packages/helm/repo1/package1/1.0.0|package1 version 1.0.0 released>*\n"+
+			"Version 1.0.0 of package1 has been released.\n• Cool feature\n• Bug &lt;fixed&gt;", p.Blocks[0].Text.Text)
+		require.Len(t, p.Blocks[1].Fields, 3)
+		assert.Equal(t, "*Repository*\nrepo1 (helm)", p.Blocks[1].Fields[0].Text)
+		assert.Equal(t, "*Contains security updates*\nYes", p.Blocks[1].Fields[2].Text)
+	})
+
+	t.Run("microsoft teams", func(t *testing.T) {
+		t.Parallel()
+		payload, err := buildChatPayload(hub.MicrosoftTeamsWebhook, newRepoChatMessage(repoData))
+		require.NoError(t, err)
+		var p map[string]interface{}
+		require.NoError(t, json.Unmarshal(payload, &p))
+		assert.Equal(t, "MessageCard", p["@type"])
+		assert.Equal(t, "C00000", p["themeColor"])
+		assert.Equal(t, "Something went wrong tracking repository repo1", p["title"])
+		assert.Equal(t, "We encountered some errors while tracking this repository.\n\n- error 1\n\n- error 2", p["text"])
+		actions := p["potentialAction"].([]interface{})
+		targets := actions[0].(map[string]interface{})["targets"].([]interface{})
+		assert.Equal(t,
+			"http://baseURL/control-panel/repositories?user-alias=user1&org-name=&repo-name=repo1",
+			targets[0].(map[string]interface{})["uri"],
+		)
+	})
+
+	t.Run("discord", func(t *testing.T) {
+		t.Parallel()
+		payload, err := buildChatPayload(hub.DiscordWebhook, newRepoChatMessage(&hub.RepositoryNotificationTemplateData{
+			BaseURL: "http://baseURL",
+			Event: map[string]interface{}{
+				"id":   "eventID",
+				"kind": "repository.ownership-claim",
+			},
+			Repository: map[string]interface{}{
+				"kind":             "helm",
+				"name":             "repo1",
+				"userAlias":        "",
+				"organizationName": "org1",
+			},
+		}))
+		require.NoError(t, err)
+		var p struct {
+			Embeds []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+				Color       int    `json:"color"`
+			} `json:"embeds"`
+		}
+		require.NoError(t, json.Unmarshal(payload, &p))
+		require.Len(t, p.Embeds, 1)
+		assert.Equal(t, "repo1 repository ownership has been claimed", p.Embeds[0].Title)
+		assert.Equal(t, "http://baseURL", p.Embeds[0].URL)
+		assert.Equal(t, "The repo1 repository has been transferred to organization org1, who claimed its ownership.", p.Embeds[0].Description)
+		assert.Equal(t, chatDefaultColor, p.Embeds[0].Color)
+	})
+
+	t.Run("security alert", func(t *testing.T) {
+		t.Parallel()
+		m := newPkgChatMessage(&hub.PackageNotificationTemplateData{
+			Event: map[string]interface{}{
+				"id":   "eventID",
+				"kind": "package.security-alert",
+				"addedVulnerabilities": []interface{}{
+					map[string]interface{}{"id": "CVE-0000-0001", "severity": "critical"},
+				},
+			},
+			Package: map[string]interface{}{
+				"name":    "package1",
+				"version": "1.0.0",
+				"securityReportSummary": map[string]interface{}{
+					"critical": 1,
+					"high":     0,
+				},
+				"repository": map[string]interface{}{
+					"kind": "helm",
+					"name": "repo1",
+				},
+			},
+		})
+		assert.Equal(t, "package1 version 1.0.0 security alert", m.title)
+		assert.Equal(t, chatAlertColor, m.color)
+		assert.Equal(t, []string{"CVE-0000-0001 (critical)"}, m.lines)
+		assert.Equal(t, &chatField{name: "Critical", value: "1"}, m.fields[len(m.fields)-1])
+	})
+
+	t.Run("unsupported webhook kind", func(t *testing.T) {
+		t.Parallel()
+		_, err := BuildPkgChatPayload(hub.GenericWebhook, pkgData)
+		assert.Error(t, err)
+	})
+}
+
+func TestTruncateLines(t *testing.T) {
+	lines := make([]string, 0, chatMaxLines+5)
+	for i := 0; i < chatMaxLines+5; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	lines[0] = strings.Repeat("a", chatMaxLineLength+1)
+
+	truncated := truncateLines(lines)
+	require.Len(t, truncated, chatMaxLines+1)
+	assert.Equal(t, strings.Repeat("a", chatMaxLineLength-1)+"…", truncated[0])
+	assert.Equal(t, "(5 more)", truncated[chatMaxLines])
+}
@@ -0,0 +1,97 @@
+package notification
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// digestTemplateData represents the data available to the digest email
+// template.
+type digestTemplateData struct {
+	BaseURL              string
+	Period               string
+	Repositories         []*digestRepository
+	UpdatesCount         int
+	SecurityUpdatesCount int
+}
+
+// digestRepository represents a repository included in a digest, with the
+// packages notifications about it.
+type digestRepository struct {
+	Kind      string
+	Name      string
+	Publisher string
+	Updates   []*digestUpdate
+}
+
+// digestUpdate represents a package notification included in a digest.
+type digestUpdate struct {
+	*hub.PackageNotificationTemplateData
+	Security bool
+}
+
+// newDigestTemplateData creates a new digest template data instance from the
+// packages notifications template data provided. Notifications are grouped by
+// repository, and those related to security are listed first.
+func newDigestTemplateData(
+	baseURL string,
+	p hub.DeliveryPreference,
+	notifications []*hub.PackageNotificationTemplateData,
+) *digestTemplateData {
+	d := &digestTemplateData{
+		BaseURL: baseURL,
+		Period:  "daily",
+	}
+	if p == hub.WeeklyDigestDelivery {
+		d.Period = "weekly"
+	}
+
+	repositories := make(map[string]*digestRepository)
+	for _, n := range notifications {
+		r, _ := n.Package["repository"].(map[string]interface{})
+		key := fmt.Sprintf("%s/%s/%s", r["kind"], r["publisher"], r["name"])
+		dr, ok := repositories[key]
+		if !ok {
+			dr = &digestRepository{
+				Kind:      toString(r["kind"]),
+				Name:      toString(r["name"]),
+				Publisher: toString(r["publisher"]),
+			}
+			repositories[key] = dr
+			d.Repositories = append(d.Repositories, dr)
+		}
+		u := &digestUpdate{PackageNotificationTemplateData: n}
+		if n.Event["kind"] == "package.security-alert" {
+			u.Security = true
+		}
+		if securityUpdates, _ := n.Package["containsSecurityUpdates"].(bool); securityUpdates {
+			u.Security = true
+		}
+		if u.Security {
+			d.SecurityUpdatesCount++
+		}
+		dr.Updates = append(dr.Updates, u)
+		d.UpdatesCount++
+	}
+	for _, dr := range d.Repositories {
+		sort.SliceStable(dr.Updates, func(i, j int) bool {
+			return dr.Updates[i].Security && !dr.Updates[j].Security
+		})
+	}
+
+	return d
+}
+
+// subject returns the subject of the digest email.
+func (d *digestTemplateData) subject() string {
+	subject := fmt.Sprintf("Your %s Artifact Hub digest: %d updates", d.Period, d.UpdatesCount)
+	if d.UpdatesCount == 1 {
+		subject = fmt.Sprintf("Your %s Artifact Hub digest: 1 update", d.Period)
+	}
+	if d.SecurityUpdatesCount > 0 {
+		subject += fmt.Sprintf(" (%d related to security)", d.SecurityUpdatesCount)
+	}
+	return subject
+}
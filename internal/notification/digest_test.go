@@ -0,0 +1,66 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDigestTemplateData(t *testing.T) {
+	newPkgTmplData := func(eventKind, name, repoName string, securityUpdates bool) *hub.PackageNotificationTemplateData {
+		return &hub.PackageNotificationTemplateData{
+			Event: map[string]interface{}{
+				"kind": eventKind,
+			},
+			Package: map[string]interface{}{
+				"name":                    name,
+				"version":                 "1.0.0",
+				"containsSecurityUpdates": securityUpdates,
+				"repository": map[string]interface{}{
+					"kind":      "helm",
+					"name":      repoName,
+					"publisher": "org1",
+				},
+			},
+		}
+	}
+	n1 := newPkgTmplData("package.new-release", "pkg1", "repo1", false)
+	n2 := newPkgTmplData("package.new-release", "pkg2", "repo2", false)
+	n3 := newPkgTmplData("package.new-release", "pkg3", "repo1", true)
+	n4 := newPkgTmplData("package.security-alert", "pkg4", "repo2", false)
+
+	t.Run("daily digest", func(t *testing.T) {
+		t.Parallel()
+		d := newDigestTemplateData("http://baseURL", hub.DailyDigestDelivery, []*hub.PackageNotificationTemplateData{
+			n1, n2, n3, n4,
+		})
+		assert.Equal(t, "http://baseURL", d.BaseURL)
+		assert.Equal(t, "daily", d.Period)
+		assert.Equal(t, 4, d.UpdatesCount)
+		assert.Equal(t, 2, d.SecurityUpdatesCount)
+		require.Len(t, d.Repositories, 2)
+		assert.Equal(t, "repo1", d.Repositories[0].Name)
+		assert.Equal(t, "org1", d.Repositories[0].Publisher)
+		assert.Equal(t, []*digestUpdate{
+			{PackageNotificationTemplateData: n3, Security: true},
+			{PackageNotificationTemplateData: n1},
+		}, d.Repositories[0].Updates)
+		assert.Equal(t, "repo2", d.Repositories[1].Name)
+		assert.Equal(t, []*digestUpdate{
+			{PackageNotificationTemplateData: n4, Security: true},
+			{PackageNotificationTemplateData: n2},
+		}, d.Repositories[1].Updates)
+		assert.Equal(t, "Your daily Artifact Hub digest: 4 updates (2 related to security)", d.subject())
+	})
+
+	t.Run("weekly digest", func(t *testing.T) {
+		t.Parallel()
+		d := newDigestTemplateData("http://baseURL", hub.WeeklyDigestDelivery, []*hub.PackageNotificationTemplateData{
+			n1,
+		})
+		assert.Equal(t, "weekly", d.Period)
+		assert.Equal(t, "Your weekly Artifact Hub digest: 1 update", d.subject())
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/patrickmn/go-cache"
 	"github.com/spf13/viper"
 )
@@ -15,6 +16,10 @@ const (
 	defaultNumWorkers      = 2
 	cacheDefaultExpiration = 5 * time.Minute
 	cacheCleanupInterval   = 10 * time.Minute
+
+	// notificationCreatedChannel represents the database channel where
+	// notifications are sent when new notifications are registered.
+	notificationCreatedChannel = "notification_created"
 )
 
 // Services is a wrapper around several internal services used to handle
@@ -26,10 +31,12 @@ type Services struct {
 	SubscriptionManager hub.SubscriptionManager
 	RepositoryManager   hub.RepositoryManager
 	PackageManager      hub.PackageManager
+	Encrypter           hub.Encrypter
 }
 
 // Dispatcher handles a group of workers in charge of delivering notifications.
 type Dispatcher struct {
+	db         hub.DB
 	numWorkers int
 	workers    []*Worker
 }
@@ -38,6 +45,7 @@ type Dispatcher struct {
 func NewDispatcher(cfg *viper.Viper, svc *Services, opts ...func(d *Dispatcher)) *Dispatcher {
 	// Setup dispatcher
 	d := &Dispatcher{
+		db:         svc.DB,
 		numWorkers: defaultNumWorkers,
 	}
 	for _, o := range opts {
@@ -48,9 +56,23 @@ func NewDispatcher(cfg *viper.Viper, svc *Services, opts ...func(d *Dispatcher))
 	c := cache.New(cacheDefaultExpiration, cacheCleanupInterval)
 	baseURL := cfg.GetString("server.baseURL")
 	httpClient := &http.Client{Timeout: 10 * time.Second}
+	webhookMaxAttempts := defaultWebhookMaxAttempts
+	if cfg.IsSet("notifications.webhooks.maxAttempts") {
+		webhookMaxAttempts = cfg.GetInt("notifications.webhooks.maxAttempts")
+	}
+	webhookRetryDelay := defaultWebhookRetryDelay
+	if cfg.IsSet("notifications.webhooks.retryDelay") {
+		webhookRetryDelay = cfg.GetDuration("notifications.webhooks.retryDelay")
+	}
+	webhookMaxRetryDelay := defaultWebhookMaxRetryDelay
+	if cfg.IsSet("notifications.webhooks.maxRetryDelay") {
+		webhookMaxRetryDelay = cfg.GetDuration("notifications.webhooks.maxRetryDelay")
+	}
+	webhookRetries := WithWebhookRetries(webhookMaxAttempts, webhookRetryDelay, webhookMaxRetryDelay)
+	unsubscribeKey := WithUnsubscribeKey([]byte(cfg.GetString("server.unsubscribe.hashKey")))
 	d.workers = make([]*Worker, 0, d.numWorkers)
 	for i := 0; i < d.numWorkers; i++ {
-		d.workers = append(d.workers, NewWorker(svc, c, baseURL, httpClient))
+		d.workers = append(d.workers, NewWorker(svc, c, baseURL, httpClient, webhookRetries, unsubscribeKey))
 	}
 
 	return d
@@ -77,8 +99,21 @@ func (d *Dispatcher) Run(ctx context.Context, wg *sync.WaitGroup) {
 		go w.Run(wctx, wwg)
 	}
 
+	// Wake workers up when new notifications are registered in the database, so
+	// that they don't have to wait until the queue is polled again
+	if len(d.workers) > 0 {
+		go util.DBListen(wctx, d.db, notificationCreatedChannel, d.wakeUpWorkers)
+	}
+
 	// Stop workers when dispatcher is asked to stop
 	<-ctx.Done()
 	stopWorkers()
 	wwg.Wait()
 }
+
+// wakeUpWorkers wakes up all the dispatcher's workers.
+func (d *Dispatcher) wakeUpWorkers() {
+	for _, w := range d.workers {
+		w.wakeUp()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/jackc/pgx/v4"
@@ -13,7 +14,10 @@ import (
 const (
 	// Database queries
 	addNotificationDBQ          = `select add_notification($1::jsonb)`
+	addWebhookDeliveryDBQ       = `select add_webhook_delivery($1::jsonb)`
+	getPendingDigestDBQ         = `select get_pending_digest()`
 	getPendingNotificationDBQ   = `select get_pending_notification()`
+	scheduleRetryDBQ            = `select schedule_notification_retry($1::uuid, $2::int, $3::text)`
 	updateNotificationStatusDBQ = `select update_notification_status($1::uuid, $2::boolean, $3::text)`
 )
 
@@ -51,6 +55,20 @@ func (m *Manager) Add(ctx context.Context, tx pgx.Tx, n *hub.Notification) error
 	return err
 }
 
+// AddWebhookDelivery registers the provided webhook delivery attempt in the
+// database.
+func (m *Manager) AddWebhookDelivery(ctx context.Context, tx pgx.Tx, d *hub.WebhookDelivery) error {
+	if _, err := uuid.FromString(d.NotificationID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid notification id")
+	}
+	if _, err := uuid.FromString(d.WebhookID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid webhook id")
+	}
+	dJSON, _ := json.Marshal(d)
+	_, err := tx.Exec(ctx, addWebhookDeliveryDBQ, dJSON)
+	return err
+}
+
 // GetPending returns a pending notification to be delivered if available.
 func (m *Manager) GetPending(ctx context.Context, tx pgx.Tx) (*hub.Notification, error) {
 	var dataJSON []byte
@@ -64,6 +82,40 @@ func (m *Manager) GetPending(ctx context.Context, tx pgx.Tx) (*hub.Notification,
 	return n, nil
 }
 
+// GetPendingDigest returns a digest with the pending notifications of a user
+// who prefers to receive them together, if any is due to be delivered.
+func (m *Manager) GetPendingDigest(ctx context.Context, tx pgx.Tx) (*hub.NotificationDigest, error) {
+	var dataJSON []byte
+	if err := tx.QueryRow(ctx, getPendingDigestDBQ).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	var dg *hub.NotificationDigest
+	if err := json.Unmarshal(dataJSON, &dg); err != nil {
+		return nil, err
+	}
+	return dg, nil
+}
+
+// ScheduleRetry schedules a new delivery attempt of the provided notification
+// after the delay provided, registering the error of the last attempt.
+func (m *Manager) ScheduleRetry(
+	ctx context.Context,
+	tx pgx.Tx,
+	notificationID string,
+	delay time.Duration,
+	deliveryErr error,
+) error {
+	if _, err := uuid.FromString(notificationID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid notification id")
+	}
+	var deliveryErrStr string
+	if deliveryErr != nil {
+		deliveryErrStr = deliveryErr.Error()
+	}
+	_, err := tx.Exec(ctx, scheduleRetryDBQ, notificationID, int(delay.Seconds()), deliveryErrStr)
+	return err
+}
+
 // UpdateStatus the provided notification status in the database.
 func (m *Manager) UpdateStatus(
 	ctx context.Context,
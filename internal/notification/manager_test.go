@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
@@ -104,6 +105,71 @@ func TestAdd(t *testing.T) {
 	})
 }
 
+func TestAddWebhookDelivery(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			d      *hub.WebhookDelivery
+		}{
+			{
+				"invalid notification id",
+				&hub.WebhookDelivery{
+					NotificationID: "invalid",
+				},
+			},
+			{
+				"invalid webhook id",
+				&hub.WebhookDelivery{
+					NotificationID: validUUID,
+					WebhookID:      "invalid",
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager()
+				err := m.AddWebhookDelivery(ctx, nil, tc.d)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	d := &hub.WebhookDelivery{
+		NotificationID: validUUID,
+		WebhookID:      validUUID,
+		Attempt:        1,
+		StatusCode:     200,
+		LatencyMS:      10,
+	}
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, addWebhookDeliveryDBQ, mock.Anything).Return(tests.ErrFakeDB)
+		m := NewManager()
+
+		err := m.AddWebhookDelivery(ctx, tx, d)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, addWebhookDeliveryDBQ, mock.Anything).Return(nil)
+		m := NewManager()
+
+		err := m.AddWebhookDelivery(ctx, tx, d)
+		assert.NoError(t, err)
+		tx.AssertExpectations(t)
+	})
+}
+
 func TestGetPending(t *testing.T) {
 	ctx := context.Background()
 
@@ -156,6 +222,106 @@ func TestGetPending(t *testing.T) {
 	})
 }
 
+func TestGetPendingDigest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("QueryRow", ctx, getPendingDigestDBQ).Return(nil, tests.ErrFakeDB)
+		m := NewManager()
+
+		dg, err := m.GetPendingDigest(ctx, tx)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, dg)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		dailyDigest := hub.DailyDigestDelivery
+		expectedDigest := &hub.NotificationDigest{
+			User: &hub.User{
+				UserID:             "userID",
+				Email:              "user1@email.com",
+				DeliveryPreference: &dailyDigest,
+			},
+			Notifications: []*hub.Notification{
+				{
+					NotificationID: "notificationID",
+					Event: &hub.Event{
+						EventKind:      hub.NewRelease,
+						PackageID:      "packageID",
+						PackageVersion: "1.0.0",
+					},
+				},
+			},
+		}
+
+		tx := &tests.TXMock{}
+		tx.On("QueryRow", ctx, getPendingDigestDBQ).Return([]byte(`
+		{
+			"user": {
+				"user_id": "userID",
+				"email": "user1@email.com",
+				"delivery_preference": 1
+			},
+			"notifications": [
+				{
+					"notification_id": "notificationID",
+					"event": {
+						"event_kind": 0,
+						"package_id": "packageID",
+						"package_version": "1.0.0"
+					}
+				}
+			]
+		}
+		`), nil)
+		m := NewManager()
+
+		dg, err := m.GetPendingDigest(ctx, tx)
+		require.NoError(t, err)
+		assert.Equal(t, expectedDigest, dg)
+		tx.AssertExpectations(t)
+	})
+}
+
+func TestScheduleRetry(t *testing.T) {
+	ctx := context.Background()
+	notificationID := "00000000-0000-0000-0000-000000000001"
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager()
+		err := m.ScheduleRetry(ctx, nil, "invalidNotificationID", time.Minute, nil)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "invalid notification id")
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, scheduleRetryDBQ, notificationID, 60, tests.ErrFake.Error()).Return(tests.ErrFakeDB)
+		m := NewManager()
+
+		err := m.ScheduleRetry(ctx, tx, notificationID, time.Minute, tests.ErrFake)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, scheduleRetryDBQ, notificationID, 60, tests.ErrFake.Error()).Return(nil)
+		m := NewManager()
+
+		err := m.ScheduleRetry(ctx, tx, notificationID, time.Minute, tests.ErrFake)
+		assert.NoError(t, err)
+		tx.AssertExpectations(t)
+	})
+}
+
 func TestUpdateStatus(t *testing.T) {
 	ctx := context.Background()
 	notificationID := "00000000-0000-0000-0000-000000000001"
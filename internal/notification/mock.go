@@ -2,6 +2,7 @@ package notification
 
 import (
 	"context"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/jackc/pgx/v4"
@@ -19,6 +20,12 @@ func (m *ManagerMock) Add(ctx context.Context, tx pgx.Tx, n *hub.Notification) e
 	return args.Error(0)
 }
 
+// AddWebhookDelivery implements the NotificationManager interface.
+func (m *ManagerMock) AddWebhookDelivery(ctx context.Context, tx pgx.Tx, d *hub.WebhookDelivery) error {
+	args := m.Called(ctx, tx, d)
+	return args.Error(0)
+}
+
 // GetPending implements the NotificationManager interface.
 func (m *ManagerMock) GetPending(ctx context.Context, tx pgx.Tx) (*hub.Notification, error) {
 	args := m.Called(ctx, tx)
@@ -26,6 +33,25 @@ func (m *ManagerMock) GetPending(ctx context.Context, tx pgx.Tx) (*hub.Notificat
 	return data, args.Error(1)
 }
 
+// GetPendingDigest implements the NotificationManager interface.
+func (m *ManagerMock) GetPendingDigest(ctx context.Context, tx pgx.Tx) (*hub.NotificationDigest, error) {
+	args := m.Called(ctx, tx)
+	data, _ := args.Get(0).(*hub.NotificationDigest)
+	return data, args.Error(1)
+}
+
+// ScheduleRetry implements the NotificationManager interface.
+func (m *ManagerMock) ScheduleRetry(
+	ctx context.Context,
+	tx pgx.Tx,
+	notificationID string,
+	delay time.Duration,
+	deliveryErr error,
+) error {
+	args := m.Called(ctx, tx, notificationID, delay, deliveryErr)
+	return args.Error(0)
+}
+
 // UpdateStatus implements the NotificationManager interface.
 func (m *ManagerMock) UpdateStatus(
 	ctx context.Context,
@@ -0,0 +1,48 @@
+package notification
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// WebhookSignatureHeader represents the header used to send the
+	// signature of the webhooks payloads.
+	WebhookSignatureHeader = "X-ArtifactHub-Signature"
+
+	// WebhookLegacySecretHeader represents the header used to send the
+	// webhooks secret as is, when the legacy mode has been enabled.
+	WebhookLegacySecretHeader = "X-ArtifactHub-Secret"
+)
+
+// SignWebhookRequest adds to the webhook request provided the headers that
+// allow receivers to verify its authenticity. The signature header has the
+// format t=<timestamp>,v1=<signature>, where the signature is the hex encoded
+// HMAC-SHA256 of the timestamp and the payload joined by a dot, using the
+// webhook secret as key. Receivers can use the timestamp to reject replayed
+// requests. When legacySecretHeader is true, the secret will also be sent as
+// is in the legacy secret header.
+func SignWebhookRequest(req *http.Request, payload []byte, secret string, legacySecretHeader bool) {
+	if secret == "" {
+		return
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(WebhookSignatureHeader, fmt.Sprintf("t=%s,v1=%s", ts, WebhookSignature(secret, ts, payload)))
+	if legacySecretHeader {
+		req.Header.Set(WebhookLegacySecretHeader, secret)
+	}
+}
+
+// WebhookSignature returns the hex encoded HMAC-SHA256 signature of the
+// timestamp and payload provided, using the secret as key.
+func WebhookSignature(secret, ts string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(ts + "."))
+	_, _ = mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,56 @@
+package notification
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWebhookRequest(t *testing.T) {
+	payload := []byte("payload")
+
+	t.Run("no secret provided", func(t *testing.T) {
+		t.Parallel()
+		req, _ := http.NewRequest("POST", "http://webhook.url", nil)
+		SignWebhookRequest(req, payload, "", true)
+		assert.Empty(t, req.Header.Get(WebhookSignatureHeader))
+		assert.Empty(t, req.Header.Get(WebhookLegacySecretHeader))
+	})
+
+	t.Run("request signed", func(t *testing.T) {
+		t.Parallel()
+		req, _ := http.NewRequest("POST", "http://webhook.url", nil)
+		SignWebhookRequest(req, payload, "secret", false)
+		assert.Empty(t, req.Header.Get(WebhookLegacySecretHeader))
+
+		parts := strings.Split(req.Header.Get(WebhookSignatureHeader), ",")
+		require.Len(t, parts, 2)
+		require.True(t, strings.HasPrefix(parts[0], "t="))
+		ts, err := strconv.ParseInt(strings.TrimPrefix(parts[0], "t="), 10, 64)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), time.Unix(ts, 0), 1*time.Minute)
+		expectedSignature := WebhookSignature("secret", strconv.FormatInt(ts, 10), payload)
+		assert.Equal(t, "v1="+expectedSignature, parts[1])
+	})
+
+	t.Run("request signed and secret sent in legacy header", func(t *testing.T) {
+		t.Parallel()
+		req, _ := http.NewRequest("POST", "http://webhook.url", nil)
+		SignWebhookRequest(req, payload, "secret", true)
+		assert.NotEmpty(t, req.Header.Get(WebhookSignatureHeader))
+		assert.Equal(t, "secret", req.Header.Get(WebhookLegacySecretHeader))
+	})
+}
+
+func TestWebhookSignature(t *testing.T) {
+	t.Parallel()
+
+	// echo -n "1600000000.payload" | openssl dgst -sha256 -hmac secret
+	expectedSignature := "36955cad05cf254b22576d6b463a5be66c54d719d909f1b39acdf57e6f6f2e10"
+	assert.Equal(t, expectedSignature, WebhookSignature("secret", "1600000000", []byte("payload")))
+}
@@ -0,0 +1,188 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/artifacthub/hub/cmd/hub/handlers/pkg"
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// TemplateFuncs represents the library of functions available to webhooks
+// templates.
+var TemplateFuncs = template.FuncMap{
+	"date":    tmplDate,
+	"default": tmplDefault,
+	"join":    tmplJoin,
+	"json":    tmplJSON,
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+}
+
+// ParseTemplate parses the webhook template provided, making the functions
+// library available to it.
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("").Funcs(TemplateFuncs).Parse(text)
+}
+
+// NewPkgNotificationTemplateData creates the data available to packages
+// notifications templates from the event and package provided.
+func NewPkgNotificationTemplateData(
+	baseURL string,
+	e *hub.Event,
+	p *hub.Package,
+) *hub.PackageNotificationTemplateData {
+	eventData := map[string]interface{}{
+		"id": e.EventID,
+	}
+	switch e.EventKind {
+	case hub.NewRelease:
+		eventData["kind"] = "package.new-release"
+	case hub.SecurityAlert:
+		eventData["kind"] = "package.security-alert"
+		eventData["addedVulnerabilities"] = e.Data["added_vulnerabilities"]
+	}
+	publisher := p.Repository.OrganizationName
+	if publisher == "" {
+		publisher = p.Repository.UserAlias
+	}
+	var securityReportSummary map[string]interface{}
+	if p.SecurityReportSummary != nil {
+		securityReportSummary = map[string]interface{}{
+			"critical": p.SecurityReportSummary.Critical,
+			"high":     p.SecurityReportSummary.High,
+			"medium":   p.SecurityReportSummary.Medium,
+			"low":      p.SecurityReportSummary.Low,
+			"unknown":  p.SecurityReportSummary.Unknown,
+		}
+	}
+
+	return &hub.PackageNotificationTemplateData{
+		BaseURL: baseURL,
+		Event:   eventData,
+		Package: map[string]interface{}{
+			"name":                    p.Name,
+			"version":                 p.Version,
+			"logoImageID":             p.LogoImageID,
+			"url":                     pkg.BuildURL(baseURL, p, e.PackageVersion),
+			"changes":                 p.Changes,
+			"containsSecurityUpdates": p.ContainsSecurityUpdates,
+			"prerelease":              p.Prerelease,
+			"createdAt":               p.CreatedAt,
+			"securityReportSummary":   securityReportSummary,
+			"repository": map[string]interface{}{
+				"kind":      hub.GetKindName(p.Repository.Kind),
+				"name":      p.Repository.Name,
+				"publisher": publisher,
+			},
+		},
+	}
+}
+
+// NewRepoNotificationTemplateData creates the data available to repositories
+// notifications templates from the event and repository provided.
+func NewRepoNotificationTemplateData(
+	baseURL string,
+	e *hub.Event,
+	r *hub.Repository,
+) *hub.RepositoryNotificationTemplateData {
+	var eventKindStr string
+	switch e.EventKind {
+	case hub.RepositoryTrackingErrors:
+		eventKindStr = "repository.tracking-errors"
+	case hub.RepositoryOwnershipClaim:
+		eventKindStr = "repository.ownership-claim"
+	}
+
+	return &hub.RepositoryNotificationTemplateData{
+		BaseURL: baseURL,
+		Event: map[string]interface{}{
+			"id":   e.EventID,
+			"kind": eventKindStr,
+		},
+		Repository: map[string]interface{}{
+			"kind":               hub.GetKindName(r.Kind),
+			"name":               r.Name,
+			"userAlias":          r.UserAlias,
+			"organizationName":   r.OrganizationName,
+			"lastTrackingErrors": strings.Split(r.LastTrackingErrors, "\n"),
+		},
+	}
+}
+
+// tmplDate formats the date provided (a time, a unix timestamp or a RFC3339
+// string) using the layout given. Dates are formatted in UTC.
+func tmplDate(layout string, v interface{}) (string, error) {
+	var t time.Time
+	switch v := v.(type) {
+	case time.Time:
+		t = v
+	case int:
+		t = time.Unix(int64(v), 0)
+	case int64:
+		t = time.Unix(v, 0)
+	case float64:
+		t = time.Unix(int64(v), 0)
+	case string:
+		var err error
+		t, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return "", fmt.Errorf("invalid date: %s", v)
+		}
+	default:
+		return "", fmt.Errorf("unsupported date value: %v", v)
+	}
+	return t.UTC().Format(layout), nil
+}
+
+// tmplDefault returns the default value provided when the value given is
+// empty (nil, zero or with no elements).
+func tmplDefault(def, v interface{}) interface{} {
+	if v == nil {
+		return def
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		if rv.Len() == 0 {
+			return def
+		}
+	default:
+		if rv.IsZero() {
+			return def
+		}
+	}
+	return v
+}
+
+// tmplJoin joins the elements of the list provided using the separator given.
+func tmplJoin(sep string, v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(v, sep)
+	case []interface{}:
+		elems := make([]string, 0, len(v))
+		for _, e := range v {
+			elems = append(elems, toString(e))
+		}
+		return strings.Join(elems, sep)
+	default:
+		return toString(v)
+	}
+}
+
+// tmplJSON returns the json representation of the value provided, which can
+// be safely embedded in json payloads (strings are quoted and escaped).
+func tmplJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,81 @@
+package notification
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTemplate(t *testing.T) {
+	t.Run("invalid template", func(t *testing.T) {
+		t.Parallel()
+		_, err := ParseTemplate("{{ unknown .Package.name }}")
+		assert.Error(t, err)
+	})
+
+	t.Run("template functions", func(t *testing.T) {
+		data := &hub.PackageNotificationTemplateData{
+			Package: map[string]interface{}{
+				"name":        "package1",
+				"description": `Some "quoted" text`,
+				"changes":     []string{"Cool feature", "Bug fixed"},
+				"keywords":    []interface{}{"key1", 2},
+				"createdAt":   int64(1592299234),
+				"prerelease":  false,
+			},
+		}
+		testCases := []struct {
+			tmpl           string
+			expectedOutput string
+		}{
+			{`{{ json .Package.description }}`, `"Some \"quoted\" text"`},
+			{`{{ json .Package.changes }}`, `["Cool feature","Bug fixed"]`},
+			{`{{ json .Package.missing }}`, `null`},
+			{`{{ join ", " .Package.changes }}`, `Cool feature, Bug fixed`},
+			{`{{ join "|" .Package.keywords }}`, `key1|2`},
+			{`{{ join "|" .Package.missing }}`, ``},
+			{`{{ upper .Package.name }}`, `PACKAGE1`},
+			{`{{ lower "PACKAGE1" }}`, `package1`},
+			{`{{ date "2006-01-02 15:04" .Package.createdAt }}`, `2020-06-16 09:20`},
+			{`{{ date "Jan 2, 2006" "2020-06-16T11:20:34+02:00" }}`, `Jun 16, 2020`},
+			{`{{ default "none" .Package.missing }}`, `none`},
+			{`{{ default "no" .Package.prerelease }}`, `no`},
+			{`{{ default "none" .Package.name }}`, `package1`},
+			{`{{ .Package.description | json | printf "%s" }}`, `"Some \"quoted\" text"`},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.tmpl, func(t *testing.T) {
+				t.Parallel()
+				tmpl, err := ParseTemplate(tc.tmpl)
+				require.NoError(t, err)
+				var output bytes.Buffer
+				require.NoError(t, tmpl.Execute(&output, data))
+				assert.Equal(t, tc.expectedOutput, output.String())
+			})
+		}
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		t.Parallel()
+		tmpl, err := ParseTemplate(`{{ date "2006-01-02" "invalid" }}`)
+		require.NoError(t, err)
+		var output bytes.Buffer
+		assert.Error(t, tmpl.Execute(&output, nil))
+	})
+}
+
+func TestTmplDate(t *testing.T) {
+	ts := time.Date(2020, 6, 16, 9, 20, 34, 0, time.UTC)
+	for _, v := range []interface{}{ts, 1592299234, int64(1592299234), float64(1592299234)} {
+		output, err := tmplDate(time.RFC3339, v)
+		require.NoError(t, err)
+		assert.Equal(t, "2020-06-16T09:20:34Z", output)
+	}
+	_, err := tmplDate(time.RFC3339, true)
+	assert.Error(t, err)
+}
@@ -0,0 +1,216 @@
+package notification
+
+import "html/template"
+
+var digestEmailTmpl = template.Must(template.New("").Parse(`
+<!doctype html>
+<html>
+  <head>
+    <meta name="viewport" content="width=device-width">
+    <meta http-equiv="Content-Type" content="text/html; charset=UTF-8">
+    <title>Artifact Hub {{ .Period }} digest</title>
+    <style>
+    @media only screen and (max-width: 620px) {
+      table[class=body] h1 {
+        font-size: 28px !important;
+        margin-bottom: 10px !important;
+      }
+      table[class=body] p,
+            table[class=body] ul,
+            table[class=body] ol,
+            table[class=body] td,
+            table[class=body] span,
+            table[class=body] a {
+        font-size: 16px !important;
+      }
+      table[class=body] .wrapper,
+      table[class=body] .article {
+        padding: 10px !important;
+      }
+      table[class=body] .content {
+        padding: 0 !important;
+      }
+      table[class=body] .container {
+        padding: 0 !important;
+        width: 100% !important;
+      }
+      table[class=body] .main {
+        border-left-width: 0 !important;
+        border-radius: 0 !important;
+        border-right-width: 0 !important;
+      }
+      table[class=body] .btn table {
+        width: 100% !important;
+      }
+      table[class=body] .btn a {
+        width: 100% !important;
+      }
+      table[class=body] .img-responsive {
+        height: auto !important;
+        max-width: 100% !important;
+        width: auto !important;
+      }
+    }
+
+    a[x-apple-data-detectors] {
+      color: inherit !important;
+      text-decoration: none !important;
+      font-size: inherit !important;
+      font-family: inherit !important;
+      font-weight: inherit !important;
+      line-height: inherit !important;
+    }
+
+    @media all {
+      .ExternalClass {
+        width: 100%;
+      }
+      .ExternalClass,
+            .ExternalClass p,
+            .ExternalClass span,
+            .ExternalClass font,
+            .ExternalClass td,
+            .ExternalClass div {
+        line-height: 100%;
+      }
+      .apple-link a {
+        color: inherit !important;
+        font-family: inherit !important;
+        font-size: inherit !important;
+        font-weight: inherit !important;
+        line-height: inherit !important;
+        text-decoration: none !important;
+      }
+      #MessageViewBody a {
+        color: inherit;
+        text-decoration: none;
+        font-size: inherit;
+        font-family: inherit;
+        font-weight: inherit;
+        line-height: inherit;
+      }
+    }
+    </style>
+  </head>
+  <body class="" style="background-color: #f4f4f4; font-family: sans-serif; -webkit-font-smoothing: antialiased; font-size: 14px; line-height: 1.4; margin: 0; padding: 0; -ms-text-size-adjust: 100%; -webkit-text-size-adjust: 100%;">
+    <table border="0" cellpadding="0" cellspacing="0" class="body" style="border-collapse: separate; mso-table-lspace: 0pt; mso-table-rspace: 0pt; width: 100%; background-color: #f4f4f4;">
+      <tr>
+        <td style="font-family: sans-serif; font-size: 14px; vertical-align: top;">&nbsp;</td>
+        <td class="container" style="font-family: sans-serif; font-size: 14px; vertical-align: top; display: block; Margin: 0 auto; max-width: 580px; padding: 10px; width: 580px;">
+          <div class="content" style="box-sizing: border-box; display: block; Margin: 0 auto; max-width: 580px; padding: 10px;">
+
+            <!-- START CENTERED WHITE CONTAINER -->
+            <span class="preheader" style="color: transparent; display: none; height: 0; max-height: 0; max-width: 0; opacity: 0; overflow: hidden; mso-hide: all; visibility: hidden; width: 0;">{{ .UpdatesCount }} updates in the packages you are subscribed to</span>
+            <table class="main" style="border-collapse: separate; mso-table-lspace: 0pt; mso-table-rspace: 0pt; width: 100%; background: #ffffff; border-radius: 3px; border-top: 7px solid #659DBD;">
+
+              <!-- START MAIN CONTENT AREA -->
+              <tr>
+                <td class="wrapper" style="font-family: sans-serif; font-size: 14px; vertical-align: top; box-sizing: border-box; padding: 20px;">
+                  <table border="0" cellpadding="0" cellspacing="0" style="border-collapse: separate; mso-table-lspace: 0pt; mso-table-rspace: 0pt; width: 100%;">
+                    <tr>
+                      <td style="font-family: sans-serif; font-size: 14px; vertical-align: top; text-align: center;">
+                        <h2 style="color: #39596c; font-family: sans-serif; margin: 0; Margin-top: 30px; Margin-bottom: 15px;">Your {{ .Period }} digest</h2>
+                        <p style="font-family: sans-serif; font-size: 14px; font-weight: normal; margin: 0; Margin-bottom: 30px;">There have been <b>{{ .UpdatesCount }}</b> updates in the packages you are subscribed to{{ if .SecurityUpdatesCount }}, <b>{{ .SecurityUpdatesCount }}</b> of them related to security{{ end }}.</p>
+                      </td>
+                    </tr>
+
+                    {{ range $repository := .Repositories }}
+                      <tr>
+                        <td style="font-family: sans-serif; font-size: 14px;">
+                          <hr style="border-top: 1px solid #659DBD; border-bottom: none;" />
+                          <h4 style="color: #39596c; font-family: sans-serif; font-size: 14px; Margin-top: 20px; Margin-bottom: 10px;"><img style="margin-right: 5px; margin-bottom: -2px;" height="14px" src="{{ $.BaseURL }}/static/media/{{ $repository.Kind }}_icon.png">{{ $repository.Name }} <span style="color: #545454; font-size: 12px; font-weight: normal;">({{ $repository.Publisher }})</span></h4>
+                          <table border="0" cellpadding="0" cellspacing="0" style="border-collapse: separate; mso-table-lspace: 0pt; mso-table-rspace: 0pt; width: 100%; box-sizing: border-box; Margin-bottom: 20px;">
+                            <tbody>
+                              {{ range $update := $repository.Updates }}
+                                <tr>
+                                  <td style="font-family: sans-serif; font-size: 14px; vertical-align: top; padding: 5px 0;">
+                                    <a href="{{ $update.Package.url }}" target="_blank" style="color: #39596C; font-weight: bold; text-decoration: none;">{{ $update.Package.name }} {{ $update.Package.version }}</a>
+                                    {{ if eq $update.Event.kind "package.security-alert" }}
+                                      <span style="color: #721c24; background-color: #f8d7da; border: 1px solid #f5c6cb; border-radius: 3px; font-size: 11px; margin-left: 5px; padding: 1px 5px;">Security alert</span>
+                                    {{ else if $update.Security }}
+                                      <span style="color: #856404; background-color: #fff3cd; border: 1px solid #ffeeba; border-radius: 3px; font-size: 11px; margin-left: 5px; padding: 1px 5px;">Security updates</span>
+                                    {{ end }}
+                                    {{ if $update.Package.prerelease }}
+                                      <span style="color: #545454; background-color: #f4f4f4; border: 1px solid #dddddd; border-radius: 3px; font-size: 11px; margin-left: 5px; padding: 1px 5px;">Pre-release</span>
+                                    {{ end }}
+                                    {{ if eq $update.Event.kind "package.security-alert" }}
+                                      <ul style="Margin: 5px 0 0 0; color: #545454; font-size: 12px;">
+                                        {{ range $vulnerability := $update.Event.addedVulnerabilities }}
+                                          <li>{{ $vulnerability.id }} ({{ $vulnerability.severity }})</li>
+                                        {{ end }}
+                                      </ul>
+                                    {{ else if $update.Package.changes }}
+                                      <ul style="Margin: 5px 0 0 0; color: #545454; font-size: 12px;">
+                                        {{ range $change := $update.Package.changes }}
+                                          <li>{{ $change }}</li>
+                                        {{ end }}
+                                      </ul>
+                                    {{ end }}
+                                  </td>
+                                </tr>
+                              {{ end }}
+                            </tbody>
+                          </table>
+                        </td>
+                      </tr>
+                    {{ end }}
+
+                    <tr>
+                      <td style="font-family: sans-serif; font-size: 14px;">
+                        <hr style="border-top: 1px solid #659DBD; border-bottom: none;" />
+                        <p style="font-family: sans-serif; font-size: 14px; font-weight: normal; margin: 0; Margin-bottom: 30px;"></p>
+                      </td>
+                    </tr>
+
+                    <tr>
+                      <td style="font-family: sans-serif; font-size: 14px; text-align: center;">
+                        <table border="0" cellpadding="0" cellspacing="0" class="btn btn-primary" style="border-collapse: separate; mso-table-lspace: 0pt; mso-table-rspace: 0pt; width: 100%; box-sizing: border-box;">
+                          <tbody>
+                            <tr>
+                              <td align="left" style="font-family: sans-serif; font-size: 14px; vertical-align: top; padding-bottom: 30px;">
+                                <table border="0" cellpadding="0" cellspacing="0" style="width: 100%; border-collapse: separate; mso-table-lspace: 0pt; mso-table-rspace: 0pt;">
+                                  <tbody>
+                                    <tr>
+                                      <td style="font-family: sans-serif; font-size: 14px; border-radius: 5px; vertical-align: top;"><div style="text-align: center;"> <a href="{{ .BaseURL }}/control-panel/settings/subscriptions" target="_blank" style="display: inline-block; color: #ffffff; background-color: #39596C; border: solid 1px #39596C; border-radius: 5px; box-sizing: border-box; cursor: pointer; text-decoration: none; font-size: 14px; font-weight: bold; margin: 0; padding: 12px 25px; border-color: #39596C;">Manage subscriptions</a> </div></td>
+                                    </tr>
+                                  </tbody>
+                                </table>
+                              </td>
+                            </tr>
+                          </tbody>
+                        </table>
+                      </td>
+                    </tr>
+                  </table>
+                </td>
+              </tr>
+
+            <!-- END MAIN CONTENT AREA -->
+            </table>
+
+            <!-- START FOOTER -->
+            <div class="footer" style="clear: both; Margin-top: 10px; text-align: center; width: 100%;">
+              <table border="0" cellpadding="0" cellspacing="0" style="border-collapse: separate; mso-table-lspace: 0pt; mso-table-rspace: 0pt; width: 100%;">
+                <tr>
+                  <td class="content-block powered-by" style="font-family: sans-serif; vertical-align: top; padding-bottom: 10px; padding-top: 10px; font-size: 10px; color: #545454; text-align: center;">
+                    <p style="color: #545454; font-size: 10px; text-align: center; text-decoration: none;">You are receiving this {{ .Period }} digest because of your notifications delivery preference. You can change it in your <a href="{{ .BaseURL }}/control-panel/settings/profile" target="_blank" style="text-decoration: underline; color: #545454;">profile settings</a>.</p>
+                  </td>
+                </tr>
+                <tr>
+                  <td class="content-block powered-by" style="font-family: sans-serif; vertical-align: top; padding-bottom: 10px; padding-top: 10px; font-size: 12px; color: #39596C; text-align: center;">
+                    <a href="{{ .BaseURL }}" style="color: #39596C; font-size: 12px; text-align: center; text-decoration: none;">© Artifact Hub</a>
+                  </td>
+                </tr>
+              </table>
+            </div>
+            <!-- END FOOTER -->
+
+          <!-- END CENTERED WHITE CONTAINER -->
+          </div>
+        </td>
+        <td style="font-family: sans-serif; font-size: 14px; vertical-align: top;">&nbsp;</td>
+      </tr>
+    </table>
+  </body>
+</html>
+`))
@@ -5,15 +5,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"strings"
 	"sync"
 	"text/template"
 	"time"
 
-	"github.com/artifacthub/hub/cmd/hub/handlers/pkg"
 	"github.com/artifacthub/hub/internal/email"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/subscription"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/jackc/pgx/v4"
 	"github.com/patrickmn/go-cache"
@@ -24,9 +26,29 @@ const (
 	pauseOnEmptyQueue = 30 * time.Second
 	pauseOnError      = 10 * time.Second
 
+	defaultWebhookMaxAttempts   = 5
+	defaultWebhookRetryDelay    = 1 * time.Minute
+	defaultWebhookMaxRetryDelay = 1 * time.Hour
+
+	// digestMaxAttempts represents the maximum number of times the delivery
+	// of a digest will be attempted when it fails with a retryable error.
+	digestMaxAttempts = 5
+
+	// digestRetryDelay represents the delay applied before attempting again
+	// the delivery of a digest that failed with a retryable error.
+	digestRetryDelay = 10 * time.Minute
+
+	// webhookResponseSnippetSize represents the maximum number of bytes of
+	// the webhook response body that will be registered in each delivery.
+	webhookResponseSnippetSize = 1024
+
 	// DefaultPayloadContentType represents the default content type used for
 	// webhooks notifications.
 	DefaultPayloadContentType = "application/cloudevents+json"
+
+	// unsubscribeTokenTTL represents how long the unsubscribe tokens embedded
+	// in notifications emails are valid.
+	unsubscribeTokenTTL = 30 * 24 * time.Hour
 )
 
 var (
@@ -42,10 +64,15 @@ type HTTPClient interface {
 
 // Worker is in charge of delivering notifications to their intended recipients.
 type Worker struct {
-	svc        *Services
-	cache      *cache.Cache
-	baseURL    string
-	httpClient HTTPClient
+	svc                  *Services
+	cache                *cache.Cache
+	baseURL              string
+	httpClient           HTTPClient
+	webhookMaxAttempts   int
+	webhookRetryDelay    time.Duration
+	webhookMaxRetryDelay time.Duration
+	unsubscribeKey       []byte
+	wakeUpCh             chan struct{}
 }
 
 // NewWorker creates a new Worker instance.
@@ -54,22 +81,57 @@ func NewWorker(
 	c *cache.Cache,
 	baseURL string,
 	httpClient HTTPClient,
+	opts ...func(w *Worker),
 ) *Worker {
-	return &Worker{
-		svc:        svc,
-		cache:      c,
-		baseURL:    baseURL,
-		httpClient: httpClient,
+	w := &Worker{
+		svc:                  svc,
+		cache:                c,
+		baseURL:              baseURL,
+		httpClient:           httpClient,
+		webhookMaxAttempts:   defaultWebhookMaxAttempts,
+		webhookRetryDelay:    defaultWebhookRetryDelay,
+		webhookMaxRetryDelay: defaultWebhookMaxRetryDelay,
+		wakeUpCh:             make(chan struct{}, 1),
+	}
+	for _, o := range opts {
+		o(w)
 	}
+	return w
 }
 
-// Run is the main loop of the worker. It calls processNotification periodically
-// until it's asked to stop via the context provided.
+// WithWebhookRetries allows configuring how many times the delivery of a
+// webhook notification will be attempted, as well as the delay before the
+// first retry (doubled on each subsequent one) and the maximum delay allowed
+// between attempts.
+func WithWebhookRetries(maxAttempts int, delay, maxDelay time.Duration) func(w *Worker) {
+	return func(w *Worker) {
+		w.webhookMaxAttempts = maxAttempts
+		w.webhookRetryDelay = delay
+		w.webhookMaxRetryDelay = maxDelay
+	}
+}
+
+// WithUnsubscribeKey allows providing the key used to sign the unsubscribe
+// tokens embedded in notifications emails. When no key is provided, emails
+// will not include them.
+func WithUnsubscribeKey(key []byte) func(w *Worker) {
+	return func(w *Worker) {
+		w.unsubscribeKey = key
+	}
+}
+
+// Run is the main loop of the worker. It calls processDigest and
+// processNotification periodically until it's asked to stop via the context
+// provided. Both are called on each iteration, so that a digest that cannot
+// be delivered does not prevent other notifications from being processed.
 func (w *Worker) Run(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	for {
-		err := w.processNotification(ctx)
+		err := w.processDigest(ctx)
+		if nErr := w.processNotification(ctx); nErr == nil || errors.Is(err, pgx.ErrNoRows) {
+			err = nErr
+		}
 		switch {
 		case err == nil:
 			select {
@@ -79,6 +141,7 @@ func (w *Worker) Run(ctx context.Context, wg *sync.WaitGroup) {
 			}
 		case errors.Is(err, pgx.ErrNoRows):
 			select {
+			case <-w.wakeUpCh:
 			case <-time.After(pauseOnEmptyQueue):
 			case <-ctx.Done():
 				return
@@ -93,6 +156,16 @@ func (w *Worker) Run(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+// wakeUp wakes the worker up if it's waiting for new notifications to be
+// registered. If the worker is busy, it'll check the queue again as soon as
+// it's done.
+func (w *Worker) wakeUp() {
+	select {
+	case w.wakeUpCh <- struct{}{}:
+	default:
+	}
+}
+
 // processNotification gets a pending notification from the database and
 // delivers it.
 func (w *Worker) processNotification(ctx context.Context) error {
@@ -107,6 +180,7 @@ func (w *Worker) processNotification(ctx context.Context) error {
 		}
 
 		// Process notification
+		var d *hub.WebhookDelivery
 		switch {
 		case n.User != nil:
 			if w.svc.ES != nil {
@@ -115,13 +189,28 @@ func (w *Worker) processNotification(ctx context.Context) error {
 				err = email.ErrSenderNotAvailable
 			}
 		case n.Webhook != nil:
-			err = w.deliverWebhookNotification(ctx, n)
+			d, err = w.deliverWebhookNotification(ctx, n)
 		}
 		if errors.Is(err, ErrRetryable) {
 			log.Error().Err(err).Msg("processNotification: error delivering notification")
 			return err
 		}
 
+		// Register webhook delivery attempt and schedule a new one if it failed
+		if d != nil {
+			if err := w.addWebhookDelivery(ctx, tx, d); err != nil {
+				log.Error().Err(err).Msg("processNotification: error registering webhook delivery")
+			}
+			if err != nil && d.Attempt < w.webhookMaxAttempts {
+				delay := w.webhookNextRetryDelay(d.Attempt)
+				err = w.svc.NotificationManager.ScheduleRetry(ctx, tx, n.NotificationID, delay, err)
+				if err != nil {
+					log.Error().Err(err).Msg("processNotification: error scheduling notification retry")
+				}
+				return nil
+			}
+		}
+
 		// Update notification status
 		err = w.svc.NotificationManager.UpdateStatus(ctx, tx, n.NotificationID, true, err)
 		if err != nil {
@@ -131,21 +220,83 @@ func (w *Worker) processNotification(ctx context.Context) error {
 	})
 }
 
+// addWebhookDelivery registers the webhook delivery attempt provided in a
+// savepoint, so that an error registering it does not abort the transaction
+// used to process the notification.
+func (w *Worker) addWebhookDelivery(ctx context.Context, tx pgx.Tx, d *hub.WebhookDelivery) error {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if err := w.svc.NotificationManager.AddWebhookDelivery(ctx, sp, d); err != nil {
+		if rbErr := sp.Rollback(ctx); rbErr != nil {
+			log.Error().Err(rbErr).Msg("addWebhookDelivery: error rolling back savepoint")
+		}
+		return err
+	}
+	return sp.Commit(ctx)
+}
+
+// processDigest gets a pending notifications digest from the database and
+// delivers it via email. When the delivery fails with a retryable error, a
+// new attempt is scheduled until the maximum number of attempts is reached.
+// After that, the notifications are marked as processed with the error.
+func (w *Worker) processDigest(ctx context.Context) error {
+	return util.DBTransact(ctx, w.svc.DB, func(tx pgx.Tx) error {
+		// Get pending digest to process
+		dg, err := w.svc.NotificationManager.GetPendingDigest(ctx, tx)
+		if err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) {
+				log.Error().Err(err).Msg("processDigest: error getting pending digest")
+			}
+			return err
+		}
+
+		// Deliver digest
+		var deliveryErr error
+		if w.svc.ES != nil {
+			deliveryErr = w.deliverDigest(ctx, dg)
+		} else {
+			deliveryErr = email.ErrSenderNotAvailable
+		}
+		if errors.Is(deliveryErr, ErrRetryable) {
+			log.Error().Err(deliveryErr).Msg("processDigest: error delivering digest")
+		}
+
+		// Update status of the notifications included in the digest
+		for _, n := range dg.Notifications {
+			if errors.Is(deliveryErr, ErrRetryable) && n.Attempts+1 < digestMaxAttempts {
+				err := w.svc.NotificationManager.ScheduleRetry(ctx, tx, n.NotificationID, digestRetryDelay, deliveryErr)
+				if err != nil {
+					log.Error().Err(err).Msg("processDigest: error scheduling notification retry")
+				}
+				continue
+			}
+			err := w.svc.NotificationManager.UpdateStatus(ctx, tx, n.NotificationID, true, deliveryErr)
+			if err != nil {
+				log.Error().Err(err).Msg("processDigest: error updating notification status")
+			}
+		}
+		return nil
+	})
+}
+
+// deliverDigest delivers the provided notifications digest via email.
+func (w *Worker) deliverDigest(ctx context.Context, dg *hub.NotificationDigest) error {
+	emailData, err := w.prepareDigestEmailData(ctx, dg)
+	if err != nil {
+		return fmt.Errorf("%w: error preparing digest email data: %v", ErrRetryable, err)
+	}
+	emailData.To = dg.User.Email
+	return w.svc.ES.SendEmail(&emailData)
+}
+
 // deliverEmailNotification delivers the provided notification via email.
 func (w *Worker) deliverEmailNotification(ctx context.Context, n *hub.Notification) error {
 	// Prepare email data
-	var emailData email.Data
-	cKey := "emailData.%" + n.Event.EventID
-	cValue, ok := w.cache.Get(cKey)
-	if ok {
-		emailData = cValue.(email.Data)
-	} else {
-		var err error
-		emailData, err = w.prepareEmailData(ctx, n.Event)
-		if err != nil {
-			return fmt.Errorf("%w: error preparing email data: %v", ErrRetryable, err)
-		}
-		w.cache.SetDefault(cKey, emailData)
+	emailData, err := w.prepareEmailData(ctx, n)
+	if err != nil {
+		return fmt.Errorf("%w: error preparing email data: %v", ErrRetryable, err)
 	}
 	emailData.To = n.User.Email
 
@@ -154,52 +305,165 @@ func (w *Worker) deliverEmailNotification(ctx context.Context, n *hub.Notificati
 }
 
 // deliverWebhookNotification delivers the provided notification via webhook.
-func (w *Worker) deliverWebhookNotification(ctx context.Context, n *hub.Notification) error {
+// When the webhook endpoint is called, the details of the delivery attempt
+// are returned so that they can be registered.
+func (w *Worker) deliverWebhookNotification(
+	ctx context.Context,
+	n *hub.Notification,
+) (*hub.WebhookDelivery, error) {
+	// Prepare payload
+	var payload []byte
+	var err error
+	contentType := n.Webhook.ContentType
+	if IsChatWebhook(n.Webhook.Kind) {
+		payload, err = w.prepareChatPayload(ctx, n)
+		contentType = "application/json"
+	} else {
+		payload, err = w.prepareWebhookPayload(ctx, n)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if contentType == "" {
+		contentType = DefaultPayloadContentType
+	}
+
+	// Decrypt webhook secret (if any)
+	var secret string
+	if n.Webhook.Secret != "" {
+		secret, err = w.svc.Encrypter.Decrypt(ctx, n.Webhook.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("error decrypting webhook secret: %w", err)
+		}
+	}
+
+	// Call webhook endpoint
+	req, _ := http.NewRequest("POST", n.Webhook.URL, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", contentType)
+	SignWebhookRequest(req, payload, secret, n.Webhook.LegacySecretHeader)
+	d := &hub.WebhookDelivery{
+		NotificationID: n.NotificationID,
+		WebhookID:      n.Webhook.WebhookID,
+		Attempt:        n.Attempts + 1,
+	}
+	start := time.Now()
+	resp, err := w.httpClient.Do(req)
+	d.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		d.Error = err.Error()
+		return d, err
+	}
+	defer resp.Body.Close()
+	d.StatusCode = resp.StatusCode
+	snippet, _ := ioutil.ReadAll(io.LimitReader(resp.Body, webhookResponseSnippetSize))
+	d.Response = strings.ReplaceAll(strings.ToValidUTF8(string(snippet), ""), "\x00", "")
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		d.Error = err.Error()
+		return d, err
+	}
+	return d, nil
+}
+
+// prepareWebhookPayload prepares the payload of a generic webhook notification
+// using the webhook template, or the default one for the notification event
+// kind when none was provided.
+func (w *Worker) prepareWebhookPayload(ctx context.Context, n *hub.Notification) ([]byte, error) {
 	// Get template data
-	tmplData, err := w.preparePkgNotificationTemplateData(ctx, n.Event)
+	var tmplData interface{}
+	var err error
+	switch n.Event.EventKind {
+	case hub.NewRelease, hub.SecurityAlert:
+		tmplData, err = w.preparePkgNotificationTemplateData(ctx, n.Event)
+	case hub.RepositoryTrackingErrors, hub.RepositoryOwnershipClaim:
+		tmplData, err = w.prepareRepoNotificationTemplateData(ctx, n.Event)
+	default:
+		return nil, fmt.Errorf("unsupported event kind: %d", n.Event.EventKind)
+	}
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrRetryable, err)
+		return nil, fmt.Errorf("%w: %v", ErrRetryable, err)
 	}
 
-	// Prepare payload
+	// Execute template
 	var tmpl *template.Template
 	if n.Webhook.Template != "" {
-		var err error
-		tmpl, err = template.New("").Parse(n.Webhook.Template)
+		tmpl, err = ParseTemplate(n.Webhook.Template)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	} else {
-		tmpl = DefaultWebhookPayloadTmpl
+		tmpl = DefaultWebhookPayloadTmplFor(n.Event.EventKind)
 	}
 	var payload bytes.Buffer
 	if err := tmpl.Execute(&payload, tmplData); err != nil {
-		return err
+		return nil, err
 	}
-	contentType := n.Webhook.ContentType
-	if contentType == "" {
-		contentType = DefaultPayloadContentType
+	return payload.Bytes(), nil
+}
+
+// prepareChatPayload prepares the payload of a chat webhook notification
+// (i.e. Slack), using the built-in message for the notification event kind.
+func (w *Worker) prepareChatPayload(ctx context.Context, n *hub.Notification) ([]byte, error) {
+	var m *chatMessage
+	switch n.Event.EventKind {
+	case hub.NewRelease, hub.SecurityAlert:
+		tmplData, err := w.preparePkgNotificationTemplateData(ctx, n.Event)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRetryable, err)
+		}
+		m = newPkgChatMessage(tmplData)
+	case hub.RepositoryTrackingErrors, hub.RepositoryOwnershipClaim:
+		tmplData, err := w.prepareRepoNotificationTemplateData(ctx, n.Event)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRetryable, err)
+		}
+		m = newRepoChatMessage(tmplData)
+	default:
+		return nil, fmt.Errorf("unsupported event kind: %d", n.Event.EventKind)
 	}
+	return buildChatPayload(n.Webhook.Kind, m)
+}
 
-	// Call webhook endpoint
-	req, _ := http.NewRequest("POST", n.Webhook.URL, &payload)
-	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("X-ArtifactHub-Secret", n.Webhook.Secret)
-	resp, err := w.httpClient.Do(req)
-	if err != nil {
-		return err
+// webhookNextRetryDelay returns the delay to apply before attempting again
+// the delivery of a webhook notification. The delay grows exponentially with
+// the number of attempts made so far, up to the maximum delay configured.
+func (w *Worker) webhookNextRetryDelay(attempts int) time.Duration {
+	delay := w.webhookRetryDelay
+	for i := 1; i < attempts && delay < w.webhookMaxRetryDelay; i++ {
+		delay *= 2
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	if delay > w.webhookMaxRetryDelay {
+		delay = w.webhookMaxRetryDelay
 	}
-	return nil
+	return delay
+}
+
+// pkgEmailTemplateData represents the data available to packages
+// notifications email templates.
+type pkgEmailTemplateData struct {
+	*hub.PackageNotificationTemplateData
+	UnsubscribeURL string
 }
 
-// prepareEmailData prepares the email data corresponding to the event provided.
-func (w *Worker) prepareEmailData(ctx context.Context, e *hub.Event) (email.Data, error) {
+// repoEmailTemplateData represents the data available to repositories
+// notifications email templates.
+type repoEmailTemplateData struct {
+	*hub.RepositoryNotificationTemplateData
+	UnsubscribeURL string
+}
+
+// prepareEmailData prepares the email data corresponding to the notification
+// provided. Emails include a link (and the corresponding List-Unsubscribe
+// header) that allows the recipient to unsubscribe without logging in.
+func (w *Worker) prepareEmailData(ctx context.Context, n *hub.Notification) (email.Data, error) {
 	var subject string
 	var emailBody bytes.Buffer
+	e := n.Event
+
+	unsubscribeURL, err := w.prepareUnsubscribeURL(n)
+	if err != nil {
+		return email.Data{}, err
+	}
 
 	switch e.EventKind {
 	case hub.NewRelease:
@@ -208,7 +472,22 @@ func (w *Worker) prepareEmailData(ctx context.Context, e *hub.Event) (email.Data
 			return email.Data{}, err
 		}
 		subject = fmt.Sprintf("%s version %s released", tmplData.Package["name"], tmplData.Package["version"])
-		if err := newReleaseEmailTmpl.Execute(&emailBody, tmplData); err != nil {
+		if err := newReleaseEmailTmpl.Execute(&emailBody, &pkgEmailTemplateData{
+			PackageNotificationTemplateData: tmplData,
+			UnsubscribeURL:                  unsubscribeURL,
+		}); err != nil {
+			return email.Data{}, err
+		}
+	case hub.SecurityAlert:
+		tmplData, err := w.preparePkgNotificationTemplateData(ctx, e)
+		if err != nil {
+			return email.Data{}, err
+		}
+		subject = fmt.Sprintf("%s version %s security alert", tmplData.Package["name"], tmplData.Package["version"])
+		if err := securityAlertEmailTmpl.Execute(&emailBody, &pkgEmailTemplateData{
+			PackageNotificationTemplateData: tmplData,
+			UnsubscribeURL:                  unsubscribeURL,
+		}); err != nil {
 			return email.Data{}, err
 		}
 	case hub.RepositoryTrackingErrors:
@@ -217,7 +496,10 @@ func (w *Worker) prepareEmailData(ctx context.Context, e *hub.Event) (email.Data
 			return email.Data{}, err
 		}
 		subject = fmt.Sprintf("Something went wrong tracking repository %s", tmplData.Repository["name"])
-		if err := trackingErrorsEmailTmpl.Execute(&emailBody, tmplData); err != nil {
+		if err := trackingErrorsEmailTmpl.Execute(&emailBody, &repoEmailTemplateData{
+			RepositoryNotificationTemplateData: tmplData,
+			UnsubscribeURL:                     unsubscribeURL,
+		}); err != nil {
 			return email.Data{}, err
 		}
 	case hub.RepositoryOwnershipClaim:
@@ -231,9 +513,69 @@ func (w *Worker) prepareEmailData(ctx context.Context, e *hub.Event) (email.Data
 		}
 	}
 
-	return email.Data{
+	emailData := email.Data{
 		Subject: subject,
 		Body:    emailBody.Bytes(),
+	}
+	if unsubscribeURL != "" {
+		emailData.Headers = map[string]string{
+			"List-Unsubscribe":      fmt.Sprintf("<%s>", unsubscribeURL),
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}
+	}
+	return emailData, nil
+}
+
+// prepareUnsubscribeURL prepares the url the recipient of the email
+// notification provided can use to unsubscribe from it without logging in.
+// An empty url is returned when the notification does not support it.
+func (w *Worker) prepareUnsubscribeURL(n *hub.Notification) (string, error) {
+	if len(w.unsubscribeKey) == 0 || n.User.UserID == "" {
+		return "", nil
+	}
+	t := &hub.UnsubscribeToken{
+		UserID:    n.User.UserID,
+		EventKind: n.Event.EventKind,
+	}
+	switch n.Event.EventKind {
+	case hub.NewRelease, hub.SecurityAlert:
+		t.PackageID = n.Event.PackageID
+	case hub.RepositoryTrackingErrors:
+		t.RepositoryID = n.Event.RepositoryID
+	default:
+		return "", nil
+	}
+	token, err := subscription.NewUnsubscribeToken(w.unsubscribeKey, t, unsubscribeTokenTTL)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/api/v1/subscriptions/unsubscribe?token=%s", w.baseURL, token), nil
+}
+
+// prepareDigestEmailData prepares the email data corresponding to the
+// notifications digest provided.
+func (w *Worker) prepareDigestEmailData(ctx context.Context, dg *hub.NotificationDigest) (email.Data, error) {
+	notifications := make([]*hub.PackageNotificationTemplateData, 0, len(dg.Notifications))
+	for _, n := range dg.Notifications {
+		tmplData, err := w.preparePkgNotificationTemplateData(ctx, n.Event)
+		if err != nil {
+			return email.Data{}, err
+		}
+		notifications = append(notifications, tmplData)
+	}
+	var p hub.DeliveryPreference
+	if dg.User.DeliveryPreference != nil {
+		p = *dg.User.DeliveryPreference
+	}
+	tmplData := newDigestTemplateData(w.baseURL, p, notifications)
+	var emailBody bytes.Buffer
+	if err := digestEmailTmpl.Execute(&emailBody, tmplData); err != nil {
+		return email.Data{}, err
+	}
+
+	return email.Data{
+		Subject: tmplData.subject(),
+		Body:    emailBody.Bytes(),
 	}, nil
 }
 
@@ -261,38 +603,7 @@ func (w *Worker) preparePkgNotificationTemplateData(
 		w.cache.SetDefault(cKey, p)
 	}
 
-	// Prepare template data
-	var eventKindStr string
-	switch e.EventKind {
-	case hub.NewRelease:
-		eventKindStr = "package.new-release"
-	}
-	publisher := p.Repository.OrganizationName
-	if publisher == "" {
-		publisher = p.Repository.UserAlias
-	}
-
-	return &hub.PackageNotificationTemplateData{
-		BaseURL: w.baseURL,
-		Event: map[string]interface{}{
-			"id":   e.EventID,
-			"kind": eventKindStr,
-		},
-		Package: map[string]interface{}{
-			"name":                    p.Name,
-			"version":                 p.Version,
-			"logoImageID":             p.LogoImageID,
-			"url":                     pkg.BuildURL(w.baseURL, p, e.PackageVersion),
-			"changes":                 p.Changes,
-			"containsSecurityUpdates": p.ContainsSecurityUpdates,
-			"prerelease":              p.Prerelease,
-			"repository": map[string]interface{}{
-				"kind":      hub.GetKindName(p.Repository.Kind),
-				"name":      p.Repository.Name,
-				"publisher": publisher,
-			},
-		},
-	}, nil
+	return NewPkgNotificationTemplateData(w.baseURL, e, p), nil
 }
 
 // prepareRepoNotificationTemplateData prepares the data available to
@@ -316,34 +627,28 @@ func (w *Worker) prepareRepoNotificationTemplateData(
 		w.cache.SetDefault(cKey, r)
 	}
 
-	// Prepare template data
-	var eventKindStr string
-	switch e.EventKind {
+	return NewRepoNotificationTemplateData(w.baseURL, e, r), nil
+}
+
+// DefaultWebhookPayloadTmplFor returns the template used for the webhook
+// payload of the event kind provided when the webhook uses the default
+// template.
+func DefaultWebhookPayloadTmplFor(kind hub.EventKind) *template.Template {
+	switch kind {
+	case hub.SecurityAlert:
+		return DefaultSecurityAlertWebhookPayloadTmpl
 	case hub.RepositoryTrackingErrors:
-		eventKindStr = "repository.tracking-errors"
+		return DefaultRepositoryTrackingErrorsWebhookPayloadTmpl
 	case hub.RepositoryOwnershipClaim:
-		eventKindStr = "repository.ownership-claim"
+		return DefaultRepositoryOwnershipClaimWebhookPayloadTmpl
+	default:
+		return DefaultWebhookPayloadTmpl
 	}
-
-	return &hub.RepositoryNotificationTemplateData{
-		BaseURL: w.baseURL,
-		Event: map[string]interface{}{
-			"id":   e.EventID,
-			"kind": eventKindStr,
-		},
-		Repository: map[string]interface{}{
-			"kind":               hub.GetKindName(r.Kind),
-			"name":               r.Name,
-			"userAlias":          r.UserAlias,
-			"organizationName":   r.OrganizationName,
-			"lastTrackingErrors": strings.Split(r.LastTrackingErrors, "\n"),
-		},
-	}, nil
 }
 
 // DefaultWebhookPayloadTmpl is the template used for the webhook payload when
 // the webhook uses the default template.
-var DefaultWebhookPayloadTmpl = template.Must(template.New("").Parse(`
+var DefaultWebhookPayloadTmpl = template.Must(ParseTemplate(`
 {
 	"specversion" : "1.0",
 	"id" : "{{ .Event.id }}",
@@ -367,3 +672,78 @@ var DefaultWebhookPayloadTmpl = template.Must(template.New("").Parse(`
 	}
 }
 `))
+
+// DefaultSecurityAlertWebhookPayloadTmpl is the template used for the webhook
+// payload of security alert events when the webhook uses the default template.
+var DefaultSecurityAlertWebhookPayloadTmpl = template.Must(ParseTemplate(`
+{
+	"specversion" : "1.0",
+	"id" : "{{ .Event.id }}",
+	"source" : "https://artifacthub.io/cloudevents",
+	"type" : "io.artifacthub.{{ .Event.kind }}",
+	"datacontenttype" : "application/json",
+	"data" : {
+		"package": {
+			"name": "{{ .Package.name }}",
+			"version": "{{ .Package.version }}",
+			"url": "{{ .Package.url }}",
+			"securityReportSummary": {
+				"critical": {{ or .Package.securityReportSummary.critical 0 }},
+				"high": {{ or .Package.securityReportSummary.high 0 }},
+				"medium": {{ or .Package.securityReportSummary.medium 0 }},
+				"low": {{ or .Package.securityReportSummary.low 0 }},
+				"unknown": {{ or .Package.securityReportSummary.unknown 0 }}
+			},
+			"repository": {
+				"kind": "{{ .Package.repository.kind }}",
+				"name": "{{ .Package.repository.name }}",
+				"publisher": "{{ .Package.repository.publisher }}"
+			}
+		},
+		"addedVulnerabilities": [{{range $i, $e := .Event.addedVulnerabilities}}{{if $i}}, {{end}}{"id": "{{ .id }}", "severity": "{{ .severity }}"}{{end}}]
+	}
+}
+`))
+
+// DefaultRepositoryTrackingErrorsWebhookPayloadTmpl is the template used for
+// the webhook payload of repository tracking errors events when the webhook
+// uses the default template.
+var DefaultRepositoryTrackingErrorsWebhookPayloadTmpl = template.Must(ParseTemplate(`
+{
+	"specversion" : "1.0",
+	"id" : "{{ .Event.id }}",
+	"source" : "https://artifacthub.io/cloudevents",
+	"type" : "io.artifacthub.{{ .Event.kind }}",
+	"datacontenttype" : "application/json",
+	"data" : {
+		"repository": {
+			"kind": "{{ .Repository.kind }}",
+			"name": "{{ .Repository.name }}",
+			"userAlias": "{{ .Repository.userAlias }}",
+			"organizationName": "{{ .Repository.organizationName }}",
+			"lastTrackingErrors": {{ json .Repository.lastTrackingErrors }}
+		}
+	}
+}
+`))
+
+// DefaultRepositoryOwnershipClaimWebhookPayloadTmpl is the template used for
+// the webhook payload of repository ownership claim events when the webhook
+// uses the default template.
+var DefaultRepositoryOwnershipClaimWebhookPayloadTmpl = template.Must(ParseTemplate(`
+{
+	"specversion" : "1.0",
+	"id" : "{{ .Event.id }}",
+	"source" : "https://artifacthub.io/cloudevents",
+	"type" : "io.artifacthub.{{ .Event.kind }}",
+	"datacontenttype" : "application/json",
+	"data" : {
+		"repository": {
+			"kind": "{{ .Repository.kind }}",
+			"name": "{{ .Repository.name }}",
+			"userAlias": "{{ .Repository.userAlias }}",
+			"organizationName": "{{ .Repository.organizationName }}"
+		}
+	}
+}
+`))
@@ -2,6 +2,8 @@ package notification
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -11,14 +13,17 @@ import (
 	"time"
 
 	"github.com/artifacthub/hub/internal/email"
+	"github.com/artifacthub/hub/internal/encryption"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/pkg"
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/subscription"
 	"github.com/artifacthub/hub/internal/tests"
+	"github.com/jackc/pgx/v4"
 	"github.com/patrickmn/go-cache"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWorker(t *testing.T) {
@@ -33,12 +38,31 @@ func TestWorker(t *testing.T) {
 		EventKind:    hub.RepositoryTrackingErrors,
 		RepositoryID: "repositoryID",
 	}
+	e3 := &hub.Event{
+		EventID:        "eventID",
+		EventKind:      hub.SecurityAlert,
+		PackageID:      "packageID",
+		PackageVersion: "1.0.0",
+		Data: map[string]interface{}{
+			"added_vulnerabilities": []interface{}{
+				map[string]interface{}{
+					"id":       "CVE-0000-0001",
+					"severity": "critical",
+				},
+				map[string]interface{}{
+					"id":       "CVE-0000-0002",
+					"severity": "high",
+				},
+			},
+		},
+	}
 	u := &hub.User{
 		Email: "user1@email.com",
 	}
 	wh := &hub.Webhook{
-		Name: "webhook1",
-		URL:  "http://webhook1.url",
+		WebhookID: "webhookID",
+		Name:      "webhook1",
+		URL:       "http://webhook1.url",
 	}
 	n1 := &hub.Notification{
 		NotificationID: "notificationID",
@@ -55,6 +79,11 @@ func TestWorker(t *testing.T) {
 		Event:          e2,
 		User:           u,
 	}
+	n4 := &hub.Notification{
+		NotificationID: "notificationID",
+		Event:          e3,
+		User:           u,
+	}
 	gpi := &hub.GetPackageInput{
 		PackageID: e1.PackageID,
 		Version:   e1.PackageVersion,
@@ -69,6 +98,11 @@ func TestWorker(t *testing.T) {
 		},
 		ContainsSecurityUpdates: true,
 		Prerelease:              true,
+		SecurityReportSummary: &hub.SecurityReportSummary{
+			Critical: 2,
+			High:     1,
+			Low:      4,
+		},
 		Repository: &hub.Repository{
 			Kind:             hub.Helm,
 			Name:             "repo1",
@@ -80,11 +114,166 @@ func TestWorker(t *testing.T) {
 		Name:             "repo1",
 		OrganizationName: "org1",
 	}
+	weeklyDigest := hub.WeeklyDigestDelivery
+	dg := &hub.NotificationDigest{
+		User: &hub.User{
+			Email:              "user1@email.com",
+			DeliveryPreference: &weeklyDigest,
+		},
+		Notifications: []*hub.Notification{
+			{NotificationID: "notification1ID", Event: e1},
+			{NotificationID: "notification2ID", Event: e3},
+		},
+	}
+
+	t.Run("error getting pending digest", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, tests.ErrFake)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("error getting package preparing digest email data, retry scheduled", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(dg, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(nil, tests.ErrFake)
+		retryable := mock.MatchedBy(func(err error) bool { return errors.Is(err, ErrRetryable) })
+		sw.nm.On("ScheduleRetry", sw.ctx, sw.tx, "notification1ID", digestRetryDelay, retryable).Return(nil)
+		sw.nm.On("ScheduleRetry", sw.ctx, sw.tx, "notification2ID", digestRetryDelay, retryable).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("error getting package preparing digest email data, max attempts reached", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		dg := &hub.NotificationDigest{
+			User: dg.User,
+			Notifications: []*hub.Notification{
+				{NotificationID: "notification1ID", Event: e1, Attempts: digestMaxAttempts - 1},
+				{NotificationID: "notification2ID", Event: e3, Attempts: 1},
+			},
+		}
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(dg, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(nil, tests.ErrFake)
+		retryable := mock.MatchedBy(func(err error) bool { return errors.Is(err, ErrRetryable) })
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notification1ID", true, retryable).Return(nil)
+		sw.nm.On("ScheduleRetry", sw.ctx, sw.tx, "notification2ID", digestRetryDelay, retryable).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("pending notifications processed when digest delivery fails", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		processed := make(chan struct{})
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, tests.ErrFake)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Run(func(args mock.Arguments) {
+			close(processed)
+		}).Return(nil, pgx.ErrNoRows).Once()
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		select {
+		case <-processed:
+		case <-time.After(2 * time.Second):
+			t.Error("pending notifications were not processed")
+		}
+		sw.assertExpectations(t)
+	})
+
+	t.Run("error sending digest email", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(dg, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.es.On("SendEmail", mock.Anything).Return(tests.ErrFake)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notification1ID", true, tests.ErrFake).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notification2ID", true, tests.ErrFake).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("digest email delivered successfully", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(dg, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.es.On("SendEmail", mock.MatchedBy(func(data *email.Data) bool {
+			body := string(data.Body)
+			return data.To == "user1@email.com" &&
+				data.Subject == "Your weekly Artifact Hub digest: 2 updates (2 related to security)" &&
+				strings.Contains(body, "CVE-0000-0001 (critical)") &&
+				strings.Contains(body, "Cool feature")
+		})).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notification1ID", true, nil).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notification2ID", true, nil).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("worker woken up while waiting for new notifications", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		processed := make(chan struct{})
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows).Twice()
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Run(func(args mock.Arguments) {
+			w.wakeUp()
+		}).Return(nil, pgx.ErrNoRows).Once()
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Run(func(args mock.Arguments) {
+			close(processed)
+		}).Return(nil, pgx.ErrNoRows).Once()
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		go w.Run(sw.ctx, sw.wg)
+		select {
+		case <-processed:
+		case <-time.After(2 * time.Second):
+			t.Error("worker was not woken up")
+		}
+		sw.assertExpectations(t)
+	})
 
 	t.Run("error getting pending notification", func(t *testing.T) {
 		t.Parallel()
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(nil, tests.ErrFake)
 		sw.tx.On("Rollback", sw.ctx).Return(nil)
 
@@ -97,6 +286,7 @@ func TestWorker(t *testing.T) {
 		t.Parallel()
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n1, nil)
 		sw.pm.On("Get", sw.ctx, gpi).Return(nil, tests.ErrFake)
 		sw.tx.On("Rollback", sw.ctx).Return(nil)
@@ -110,6 +300,7 @@ func TestWorker(t *testing.T) {
 		t.Parallel()
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n3, nil)
 		sw.rm.On("GetByID", sw.ctx, "repositoryID").Return(nil, tests.ErrFake)
 		sw.tx.On("Rollback", sw.ctx).Return(nil)
@@ -123,6 +314,8 @@ func TestWorker(t *testing.T) {
 		t.Parallel()
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n1, nil)
 		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
 		sw.es.On("SendEmail", mock.Anything).Return(tests.ErrFake)
@@ -138,6 +331,8 @@ func TestWorker(t *testing.T) {
 		t.Parallel()
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n3, nil)
 		sw.rm.On("GetByID", sw.ctx, "repositoryID").Return(r, nil)
 		sw.es.On("SendEmail", mock.Anything).Return(tests.ErrFake)
@@ -153,6 +348,8 @@ func TestWorker(t *testing.T) {
 		t.Parallel()
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n1, nil)
 		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
 		sw.es.On("SendEmail", mock.Anything).Return(nil)
@@ -164,10 +361,32 @@ func TestWorker(t *testing.T) {
 		sw.assertExpectations(t)
 	})
 
+	t.Run("security alert email notification delivered successfully", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n4, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.es.On("SendEmail", mock.MatchedBy(func(data *email.Data) bool {
+			return data.Subject == "package1 version 1.0.0 security alert" &&
+				strings.Contains(string(data.Body), "CVE-0000-0001")
+		})).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n4.NotificationID, true, nil).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
 	t.Run("repository email notification delivered successfully", func(t *testing.T) {
 		t.Parallel()
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n3, nil)
 		sw.rm.On("GetByID", sw.ctx, "repositoryID").Return(r, nil)
 		sw.es.On("SendEmail", mock.Anything).Return(nil)
@@ -179,10 +398,52 @@ func TestWorker(t *testing.T) {
 		sw.assertExpectations(t)
 	})
 
+	t.Run("email notification including unsubscribe link delivered successfully", func(t *testing.T) {
+		t.Parallel()
+		n := &hub.Notification{
+			NotificationID: "notificationID",
+			Event:          e2,
+			User: &hub.User{
+				UserID: "userID",
+				Email:  "user1@email.com",
+			},
+		}
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n, nil)
+		sw.rm.On("GetByID", sw.ctx, "repositoryID").Return(r, nil)
+		sw.es.On("SendEmail", mock.MatchedBy(func(data *email.Data) bool {
+			prefix := "http://baseURL/api/v1/subscriptions/unsubscribe?token="
+			listUnsubscribe := data.Headers["List-Unsubscribe"]
+			if !strings.HasPrefix(listUnsubscribe, "<"+prefix) {
+				return false
+			}
+			token := strings.TrimSuffix(strings.TrimPrefix(listUnsubscribe, "<"+prefix), ">")
+			ut, err := subscription.ParseUnsubscribeToken([]byte("key"), token)
+			if err != nil {
+				return false
+			}
+			return ut.UserID == "userID" &&
+				ut.EventKind == hub.RepositoryTrackingErrors &&
+				ut.RepositoryID == "repositoryID" &&
+				data.Headers["List-Unsubscribe-Post"] == "List-Unsubscribe=One-Click" &&
+				strings.Contains(string(data.Body), prefix+token)
+		})).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n.NotificationID, true, nil).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "http://baseURL", sw.hc, WithUnsubscribeKey([]byte("key")))
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
 	t.Run("error getting package preparing webhook payload", func(t *testing.T) {
 		t.Parallel()
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n2, nil)
 		sw.pm.On("Get", sw.ctx, gpi).Return(nil, tests.ErrFake)
 		sw.tx.On("Rollback", sw.ctx).Return(nil)
@@ -192,32 +453,130 @@ func TestWorker(t *testing.T) {
 		sw.assertExpectations(t)
 	})
 
-	t.Run("webhook call returned an error", func(t *testing.T) {
+	t.Run("error decrypting webhook secret", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(&hub.Notification{
+			NotificationID: "notificationID",
+			Event:          e1,
+			Webhook: &hub.Webhook{
+				URL:    "http://webhook1.url",
+				Secret: "encryptedSecret",
+			},
+		}, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.enc.On("Decrypt", sw.ctx, "encryptedSecret").Return("", tests.ErrFake)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n2.NotificationID, true, mock.Anything).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("webhook call returned an error: retry scheduled", func(t *testing.T) {
 		t.Parallel()
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n2, nil)
 		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
 		sw.hc.On("Do", mock.Anything).Return(nil, tests.ErrFake)
+		sw.tx.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.MatchedBy(func(d *hub.WebhookDelivery) bool {
+			return d.NotificationID == n2.NotificationID &&
+				d.WebhookID == wh.WebhookID &&
+				d.Attempt == 1 &&
+				d.StatusCode == 0 &&
+				d.Error == tests.ErrFake.Error()
+		})).Return(nil)
+		sw.nm.On("ScheduleRetry", sw.ctx, sw.tx, n2.NotificationID, 1*time.Minute, tests.ErrFake).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("webhook call returned an error: last attempt", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(&hub.Notification{
+			NotificationID: "notificationID",
+			Event:          e1,
+			Webhook:        wh,
+			Attempts:       2,
+		}, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.hc.On("Do", mock.Anything).Return(nil, tests.ErrFake)
+		sw.tx.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.MatchedBy(func(d *hub.WebhookDelivery) bool {
+			return d.Attempt == 3
+		})).Return(nil)
 		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n2.NotificationID, true, tests.ErrFake).Return(nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
 
+		w := NewWorker(sw.svc, sw.cache, "", sw.hc, WithWebhookRetries(3, time.Minute, time.Hour))
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("webhook call returned an unexpected status code: retry scheduled", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(&hub.Notification{
+			NotificationID: "notificationID",
+			Event:          e1,
+			Webhook:        wh,
+			Attempts:       2,
+		}, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.hc.On("Do", mock.Anything).Return(&http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader("not found")),
+			StatusCode: http.StatusNotFound,
+		}, nil)
+		sw.tx.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.MatchedBy(func(d *hub.WebhookDelivery) bool {
+			return d.Attempt == 3 &&
+				d.StatusCode == http.StatusNotFound &&
+				d.Response == "not found" &&
+				d.Error == "unexpected status code: 404"
+		})).Return(nil)
+		sw.nm.On("ScheduleRetry", sw.ctx, sw.tx, n2.NotificationID, 4*time.Minute, mock.Anything).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
 		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
 		go w.Run(sw.ctx, sw.wg)
 		sw.assertExpectations(t)
 	})
 
-	t.Run("webhook call returned an unexpected status code", func(t *testing.T) {
+	t.Run("webhook notification delivered successfully", func(t *testing.T) {
 		t.Parallel()
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n2, nil)
 		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
 		sw.hc.On("Do", mock.Anything).Return(&http.Response{
 			Body:       ioutil.NopCloser(strings.NewReader("")),
-			StatusCode: http.StatusNotFound,
+			StatusCode: http.StatusOK,
 		}, nil)
-		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n2.NotificationID, true, mock.Anything).Return(nil)
+		sw.tx.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.MatchedBy(func(d *hub.WebhookDelivery) bool {
+			return d.Attempt == 1 && d.StatusCode == http.StatusOK && d.Error == ""
+		})).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n2.NotificationID, true, nil).Return(nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
 
 		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
@@ -225,22 +584,53 @@ func TestWorker(t *testing.T) {
 		sw.assertExpectations(t)
 	})
 
-	t.Run("webhook notification delivered successfully", func(t *testing.T) {
+	t.Run("webhook call returned a redirection status code: retry scheduled", func(t *testing.T) {
+		t.Parallel()
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n2, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.hc.On("Do", mock.Anything).Return(&http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			StatusCode: http.StatusFound,
+		}, nil)
+		sw.tx.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.MatchedBy(func(d *hub.WebhookDelivery) bool {
+			return d.StatusCode == http.StatusFound && d.Error == "unexpected status code: 302"
+		})).Return(nil)
+		sw.nm.On("ScheduleRetry", sw.ctx, sw.tx, n2.NotificationID, 1*time.Minute, mock.Anything).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("error registering webhook delivery: notification status updated", func(t *testing.T) {
 		t.Parallel()
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
 		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n2, nil)
 		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
 		sw.hc.On("Do", mock.Anything).Return(&http.Response{
 			Body:       ioutil.NopCloser(strings.NewReader("")),
 			StatusCode: http.StatusOK,
 		}, nil)
+		sp := &tests.TXMock{}
+		sw.tx.On("Begin", sw.ctx).Return(sp, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sp, mock.Anything).Return(tests.ErrFakeDB)
+		sp.On("Rollback", sw.ctx).Return(nil)
 		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n2.NotificationID, true, nil).Return(nil)
 		sw.tx.On("Commit", sw.ctx).Return(nil)
 
 		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
 		go w.Run(sw.ctx, sw.wg)
 		sw.assertExpectations(t)
+		sp.AssertExpectations(t)
 	})
 
 	t.Run("webhook notification delivered successfully (real http server)", func(t *testing.T) {
@@ -300,14 +690,31 @@ func TestWorker(t *testing.T) {
 					}
 					assert.Equal(t, "POST", r.Method)
 					assert.Equal(t, contentType, r.Header.Get("Content-Type"))
-					assert.Equal(t, tc.secret, r.Header.Get("X-ArtifactHub-Secret"))
+					assert.Empty(t, r.Header.Get("X-ArtifactHub-Secret"))
 					payload, _ := ioutil.ReadAll(r.Body)
 					assert.Equal(t, tc.expectedPayload, payload)
+					signature := r.Header.Get(WebhookSignatureHeader)
+					if tc.secret != "" {
+						parts := strings.Split(signature, ",")
+						require.Len(t, parts, 2)
+						ts := strings.TrimPrefix(parts[0], "t=")
+						expectedSignature := WebhookSignature(tc.secret, ts, payload)
+						assert.Equal(t, "v1="+expectedSignature, parts[1])
+					} else {
+						assert.Empty(t, signature)
+					}
 				}))
 				defer ts.Close()
 
 				sw := newServicesWrapper()
+				var encryptedSecret string
+				if tc.secret != "" {
+					encryptedSecret = "encrypted-" + tc.secret
+					sw.enc.On("Decrypt", sw.ctx, encryptedSecret).Return(tc.secret, nil)
+				}
 				sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+				sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+				sw.tx.On("Rollback", sw.ctx).Return(nil)
 				sw.nm.On("GetPending", sw.ctx, sw.tx).Return(&hub.Notification{
 					NotificationID: "notificationID",
 					Event:          e1,
@@ -315,10 +722,12 @@ func TestWorker(t *testing.T) {
 						URL:         ts.URL,
 						ContentType: tc.contentType,
 						Template:    tc.template,
-						Secret:      tc.secret,
+						Secret:      encryptedSecret,
 					},
 				}, nil)
 				sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+				sw.tx.On("Begin", sw.ctx).Return(sw.tx, nil)
+				sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.Anything).Return(nil)
 				sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n2.NotificationID, true, nil).Return(nil)
 				sw.tx.On("Commit", sw.ctx).Return(nil)
 
@@ -328,6 +737,171 @@ func TestWorker(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("security alert webhook notification delivered successfully (real http server)", func(t *testing.T) {
+		t.Parallel()
+		expectedPayload := []byte(`
+{
+	"specversion" : "1.0",
+	"id" : "eventID",
+	"source" : "https://artifacthub.io/cloudevents",
+	"type" : "io.artifacthub.package.security-alert",
+	"datacontenttype" : "application/json",
+	"data" : {
+		"package": {
+			"name": "package1",
+			"version": "1.0.0",
+			"url": "http://baseURL/packages/helm/repo1/package1/1.0.0",
+			"securityReportSummary": {
+				"critical": 2,
+				"high": 1,
+				"medium": 0,
+				"low": 4,
+				"unknown": 0
+			},
+			"repository": {
+				"kind": "helm",
+				"name": "repo1",
+				"publisher": "org1"
+			}
+		},
+		"addedVulnerabilities": [{"id": "CVE-0000-0001", "severity": "critical"}, {"id": "CVE-0000-0002", "severity": "high"}]
+	}
+}
+`)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			payload, _ := ioutil.ReadAll(r.Body)
+			assert.Equal(t, expectedPayload, payload)
+		}))
+		defer ts.Close()
+
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(&hub.Notification{
+			NotificationID: "notificationID",
+			Event:          e3,
+			Webhook: &hub.Webhook{
+				URL: ts.URL,
+			},
+		}, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.tx.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.Anything).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notificationID", true, nil).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "http://baseURL", http.DefaultClient)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("repository webhook notification delivered successfully (real http server)", func(t *testing.T) {
+		t.Parallel()
+		expectedPayload := []byte(`
+{
+	"specversion" : "1.0",
+	"id" : "eventID",
+	"source" : "https://artifacthub.io/cloudevents",
+	"type" : "io.artifacthub.repository.tracking-errors",
+	"datacontenttype" : "application/json",
+	"data" : {
+		"repository": {
+			"kind": "helm",
+			"name": "repo2",
+			"userAlias": "user1",
+			"organizationName": "",
+			"lastTrackingErrors": ["error 1","error \"2\""]
+		}
+	}
+}
+`)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			payload, _ := ioutil.ReadAll(r.Body)
+			assert.Equal(t, expectedPayload, payload)
+		}))
+		defer ts.Close()
+
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(&hub.Notification{
+			NotificationID: "notificationID",
+			Event:          e2,
+			Webhook: &hub.Webhook{
+				URL: ts.URL,
+			},
+		}, nil)
+		sw.rm.On("GetByID", sw.ctx, e2.RepositoryID).Return(&hub.Repository{
+			Kind:               hub.Helm,
+			Name:               "repo2",
+			UserAlias:          "user1",
+			LastTrackingErrors: "error 1\nerror \"2\"",
+		}, nil)
+		sw.tx.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.Anything).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notificationID", true, nil).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "http://baseURL", http.DefaultClient)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("chat webhook notification delivered successfully (real http server)", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			var payload map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, "Something went wrong tracking repository repo1", payload["text"])
+		}))
+		defer ts.Close()
+
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPendingDigest", sw.ctx, sw.tx).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(&hub.Notification{
+			NotificationID: "notificationID",
+			Event:          e2,
+			Webhook: &hub.Webhook{
+				URL:         ts.URL,
+				Kind:        hub.SlackWebhook,
+				ContentType: "text/plain",
+				Template:    "ignored",
+			},
+		}, nil)
+		sw.rm.On("GetByID", sw.ctx, e2.RepositoryID).Return(r, nil)
+		sw.tx.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("AddWebhookDelivery", sw.ctx, sw.tx, mock.Anything).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, "notificationID", true, nil).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "http://baseURL", http.DefaultClient)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+}
+
+func TestWebhookNextRetryDelay(t *testing.T) {
+	w := NewWorker(nil, nil, "", nil, WithWebhookRetries(10, time.Minute, 10*time.Minute))
+	testCases := []struct {
+		attempts      int
+		expectedDelay time.Duration
+	}{
+		{1, 1 * time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{4, 8 * time.Minute},
+		{5, 10 * time.Minute},
+		{100, 10 * time.Minute},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedDelay, w.webhookNextRetryDelay(tc.attempts))
+	}
 }
 
 type servicesWrapper struct {
@@ -343,6 +917,7 @@ type servicesWrapper struct {
 	pm         *pkg.ManagerMock
 	cache      *cache.Cache
 	hc         *httpClientMock
+	enc        *encryption.EncrypterMock
 	svc        *Services
 }
 
@@ -361,6 +936,7 @@ func newServicesWrapper() *servicesWrapper {
 	pm := &pkg.ManagerMock{}
 	cache := cache.New(1*time.Minute, 5*time.Minute)
 	hc := &httpClientMock{}
+	enc := &encryption.EncrypterMock{}
 
 	return &servicesWrapper{
 		ctx:        ctx,
@@ -375,6 +951,7 @@ func newServicesWrapper() *servicesWrapper {
 		pm:         pm,
 		cache:      cache,
 		hc:         hc,
+		enc:        enc,
 		svc: &Services{
 			DB:                  db,
 			ES:                  es,
@@ -382,6 +959,7 @@ func newServicesWrapper() *servicesWrapper {
 			SubscriptionManager: sm,
 			RepositoryManager:   rm,
 			PackageManager:      pm,
+			Encrypter:           enc,
 		},
 	}
 }
@@ -401,6 +979,7 @@ func (sw *servicesWrapper) assertExpectations(t *testing.T) {
 	sw.rm.AssertExpectations(t)
 	sw.pm.AssertExpectations(t)
 	sw.hc.AssertExpectations(t)
+	sw.enc.AssertExpectations(t)
 }
 
 type httpClientMock struct {
@@ -952,10 +952,16 @@ func TestUpdateSnapshotSecurityReport(t *testing.T) {
 			High:   2,
 			Medium: 1,
 		},
-		Full: map[string][]interface{}{
+		Full: map[string][]*hub.SecurityReportTarget{
 			"organization/image:tag": {
-				map[string]interface{}{
-					"k": "v",
+				{
+					Target: "target",
+					Vulnerabilities: []*hub.Vulnerability{
+						{
+							VulnerabilityID: "CVE-0000-0001",
+							Severity:        "HIGH",
+						},
+					},
 				},
 			},
 		},
@@ -2,21 +2,43 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"strings"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/spf13/viper"
 )
 
 const (
 	DefaultBranch = "master"
+
+	// defaultGitUser represents the user used to authenticate in git
+	// repositories when only a password or token has been provided, as well
+	// as the one used for ssh connections.
+	defaultGitUser = "git"
 )
 
 // Cloner is a hub.RepositoryCloner implementation.
-type Cloner struct{}
+type Cloner struct {
+	knownHostsFile string
+}
+
+// NewCloner creates a new Cloner instance. The ssh known hosts file used to
+// verify the host keys of the git servers is read from the configuration
+// provided (git.sshKnownHosts).
+func NewCloner(cfg *viper.Viper) *Cloner {
+	return &Cloner{
+		knownHostsFile: cfg.GetString("git.sshKnownHosts"),
+	}
+}
 
 // CloneRepository implements the hub.RepositoryCloner interface.
 func (c *Cloner) CloneRepository(ctx context.Context, r *hub.Repository) (string, string, error) {
@@ -36,6 +58,12 @@ func (c *Cloner) CloneRepository(ctx context.Context, r *hub.Repository) (string
 		}
 	}
 
+	// Setup authentication (private repositories)
+	cloneURL, auth, err := getGitAuth(r, repoBaseURL, c.knownHostsFile)
+	if err != nil {
+		return "", "", err
+	}
+
 	// Clone git repository
 	tmpDir, err := ioutil.TempDir("", "artifact-hub")
 	if err != nil {
@@ -46,7 +74,8 @@ func (c *Cloner) CloneRepository(ctx context.Context, r *hub.Repository) (string
 		branch = DefaultBranch
 	}
 	_, err = git.PlainCloneContext(ctx, tmpDir, false, &git.CloneOptions{
-		URL:           repoBaseURL,
+		URL:           cloneURL,
+		Auth:          auth,
 		ReferenceName: plumbing.NewBranchReferenceName(branch),
 		SingleBranch:  true,
 		Depth:         1,
@@ -57,3 +86,44 @@ func (c *Cloner) CloneRepository(ctx context.Context, r *hub.Repository) (string
 
 	return tmpDir, packagesPath, nil
 }
+
+// getGitAuth returns the url and the authentication method that should be
+// used to access the git repository provided. When the repository has an ssh
+// key, the git repository will be accessed over ssh using the same host and
+// path, verifying the server's host key using the known hosts file provided.
+// Otherwise, if some credentials have been provided, http basic auth will be
+// used (tokens can be provided as the password). The repository credentials
+// are expected to have been already decrypted.
+func getGitAuth(r *hub.Repository, repoBaseURL, knownHostsFile string) (string, transport.AuthMethod, error) {
+	switch {
+	case r.SSHKey != "":
+		if knownHostsFile == "" {
+			return "", nil, errors.New("ssh known hosts file not configured")
+		}
+		hostKeyCallback, err := gitssh.NewKnownHostsCallback(knownHostsFile)
+		if err != nil {
+			return "", nil, fmt.Errorf("error loading ssh known hosts file: %w", err)
+		}
+		auth, err := gitssh.NewPublicKeys(defaultGitUser, []byte(r.SSHKey), "")
+		if err != nil {
+			return "", nil, fmt.Errorf("error parsing ssh key: %w", err)
+		}
+		auth.HostKeyCallback = hostKeyCallback
+		u, err := url.Parse(repoBaseURL)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid repository url: %w", err)
+		}
+		return fmt.Sprintf("ssh://%s@%s%s", defaultGitUser, u.Hostname(), u.Path), auth, nil
+	case r.AuthUser != "" || r.AuthPass != "":
+		username := r.AuthUser
+		if username == "" {
+			username = defaultGitUser
+		}
+		return repoBaseURL, &githttp.BasicAuth{
+			Username: username,
+			Password: r.AuthPass,
+		}, nil
+	default:
+		return repoBaseURL, nil, nil
+	}
+}
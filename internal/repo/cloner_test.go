@@ -0,0 +1,272 @@
+package repo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func TestGetGitAuth(t *testing.T) {
+	repoBaseURL := "https://github.com/org1/repo1"
+
+	t.Run("public repository", func(t *testing.T) {
+		t.Parallel()
+		u, auth, err := getGitAuth(&hub.Repository{}, repoBaseURL, "")
+		assert.NoError(t, err)
+		assert.Equal(t, repoBaseURL, u)
+		assert.Nil(t, auth)
+	})
+
+	t.Run("basic auth", func(t *testing.T) {
+		testCases := []struct {
+			r            *hub.Repository
+			expectedAuth *githttp.BasicAuth
+		}{
+			{
+				&hub.Repository{AuthUser: "user1", AuthPass: "pass1"},
+				&githttp.BasicAuth{Username: "user1", Password: "pass1"},
+			},
+			{
+				&hub.Repository{AuthPass: "token"},
+				&githttp.BasicAuth{Username: "git", Password: "token"},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.expectedAuth.Username, func(t *testing.T) {
+				t.Parallel()
+				u, auth, err := getGitAuth(tc.r, repoBaseURL, "")
+				assert.NoError(t, err)
+				assert.Equal(t, repoBaseURL, u)
+				assert.Equal(t, tc.expectedAuth, auth)
+			})
+		}
+	})
+
+	t.Run("ssh known hosts file not configured", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{SSHKey: generateSSHKey(t)}
+		_, _, err := getGitAuth(r, repoBaseURL, "")
+		assert.Error(t, err)
+	})
+
+	t.Run("ssh known hosts file not found", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{SSHKey: generateSSHKey(t)}
+		_, _, err := getGitAuth(r, repoBaseURL, "testdata/missing_known_hosts")
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid ssh key", func(t *testing.T) {
+		t.Parallel()
+		knownHostsFile := writeKnownHostsFile(t, "github.com", generateSSHSigner(t).PublicKey())
+		defer os.Remove(knownHostsFile)
+		_, _, err := getGitAuth(&hub.Repository{SSHKey: "invalid"}, repoBaseURL, knownHostsFile)
+		assert.Error(t, err)
+	})
+
+	t.Run("ssh key auth", func(t *testing.T) {
+		t.Parallel()
+		knownHostsFile := writeKnownHostsFile(t, "github.com", generateSSHSigner(t).PublicKey())
+		defer os.Remove(knownHostsFile)
+		r := &hub.Repository{SSHKey: generateSSHKey(t)}
+		u, auth, err := getGitAuth(r, "https://github.com:443/org1/repo1", knownHostsFile)
+		assert.NoError(t, err)
+		assert.Equal(t, "ssh://git@github.com/org1/repo1", u)
+		require.IsType(t, &gitssh.PublicKeys{}, auth)
+		assert.Equal(t, "git", auth.(*gitssh.PublicKeys).User)
+		assert.NotNil(t, auth.(*gitssh.PublicKeys).HostKeyCallback)
+	})
+}
+
+func TestCloneOverSSH(t *testing.T) {
+	if _, err := exec.LookPath("git-upload-pack"); err != nil {
+		t.Skip("git-upload-pack not available")
+	}
+
+	// Setup git repository with a single commit in the default branch
+	reposDir, err := ioutil.TempDir("", "artifact-hub-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(reposDir)
+	gitRepo, err := git.PlainInit(filepath.Join(reposDir, "org1", "repo1"), false)
+	require.NoError(t, err)
+	wt, err := gitRepo.Worktree()
+	require.NoError(t, err)
+	hash, err := wt.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  "user1",
+			Email: "user1@email.com",
+			When:  time.Now(),
+		},
+	})
+	require.NoError(t, err)
+
+	// Setup ssh server serving the git repository
+	sshKey := generateSSHKey(t)
+	clientSigner, err := ssh.ParsePrivateKey([]byte(sshKey))
+	require.NoError(t, err)
+	hostSigner := generateSSHSigner(t)
+	addr := startGitSSHServer(t, hostSigner, clientSigner.PublicKey(), reposDir)
+	r := &hub.Repository{SSHKey: sshKey}
+	repoBaseURL := fmt.Sprintf("https://%s/org1/repo1", addr)
+	cloneURL := fmt.Sprintf("ssh://git@%s/org1/repo1", addr)
+
+	t.Run("host key pinned: clone succeeded", func(t *testing.T) {
+		knownHostsFile := writeKnownHostsFile(t, addr, hostSigner.PublicKey())
+		defer os.Remove(knownHostsFile)
+		_, auth, err := getGitAuth(r, repoBaseURL, knownHostsFile)
+		require.NoError(t, err)
+
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		clonedRepo, err := git.PlainClone(dir, false, &git.CloneOptions{
+			URL:  cloneURL,
+			Auth: auth,
+		})
+		require.NoError(t, err)
+		head, err := clonedRepo.Head()
+		require.NoError(t, err)
+		assert.Equal(t, hash, head.Hash())
+	})
+
+	t.Run("host key mismatch: clone failed", func(t *testing.T) {
+		knownHostsFile := writeKnownHostsFile(t, addr, generateSSHSigner(t).PublicKey())
+		defer os.Remove(knownHostsFile)
+		_, auth, err := getGitAuth(r, repoBaseURL, knownHostsFile)
+		require.NoError(t, err)
+
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		_, err = git.PlainClone(dir, false, &git.CloneOptions{
+			URL:  cloneURL,
+			Auth: auth,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "key mismatch")
+	})
+}
+
+func generateSSHSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	signer, err := ssh.ParsePrivateKey([]byte(generateSSHKey(t)))
+	require.NoError(t, err)
+	return signer
+}
+
+func writeKnownHostsFile(t *testing.T, addr string, key ssh.PublicKey) string {
+	t.Helper()
+	f, err := ioutil.TempFile("", "artifact-hub-test-known-hosts")
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString(knownhosts.Line([]string{knownhosts.Normalize(addr)}, key) + "\n")
+	require.NoError(t, err)
+	return f.Name()
+}
+
+// startGitSSHServer starts a ssh server that serves the git repositories
+// located in the directory provided using git-upload-pack. Only the client key
+// provided is authorized. It returns the address the server is listening on.
+func startGitSSHServer(t *testing.T, hostSigner ssh.Signer, clientKey ssh.PublicKey, reposDir string) string {
+	t.Helper()
+	cfg := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if bytes.Equal(key.Marshal(), clientKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unauthorized key")
+		},
+	}
+	cfg.AddHostKey(hostSigner)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serveGitSSHConn(conn, cfg, reposDir)
+		}
+	}()
+	return l.Addr().String()
+}
+
+// serveGitSSHConn handles the ssh connection provided, running the
+// git-upload-pack commands requested on the repositories in reposDir.
+func serveGitSSHConn(conn net.Conn, cfg *ssh.ServerConfig, reposDir string) {
+	defer conn.Close()
+	_, chans, reqs, err := ssh.NewServerConn(conn, cfg)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for nc := range chans {
+		if nc.ChannelType() != "session" {
+			_ = nc.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		ch, chReqs, err := nc.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer ch.Close()
+			for req := range chReqs {
+				var payload struct{ Command string }
+				if req.Type != "exec" || ssh.Unmarshal(req.Payload, &payload) != nil {
+					_ = req.Reply(false, nil)
+					continue
+				}
+				parts := strings.SplitN(payload.Command, " ", 2)
+				if len(parts) != 2 || parts[0] != "git-upload-pack" {
+					_ = req.Reply(false, nil)
+					return
+				}
+				_ = req.Reply(true, nil)
+				cmd := exec.Command("git-upload-pack", filepath.Join(reposDir, strings.Trim(parts[1], "'")))
+				cmd.Stdout = ch
+				cmd.Stderr = ch.Stderr()
+				stdin, err := cmd.StdinPipe()
+				if err != nil {
+					return
+				}
+				var status uint32
+				if err := cmd.Start(); err != nil {
+					status = 1
+				} else {
+					go func() {
+						_, _ = io.Copy(stdin, ch)
+						stdin.Close()
+					}()
+					if err := cmd.Wait(); err != nil {
+						status = 1
+					}
+				}
+				_, _ = ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+				return
+			}
+		}()
+	}
+}
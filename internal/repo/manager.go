@@ -17,13 +17,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/artifacthub/hub/internal/encryption"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/util"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-github/github"
+	"github.com/rs/zerolog/log"
 	"github.com/satori/uuid"
 	"github.com/spf13/viper"
+	"golang.org/x/crypto/ssh"
 	"golang.org/x/oauth2"
 	"gopkg.in/yaml.v2"
 )
@@ -34,19 +38,27 @@ const (
 	checkRepoNameAvailDBQ     = `select repository_id from repository where name = $1`
 	checkRepoURLAvailDBQ      = `select repository_id from repository where trim(trailing '/' from url) = $1`
 	deleteRepoDBQ             = `select delete_repository($1::uuid, $2::text)`
+	enqueueTrackingJobsDBQ    = `select enqueue_tracking_jobs($1::uuid[])`
 	getAllReposDBQ            = `select get_all_repositories($1::boolean)`
 	getOrgReposDBQ            = `select get_org_repositories($1::uuid, $2::text, $3::boolean)`
 	getRepoByIDDBQ            = `select get_repository_by_id($1::uuid, $2::boolean)`
 	getRepoByNameDBQ          = `select get_repository_by_name($1::text, $2::boolean)`
 	getRepoPkgsDigestDBQ      = `select get_repository_packages_digest($1::uuid)`
+	getReposByGitURLDBQ       = `select get_repositories_by_git_url($1::text, $2::boolean)`
 	getReposByKindDBQ         = `select get_repositories_by_kind($1::int, $2::boolean)`
+	getTrackingJobDBQ         = `select get_tracking_job($1::uuid, $2::text, $3::uuid)`
 	getUserReposDBQ           = `select get_user_repositories($1::uuid, $2::boolean)`
 	getUserEmailDBQ           = `select email from "user" where user_id = $1`
 	setLastTrackingResultsDBQ = `select set_last_tracking_results($1::uuid, $2::text, $3::boolean)`
 	setVerifiedPublisherDBQ   = `select set_verified_publisher($1::uuid, $2::boolean)`
+	trackRepoDBQ              = `select enqueue_tracking_job($1::uuid, $2::text)`
 	transferRepoDBQ           = `select transfer_repository($1::text, $2::uuid, $3::text, $4::boolean)`
 	updateRepoDBQ             = `select update_repository($1::uuid, $2::jsonb)`
 	updateRepoDigestDBQ       = `update repository set digest = $2 where repository_id = $1`
+
+	// minTrackingInterval represents the minimum tracking interval (in
+	// minutes) that can be set for a repository.
+	minTrackingInterval = 5
 )
 
 var (
@@ -61,8 +73,9 @@ var (
 	ErrSchemeNotSupported = errors.New("scheme not supported")
 
 	// GitRepoURLRE is a regexp used to validate and parse a git based
-	// repository URL.
-	GitRepoURLRE = regexp.MustCompile(`^(https:\/\/(github|gitlab)\.com\/[A-Za-z0-9_.-]+\/[A-Za-z0-9_.-]+)\/?(.*)$`)
+	// repository URL. Any git provider is supported, as long as the git
+	// repository base url has the https://host/owner/repo format.
+	GitRepoURLRE = regexp.MustCompile(`^(https:\/\/([A-Za-z0-9.-]+(?::[0-9]+)?)\/[A-Za-z0-9_.-]+\/[A-Za-z0-9_.-]+)\/?(.*)$`)
 )
 
 // HTTPGetter defines the methods an HTTPGetter implementation must provide.
@@ -79,6 +92,10 @@ type Manager struct {
 	helmIndexLoader hub.HelmIndexLoader
 	az              hub.Authorizer
 	gh              *github.Client
+	hc              hub.HTTPClient
+	enc             hub.Encrypter
+
+	getGitRemoteDigest func(repoURL, branch string, auth transport.AuthMethod) (string, error)
 }
 
 // NewManager creates a new Manager instance.
@@ -105,14 +122,27 @@ func NewManager(cfg *viper.Viper, db hub.DB, az hub.Authorizer, opts ...func(m *
 		m.gh = github.NewClient(http.DefaultClient)
 	}
 
-	// Setup HTTP getter
+	// Setup HTTP getter and client
 	if m.hg == nil {
 		m.hg = &http.Client{Timeout: 10 * time.Second}
 	}
+	if m.hc == nil {
+		m.hc = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	// Setup git remote digest getter
+	if m.getGitRemoteDigest == nil {
+		m.getGitRemoteDigest = getGitRemoteDigest
+	}
 
 	// Setup repository cloner
 	if m.rc == nil {
-		m.rc = &Cloner{}
+		m.rc = NewCloner(cfg)
+	}
+
+	// Setup encrypter
+	if m.enc == nil {
+		m.enc = encryption.NewEncrypter(nil)
 	}
 
 	return m
@@ -126,6 +156,14 @@ func WithHelmIndexLoader(l hub.HelmIndexLoader) func(m *Manager) {
 	}
 }
 
+// WithEncrypter allows providing the encrypter used to encrypt the
+// repositories credentials before storing them in the database.
+func WithEncrypter(enc hub.Encrypter) func(m *Manager) {
+	return func(m *Manager) {
+		m.enc = enc
+	}
+}
+
 // Add adds the provided repository to the database.
 func (m *Manager) Add(ctx context.Context, orgName string, r *hub.Repository) error {
 	userID := ctx.Value(hub.UserIDKey).(string)
@@ -140,6 +178,9 @@ func (m *Manager) Add(ctx context.Context, orgName string, r *hub.Repository) er
 	if !repositoryNameRE.MatchString(r.Name) {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid name")
 	}
+	if r.TrackingInterval != 0 && r.TrackingInterval < minTrackingInterval {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid tracking interval")
+	}
 	if err := m.validateURL(r); err != nil {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, err.Error())
 	}
@@ -158,6 +199,11 @@ func (m *Manager) Add(ctx context.Context, orgName string, r *hub.Repository) er
 		}
 	}
 
+	// Encrypt ssh key (if provided) before storing it
+	if err := m.encryptCredentials(ctx, r); err != nil {
+		return err
+	}
+
 	// Add repository to the database
 	rJSON, _ := json.Marshal(r)
 	_, err := m.db.Exec(ctx, addRepoDBQ, userID, orgName, rJSON)
@@ -451,14 +497,22 @@ func (m *Manager) GetOwnedByOrgJSON(
 	}
 
 	// Get org repositories from database
-	return util.DBQueryJSON(ctx, m.db, getOrgReposDBQ, userID, orgName, includeCredentials)
+	dataJSON, err := util.DBQueryJSON(ctx, m.db, getOrgReposDBQ, userID, orgName, includeCredentials)
+	if err != nil || !includeCredentials {
+		return dataJSON, err
+	}
+	return prepareOwnedCredentials(dataJSON)
 }
 
 // GetOwnedByUserJSON returns all repositories that belong to the user making
 // the request.
 func (m *Manager) GetOwnedByUserJSON(ctx context.Context, includeCredentials bool) ([]byte, error) {
 	userID := ctx.Value(hub.UserIDKey).(string)
-	return util.DBQueryJSON(ctx, m.db, getUserReposDBQ, userID, includeCredentials)
+	dataJSON, err := util.DBQueryJSON(ctx, m.db, getUserReposDBQ, userID, includeCredentials)
+	if err != nil || !includeCredentials {
+		return dataJSON, err
+	}
+	return prepareOwnedCredentials(dataJSON)
 }
 
 // GetRemoteDigest gets the repository's digest available in the remote.
@@ -493,37 +547,89 @@ func (m *Manager) GetRemoteDigest(ctx context.Context, r *hub.Repository) (strin
 		}
 		digest = desc.Digest.String()
 
-	case SchemeIsHTTP(u) && u.Host == "github.com":
-		// Digest is obtained from the last commit in the repository
+	case SchemeIsHTTP(u) && GitRepoURLRE.MatchString(r.URL):
+		// Digest is obtained from the last commit in the repository's branch
+		matches := GitRepoURLRE.FindStringSubmatch(r.URL)
+		repoBaseURL := matches[1]
 		pathParts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
-		if len(pathParts) < 2 {
-			break
-		}
 		owner := pathParts[0]
-		repo := pathParts[1]
+		repo := strings.TrimSuffix(pathParts[1], ".git")
 		branch := r.Branch
 		if branch == "" {
 			branch = DefaultBranch
 		}
-		opt := &github.CommitsListOptions{
-			SHA: branch,
-			ListOptions: github.ListOptions{
-				Page:    0,
-				PerPage: 1,
-			},
+		private := r.AuthUser != "" || r.AuthPass != "" || r.SSHKey != ""
+		var err error
+		switch {
+		case private:
+			var remoteURL string
+			var auth transport.AuthMethod
+			remoteURL, auth, err = getGitAuth(r, repoBaseURL, m.cfg.GetString("git.sshKnownHosts"))
+			if err == nil {
+				digest, err = m.getGitRemoteDigest(remoteURL, branch, auth)
+			}
+		case u.Host == "github.com":
+			opt := &github.CommitsListOptions{
+				SHA: branch,
+				ListOptions: github.ListOptions{
+					Page:    0,
+					PerPage: 1,
+				},
+			}
+			var commits []*github.RepositoryCommit
+			commits, _, err = m.gh.Repositories.ListCommits(ctx, owner, repo, opt)
+			if err == nil && len(commits) == 1 {
+				digest = *commits[0].SHA
+			}
+		case u.Host == "gitlab.com" && m.cfg.GetString("tracker.gitlabToken") != "":
+			token := m.cfg.GetString("tracker.gitlabToken")
+			digest, err = getGitLabDigest(ctx, m.hc, token, owner, repo, branch)
+		case u.Host == "bitbucket.org" && m.cfg.GetString("tracker.bitbucketToken") != "":
+			token := m.cfg.GetString("tracker.bitbucketToken")
+			digest, err = getBitbucketDigest(ctx, m.hc, token, owner, repo, branch)
+		default:
+			digest, err = m.getGitRemoteDigest(repoBaseURL, branch, nil)
 		}
-		commits, _, err := m.gh.Repositories.ListCommits(ctx, owner, repo, opt)
 		if err != nil {
-			return digest, err
-		}
-		if len(commits) == 1 {
-			digest = *commits[0].SHA
+			return "", err
 		}
 	}
 
 	return digest, nil
 }
 
+// GetTrackingJobJSON returns the tracking job of the provided repository
+// requested as a json object.
+func (m *Manager) GetTrackingJobJSON(ctx context.Context, name, trackingJobID string) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if name == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
+	}
+	if _, err := uuid.FromString(trackingJobID); err != nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid tracking job id")
+	}
+
+	// Authorize action if the repository is owned by an organization
+	r, err := m.GetByName(ctx, name, false)
+	if err != nil {
+		return nil, err
+	}
+	if r.OrganizationName != "" {
+		if err := m.az.Authorize(ctx, &hub.AuthorizeInput{
+			OrganizationName: r.OrganizationName,
+			UserID:           userID,
+			Action:           hub.TrackOrganizationRepository,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Get tracking job from database
+	return util.DBQueryJSON(ctx, m.db, getTrackingJobDBQ, userID, name, trackingJobID)
+}
+
 // SetLastTrackingResults updates the timestamp and errors of the last tracking
 // of the provided repository in the database.
 func (m *Manager) SetLastTrackingResults(ctx context.Context, repositoryID, errs string) error {
@@ -551,6 +657,105 @@ func (m *Manager) SetVerifiedPublisher(ctx context.Context, repositoryID string,
 	return err
 }
 
+// Track requests the tracking of the provided repository as soon as possible,
+// returning the id of the tracking job that will process it.
+func (m *Manager) Track(ctx context.Context, name string) (string, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if name == "" {
+		return "", fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
+	}
+
+	// Authorize action if the repository is owned by an organization
+	r, err := m.GetByName(ctx, name, false)
+	if err != nil {
+		return "", err
+	}
+	if r.Disabled {
+		return "", fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository is disabled")
+	}
+	if r.OrganizationName != "" {
+		if err := m.az.Authorize(ctx, &hub.AuthorizeInput{
+			OrganizationName: r.OrganizationName,
+			UserID:           userID,
+			Action:           hub.TrackOrganizationRepository,
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	// Enqueue repository tracking job in database
+	var trackingJobID string
+	err = m.db.QueryRow(ctx, trackRepoDBQ, userID, name).Scan(&trackingJobID)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return "", hub.ErrInsufficientPrivilege
+	}
+	return trackingJobID, err
+}
+
+// TrackPushed requests the tracking of the repositories affected by the push
+// event provided, returning their names. Only repositories whose push webhook
+// secret matches the one used to sign the event will be tracked. The same
+// error is returned when no repositories match the event and when none of
+// them can verify its signature, so that callers cannot probe which git
+// repositories are registered in the hub.
+func (m *Manager) TrackPushed(ctx context.Context, e *hub.RepositoryPushEvent) ([]string, error) {
+	// Validate input and extract push event details
+	if e == nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "push event not provided")
+	}
+	pe, err := parsePushEvent(e)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, err.Error())
+	}
+	if pe == nil {
+		return nil, nil
+	}
+
+	// Get repositories stored in the git repository the push event refers to
+	var rs []*hub.Repository
+	if err := util.DBQueryUnmarshal(ctx, m.db, &rs, getReposByGitURLDBQ, pe.url, true); err != nil {
+		return nil, err
+	}
+
+	// Select the repositories affected by the push event
+	var verified bool
+	var names, ids []string
+	for _, r := range rs {
+		secret, err := m.enc.Decrypt(ctx, r.PushWebhookSecret)
+		if err != nil {
+			log.Error().Err(err).Str("repo", r.Name).Msg("error decrypting push webhook secret")
+			continue
+		}
+		if !verifyPushEvent(e, secret) {
+			continue
+		}
+		verified = true
+		branch := r.Branch
+		if branch == "" {
+			branch = DefaultBranch
+		}
+		if branch != pe.branch {
+			continue
+		}
+		names = append(names, r.Name)
+		ids = append(ids, r.RepositoryID)
+	}
+	if !verified {
+		return nil, hub.ErrInsufficientPrivilege
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	// Enqueue repositories tracking jobs in database
+	if _, err := m.db.Exec(ctx, enqueueTrackingJobsDBQ, ids); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
 // Transfer transfers the provided repository to a different owner. A user
 // owned repo can be transferred to an organization the requesting user belongs
 // to. An org owned repo can be transfer to the requesting user, provided the
@@ -606,6 +811,9 @@ func (m *Manager) Update(ctx context.Context, r *hub.Repository) error {
 	if r.Name == "" {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
 	}
+	if r.TrackingInterval != 0 && r.TrackingInterval < minTrackingInterval {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid tracking interval")
+	}
 	if err := m.validateURL(r); err != nil {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, err.Error())
 	}
@@ -628,6 +836,11 @@ func (m *Manager) Update(ctx context.Context, r *hub.Repository) error {
 		}
 	}
 
+	// Encrypt ssh key (if provided) before storing it
+	if err := m.encryptCredentials(ctx, r); err != nil {
+		return err
+	}
+
 	// Update repository in database
 	rJSON, _ := json.Marshal(r)
 	_, err = m.db.Exec(ctx, updateRepoDBQ, userID, rJSON)
@@ -674,17 +887,67 @@ func (m *Manager) validateURL(r *hub.Repository) error {
 }
 
 // validateCredentials validates the credentials of the repository provided.
+// Encrypted values are never accepted from clients.
 func (m *Manager) validateCredentials(r *hub.Repository) error {
+	for _, v := range []string{r.AuthPass, r.SSHKey, r.PushWebhookSecret} {
+		if encryption.IsEncrypted(v) {
+			return errors.New("encrypted values not allowed")
+		}
+	}
 	allowPrivateRepos := m.cfg.GetBool("server.allowPrivateRepositories")
-	if !allowPrivateRepos && (r.AuthUser != "" || r.AuthPass != "") {
+	if !allowPrivateRepos && (r.AuthUser != "" || r.AuthPass != "" || r.SSHKey != "") {
 		return errors.New("private repositories not allowed")
 	}
-	if allowPrivateRepos && (r.AuthUser != "" || r.AuthPass != "") && r.Kind != hub.Helm {
-		return errors.New("only helm private repositories are allowed")
+	u, _ := url.Parse(r.URL)
+	if (r.AuthUser != "" || r.AuthPass != "") && r.Kind != hub.Helm && !SchemeIsHTTP(u) {
+		return errors.New("credentials are only supported in helm and git based repositories")
+	}
+	if r.SSHKey != "" {
+		if r.Kind == hub.Helm || !SchemeIsHTTP(u) {
+			return errors.New("ssh keys are only supported in git based repositories")
+		}
+		if _, err := ssh.ParsePrivateKey([]byte(r.SSHKey)); err != nil {
+			return errors.New("invalid ssh key")
+		}
 	}
 	return nil
 }
 
+// encryptCredentials encrypts the credentials and the push webhook secret of
+// the repository provided (if any), so that they are not stored in plaintext.
+func (m *Manager) encryptCredentials(ctx context.Context, r *hub.Repository) error {
+	for _, v := range []*string{&r.AuthPass, &r.SSHKey, &r.PushWebhookSecret} {
+		if *v == "" {
+			continue
+		}
+		encryptedValue, err := m.enc.Encrypt(ctx, *v)
+		if err != nil {
+			return err
+		}
+		*v = encryptedValue
+	}
+	return nil
+}
+
+// prepareOwnedCredentials prepares the credentials of the repositories in the
+// json array provided to be returned to their owners. The basic auth password,
+// the ssh key and the push webhook secret are never returned (only whether
+// they are set or not), as they are only decrypted when they are used.
+func prepareOwnedCredentials(dataJSON []byte) ([]byte, error) {
+	var rs []map[string]json.RawMessage
+	if err := json.Unmarshal(dataJSON, &rs); err != nil {
+		return nil, err
+	}
+	for _, r := range rs {
+		for _, k := range []string{"auth_pass", "ssh_key", "push_webhook_secret"} {
+			_, ok := r[k]
+			delete(r, k)
+			r[k+"_set"], _ = json.Marshal(ok)
+		}
+	}
+	return json.Marshal(rs)
+}
+
 // SchemeIsHTTP is a helper that checks if the scheme of the url provided is
 // http or https.
 func SchemeIsHTTP(u *url.URL) bool {
@@ -2,7 +2,14 @@ package repo
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -13,9 +20,12 @@ import (
 	"testing"
 
 	"github.com/artifacthub/hub/internal/authz"
+	"github.com/artifacthub/hub/internal/encryption"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/util"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -129,6 +139,17 @@ func TestAdd(t *testing.T) {
 				},
 				nil,
 			},
+			{
+				"invalid tracking interval",
+				"org1",
+				&hub.Repository{
+					Kind:             hub.Helm,
+					Name:             "repo1",
+					URL:              "https://repo1.com",
+					TrackingInterval: 1,
+				},
+				nil,
+			},
 			{
 				"invalid url format",
 				"org1",
@@ -314,6 +335,103 @@ func TestAdd(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("credentials validation failed", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			r      *hub.Repository
+		}{
+			{
+				"credentials are only supported in helm and git based repositories",
+				&hub.Repository{
+					Name:     "repo1",
+					URL:      "oci://registry.io/repo1",
+					Kind:     hub.OLM,
+					AuthUser: "user1",
+					AuthPass: "pass1",
+				},
+			},
+			{
+				"ssh keys are only supported in git based repositories",
+				&hub.Repository{
+					Name:   "repo1",
+					URL:    "oci://registry.io/repo1",
+					Kind:   hub.OLM,
+					SSHKey: "key",
+				},
+			},
+			{
+				"invalid ssh key",
+				&hub.Repository{
+					Name:   "repo1",
+					URL:    "https://github.com/org1/repo1",
+					Kind:   hub.OPA,
+					SSHKey: "key",
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				cfg := viper.New()
+				cfg.Set("server.allowPrivateRepositories", true)
+				m := NewManager(cfg, nil, nil)
+
+				err := m.Add(ctx, "", tc.r)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("error encrypting credentials", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{
+			Name:     "repo1",
+			URL:      "https://github.com/org1/repo1",
+			Kind:     hub.OPA,
+			AuthPass: "pass1",
+		}
+		cfg := viper.New()
+		cfg.Set("server.allowPrivateRepositories", true)
+		enc := &encryption.EncrypterMock{}
+		enc.On("Encrypt", ctx, "pass1").Return("", tests.ErrFake)
+		m := NewManager(cfg, nil, nil, WithEncrypter(enc))
+
+		err := m.Add(ctx, "", r)
+		assert.Equal(t, tests.ErrFake, err)
+		enc.AssertExpectations(t)
+	})
+
+	t.Run("add private repository succeeded: credentials are encrypted", func(t *testing.T) {
+		t.Parallel()
+		sshKey := generateSSHKey(t)
+		r := &hub.Repository{
+			Name:     "repo1",
+			URL:      "https://github.com/org1/repo1",
+			Kind:     hub.OPA,
+			AuthPass: "pass1",
+			SSHKey:   sshKey,
+		}
+		cfg := viper.New()
+		cfg.Set("server.allowPrivateRepositories", true)
+		enc := &encryption.EncrypterMock{}
+		enc.On("Encrypt", ctx, "pass1").Return("encryptedPass1", nil)
+		enc.On("Encrypt", ctx, sshKey).Return("encryptedSSHKey", nil)
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, addRepoDBQ, "userID", "", mock.MatchedBy(func(rJSON []byte) bool {
+			var rStored *hub.Repository
+			_ = json.Unmarshal(rJSON, &rStored)
+			return rStored.AuthPass == "encryptedPass1" && rStored.SSHKey == "encryptedSSHKey"
+		})).Return(nil)
+		m := NewManager(cfg, db, nil, WithEncrypter(enc))
+
+		err := m.Add(ctx, "", r)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+		enc.AssertExpectations(t)
+	})
 }
 
 func TestCheckAvailability(t *testing.T) {
@@ -1157,6 +1275,24 @@ func TestGetOwnedByOrgJSON(t *testing.T) {
 		assert.Equal(t, []byte("dataJSON"), dataJSON)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("org repositories data with credentials returned successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getOrgReposDBQ, "userID", "orgName", true).Return([]byte(`[
+			{"name": "repo1", "auth_pass": "encryptedPass1", "ssh_key": "encryptedKey1", "push_webhook_secret": "encryptedSecret1"},
+			{"name": "repo2"}
+		]`), nil)
+		m := NewManager(cfg, db, nil)
+
+		dataJSON, err := m.GetOwnedByOrgJSON(ctx, "orgName", true)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[
+			{"name": "repo1", "auth_pass_set": true, "ssh_key_set": true, "push_webhook_secret_set": true},
+			{"name": "repo2", "auth_pass_set": false, "ssh_key_set": false, "push_webhook_secret_set": false}
+		]`, string(dataJSON))
+		db.AssertExpectations(t)
+	})
 }
 
 func TestGetOwnedByUserJSON(t *testing.T) {
@@ -1193,6 +1329,24 @@ func TestGetOwnedByUserJSON(t *testing.T) {
 		assert.Equal(t, []byte("dataJSON"), dataJSON)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("user repositories data with credentials returned successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserReposDBQ, "userID", true).Return([]byte(`[
+			{"name": "repo1", "auth_pass": "encryptedPass1", "ssh_key": "encryptedKey1", "push_webhook_secret": "encryptedSecret1"},
+			{"name": "repo2"}
+		]`), nil)
+		m := NewManager(cfg, db, nil)
+
+		dataJSON, err := m.GetOwnedByUserJSON(ctx, true)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[
+			{"name": "repo1", "auth_pass_set": true, "ssh_key_set": true, "push_webhook_secret_set": true},
+			{"name": "repo2", "auth_pass_set": false, "ssh_key_set": false, "push_webhook_secret_set": false}
+		]`, string(dataJSON))
+		db.AssertExpectations(t)
+	})
 }
 
 func TestGetRemoteDigest(t *testing.T) {
@@ -1240,99 +1394,194 @@ func TestGetRemoteDigest(t *testing.T) {
 		assert.Equal(t, "a1cbe8e02116f43084632fbf313c4ed02772f93af327bbc16989a30bc04ddc89", digest)
 		assert.Nil(t, err)
 	})
-}
-
-func TestSetLastTrackingResults(t *testing.T) {
-	ctx := context.Background()
-	repoID := "00000000-0000-0000-0000-000000000001"
-
-	t.Run("invalid input", func(t *testing.T) {
-		t.Parallel()
-		m := NewManager(cfg, nil, nil)
-		err := m.SetLastTrackingResults(ctx, "invalid", "errors")
-		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
-	})
-
-	t.Run("database update succeeded", func(t *testing.T) {
-		t.Parallel()
-		db := &tests.DBMock{}
-		db.On("Exec", ctx, setLastTrackingResultsDBQ, repoID, "errors", false).Return(nil)
-		m := NewManager(cfg, db, nil)
-
-		err := m.SetLastTrackingResults(ctx, repoID, "errors")
-		assert.NoError(t, err)
-		db.AssertExpectations(t)
-	})
 
-	t.Run("database error", func(t *testing.T) {
+	t.Run("git remote: error getting digest", func(t *testing.T) {
 		t.Parallel()
-		db := &tests.DBMock{}
-		db.On("Exec", ctx, setLastTrackingResultsDBQ, repoID, "errors", false).Return(tests.ErrFakeDB)
-		m := NewManager(cfg, db, nil)
+		r := &hub.Repository{
+			Kind: hub.OPA,
+			URL:  "https://git.example.com/org1/repo1/path",
+		}
+		m := NewManager(cfg, nil, nil, withGitRemoteDigestGetter(func(repoURL, branch string, auth transport.AuthMethod) (string, error) {
+			return "", tests.ErrFake
+		}))
 
-		err := m.SetLastTrackingResults(ctx, repoID, "errors")
-		assert.Equal(t, tests.ErrFakeDB, err)
-		db.AssertExpectations(t)
+		digest, err := m.GetRemoteDigest(ctx, r)
+		assert.Empty(t, digest)
+		assert.Equal(t, tests.ErrFake, err)
 	})
-}
 
-func TestSetVerifiedPublisher(t *testing.T) {
-	ctx := context.Background()
-	repoID := "00000000-0000-0000-0000-000000000001"
+	t.Run("git remote: success", func(t *testing.T) {
+		testCases := []struct {
+			r               *hub.Repository
+			expectedRepoURL string
+			expectedBranch  string
+		}{
+			{
+				&hub.Repository{
+					Kind: hub.OPA,
+					URL:  "https://git.example.com/org1/repo1/path",
+				},
+				"https://git.example.com/org1/repo1",
+				DefaultBranch,
+			},
+			{
+				&hub.Repository{
+					Kind:   hub.Falco,
+					URL:    "https://gitlab.com/org1/repo1",
+					Branch: "main",
+				},
+				"https://gitlab.com/org1/repo1",
+				"main",
+			},
+			{
+				&hub.Repository{
+					Kind: hub.TektonTask,
+					URL:  "https://bitbucket.org/org1/repo1/",
+				},
+				"https://bitbucket.org/org1/repo1",
+				DefaultBranch,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.r.URL, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(cfg, nil, nil, withGitRemoteDigestGetter(func(repoURL, branch string, auth transport.AuthMethod) (string, error) {
+					assert.Equal(t, tc.expectedRepoURL, repoURL)
+					assert.Equal(t, tc.expectedBranch, branch)
+					return "digest", nil
+				}))
 
-	t.Run("invalid input", func(t *testing.T) {
-		t.Parallel()
-		m := NewManager(cfg, nil, nil)
-		err := m.SetVerifiedPublisher(ctx, "invalid", true)
-		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				digest, err := m.GetRemoteDigest(ctx, tc.r)
+				assert.NoError(t, err)
+				assert.Equal(t, "digest", digest)
+			})
+		}
 	})
 
-	t.Run("database update succeeded", func(t *testing.T) {
+	t.Run("git remote: private repository", func(t *testing.T) {
 		t.Parallel()
-		db := &tests.DBMock{}
-		db.On("Exec", ctx, setVerifiedPublisherDBQ, repoID, true).Return(nil)
-		m := NewManager(cfg, db, nil)
+		r := &hub.Repository{
+			Kind:     hub.OPA,
+			URL:      "https://github.com/org1/repo1/path",
+			AuthPass: "token",
+		}
+		m := NewManager(cfg, nil, nil, withGitRemoteDigestGetter(func(repoURL, branch string, auth transport.AuthMethod) (string, error) {
+			assert.Equal(t, "https://github.com/org1/repo1", repoURL)
+			assert.Equal(t, &githttp.BasicAuth{Username: "git", Password: "token"}, auth)
+			return "digest", nil
+		}))
 
-		err := m.SetVerifiedPublisher(ctx, repoID, true)
+		digest, err := m.GetRemoteDigest(ctx, r)
 		assert.NoError(t, err)
-		db.AssertExpectations(t)
+		assert.Equal(t, "digest", digest)
 	})
 
-	t.Run("database error", func(t *testing.T) {
+	t.Run("forge api: unexpected status code", func(t *testing.T) {
 		t.Parallel()
-		db := &tests.DBMock{}
-		db.On("Exec", ctx, setVerifiedPublisherDBQ, repoID, true).Return(tests.ErrFakeDB)
-		m := NewManager(cfg, db, nil)
+		r := &hub.Repository{
+			Kind: hub.OPA,
+			URL:  "https://gitlab.com/org1/repo1/path",
+		}
+		cfg := viper.New()
+		cfg.Set("tracker.gitlabToken", "token")
+		hc := &tests.HTTPClientMock{}
+		hc.On("Do", mock.Anything).Return(&http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			StatusCode: http.StatusNotFound,
+		}, nil)
+		m := NewManager(cfg, nil, nil, withHTTPClient(hc))
 
-		err := m.SetVerifiedPublisher(ctx, repoID, true)
-		assert.Equal(t, tests.ErrFakeDB, err)
-		db.AssertExpectations(t)
+		digest, err := m.GetRemoteDigest(ctx, r)
+		assert.Empty(t, digest)
+		assert.Error(t, err)
+		hc.AssertExpectations(t)
+	})
+
+	t.Run("forge api: success", func(t *testing.T) {
+		testCases := []struct {
+			r              *hub.Repository
+			cfgKey         string
+			expectedURL    string
+			expectedHeader string
+			expectedToken  string
+			respBody       string
+		}{
+			{
+				&hub.Repository{
+					Kind:   hub.OPA,
+					URL:    "https://gitlab.com/org1/repo1/path",
+					Branch: "main",
+				},
+				"tracker.gitlabToken",
+				"https://gitlab.com/api/v4/projects/org1%2Frepo1/repository/branches/main",
+				"PRIVATE-TOKEN",
+				"token",
+				`{"commit": {"id": "digest"}}`,
+			},
+			{
+				&hub.Repository{
+					Kind: hub.OPA,
+					URL:  "https://bitbucket.org/org1/repo1/path",
+				},
+				"tracker.bitbucketToken",
+				"https://api.bitbucket.org/2.0/repositories/org1/repo1/refs/branches/master",
+				"Authorization",
+				"Bearer token",
+				`{"target": {"hash": "digest"}}`,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.r.URL, func(t *testing.T) {
+				t.Parallel()
+				cfg := viper.New()
+				cfg.Set(tc.cfgKey, "token")
+				hc := &tests.HTTPClientMock{}
+				hc.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+					return req.URL.String() == tc.expectedURL && req.Header.Get(tc.expectedHeader) == tc.expectedToken
+				})).Return(&http.Response{
+					Body:       ioutil.NopCloser(strings.NewReader(tc.respBody)),
+					StatusCode: http.StatusOK,
+				}, nil)
+				m := NewManager(cfg, nil, nil, withHTTPClient(hc))
+
+				digest, err := m.GetRemoteDigest(ctx, tc.r)
+				assert.NoError(t, err)
+				assert.Equal(t, "digest", digest)
+				hc.AssertExpectations(t)
+			})
+		}
 	})
 }
 
-func TestTransfer(t *testing.T) {
+func TestGetTrackingJobJSON(t *testing.T) {
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
-	userID := "userID"
-	userIDP := &userID
-	org := "org1"
-	orgP := &org
+	jobID := "00000000-0000-0000-0000-000000000001"
 
 	t.Run("user id not found in ctx", func(t *testing.T) {
 		t.Parallel()
 		m := NewManager(cfg, nil, nil)
 		assert.Panics(t, func() {
-			_ = m.Transfer(context.Background(), "repo1", "", false)
+			_, _ = m.GetTrackingJobJSON(context.Background(), "repo1", jobID)
 		})
 	})
 
 	t.Run("invalid input", func(t *testing.T) {
 		testCases := []struct {
-			errMsg   string
-			repoName string
+			errMsg        string
+			name          string
+			trackingJobID string
 		}{
 			{
-				"repository name not provided",
+				"name not provided",
 				"",
+				jobID,
+			},
+			{
+				"invalid tracking job id",
+				"repo1",
+				"invalid",
 			},
 		}
 		for _, tc := range testCases {
@@ -1340,9 +1589,9 @@ func TestTransfer(t *testing.T) {
 			t.Run(tc.errMsg, func(t *testing.T) {
 				t.Parallel()
 				m := NewManager(cfg, nil, nil)
-
-				err := m.Transfer(ctx, tc.repoName, "", false)
+				_, err := m.GetTrackingJobJSON(ctx, tc.name, tc.trackingJobID)
 				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
 			})
 		}
 	})
@@ -1361,12 +1610,14 @@ func TestTransfer(t *testing.T) {
 		az.On("Authorize", ctx, &hub.AuthorizeInput{
 			OrganizationName: "orgName",
 			UserID:           "userID",
-			Action:           hub.TransferOrganizationRepository,
+			Action:           hub.TrackOrganizationRepository,
 		}).Return(tests.ErrFake)
 		m := NewManager(cfg, db, az)
 
-		err := m.Transfer(ctx, "repo1", "orgDest", false)
+		dataJSON, err := m.GetTrackingJobJSON(ctx, "repo1", jobID)
 		assert.Equal(t, tests.ErrFake, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
 		az.AssertExpectations(t)
 	})
 
@@ -1393,27 +1644,21 @@ func TestTransfer(t *testing.T) {
 				{
 					"repository_id": "00000000-0000-0000-0000-000000000001",
 					"name": "repo1",
-					"organization_name": "orgName"
+					"user_alias": "user1"
 				}
 				`), nil)
-				db.On("Exec", ctx, transferRepoDBQ, "repo1", userIDP, orgP, false).Return(tc.dbErr)
-				az := &authz.AuthorizerMock{}
-				az.On("Authorize", ctx, &hub.AuthorizeInput{
-					OrganizationName: "orgName",
-					UserID:           "userID",
-					Action:           hub.TransferOrganizationRepository,
-				}).Return(nil)
-				m := NewManager(cfg, db, az)
+				db.On("QueryRow", ctx, getTrackingJobDBQ, "userID", "repo1", jobID).Return(nil, tc.dbErr)
+				m := NewManager(cfg, db, nil)
 
-				err := m.Transfer(ctx, "repo1", org, false)
+				dataJSON, err := m.GetTrackingJobJSON(ctx, "repo1", jobID)
 				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
 				db.AssertExpectations(t)
-				az.AssertExpectations(t)
 			})
 		}
 	})
 
-	t.Run("transfer repository succeeded", func(t *testing.T) {
+	t.Run("tracking job data returned successfully", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
 		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
@@ -1423,34 +1668,633 @@ func TestTransfer(t *testing.T) {
 			"user_alias": "user1"
 		}
 		`), nil)
-		db.On("Exec", ctx, transferRepoDBQ, "repo1", userIDP, orgP, false).Return(nil)
+		db.On("QueryRow", ctx, getTrackingJobDBQ, "userID", "repo1", jobID).Return([]byte("dataJSON"), nil)
 		m := NewManager(cfg, db, nil)
 
-		err := m.Transfer(ctx, "repo1", org, false)
+		dataJSON, err := m.GetTrackingJobJSON(ctx, "repo1", jobID)
 		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
 		db.AssertExpectations(t)
 	})
 }
 
-func TestUpdate(t *testing.T) {
-	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+func TestSetLastTrackingResults(t *testing.T) {
+	ctx := context.Background()
+	repoID := "00000000-0000-0000-0000-000000000001"
 
-	t.Run("user id not found in ctx", func(t *testing.T) {
+	t.Run("invalid input", func(t *testing.T) {
 		t.Parallel()
 		m := NewManager(cfg, nil, nil)
-		assert.Panics(t, func() {
-			_ = m.Update(context.Background(), nil)
-		})
+		err := m.SetLastTrackingResults(ctx, "invalid", "errors")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database update succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, setLastTrackingResultsDBQ, repoID, "errors", false).Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		err := m.SetLastTrackingResults(ctx, repoID, "errors")
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, setLastTrackingResultsDBQ, repoID, "errors", false).Return(tests.ErrFakeDB)
+		m := NewManager(cfg, db, nil)
+
+		err := m.SetLastTrackingResults(ctx, repoID, "errors")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
 	})
+}
+
+func TestSetVerifiedPublisher(t *testing.T) {
+	ctx := context.Background()
+	repoID := "00000000-0000-0000-0000-000000000001"
 
 	t.Run("invalid input", func(t *testing.T) {
-		testCases := []struct {
-			errMsg string
-			r      *hub.Repository
-			lErr   error
-		}{
-			{
-				"name not provided",
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		err := m.SetVerifiedPublisher(ctx, "invalid", true)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database update succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, setVerifiedPublisherDBQ, repoID, true).Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		err := m.SetVerifiedPublisher(ctx, repoID, true)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, setVerifiedPublisherDBQ, repoID, true).Return(tests.ErrFakeDB)
+		m := NewManager(cfg, db, nil)
+
+		err := m.SetVerifiedPublisher(ctx, repoID, true)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestTrack(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.Track(context.Background(), "repo1")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		_, err := m.Track(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("repository is disabled", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"user_alias": "user1",
+			"disabled": true
+		}
+		`), nil)
+		m := NewManager(cfg, db, nil)
+
+		_, err := m.Track(ctx, "repo1")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "repository is disabled")
+		db.AssertExpectations(t)
+	})
+
+	t.Run("authorization failed", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"organization_name": "orgName"
+		}
+		`), nil)
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, &hub.AuthorizeInput{
+			OrganizationName: "orgName",
+			UserID:           "userID",
+			Action:           hub.TrackOrganizationRepository,
+		}).Return(tests.ErrFake)
+		m := NewManager(cfg, db, az)
+
+		_, err := m.Track(ctx, "repo1")
+		assert.Equal(t, tests.ErrFake, err)
+		db.AssertExpectations(t)
+		az.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+				{
+					"repository_id": "00000000-0000-0000-0000-000000000001",
+					"name": "repo1",
+					"organization_name": "orgName"
+				}
+				`), nil)
+				db.On("QueryRow", ctx, trackRepoDBQ, "userID", "repo1").Return(nil, tc.dbErr)
+				az := &authz.AuthorizerMock{}
+				az.On("Authorize", ctx, &hub.AuthorizeInput{
+					OrganizationName: "orgName",
+					UserID:           "userID",
+					Action:           hub.TrackOrganizationRepository,
+				}).Return(nil)
+				m := NewManager(cfg, db, az)
+
+				_, err := m.Track(ctx, "repo1")
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+				az.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("track repository succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"user_alias": "user1"
+		}
+		`), nil)
+		db.On("QueryRow", ctx, trackRepoDBQ, "userID", "repo1").Return("jobID", nil)
+		m := NewManager(cfg, db, nil)
+
+		trackingJobID, err := m.Track(ctx, "repo1")
+		assert.NoError(t, err)
+		assert.Equal(t, "jobID", trackingJobID)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestTrackPushed(t *testing.T) {
+	ctx := context.Background()
+	body := []byte(`
+	{
+		"ref": "refs/heads/master",
+		"repository": {
+			"html_url": "https://github.com/org1/repo1"
+		}
+	}
+	`)
+	newEvent := func(secret string) *hub.RepositoryPushEvent {
+		mac := hmac.New(sha256.New, []byte(secret))
+		_, _ = mac.Write(body)
+		header := http.Header{}
+		header.Set("X-GitHub-Event", "push")
+		header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		return &hub.RepositoryPushEvent{
+			Provider: GitHub,
+			Header:   header,
+			Body:     body,
+		}
+	}
+	reposJSON := []byte(`
+	[{
+		"repository_id": "00000000-0000-0000-0000-000000000001",
+		"name": "repo1",
+		"push_webhook_secret": "secret1"
+	}, {
+		"repository_id": "00000000-0000-0000-0000-000000000002",
+		"name": "repo2",
+		"branch": "master",
+		"push_webhook_secret": "secret1"
+	}, {
+		"repository_id": "00000000-0000-0000-0000-000000000003",
+		"name": "repo3",
+		"branch": "dev",
+		"push_webhook_secret": "secret1"
+	}, {
+		"repository_id": "00000000-0000-0000-0000-000000000004",
+		"name": "repo4",
+		"push_webhook_secret": "secret2"
+	}]
+	`)
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			e      *hub.RepositoryPushEvent
+		}{
+			{
+				"push event not provided",
+				nil,
+			},
+			{
+				"invalid provider",
+				&hub.RepositoryPushEvent{Provider: "unknown"},
+			},
+			{
+				"event not supported",
+				&hub.RepositoryPushEvent{
+					Provider: GitHub,
+					Header:   http.Header{"X-Github-Event": []string{"issues"}},
+				},
+			},
+			{
+				"event not supported",
+				&hub.RepositoryPushEvent{
+					Provider: GitLab,
+					Header:   http.Header{"X-Gitlab-Event": []string{"Issue Hook"}},
+				},
+			},
+			{
+				"invalid payload",
+				&hub.RepositoryPushEvent{
+					Provider: Gitea,
+					Header:   http.Header{"X-Gitea-Event": []string{"push"}},
+					Body:     []byte("{invalid"),
+				},
+			},
+			{
+				"repository url not found in payload",
+				&hub.RepositoryPushEvent{
+					Provider: GitHub,
+					Header:   http.Header{"X-Github-Event": []string{"push"}},
+					Body:     []byte(`{"ref": "refs/heads/main"}`),
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(cfg, nil, nil)
+				_, err := m.TrackPushed(ctx, tc.e)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("events ignored", func(t *testing.T) {
+		testCases := []struct {
+			desc string
+			e    *hub.RepositoryPushEvent
+		}{
+			{
+				"ping",
+				&hub.RepositoryPushEvent{
+					Provider: GitHub,
+					Header:   http.Header{"X-Github-Event": []string{"ping"}},
+				},
+			},
+			{
+				"tag push",
+				&hub.RepositoryPushEvent{
+					Provider: GitLab,
+					Header:   http.Header{"X-Gitlab-Event": []string{"Push Hook"}},
+					Body: []byte(`
+					{
+						"ref": "refs/tags/v1.0.0",
+						"project": {
+							"web_url": "https://gitlab.com/org1/repo1"
+						}
+					}
+					`),
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.desc, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(cfg, nil, nil)
+				names, err := m.TrackPushed(ctx, tc.e)
+				assert.NoError(t, err)
+				assert.Nil(t, names)
+			})
+		}
+	})
+
+	t.Run("error getting repositories", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getReposByGitURLDBQ, "https://github.com/org1/repo1", true).Return(nil, tests.ErrFakeDB)
+		m := NewManager(cfg, db, nil)
+
+		_, err := m.TrackPushed(ctx, newEvent("secret1"))
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("no repositories found", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getReposByGitURLDBQ, "https://github.com/org1/repo1", true).Return([]byte("[]"), nil)
+		m := NewManager(cfg, db, nil)
+
+		_, err := m.TrackPushed(ctx, newEvent("secret1"))
+		assert.Equal(t, hub.ErrInsufficientPrivilege, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("event signature could not be verified", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getReposByGitURLDBQ, "https://github.com/org1/repo1", true).Return(reposJSON, nil)
+		m := NewManager(cfg, db, nil)
+
+		_, err := m.TrackPushed(ctx, newEvent("secret3"))
+		assert.Equal(t, hub.ErrInsufficientPrivilege, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("error enqueueing tracking jobs", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getReposByGitURLDBQ, "https://github.com/org1/repo1", true).Return(reposJSON, nil)
+		db.On("Exec", ctx, enqueueTrackingJobsDBQ, []string{
+			"00000000-0000-0000-0000-000000000001",
+			"00000000-0000-0000-0000-000000000002",
+		}).Return(tests.ErrFakeDB)
+		m := NewManager(cfg, db, nil)
+
+		_, err := m.TrackPushed(ctx, newEvent("secret1"))
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("track pushed repositories succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getReposByGitURLDBQ, "https://github.com/org1/repo1", true).Return(reposJSON, nil)
+		db.On("Exec", ctx, enqueueTrackingJobsDBQ, []string{
+			"00000000-0000-0000-0000-000000000004",
+		}).Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		names, err := m.TrackPushed(ctx, newEvent("secret2"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"repo4"}, names)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("track pushed repositories succeeded: encrypted secret", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getReposByGitURLDBQ, "https://github.com/org1/repo1", true).Return([]byte(`
+		[{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"push_webhook_secret": "encryptedSecret1"
+		}]
+		`), nil)
+		db.On("Exec", ctx, enqueueTrackingJobsDBQ, []string{
+			"00000000-0000-0000-0000-000000000001",
+		}).Return(nil)
+		enc := &encryption.EncrypterMock{}
+		enc.On("Decrypt", ctx, "encryptedSecret1").Return("secret1", nil)
+		m := NewManager(cfg, db, nil, WithEncrypter(enc))
+
+		names, err := m.TrackPushed(ctx, newEvent("secret1"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"repo1"}, names)
+		db.AssertExpectations(t)
+		enc.AssertExpectations(t)
+	})
+
+	t.Run("track pushed repositories succeeded: error decrypting one secret", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getReposByGitURLDBQ, "https://github.com/org1/repo1", true).Return([]byte(`
+		[{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"push_webhook_secret": "encryptedSecret1"
+		}, {
+			"repository_id": "00000000-0000-0000-0000-000000000002",
+			"name": "repo2",
+			"push_webhook_secret": "encryptedSecret2"
+		}]
+		`), nil)
+		db.On("Exec", ctx, enqueueTrackingJobsDBQ, []string{
+			"00000000-0000-0000-0000-000000000002",
+		}).Return(nil)
+		enc := &encryption.EncrypterMock{}
+		enc.On("Decrypt", ctx, "encryptedSecret1").Return("", tests.ErrFake)
+		enc.On("Decrypt", ctx, "encryptedSecret2").Return("secret1", nil)
+		m := NewManager(cfg, db, nil, WithEncrypter(enc))
+
+		names, err := m.TrackPushed(ctx, newEvent("secret1"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"repo2"}, names)
+		db.AssertExpectations(t)
+		enc.AssertExpectations(t)
+	})
+
+	t.Run("gitlab and gitea events are verified", func(t *testing.T) {
+		t.Parallel()
+		gitLabEvent := &hub.RepositoryPushEvent{
+			Provider: GitLab,
+			Header: http.Header{
+				"X-Gitlab-Event": []string{"Push Hook"},
+				"X-Gitlab-Token": []string{"secret1"},
+			},
+		}
+		assert.True(t, verifyPushEvent(gitLabEvent, "secret1"))
+		assert.False(t, verifyPushEvent(gitLabEvent, "secret2"))
+
+		mac := hmac.New(sha256.New, []byte("secret1"))
+		_, _ = mac.Write(body)
+		giteaEvent := &hub.RepositoryPushEvent{
+			Provider: Gitea,
+			Header: http.Header{
+				"X-Gitea-Event":     []string{"push"},
+				"X-Gitea-Signature": []string{hex.EncodeToString(mac.Sum(nil))},
+			},
+			Body: body,
+		}
+		assert.True(t, verifyPushEvent(giteaEvent, "secret1"))
+		assert.False(t, verifyPushEvent(giteaEvent, "secret2"))
+		assert.False(t, verifyPushEvent(giteaEvent, ""))
+	})
+}
+
+func TestTransfer(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	userID := "userID"
+	userIDP := &userID
+	org := "org1"
+	orgP := &org
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		assert.Panics(t, func() {
+			_ = m.Transfer(context.Background(), "repo1", "", false)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg   string
+			repoName string
+		}{
+			{
+				"repository name not provided",
+				"",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(cfg, nil, nil)
+
+				err := m.Transfer(ctx, tc.repoName, "", false)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+			})
+		}
+	})
+
+	t.Run("authorization failed", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"organization_name": "orgName"
+		}
+		`), nil)
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, &hub.AuthorizeInput{
+			OrganizationName: "orgName",
+			UserID:           "userID",
+			Action:           hub.TransferOrganizationRepository,
+		}).Return(tests.ErrFake)
+		m := NewManager(cfg, db, az)
+
+		err := m.Transfer(ctx, "repo1", "orgDest", false)
+		assert.Equal(t, tests.ErrFake, err)
+		az.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+				{
+					"repository_id": "00000000-0000-0000-0000-000000000001",
+					"name": "repo1",
+					"organization_name": "orgName"
+				}
+				`), nil)
+				db.On("Exec", ctx, transferRepoDBQ, "repo1", userIDP, orgP, false).Return(tc.dbErr)
+				az := &authz.AuthorizerMock{}
+				az.On("Authorize", ctx, &hub.AuthorizeInput{
+					OrganizationName: "orgName",
+					UserID:           "userID",
+					Action:           hub.TransferOrganizationRepository,
+				}).Return(nil)
+				m := NewManager(cfg, db, az)
+
+				err := m.Transfer(ctx, "repo1", org, false)
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+				az.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("transfer repository succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"user_alias": "user1"
+		}
+		`), nil)
+		db.On("Exec", ctx, transferRepoDBQ, "repo1", userIDP, orgP, false).Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		err := m.Transfer(ctx, "repo1", org, false)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		assert.Panics(t, func() {
+			_ = m.Update(context.Background(), nil)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			r      *hub.Repository
+			lErr   error
+		}{
+			{
+				"name not provided",
 				&hub.Repository{
 					Name: "",
 				},
@@ -1491,6 +2335,16 @@ func TestUpdate(t *testing.T) {
 				},
 				nil,
 			},
+			{
+				"invalid tracking interval",
+				&hub.Repository{
+					Kind:             hub.Helm,
+					Name:             "repo1",
+					URL:              "https://repo1.com",
+					TrackingInterval: -1,
+				},
+				nil,
+			},
 			{
 				"invalid url format",
 				&hub.Repository{
@@ -1674,6 +2528,80 @@ func TestUpdate(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("update private repository failed: encrypted values not allowed", func(t *testing.T) {
+		t.Parallel()
+		cfg := viper.New()
+		cfg.Set("server.allowPrivateRepositories", true)
+		m := NewManager(cfg, nil, nil)
+
+		for _, r := range []*hub.Repository{
+			{Name: "repo1", URL: "https://github.com/org1/repo1", Kind: hub.OPA, AuthPass: "enc:v1:key:data"},
+			{Name: "repo1", URL: "https://github.com/org1/repo1", Kind: hub.OPA, SSHKey: "enc:v1:key:data"},
+			{Name: "repo1", URL: "https://github.com/org1/repo1", Kind: hub.OPA, PushWebhookSecret: "enc:v1:key:data"},
+		} {
+			err := m.Update(ctx, r)
+			assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+			assert.Contains(t, err.Error(), "encrypted values not allowed")
+		}
+	})
+
+	t.Run("update private repository succeeded: credentials and push webhook secret are encrypted", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{
+			Name:              "repo1",
+			URL:               "https://github.com/org1/repo1",
+			Kind:              hub.OPA,
+			AuthPass:          "pass1",
+			PushWebhookSecret: "secret1",
+		}
+		repoJSON, _ := json.Marshal(r)
+		cfg := viper.New()
+		cfg.Set("server.allowPrivateRepositories", true)
+		enc := &encryption.EncrypterMock{}
+		enc.On("Encrypt", ctx, "pass1").Return("encryptedPass1", nil)
+		enc.On("Encrypt", ctx, "secret1").Return("encryptedSecret1", nil)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, r.Name, false).Return(repoJSON, nil)
+		db.On("Exec", ctx, updateRepoDBQ, "userID", mock.MatchedBy(func(rJSON []byte) bool {
+			var rStored *hub.Repository
+			_ = json.Unmarshal(rJSON, &rStored)
+			return rStored.AuthPass == "encryptedPass1" &&
+				rStored.SSHKey == "" &&
+				rStored.PushWebhookSecret == "encryptedSecret1"
+		})).Return(nil)
+		m := NewManager(cfg, db, nil, WithEncrypter(enc))
+
+		err := m.Update(ctx, r)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+		enc.AssertExpectations(t)
+	})
+
+	t.Run("update private repository succeeded: ssh key cleared", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{
+			Name:        "repo1",
+			URL:         "https://github.com/org1/repo1",
+			Kind:        hub.OPA,
+			ClearSSHKey: true,
+		}
+		repoJSON, _ := json.Marshal(r)
+		cfg := viper.New()
+		cfg.Set("server.allowPrivateRepositories", true)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, r.Name, false).Return(repoJSON, nil)
+		db.On("Exec", ctx, updateRepoDBQ, "userID", mock.MatchedBy(func(rJSON []byte) bool {
+			var rStored *hub.Repository
+			_ = json.Unmarshal(rJSON, &rStored)
+			return rStored.ClearSSHKey && rStored.SSHKey == ""
+		})).Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		err := m.Update(ctx, r)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
 }
 
 func TestUpdateDigest(t *testing.T) {
@@ -1704,6 +2632,18 @@ func TestUpdateDigest(t *testing.T) {
 	})
 }
 
+func withGitRemoteDigestGetter(f func(repoURL, branch string, auth transport.AuthMethod) (string, error)) func(m *Manager) {
+	return func(m *Manager) {
+		m.getGitRemoteDigest = f
+	}
+}
+
+func withHTTPClient(hc hub.HTTPClient) func(m *Manager) {
+	return func(m *Manager) {
+		m.hc = hc
+	}
+}
+
 func withHTTPGetter(hg HTTPGetter) func(m *Manager) {
 	return func(m *Manager) {
 		m.hg = hg
@@ -1715,3 +2655,13 @@ func withRepositoryCloner(rc hub.RepositoryCloner) func(m *Manager) {
 		m.rc = rc
 	}
 }
+
+func generateSSHKey(t *testing.T) string {
+	t.Helper()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	}))
+}
@@ -160,6 +160,13 @@ func (m *ManagerMock) GetOwnedByUserJSON(ctx context.Context, includeCredentials
 	return data, args.Error(1)
 }
 
+// GetTrackingJobJSON implements the RepositoryManager interface.
+func (m *ManagerMock) GetTrackingJobJSON(ctx context.Context, name, trackingJobID string) ([]byte, error) {
+	args := m.Called(ctx, name, trackingJobID)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // SetLastTrackingResults implements the RepositoryManager interface.
 func (m *ManagerMock) SetLastTrackingResults(ctx context.Context, repositoryID, errs string) error {
 	args := m.Called(ctx, repositoryID, errs)
@@ -172,6 +179,19 @@ func (m *ManagerMock) SetVerifiedPublisher(ctx context.Context, repositoryID str
 	return args.Error(0)
 }
 
+// Track implements the RepositoryManager interface.
+func (m *ManagerMock) Track(ctx context.Context, name string) (string, error) {
+	args := m.Called(ctx, name)
+	return args.String(0), args.Error(1)
+}
+
+// TrackPushed implements the RepositoryManager interface.
+func (m *ManagerMock) TrackPushed(ctx context.Context, e *hub.RepositoryPushEvent) ([]string, error) {
+	args := m.Called(ctx, e)
+	data, _ := args.Get(0).([]string)
+	return data, args.Error(1)
+}
+
 // Transfer implements the RepositoryManager interface.
 func (m *ManagerMock) Transfer(ctx context.Context, name, orgName string, ownershipClaim bool) error {
 	args := m.Called(ctx, name, orgName, ownershipClaim)
@@ -0,0 +1,148 @@
+package repo
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+const (
+	// GitHub represents the GitHub git provider.
+	GitHub = "github"
+
+	// GitLab represents the GitLab git provider.
+	GitLab = "gitlab"
+
+	// Gitea represents the Gitea git provider.
+	Gitea = "gitea"
+
+	// Bitbucket represents the Bitbucket git provider.
+	Bitbucket = "bitbucket"
+
+	// branchRefPrefix represents the prefix of the git references that
+	// correspond to branches.
+	branchRefPrefix = "refs/heads/"
+)
+
+// errPushEventNotSupported indicates that the push event received is not
+// supported.
+var errPushEventNotSupported = errors.New("event not supported")
+
+// pushEvent represents the information extracted from a push event that is
+// needed to find out which repositories are affected by it.
+type pushEvent struct {
+	url    string
+	branch string
+}
+
+// gitHubPushPayload represents the payload of a GitHub or Gitea push event.
+type gitHubPushPayload struct {
+	Ref        string `json:"ref"`
+	Repository struct {
+		HTMLURL string `json:"html_url"`
+	} `json:"repository"`
+}
+
+// gitLabPushPayload represents the payload of a GitLab push event.
+type gitLabPushPayload struct {
+	Ref     string `json:"ref"`
+	Project struct {
+		WebURL string `json:"web_url"`
+	} `json:"project"`
+}
+
+// parsePushEvent extracts the information needed from the push event provided.
+// When the event received must be ignored (i.e. a ping or a tag push), a nil
+// pushEvent is returned.
+func parsePushEvent(e *hub.RepositoryPushEvent) (*pushEvent, error) {
+	var pe *pushEvent
+	switch e.Provider {
+	case GitHub, Gitea:
+		var eventType string
+		if e.Provider == GitHub {
+			eventType = e.Header.Get("X-GitHub-Event")
+		} else {
+			eventType = e.Header.Get("X-Gitea-Event")
+		}
+		switch eventType {
+		case "ping":
+			return nil, nil
+		case "push":
+		default:
+			return nil, errPushEventNotSupported
+		}
+		var p *gitHubPushPayload
+		if err := json.Unmarshal(e.Body, &p); err != nil || p == nil {
+			return nil, errors.New("invalid payload")
+		}
+		pe = &pushEvent{
+			url:    p.Repository.HTMLURL,
+			branch: p.Ref,
+		}
+	case GitLab:
+		if e.Header.Get("X-Gitlab-Event") != "Push Hook" {
+			return nil, errPushEventNotSupported
+		}
+		var p *gitLabPushPayload
+		if err := json.Unmarshal(e.Body, &p); err != nil || p == nil {
+			return nil, errors.New("invalid payload")
+		}
+		pe = &pushEvent{
+			url:    p.Project.WebURL,
+			branch: p.Ref,
+		}
+	default:
+		return nil, errors.New("invalid provider")
+	}
+
+	if pe.url == "" {
+		return nil, errors.New("repository url not found in payload")
+	}
+	if !strings.HasPrefix(pe.branch, branchRefPrefix) {
+		return nil, nil
+	}
+	pe.branch = strings.TrimPrefix(pe.branch, branchRefPrefix)
+	return pe, nil
+}
+
+// verifyPushEvent checks if the push event provided was signed using the
+// secret provided. GitHub and Gitea sign the payload using HMAC-SHA256, while
+// GitLab sends the secret token as is.
+func verifyPushEvent(e *hub.RepositoryPushEvent, secret string) bool {
+	if secret == "" {
+		return false
+	}
+	switch e.Provider {
+	case GitHub:
+		signature := e.Header.Get("X-Hub-Signature-256")
+		if !strings.HasPrefix(signature, "sha256=") {
+			return false
+		}
+		return validHMAC(e.Body, strings.TrimPrefix(signature, "sha256="), secret)
+	case Gitea:
+		return validHMAC(e.Body, e.Header.Get("X-Gitea-Signature"), secret)
+	case GitLab:
+		token := e.Header.Get("X-Gitlab-Token")
+		return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	default:
+		return false
+	}
+}
+
+// validHMAC checks if the hex encoded signature provided matches the
+// HMAC-SHA256 of the body using the secret provided.
+func validHMAC(body []byte, signature, secret string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
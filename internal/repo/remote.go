@@ -0,0 +1,216 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+const (
+	// gitLabAPIURL represents the base url of the GitLab API.
+	gitLabAPIURL = "https://gitlab.com/api/v4"
+
+	// bitbucketAPIURL represents the base url of the Bitbucket API.
+	bitbucketAPIURL = "https://api.bitbucket.org/2.0"
+)
+
+// gitProviders is used to cache the git provider detected for each of the
+// self-hosted git instances.
+var gitProviders sync.Map
+
+// GetGitProvider returns the git provider hosting the git repository url
+// provided. Well known hosts are identified directly, whereas self-hosted
+// GitLab and Gitea instances are detected by querying the version endpoint of
+// their APIs (the result is cached per host). An empty string is returned
+// when the provider could not be detected.
+func GetGitProvider(ctx context.Context, hc hub.HTTPClient, repoURL string) string {
+	u, err := url.Parse(repoURL)
+	if err != nil || !SchemeIsHTTP(u) || u.Host == "" {
+		return ""
+	}
+	switch u.Host {
+	case "github.com":
+		return GitHub
+	case "gitlab.com":
+		return GitLab
+	case "bitbucket.org":
+		return Bitbucket
+	}
+	if provider, ok := gitProviders.Load(u.Host); ok {
+		return provider.(string)
+	}
+	var provider string
+	for _, p := range []string{GitLab, Gitea} {
+		found, err := isGitInstance(ctx, hc, u, p)
+		if err != nil {
+			return ""
+		}
+		if found {
+			provider = p
+			break
+		}
+	}
+	gitProviders.Store(u.Host, provider)
+	return provider
+}
+
+// isGitInstance checks if the host of the url provided is an instance of the
+// GitLab or Gitea git provider given, querying its API version endpoint. The
+// GitLab endpoint requires authentication, so a GitLab specific unauthorized
+// response is also accepted.
+func isGitInstance(ctx context.Context, hc hub.HTTPClient, u *url.URL, provider string) (bool, error) {
+	var versionURL string
+	switch provider {
+	case GitLab:
+		versionURL = fmt.Sprintf("%s://%s/api/v4/version", u.Scheme, u.Host)
+	case Gitea:
+		versionURL = fmt.Sprintf("%s://%s/api/v1/version", u.Scheme, u.Host)
+	default:
+		return false, nil
+	}
+	req, _ := http.NewRequestWithContext(ctx, "GET", versionURL, nil)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	var b struct {
+		Version string `json:"version"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&b); err != nil {
+		return false, nil
+	}
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return b.Version != "", nil
+	case resp.StatusCode == http.StatusUnauthorized && provider == GitLab:
+		return b.Message == "401 Unauthorized", nil
+	default:
+		return false, nil
+	}
+}
+
+// GetGitFilesBaseURLs returns the base urls used by the git provider provided
+// to serve the files of the branch given, both rendered (blob) and raw. The
+// GitHub urls layout is used when the provider is unknown.
+func GetGitFilesBaseURLs(provider, repoBaseURL, branch string) (blobURL, rawURL string) {
+	var blobPath, rawPath string
+	switch provider {
+	case GitLab:
+		blobPath, rawPath = "-/blob", "-/raw"
+	case Bitbucket:
+		blobPath, rawPath = "src", "raw"
+	case Gitea:
+		blobPath, rawPath = "src/branch", "raw/branch"
+	default:
+		blobPath, rawPath = "blob", "raw"
+	}
+	blobURL = fmt.Sprintf("%s/%s/%s", repoBaseURL, blobPath, branch)
+	rawURL = fmt.Sprintf("%s/%s/%s", repoBaseURL, rawPath, branch)
+	return
+}
+
+// getGitRemoteDigest returns the hash of the last commit of the branch
+// provided available in the git remote. The references in the remote are
+// listed (like git ls-remote does), so the repository does not need to be
+// cloned. This works with any git remote, regardless of the provider hosting
+// it. The authentication method provided will be used when not nil.
+func getGitRemoteDigest(repoURL, branch string, auth transport.AuthMethod) (string, error) {
+	rem := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{repoURL},
+	})
+	refs, err := rem.List(&git.ListOptions{Auth: auth})
+	if err != nil {
+		return "", err
+	}
+	refName := plumbing.NewBranchReferenceName(branch)
+	for _, ref := range refs {
+		if ref.Name() == refName {
+			return ref.Hash().String(), nil
+		}
+	}
+	return "", fmt.Errorf("branch %s not found in remote", branch)
+}
+
+// getGitLabDigest returns the hash of the last commit of the branch provided
+// in the GitLab project identified by the owner and repo provided, using the
+// GitLab API.
+func getGitLabDigest(
+	ctx context.Context,
+	hc hub.HTTPClient,
+	token, owner, repo, branch string,
+) (string, error) {
+	u := fmt.Sprintf("%s/projects/%s/repository/branches/%s",
+		gitLabAPIURL,
+		url.PathEscape(owner+"/"+repo),
+		url.PathEscape(branch),
+	)
+	req, _ := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if token != "" {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}
+	var b struct {
+		Commit struct {
+			ID string `json:"id"`
+		} `json:"commit"`
+	}
+	if err := doAPIRequest(hc, req, &b); err != nil {
+		return "", err
+	}
+	return b.Commit.ID, nil
+}
+
+// getBitbucketDigest returns the hash of the last commit of the branch
+// provided in the Bitbucket repository identified by the owner and repo
+// provided, using the Bitbucket API.
+func getBitbucketDigest(
+	ctx context.Context,
+	hc hub.HTTPClient,
+	token, owner, repo, branch string,
+) (string, error) {
+	u := fmt.Sprintf("%s/repositories/%s/%s/refs/branches/%s",
+		bitbucketAPIURL,
+		url.PathEscape(owner),
+		url.PathEscape(repo),
+		url.PathEscape(branch),
+	)
+	req, _ := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	var b struct {
+		Target struct {
+			Hash string `json:"hash"`
+		} `json:"target"`
+	}
+	if err := doAPIRequest(hc, req, &b); err != nil {
+		return "", err
+	}
+	return b.Target.Hash, nil
+}
+
+// doAPIRequest does the request provided, decoding the json body of the
+// response into the value provided.
+func doAPIRequest(hc hub.HTTPClient, req *http.Request, v interface{}) error {
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code received: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
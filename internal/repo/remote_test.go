@@ -0,0 +1,175 @@
+package repo
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetGitProvider(t *testing.T) {
+	ctx := context.Background()
+
+	// versionRequest returns a matcher for the requests to the version
+	// endpoint provided
+	versionRequest := func(u string) interface{} {
+		return mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == u
+		})
+	}
+	response := func(statusCode int, body string) *http.Response {
+		return &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			StatusCode: statusCode,
+		}
+	}
+
+	t.Run("well known hosts", func(t *testing.T) {
+		t.Parallel()
+		testCases := map[string]string{
+			"https://github.com/org1/repo1":    GitHub,
+			"https://gitlab.com/org1/repo1":    GitLab,
+			"https://bitbucket.org/org1/repo1": Bitbucket,
+			"oci://registry.io/org1/repo1":     "",
+			"invalid url":                      "",
+		}
+		for repoURL, expectedProvider := range testCases {
+			assert.Equal(t, expectedProvider, GetGitProvider(ctx, nil, repoURL))
+		}
+	})
+
+	t.Run("self-hosted gitlab instance", func(t *testing.T) {
+		t.Parallel()
+		hc := &tests.HTTPClientMock{}
+		hc.On("Do", versionRequest("https://git1.example.com/api/v4/version")).
+			Return(response(http.StatusUnauthorized, `{"message": "401 Unauthorized"}`), nil).Once()
+
+		assert.Equal(t, GitLab, GetGitProvider(ctx, hc, "https://git1.example.com/org1/repo1"))
+		assert.Equal(t, GitLab, GetGitProvider(ctx, hc, "https://git1.example.com/org1/repo2"))
+		hc.AssertExpectations(t)
+	})
+
+	t.Run("self-hosted gitea instance", func(t *testing.T) {
+		t.Parallel()
+		hc := &tests.HTTPClientMock{}
+		hc.On("Do", versionRequest("https://git2.example.com/api/v4/version")).
+			Return(response(http.StatusNotFound, `Not found`), nil)
+		hc.On("Do", versionRequest("https://git2.example.com/api/v1/version")).
+			Return(response(http.StatusOK, `{"version": "1.13.0"}`), nil)
+
+		assert.Equal(t, Gitea, GetGitProvider(ctx, hc, "https://git2.example.com/org1/repo1"))
+		hc.AssertExpectations(t)
+	})
+
+	t.Run("unknown self-hosted instance", func(t *testing.T) {
+		t.Parallel()
+		hc := &tests.HTTPClientMock{}
+		hc.On("Do", mock.Anything).Return(response(http.StatusNotFound, `{}`), nil)
+
+		assert.Equal(t, "", GetGitProvider(ctx, hc, "https://git3.example.com/org1/repo1"))
+		hc.AssertExpectations(t)
+	})
+
+	t.Run("error querying instance is not cached", func(t *testing.T) {
+		t.Parallel()
+		hc := &tests.HTTPClientMock{}
+		hc.On("Do", versionRequest("https://git4.example.com/api/v4/version")).
+			Return(nil, tests.ErrFake).Once()
+		hc.On("Do", versionRequest("https://git4.example.com/api/v4/version")).
+			Return(response(http.StatusUnauthorized, `{"message": "401 Unauthorized"}`), nil).Once()
+
+		assert.Equal(t, "", GetGitProvider(ctx, hc, "https://git4.example.com/org1/repo1"))
+		assert.Equal(t, GitLab, GetGitProvider(ctx, hc, "https://git4.example.com/org1/repo1"))
+		hc.AssertExpectations(t)
+	})
+}
+
+func TestGetGitFilesBaseURLs(t *testing.T) {
+	testCases := []struct {
+		provider        string
+		expectedBlobURL string
+		expectedRawURL  string
+	}{
+		{
+			GitHub,
+			"https://git.host/org1/repo1/blob/main",
+			"https://git.host/org1/repo1/raw/main",
+		},
+		{
+			GitLab,
+			"https://git.host/org1/repo1/-/blob/main",
+			"https://git.host/org1/repo1/-/raw/main",
+		},
+		{
+			Bitbucket,
+			"https://git.host/org1/repo1/src/main",
+			"https://git.host/org1/repo1/raw/main",
+		},
+		{
+			Gitea,
+			"https://git.host/org1/repo1/src/branch/main",
+			"https://git.host/org1/repo1/raw/branch/main",
+		},
+		{
+			"",
+			"https://git.host/org1/repo1/blob/main",
+			"https://git.host/org1/repo1/raw/main",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.provider, func(t *testing.T) {
+			t.Parallel()
+			blobURL, rawURL := GetGitFilesBaseURLs(tc.provider, "https://git.host/org1/repo1", "main")
+			assert.Equal(t, tc.expectedBlobURL, blobURL)
+			assert.Equal(t, tc.expectedRawURL, rawURL)
+		})
+	}
+}
+
+func TestGetGitRemoteDigest(t *testing.T) {
+	// Setup git repository with a single commit in the default branch
+	dir, err := ioutil.TempDir("", "artifact-hub-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	gitRepo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	wt, err := gitRepo.Worktree()
+	require.NoError(t, err)
+	hash, err := wt.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  "user1",
+			Email: "user1@email.com",
+			When:  time.Now(),
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("branch found", func(t *testing.T) {
+		digest, err := getGitRemoteDigest(dir, DefaultBranch, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, hash.String(), digest)
+	})
+
+	t.Run("branch not found", func(t *testing.T) {
+		digest, err := getGitRemoteDigest(dir, "unknown", nil)
+		assert.Empty(t, digest)
+		assert.Error(t, err)
+	})
+
+	t.Run("error listing remote references", func(t *testing.T) {
+		digest, err := getGitRemoteDigest(dir+"-missing", DefaultBranch, nil)
+		assert.Empty(t, digest)
+		assert.Error(t, err)
+	})
+}
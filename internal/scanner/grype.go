@@ -0,0 +1,199 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/spf13/viper"
+)
+
+// grypeOSPackagesTypes represents the Grype artifacts types that correspond
+// to operating system packages.
+var grypeOSPackagesTypes = map[string]struct{}{
+	"apk": {},
+	"deb": {},
+	"rpm": {},
+}
+
+// GrypeScanner is an implementation of the Scanner interface that uses Grype.
+// The json reports generated by Grype are imported and converted to the
+// normalized security report format.
+type GrypeScanner struct {
+	ctx context.Context
+	cfg *viper.Viper
+}
+
+// NewGrypeScanner creates a new GrypeScanner instance. The grype binary must
+// be available.
+func NewGrypeScanner(ctx context.Context, cfg *viper.Viper) (Scanner, error) {
+	if _, err := exec.LookPath("grype"); err != nil {
+		return nil, fmt.Errorf("grype not found: %w", err)
+	}
+	return &GrypeScanner{
+		ctx: ctx,
+		cfg: cfg,
+	}, nil
+}
+
+// Scan implements the Scanner interface.
+func (s *GrypeScanner) Scan(image string) ([]*hub.SecurityReportTarget, error) {
+	// Setup grype command
+	cmd := exec.CommandContext(s.ctx, "grype", "--quiet", "-o", "json", "registry:"+image) // #nosec
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// clean environment
+	cmd.Env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"USER=" + os.Getenv("USER"),
+		"HOME=" + os.Getenv("HOME"),
+		"GRYPE_DB_CACHE_DIR=" + os.Getenv("GRYPE_DB_CACHE_DIR"),
+	}
+
+	// If the registry is the Docker Hub, include credentials to avoid rate
+	// limiting issues.
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing image %s ref: %w", image, err)
+	}
+	if strings.HasSuffix(ref.Context().Registry.Name(), "docker.io") {
+		cmd.Env = append(cmd.Env,
+			"GRYPE_REGISTRY_AUTH_AUTHORITY="+ref.Context().Registry.Name(),
+			"GRYPE_REGISTRY_AUTH_USERNAME="+s.cfg.GetString("scanner.dockerUsername"),
+			"GRYPE_REGISTRY_AUTH_PASSWORD="+s.cfg.GetString("scanner.dockerPassword"),
+		)
+	}
+
+	// Run grype command
+	if err := cmd.Run(); err != nil {
+		if strings.Contains(stderr.String(), "MANIFEST_UNKNOWN") {
+			return nil, ErrImageNotFound
+		}
+		return nil, fmt.Errorf("error running grype on image %s: %w: %s", image, err, stderr.String())
+	}
+	targets, err := ImportGrypeReport(image, stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error importing image %s grype report: %w", image, err)
+	}
+	return targets, nil
+}
+
+// grypeReport represents a Grype json report.
+type grypeReport struct {
+	Matches []*grypeMatch `json:"matches"`
+	Distro  struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"distro"`
+}
+
+// grypeMatch represents a vulnerability match in a Grype json report.
+type grypeMatch struct {
+	Vulnerability struct {
+		ID          string   `json:"id"`
+		Namespace   string   `json:"namespace"`
+		Severity    string   `json:"severity"`
+		Description string   `json:"description"`
+		URLs        []string `json:"urls"`
+		CVSS        []struct {
+			Version string `json:"version"`
+			Vector  string `json:"vector"`
+			Metrics struct {
+				BaseScore float64 `json:"baseScore"`
+			} `json:"metrics"`
+		} `json:"cvss"`
+		Fix struct {
+			Versions []string `json:"versions"`
+		} `json:"fix"`
+	} `json:"vulnerability"`
+	Artifact struct {
+		Name      string `json:"name"`
+		Version   string `json:"version"`
+		Type      string `json:"type"`
+		Locations []struct {
+			Path string `json:"path"`
+		} `json:"locations"`
+	} `json:"artifact"`
+}
+
+// ImportGrypeReport converts the Grype json report provided, generated for
+// the image provided, to the normalized security report format. Operating
+// system packages vulnerabilities are grouped in a single target, whereas
+// the rest are grouped by the location of the artifact affected.
+func ImportGrypeReport(image string, data []byte) ([]*hub.SecurityReportTarget, error) {
+	var report *grypeReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, nil
+	}
+
+	targetsByName := make(map[string]*hub.SecurityReportTarget)
+	for _, m := range report.Matches {
+		// Get target the vulnerability belongs to
+		var targetName, targetType string
+		if _, ok := grypeOSPackagesTypes[m.Artifact.Type]; ok {
+			targetName = image
+			if report.Distro.Name != "" {
+				targetName = fmt.Sprintf("%s (%s %s)", image, report.Distro.Name, report.Distro.Version)
+			}
+			targetType = report.Distro.Name
+		} else {
+			targetName = m.Artifact.Type
+			if len(m.Artifact.Locations) > 0 {
+				targetName = strings.TrimPrefix(m.Artifact.Locations[0].Path, "/")
+			}
+			targetType = m.Artifact.Type
+		}
+		target, ok := targetsByName[targetName]
+		if !ok {
+			target = &hub.SecurityReportTarget{
+				Target: targetName,
+				Type:   targetType,
+			}
+			targetsByName[targetName] = target
+		}
+
+		// Add vulnerability to target
+		var cvss map[string]interface{}
+		for _, entry := range m.Vulnerability.CVSS {
+			if strings.HasPrefix(entry.Version, "3") {
+				cvss = map[string]interface{}{
+					"nvd": map[string]interface{}{
+						"V3Vector": entry.Vector,
+						"V3Score":  entry.Metrics.BaseScore,
+					},
+				}
+			}
+		}
+		target.Vulnerabilities = append(target.Vulnerabilities, &hub.Vulnerability{
+			VulnerabilityID:  m.Vulnerability.ID,
+			PkgName:          m.Artifact.Name,
+			InstalledVersion: m.Artifact.Version,
+			FixedVersion:     strings.Join(m.Vulnerability.Fix.Versions, ", "),
+			Severity:         normalizeSeverity(m.Vulnerability.Severity),
+			SeveritySource:   m.Vulnerability.Namespace,
+			Description:      m.Vulnerability.Description,
+			CVSS:             cvss,
+			References:       m.Vulnerability.URLs,
+		})
+	}
+
+	targets := make([]*hub.SecurityReportTarget, 0, len(targetsByName))
+	for _, target := range targetsByName {
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Target < targets[j].Target
+	})
+	return targets, nil
+}
@@ -0,0 +1,147 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportGrypeReport(t *testing.T) {
+	image := "repo/image:tag"
+
+	t.Run("invalid report", func(t *testing.T) {
+		t.Parallel()
+		targets, err := ImportGrypeReport(image, []byte(`invalid: "`))
+		assert.Error(t, err)
+		assert.Nil(t, targets)
+	})
+
+	t.Run("report imported successfully", func(t *testing.T) {
+		t.Parallel()
+		targets, err := ImportGrypeReport(image, sampleGrypeReportData)
+		require.NoError(t, err)
+		assert.Equal(t, []*hub.SecurityReportTarget{
+			{
+				Target: "home/hub/web/yarn.lock",
+				Type:   "npm",
+				Vulnerabilities: []*hub.Vulnerability{
+					{
+						VulnerabilityID:  "GHSA-6x33-pw7p-hmpq",
+						PkgName:          "http-proxy",
+						InstalledVersion: "1.18.0",
+						FixedVersion:     "1.18.1",
+						Severity:         SeverityHigh,
+						SeveritySource:   "github:npm",
+						Description:      "Denial of Service in http-proxy",
+						References:       []string{"https://github.com/advisories/GHSA-6x33-pw7p-hmpq"},
+					},
+				},
+			},
+			{
+				Target: "repo/image:tag (alpine 3.12.0)",
+				Type:   "alpine",
+				Vulnerabilities: []*hub.Vulnerability{
+					{
+						VulnerabilityID:  "CVE-2021-3711",
+						PkgName:          "libssl1.1",
+						InstalledVersion: "1.1.1g-r0",
+						FixedVersion:     "1.1.1l-r0",
+						Severity:         SeverityCritical,
+						SeveritySource:   "alpine:3.12",
+						CVSS: map[string]interface{}{
+							"nvd": map[string]interface{}{
+								"V3Vector": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+								"V3Score":  9.8,
+							},
+						},
+					},
+					{
+						VulnerabilityID:  "CVE-2020-28928",
+						PkgName:          "musl",
+						InstalledVersion: "1.1.24-r9",
+						Severity:         SeverityLow,
+						SeveritySource:   "alpine:3.12",
+					},
+				},
+			},
+		}, targets)
+	})
+}
+
+var sampleGrypeReportData = []byte(`
+{
+  "matches": [
+    {
+      "vulnerability": {
+        "id": "CVE-2021-3711",
+        "namespace": "alpine:3.12",
+        "severity": "Critical",
+        "cvss": [
+          {
+            "version": "3.1",
+            "vector": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+            "metrics": {
+              "baseScore": 9.8
+            }
+          }
+        ],
+        "fix": {
+          "versions": ["1.1.1l-r0"],
+          "state": "fixed"
+        }
+      },
+      "artifact": {
+        "name": "libssl1.1",
+        "version": "1.1.1g-r0",
+        "type": "apk",
+        "locations": [{"path": "/lib/apk/db/installed"}]
+      }
+    },
+    {
+      "vulnerability": {
+        "id": "GHSA-6x33-pw7p-hmpq",
+        "namespace": "github:npm",
+        "severity": "High",
+        "description": "Denial of Service in http-proxy",
+        "urls": ["https://github.com/advisories/GHSA-6x33-pw7p-hmpq"],
+        "fix": {
+          "versions": ["1.18.1"],
+          "state": "fixed"
+        }
+      },
+      "artifact": {
+        "name": "http-proxy",
+        "version": "1.18.0",
+        "type": "npm",
+        "locations": [{"path": "/home/hub/web/yarn.lock"}]
+      }
+    },
+    {
+      "vulnerability": {
+        "id": "CVE-2020-28928",
+        "namespace": "alpine:3.12",
+        "severity": "Negligible",
+        "fix": {
+          "versions": [],
+          "state": "not-fixed"
+        }
+      },
+      "artifact": {
+        "name": "musl",
+        "version": "1.1.24-r9",
+        "type": "apk",
+        "locations": [{"path": "/lib/apk/db/installed"}]
+      }
+    }
+  ],
+  "source": {
+    "type": "image"
+  },
+  "distro": {
+    "name": "alpine",
+    "version": "3.12.0"
+  }
+}
+`)
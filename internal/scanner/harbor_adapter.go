@@ -0,0 +1,226 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/spf13/viper"
+)
+
+const (
+	// harborAdapterReportMimeType represents the mime type of the
+	// vulnerability reports requested to the scanner adapter.
+	harborAdapterReportMimeType = "application/vnd.security.vulnerability.report; version=1.1"
+
+	// harborAdapterPollInterval represents the default time to wait between
+	// requests while the report of a scan is not ready yet.
+	harborAdapterPollInterval = 5 * time.Second
+
+	// harborAdapterMaxWait represents the default maximum time to wait for the
+	// report of a scan to be ready.
+	harborAdapterMaxWait = 10 * time.Minute
+)
+
+// HarborAdapterScanner is an implementation of the Scanner interface that
+// requests the scans over HTTP to a scanner adapter implementing the Harbor
+// pluggable scanner API (https://github.com/goharbor/pluggable-scanner-spec),
+// like harbor-scanner-trivy. Unlike TrivyScanner, it does not require the
+// trivy binary to be available.
+type HarborAdapterScanner struct {
+	ctx          context.Context
+	cfg          *viper.Viper
+	url          string
+	hc           hub.HTTPClient
+	pollInterval time.Duration
+	maxWait      time.Duration
+}
+
+// NewHarborAdapterScanner creates a new HarborAdapterScanner instance.
+func NewHarborAdapterScanner(ctx context.Context, cfg *viper.Viper) (Scanner, error) {
+	adapterURL := cfg.GetString("scanner.harborAdapterURL")
+	if adapterURL == "" {
+		return nil, errors.New("harbor adapter url not set")
+	}
+	maxWait := harborAdapterMaxWait
+	if cfg.IsSet("scanner.harborAdapterMaxWait") {
+		maxWait = cfg.GetDuration("scanner.harborAdapterMaxWait")
+	}
+	hc := &http.Client{
+		Timeout: 30 * time.Second,
+		// The adapter replies with a 302 status code while the scan report is
+		// not ready yet, it must not be followed.
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return &HarborAdapterScanner{
+		ctx:          ctx,
+		cfg:          cfg,
+		url:          strings.TrimSuffix(adapterURL, "/"),
+		hc:           hc,
+		pollInterval: harborAdapterPollInterval,
+		maxWait:      maxWait,
+	}, nil
+}
+
+// Scan implements the Scanner interface.
+func (s *HarborAdapterScanner) Scan(image string) ([]*hub.SecurityReportTarget, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing image %s ref: %w", image, err)
+	}
+
+	// Request image scan
+	scanID, err := s.requestScan(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait for the scan report to be ready (up to the maximum wait time)
+	timeout := time.NewTimer(s.maxWait)
+	defer timeout.Stop()
+	for {
+		report, retryAfter, err := s.getReport(scanID)
+		if err != nil {
+			return nil, err
+		}
+		if report != nil {
+			return report.toTargets(image), nil
+		}
+		select {
+		case <-time.After(retryAfter):
+		case <-timeout.C:
+			return nil, fmt.Errorf("scan report not ready after %s", s.maxWait)
+		case <-s.ctx.Done():
+			return nil, s.ctx.Err()
+		}
+	}
+}
+
+// requestScan requests the scanner adapter to scan the image provided, returning
+// the id of the scan request.
+func (s *HarborAdapterScanner) requestScan(ref name.Reference) (string, error) {
+	registry := ref.Context().Registry
+	scanRequest := map[string]interface{}{
+		"registry": map[string]interface{}{
+			"url": registry.Scheme() + "://" + registry.RegistryStr(),
+		},
+		"artifact": map[string]interface{}{
+			"repository": ref.Context().RepositoryStr(),
+			"tag":        ref.Identifier(),
+			"mime_type":  "application/vnd.docker.distribution.manifest.v2+json",
+		},
+	}
+
+	// If the registry is the Docker Hub, include credentials to avoid rate
+	// limiting issues.
+	if strings.HasSuffix(registry.Name(), "docker.io") {
+		username := s.cfg.GetString("scanner.dockerUsername")
+		password := s.cfg.GetString("scanner.dockerPassword")
+		if username != "" {
+			credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+			scanRequest["registry"].(map[string]interface{})["authorization"] = "Basic " + credentials
+		}
+	}
+
+	body, _ := json.Marshal(scanRequest)
+	req, _ := http.NewRequestWithContext(s.ctx, "POST", s.url+"/api/v1/scan", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/vnd.scanner.adapter.scan.request+json; version=1.0")
+	resp, err := s.hc.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return "", fmt.Errorf("unexpected status code requesting scan: %d", resp.StatusCode)
+	}
+	var scanResponse struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&scanResponse); err != nil {
+		return "", err
+	}
+	return scanResponse.ID, nil
+}
+
+// getReport gets the report of the scan provided. When the report is not
+// ready yet, the time to wait before trying again is returned.
+func (s *HarborAdapterScanner) getReport(scanID string) (*harborAdapterReport, time.Duration, error) {
+	u := s.url + "/api/v1/scan/" + scanID + "/report"
+	req, _ := http.NewRequestWithContext(s.ctx, "GET", u, nil)
+	req.Header.Set("Accept", harborAdapterReportMimeType)
+	resp, err := s.hc.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var report *harborAdapterReport
+		if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+			return nil, 0, err
+		}
+		return report, 0, nil
+	case http.StatusFound:
+		retryAfter := s.pollInterval
+		if v, err := strconv.Atoi(resp.Header.Get("Refresh-After")); err == nil && v > 0 {
+			retryAfter = time.Duration(v) * time.Second
+		}
+		return nil, retryAfter, nil
+	default:
+		body, _ := ioutil.ReadAll(resp.Body)
+		if strings.Contains(string(body), "MANIFEST_UNKNOWN") || strings.Contains(string(body), "NAME_UNKNOWN") {
+			return nil, 0, ErrImageNotFound
+		}
+		return nil, 0, fmt.Errorf("unexpected status code getting report: %d: %s", resp.StatusCode, body)
+	}
+}
+
+// harborAdapterReport represents the vulnerabilities report returned by the
+// scanner adapter.
+type harborAdapterReport struct {
+	Vulnerabilities []*harborAdapterVulnerability `json:"vulnerabilities"`
+}
+
+// harborAdapterVulnerability represents a vulnerability in a harborAdapterReport.
+type harborAdapterVulnerability struct {
+	ID          string   `json:"id"`
+	Package     string   `json:"package"`
+	Version     string   `json:"version"`
+	FixVersion  string   `json:"fix_version"`
+	Severity    string   `json:"severity"`
+	Description string   `json:"description"`
+	Links       []string `json:"links"`
+}
+
+// toTargets converts the report to the normalized security report format. As
+// the scanner adapter report does not provide targets information, all
+// vulnerabilities are grouped in a single target named after the image.
+func (r *harborAdapterReport) toTargets(image string) []*hub.SecurityReportTarget {
+	target := &hub.SecurityReportTarget{
+		Target: image,
+	}
+	for _, v := range r.Vulnerabilities {
+		target.Vulnerabilities = append(target.Vulnerabilities, &hub.Vulnerability{
+			VulnerabilityID:  v.ID,
+			PkgName:          v.Package,
+			InstalledVersion: v.Version,
+			FixedVersion:     v.FixVersion,
+			Severity:         normalizeSeverity(v.Severity),
+			Description:      v.Description,
+			References:       v.Links,
+		})
+	}
+	return []*hub.SecurityReportTarget{target}
+}
@@ -0,0 +1,145 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHarborAdapterScannerScan(t *testing.T) {
+	image := "quay.io/org/image:1.0.0"
+
+	t.Run("error requesting scan", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		s := newHarborAdapterScannerForTests(t, ts.URL)
+		targets, err := s.Scan(image)
+		assert.Error(t, err)
+		assert.Nil(t, targets)
+	})
+
+	t.Run("image not found", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/v1/scan":
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte(`{"id": "scanID"}`))
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"error": {"message": "MANIFEST_UNKNOWN: manifest unknown"}}`))
+			}
+		}))
+		defer ts.Close()
+
+		s := newHarborAdapterScannerForTests(t, ts.URL)
+		targets, err := s.Scan(image)
+		assert.True(t, errors.Is(err, ErrImageNotFound))
+		assert.Nil(t, targets)
+	})
+
+	t.Run("scan report not ready in time", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/v1/scan":
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte(`{"id": "scanID"}`))
+			default:
+				w.WriteHeader(http.StatusFound)
+			}
+		}))
+		defer ts.Close()
+
+		s := newHarborAdapterScannerForTests(t, ts.URL)
+		s.maxWait = 50 * time.Millisecond
+		targets, err := s.Scan(image)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "scan report not ready")
+		assert.Nil(t, targets)
+	})
+
+	t.Run("image scanned successfully", func(t *testing.T) {
+		t.Parallel()
+		var reportRequests int
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/v1/scan":
+				var scanRequest map[string]map[string]interface{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&scanRequest))
+				assert.Equal(t, "https://quay.io", scanRequest["registry"]["url"])
+				assert.Equal(t, "org/image", scanRequest["artifact"]["repository"])
+				assert.Equal(t, "1.0.0", scanRequest["artifact"]["tag"])
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte(`{"id": "scanID"}`))
+			case "/api/v1/scan/scanID/report":
+				assert.Equal(t, harborAdapterReportMimeType, r.Header.Get("Accept"))
+				reportRequests++
+				if reportRequests == 1 {
+					w.WriteHeader(http.StatusFound)
+					return
+				}
+				_, _ = w.Write([]byte(`
+{
+  "vulnerabilities": [
+    {
+      "id": "CVE-2021-0001",
+      "package": "pkg1",
+      "version": "1.0.0",
+      "fix_version": "1.0.1",
+      "severity": "Critical",
+      "description": "description",
+      "links": ["https://link1"]
+    }
+  ]
+}
+`))
+			}
+		}))
+		defer ts.Close()
+
+		s := newHarborAdapterScannerForTests(t, ts.URL)
+		targets, err := s.Scan(image)
+		require.NoError(t, err)
+		assert.Equal(t, 2, reportRequests)
+		assert.Equal(t, []*hub.SecurityReportTarget{
+			{
+				Target: image,
+				Vulnerabilities: []*hub.Vulnerability{
+					{
+						VulnerabilityID:  "CVE-2021-0001",
+						PkgName:          "pkg1",
+						InstalledVersion: "1.0.0",
+						FixedVersion:     "1.0.1",
+						Severity:         SeverityCritical,
+						Description:      "description",
+						References:       []string{"https://link1"},
+					},
+				},
+			},
+		}, targets)
+	})
+}
+
+func newHarborAdapterScannerForTests(t *testing.T, url string) *HarborAdapterScanner {
+	cfg := viper.New()
+	cfg.Set("scanner.harborAdapterURL", url)
+	s, err := NewHarborAdapterScanner(context.Background(), cfg)
+	require.NoError(t, err)
+	harborAdapterScanner := s.(*HarborAdapterScanner)
+	harborAdapterScanner.pollInterval = 10 * time.Millisecond
+	return harborAdapterScanner
+}
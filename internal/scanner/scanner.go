@@ -2,17 +2,77 @@ package scanner
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/spf13/viper"
+)
+
+const (
+	// DefaultBackend represents the scanner backend used when none has been
+	// provided in the configuration.
+	DefaultBackend = "trivy"
+
+	// SeverityCritical represents the critical severity level.
+	SeverityCritical = "CRITICAL"
+
+	// SeverityHigh represents the high severity level.
+	SeverityHigh = "HIGH"
+
+	// SeverityMedium represents the medium severity level.
+	SeverityMedium = "MEDIUM"
+
+	// SeverityLow represents the low severity level.
+	SeverityLow = "LOW"
+
+	// SeverityUnknown represents the unknown severity level.
+	SeverityUnknown = "UNKNOWN"
+)
+
+var (
+	// ErrImageNotFound represents that the image provided was not found in the
+	// repository.
+	ErrImageNotFound = errors.New("image not found")
+
+	// ErrBackendNotFound indicates that the scanner backend requested has not
+	// been registered.
+	ErrBackendNotFound = errors.New("scanner backend not found")
 )
 
 // Scanner describes the methods a Scanner implementation must provide.
 type Scanner interface {
-	Scan(image string) ([]byte, error)
+	Scan(image string) ([]*hub.SecurityReportTarget, error)
+}
+
+// BackendFactory represents a function that sets up a Scanner backend using
+// the configuration provided.
+type BackendFactory func(ctx context.Context, cfg *viper.Viper) (Scanner, error)
+
+// backends contains the scanner backends available, indexed by the name used
+// to select them in the configuration (scanner.backend).
+var backends = map[string]BackendFactory{
+	"trivy":          NewTrivyScanner,
+	"harbor-adapter": NewHarborAdapterScanner,
+	"grype":          NewGrypeScanner,
+}
+
+// RegisterBackend registers a new scanner backend under the name provided,
+// replacing any other backend previously registered with the same name.
+func RegisterBackend(name string, factory BackendFactory) {
+	backends[name] = factory
+}
+
+// New sets up the scanner backend selected in the configuration provided.
+func New(ctx context.Context, cfg *viper.Viper) (Scanner, error) {
+	cfg.SetDefault("scanner.backend", DefaultBackend)
+	name := cfg.GetString("scanner.backend")
+	factory, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrBackendNotFound, name)
+	}
+	return factory(ctx, cfg)
 }
 
 // ScanSnapshot scans the provided package's snapshot for security
@@ -22,24 +82,20 @@ func ScanSnapshot(
 	scanner Scanner,
 	snapshot *hub.SnapshotToScan,
 ) (*hub.SnapshotSecurityReport, error) {
-	full := make(map[string][]interface{})
+	full := make(map[string][]*hub.SecurityReportTarget)
 
 	for _, image := range snapshot.ContainersImages {
 		parts := strings.Split(image.Image, ":")
 		if len(parts) == 1 || parts[1] == "latest" {
 			continue
 		}
-		reportData, err := scanner.Scan(image.Image)
+		imageFullReport, err := scanner.Scan(image.Image)
 		if err != nil {
 			if errors.Is(err, ErrImageNotFound) {
 				continue
 			}
 			return nil, fmt.Errorf("error scanning image %s: %w", image.Image, err)
 		}
-		var imageFullReport []interface{}
-		if err := json.Unmarshal(reportData, &imageFullReport); err != nil {
-			return nil, fmt.Errorf("error unmarshalling image %s full report: %w", image.Image, err)
-		}
 		if imageFullReport != nil {
 			full[image.Image] = imageFullReport
 		}
@@ -61,29 +117,21 @@ func ScanSnapshot(
 
 // generateSummary generates a summary of the security report from the full
 // report
-func generateSummary(full map[string][]interface{}) *hub.SecurityReportSummary {
+func generateSummary(full map[string][]*hub.SecurityReportTarget) *hub.SecurityReportSummary {
 	summary := &hub.SecurityReportSummary{}
 	for _, targets := range full {
-		for _, entry := range targets {
-			var target *Target
-			entryJSON, err := json.Marshal(entry)
-			if err != nil {
-				continue
-			}
-			if err := json.Unmarshal(entryJSON, &target); err != nil {
-				continue
-			}
+		for _, target := range targets {
 			for _, vulnerability := range target.Vulnerabilities {
 				switch vulnerability.Severity {
-				case "CRITICAL":
+				case SeverityCritical:
 					summary.Critical++
-				case "HIGH":
+				case SeverityHigh:
 					summary.High++
-				case "MEDIUM":
+				case SeverityMedium:
 					summary.Medium++
-				case "LOW":
+				case SeverityLow:
 					summary.Low++
-				case "UNKNOWN":
+				case SeverityUnknown:
 					summary.Unknown++
 				}
 			}
@@ -92,12 +140,19 @@ func generateSummary(full map[string][]interface{}) *hub.SecurityReportSummary {
 	return summary
 }
 
-// Target represents a target in a security report.
-type Target struct {
-	Vulnerabilities []*Vulnerability `json:"Vulnerabilities"`
-}
-
-// Vulnerability represents a vulnerability in a security report target.
-type Vulnerability struct {
-	Severity string `json:"Severity"`
+// normalizeSeverity converts the severity provided to one of the severity
+// levels used in the normalized security reports.
+func normalizeSeverity(severity string) string {
+	switch strings.ToUpper(severity) {
+	case SeverityCritical:
+		return SeverityCritical
+	case SeverityHigh:
+		return SeverityHigh
+	case SeverityMedium:
+		return SeverityMedium
+	case SeverityLow, "NEGLIGIBLE":
+		return SeverityLow
+	default:
+		return SeverityUnknown
+	}
 }
@@ -2,16 +2,51 @@ package scanner
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"testing"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("backend not found", func(t *testing.T) {
+		t.Parallel()
+		cfg := viper.New()
+		cfg.Set("scanner.backend", "unknown")
+		s, err := New(ctx, cfg)
+		assert.True(t, errors.Is(err, ErrBackendNotFound))
+		assert.Nil(t, s)
+	})
+
+	t.Run("registered backend set up successfully", func(t *testing.T) {
+		scannerMock := &Mock{}
+		RegisterBackend("mock", func(ctx context.Context, cfg *viper.Viper) (Scanner, error) {
+			return scannerMock, nil
+		})
+		cfg := viper.New()
+		cfg.Set("scanner.backend", "mock")
+		s, err := New(ctx, cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, scannerMock, s)
+	})
+
+	t.Run("harbor adapter backend set up successfully", func(t *testing.T) {
+		t.Parallel()
+		cfg := viper.New()
+		cfg.Set("scanner.backend", "harbor-adapter")
+		cfg.Set("scanner.harborAdapterURL", "http://harbor-scanner-trivy:8080")
+		s, err := New(ctx, cfg)
+		assert.NoError(t, err)
+		assert.IsType(t, &HarborAdapterScanner{}, s)
+	})
+}
+
 func TestScanSnapshot(t *testing.T) {
 	ctx := context.Background()
 	packageID := "00000000-0000-0000-0000-000000000001"
@@ -84,29 +119,12 @@ func TestScanSnapshot(t *testing.T) {
 		}, report)
 	})
 
-	t.Run("error unmarshalling report", func(t *testing.T) {
-		t.Parallel()
-		scannerMock := &Mock{}
-		scannerMock.On("Scan", image).Return(`invalid: "`, nil)
-
-		snapshot := &hub.SnapshotToScan{
-			PackageID: packageID,
-			Version:   version,
-			ContainersImages: []*hub.ContainerImage{
-				{
-					Image: image,
-				},
-			},
-		}
-		report, err := ScanSnapshot(ctx, scannerMock, snapshot)
-		require.Error(t, err)
-		assert.Nil(t, report)
-	})
-
 	t.Run("image report generated successfully", func(t *testing.T) {
 		t.Parallel()
 		scannerMock := &Mock{}
-		scannerMock.On("Scan", image).Return(sampleReportData, nil)
+		imageFullReport, err := parseTrivyReport(sampleReportData)
+		require.NoError(t, err)
+		scannerMock.On("Scan", image).Return(imageFullReport, nil)
 
 		snapshot := &hub.SnapshotToScan{
 			PackageID: packageID,
@@ -119,14 +137,11 @@ func TestScanSnapshot(t *testing.T) {
 		}
 		report, err := ScanSnapshot(ctx, scannerMock, snapshot)
 		require.Nil(t, err)
-		var expectedImageFullReport []interface{}
-		err = json.Unmarshal(sampleReportData, &expectedImageFullReport)
-		require.NoError(t, err)
 		assert.Equal(t, &hub.SnapshotSecurityReport{
 			PackageID: packageID,
 			Version:   version,
-			Full: map[string][]interface{}{
-				image: expectedImageFullReport,
+			Full: map[string][]*hub.SecurityReportTarget{
+				image: imageFullReport,
 			},
 			Summary: &hub.SecurityReportSummary{
 				High:   8,
@@ -3,20 +3,18 @@ package scanner
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
 
+	"github.com/artifacthub/hub/internal/hub"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/spf13/viper"
 )
 
-// ErrImageNotFound represents that the image provided was not found in the
-// repository.
-var ErrImageNotFound = errors.New("image not found")
-
 // TrivyScanner is an implementation of the Scanner interface that uses Trivy.
 type TrivyScanner struct {
 	Ctx context.Context
@@ -24,8 +22,25 @@ type TrivyScanner struct {
 	URL string
 }
 
+// NewTrivyScanner creates a new TrivyScanner instance. The trivy binary must
+// be available, as it'll be used in client mode to scan the images.
+func NewTrivyScanner(ctx context.Context, cfg *viper.Viper) (Scanner, error) {
+	if _, err := exec.LookPath("trivy"); err != nil {
+		return nil, fmt.Errorf("trivy not found: %w", err)
+	}
+	trivyURL := cfg.GetString("scanner.trivyURL")
+	if trivyURL == "" {
+		return nil, errors.New("trivy url not set")
+	}
+	return &TrivyScanner{
+		Ctx: ctx,
+		Cfg: cfg,
+		URL: trivyURL,
+	}, nil
+}
+
 // Scan implements the Scanner interface.
-func (s *TrivyScanner) Scan(image string) ([]byte, error) {
+func (s *TrivyScanner) Scan(image string) ([]*hub.SecurityReportTarget, error) {
 	// Setup trivy command
 	cmd := exec.CommandContext(s.Ctx, "trivy", "client", "--quiet", "--remote", s.URL, "-f", "json", image) // #nosec
 	var stdout, stderr bytes.Buffer
@@ -60,5 +75,37 @@ func (s *TrivyScanner) Scan(image string) ([]byte, error) {
 		}
 		return nil, fmt.Errorf("error running trivy on image %s: %w: %s", image, err, stderr.String())
 	}
-	return stdout.Bytes(), nil
+	targets, err := parseTrivyReport(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing image %s trivy report: %w", image, err)
+	}
+	return targets, nil
+}
+
+// parseTrivyReport converts the Trivy json report provided to the normalized
+// security report format. Both the legacy format (a list of targets) and the
+// one including the schema version (targets available in Results) are
+// supported.
+func parseTrivyReport(data []byte) ([]*hub.SecurityReportTarget, error) {
+	var targets []*hub.SecurityReportTarget
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("{")) {
+		var report struct {
+			Results []*hub.SecurityReportTarget `json:"Results"`
+		}
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, err
+		}
+		targets = report.Results
+	} else {
+		if err := json.Unmarshal(data, &targets); err != nil {
+			return nil, err
+		}
+	}
+	for _, target := range targets {
+		for _, vulnerability := range target.Vulnerabilities {
+			vulnerability.Severity = normalizeSeverity(vulnerability.Severity)
+		}
+	}
+	return targets, nil
 }
@@ -0,0 +1,80 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTrivyReport(t *testing.T) {
+	t.Run("invalid report", func(t *testing.T) {
+		t.Parallel()
+		targets, err := parseTrivyReport([]byte(`invalid: "`))
+		assert.Error(t, err)
+		assert.Nil(t, targets)
+	})
+
+	t.Run("legacy format report parsed successfully", func(t *testing.T) {
+		t.Parallel()
+		targets, err := parseTrivyReport(sampleReportData)
+		require.NoError(t, err)
+		require.Len(t, targets, 2)
+		assert.Equal(t, "artifacthub/hub:v0.7.0 (alpine 3.12.0)", targets[0].Target)
+		assert.Nil(t, targets[0].Vulnerabilities)
+		assert.Equal(t, "home/hub/web/yarn.lock", targets[1].Target)
+		assert.Len(t, targets[1].Vulnerabilities, 9)
+		assert.Equal(t, &hub.Vulnerability{
+			VulnerabilityID:  "GHSA-6x33-pw7p-hmpq",
+			PkgName:          "http-proxy",
+			InstalledVersion: "1.18.0",
+			FixedVersion:     "1.18.1",
+			Severity:         SeverityHigh,
+			Title:            "Denial of Service in http-proxy",
+			References: []string{
+				"https://github.com/advisories/GHSA-6x33-pw7p-hmpq",
+				"https://github.com/http-party/node-http-proxy/pull/1447/files",
+			},
+		}, targets[1].Vulnerabilities[1])
+	})
+
+	t.Run("schema v2 format report parsed successfully", func(t *testing.T) {
+		t.Parallel()
+		targets, err := parseTrivyReport([]byte(`
+{
+  "SchemaVersion": 2,
+  "ArtifactName": "repo/image:tag",
+  "Results": [
+    {
+      "Target": "repo/image:tag (debian 10.9)",
+      "Type": "debian",
+      "Vulnerabilities": [
+        {
+          "VulnerabilityID": "CVE-2021-0001",
+          "PkgName": "pkg1",
+          "InstalledVersion": "1.0.0",
+          "Severity": "critical"
+        }
+      ]
+    }
+  ]
+}
+`))
+		require.NoError(t, err)
+		assert.Equal(t, []*hub.SecurityReportTarget{
+			{
+				Target: "repo/image:tag (debian 10.9)",
+				Type:   "debian",
+				Vulnerabilities: []*hub.Vulnerability{
+					{
+						VulnerabilityID:  "CVE-2021-0001",
+						PkgName:          "pkg1",
+						InstalledVersion: "1.0.0",
+						Severity:         SeverityCritical,
+					},
+				},
+			},
+		}, targets)
+	})
+}
@@ -0,0 +1,46 @@
+package subscription
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// ValidateFilters checks if the notification filters provided are valid.
+func ValidateFilters(f *hub.NotificationFilters) error {
+	if f == nil || f.VersionConstraint == "" {
+		return nil
+	}
+	if _, err := semver.NewConstraint(f.VersionConstraint); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid version constraint")
+	}
+	return nil
+}
+
+// MatchFilters checks if the package version provided passes the notification
+// filters given. Versions that aren't valid semver never pass the filters that
+// require checking the version.
+func MatchFilters(f *hub.NotificationFilters, p *hub.Package) bool {
+	if f == nil {
+		return true
+	}
+	v, _ := semver.NewVersion(p.Version)
+
+	if f.SecurityUpdatesOnly && !p.ContainsSecurityUpdates {
+		return false
+	}
+	if f.ExcludePrereleases && (p.Prerelease || (v != nil && v.Prerelease() != "")) {
+		return false
+	}
+	if f.MajorMinorOnly && (v == nil || v.Patch() != 0) {
+		return false
+	}
+	if f.VersionConstraint != "" {
+		c, err := semver.NewConstraint(f.VersionConstraint)
+		if err != nil || v == nil || !c.Check(v) {
+			return false
+		}
+	}
+	return true
+}
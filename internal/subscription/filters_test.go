@@ -0,0 +1,114 @@
+package subscription
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFilters(t *testing.T) {
+	t.Run("valid filters", func(t *testing.T) {
+		t.Parallel()
+		assert.NoError(t, ValidateFilters(nil))
+		assert.NoError(t, ValidateFilters(&hub.NotificationFilters{ExcludePrereleases: true}))
+		assert.NoError(t, ValidateFilters(&hub.NotificationFilters{VersionConstraint: ">=2.0.0 <3"}))
+	})
+
+	t.Run("invalid version constraint", func(t *testing.T) {
+		t.Parallel()
+		err := ValidateFilters(&hub.NotificationFilters{VersionConstraint: "invalid"})
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "invalid version constraint")
+	})
+}
+
+func TestMatchFilters(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		f        *hub.NotificationFilters
+		p        *hub.Package
+		expected bool
+	}{
+		{
+			"no filters",
+			nil,
+			&hub.Package{Version: "1.0.1"},
+			true,
+		},
+		{
+			"major minor only: patch release",
+			&hub.NotificationFilters{MajorMinorOnly: true},
+			&hub.Package{Version: "1.0.1"},
+			false,
+		},
+		{
+			"major minor only: minor release",
+			&hub.NotificationFilters{MajorMinorOnly: true},
+			&hub.Package{Version: "1.1.0"},
+			true,
+		},
+		{
+			"major minor only: invalid semver version",
+			&hub.NotificationFilters{MajorMinorOnly: true},
+			&hub.Package{Version: "latest"},
+			false,
+		},
+		{
+			"exclude prereleases: prerelease flag set",
+			&hub.NotificationFilters{ExcludePrereleases: true},
+			&hub.Package{Version: "1.0.0", Prerelease: true},
+			false,
+		},
+		{
+			"exclude prereleases: semver prerelease",
+			&hub.NotificationFilters{ExcludePrereleases: true},
+			&hub.Package{Version: "2.0.0-rc.1"},
+			false,
+		},
+		{
+			"exclude prereleases: stable release",
+			&hub.NotificationFilters{ExcludePrereleases: true},
+			&hub.Package{Version: "2.0.0"},
+			true,
+		},
+		{
+			"security updates only: no security updates",
+			&hub.NotificationFilters{SecurityUpdatesOnly: true},
+			&hub.Package{Version: "1.0.0"},
+			false,
+		},
+		{
+			"security updates only: contains security updates",
+			&hub.NotificationFilters{SecurityUpdatesOnly: true},
+			&hub.Package{Version: "1.0.0", ContainsSecurityUpdates: true},
+			true,
+		},
+		{
+			"version constraint: out of range",
+			&hub.NotificationFilters{VersionConstraint: ">=2.0.0 <3"},
+			&hub.Package{Version: "3.0.0"},
+			false,
+		},
+		{
+			"version constraint: in range",
+			&hub.NotificationFilters{VersionConstraint: ">=2.0.0 <3"},
+			&hub.Package{Version: "2.3.1"},
+			true,
+		},
+		{
+			"several filters",
+			&hub.NotificationFilters{MajorMinorOnly: true, VersionConstraint: ">=2.0.0 <3"},
+			&hub.Package{Version: "2.3.1"},
+			false,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, MatchFilters(tc.f, tc.p))
+		})
+	}
+}
@@ -11,15 +11,18 @@ import (
 
 const (
 	// Database queries
-	addOptOutDBQ               = `select add_opt_out($1::jsonb)`
-	addSubscriptionDBQ         = `select add_subscription($1::jsonb)`
-	deleteOptOutDBQ            = `select delete_opt_out($1::uuid, $2::uuid)`
-	deleteSubscriptionDBQ      = `select delete_subscription($1::jsonb)`
-	getPkgSubscriptorsDBQ      = `select get_package_subscriptors($1::uuid, $2::integer)`
-	getRepoSubscriptorsDBQ     = `select get_repository_subscriptors($1::uuid, $2::integer)`
-	getUserOptOutEntriesDBQ    = `select get_user_opt_out_entries($1::uuid)`
-	getUserPkgSubscriptionsDBQ = `select get_user_package_subscriptions($1::uuid, $2::uuid)`
-	getUserSubscriptionsDBQ    = `select get_user_subscriptions($1::uuid)`
+	addOptOutDBQ                = `select add_opt_out($1::jsonb)`
+	addSubscriptionDBQ          = `select add_subscription($1::jsonb)`
+	deleteOptOutDBQ             = `select delete_opt_out($1::uuid, $2::uuid)`
+	deleteSubscriptionDBQ       = `select delete_subscription($1::jsonb)`
+	getPkgSubscriptorsDBQ       = `select get_package_subscriptors($1::uuid, $2::integer)`
+	getRepoSubscriptorsDBQ      = `select get_repository_subscriptors($1::uuid, $2::integer)`
+	getUserOptOutEntriesDBQ     = `select get_user_opt_out_entries($1::uuid)`
+	getUserOrgSubscriptionsDBQ  = `select get_user_organization_subscriptions($1::uuid)`
+	getUserPkgSubscriptionsDBQ  = `select get_user_package_subscriptions($1::uuid, $2::uuid)`
+	getUserRepoSubscriptionsDBQ = `select get_user_repository_subscriptions($1::uuid)`
+	getUserSubscriptionsDBQ     = `select get_user_subscriptions($1::uuid)`
+	unsubscribeDBQ              = `select unsubscribe($1::jsonb)`
 )
 
 // Manager provides an API to manage subscriptions.
@@ -117,13 +120,36 @@ func (m *Manager) GetOptOutListJSON(ctx context.Context) ([]byte, error) {
 	return dataJSON, nil
 }
 
+// GetOrganizationsByUserJSON returns all the organizations subscriptions of
+// the user doing the request as as json array of objects.
+func (m *Manager) GetOrganizationsByUserJSON(ctx context.Context) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+	var dataJSON []byte
+	if err := m.db.QueryRow(ctx, getUserOrgSubscriptionsDBQ, userID).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
+// GetRepositoriesByUserJSON returns all the repositories subscriptions of the
+// user doing the request as as json array of objects.
+func (m *Manager) GetRepositoriesByUserJSON(ctx context.Context) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+	var dataJSON []byte
+	if err := m.db.QueryRow(ctx, getUserRepoSubscriptionsDBQ, userID).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
 // GetSubscriptors returns the users subscribed to receive notifications for
-// certain kind of events.
+// certain kind of events. For packages events, users subscribed to the
+// package's repository or to the organization owning it are included as well.
 func (m *Manager) GetSubscriptors(ctx context.Context, e *hub.Event) ([]*hub.User, error) {
 	var dataJSON []byte
 	var err error
 	switch e.EventKind {
-	case hub.NewRelease:
+	case hub.NewRelease, hub.SecurityAlert:
 		err = m.db.QueryRow(ctx, getPkgSubscriptorsDBQ, e.PackageID, e.EventKind).Scan(&dataJSON)
 	case hub.RepositoryTrackingErrors:
 		err = m.db.QueryRow(ctx, getRepoSubscriptorsDBQ, e.RepositoryID, e.EventKind).Scan(&dataJSON)
@@ -142,16 +168,62 @@ func (m *Manager) GetSubscriptors(ctx context.Context, e *hub.Event) ([]*hub.Use
 	return subscriptors, nil
 }
 
+// Unsubscribe stops the notifications described in the unsubscribe token
+// provided from being delivered to the token's user. The token is expected
+// to have been verified already (see ParseUnsubscribeToken).
+func (m *Manager) Unsubscribe(ctx context.Context, t *hub.UnsubscribeToken) error {
+	if _, err := uuid.FromString(t.UserID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid user id")
+	}
+	switch t.EventKind {
+	case hub.NewRelease, hub.SecurityAlert:
+		if _, err := uuid.FromString(t.PackageID); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+		}
+	case hub.RepositoryTrackingErrors:
+		if _, err := uuid.FromString(t.RepositoryID); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+		}
+	default:
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid event kind")
+	}
+	tJSON, _ := json.Marshal(t)
+	_, err := m.db.Exec(ctx, unsubscribeDBQ, tJSON)
+	return err
+}
+
 // validateSubscription checks if the subscription provided is valid to be used
 // as input for some database functions calls.
 func validateSubscription(s *hub.Subscription) error {
-	if _, err := uuid.FromString(s.PackageID); err != nil {
-		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
-	}
-	if s.EventKind != hub.NewRelease {
+	var targets int
+	if s.PackageID != "" {
+		targets++
+		if _, err := uuid.FromString(s.PackageID); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+		}
+	}
+	if s.RepositoryID != "" {
+		targets++
+		if _, err := uuid.FromString(s.RepositoryID); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+		}
+	}
+	if s.OrganizationID != "" {
+		targets++
+		if _, err := uuid.FromString(s.OrganizationID); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid organization id")
+		}
+	}
+	if targets != 1 {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "one of package, repository or organization id must be provided")
+	}
+	if s.EventKind != hub.NewRelease && s.EventKind != hub.SecurityAlert {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid event kind")
 	}
-	return nil
+	if s.Filters != nil && s.EventKind != hub.NewRelease {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "filters only supported in new release subscriptions")
+	}
+	return ValidateFilters(s.Filters)
 }
 
 // validateOptOut checks if the opt-out information provided is valid to be
@@ -15,6 +15,7 @@ const (
 	userID       = "00000000-0000-0000-0000-000000000001"
 	packageID    = "00000000-0000-0000-0000-000000000001"
 	repositoryID = "00000000-0000-0000-0000-000000000001"
+	orgID        = "00000000-0000-0000-0000-000000000001"
 	optOutID     = "00000000-0000-0000-0000-000000000001"
 )
 
@@ -40,6 +41,29 @@ func TestAdd(t *testing.T) {
 					PackageID: "invalid",
 				},
 			},
+			{
+				"invalid repository id",
+				&hub.Subscription{
+					RepositoryID: "invalid",
+				},
+			},
+			{
+				"invalid organization id",
+				&hub.Subscription{
+					OrganizationID: "invalid",
+				},
+			},
+			{
+				"one of package, repository or organization id must be provided",
+				&hub.Subscription{},
+			},
+			{
+				"one of package, repository or organization id must be provided",
+				&hub.Subscription{
+					PackageID:    packageID,
+					RepositoryID: repositoryID,
+				},
+			},
 			{
 				"invalid event kind",
 				&hub.Subscription{
@@ -47,6 +71,22 @@ func TestAdd(t *testing.T) {
 					EventKind: hub.EventKind(5),
 				},
 			},
+			{
+				"filters only supported in new release subscriptions",
+				&hub.Subscription{
+					PackageID: packageID,
+					EventKind: hub.SecurityAlert,
+					Filters:   &hub.NotificationFilters{ExcludePrereleases: true},
+				},
+			},
+			{
+				"invalid version constraint",
+				&hub.Subscription{
+					PackageID: packageID,
+					EventKind: hub.NewRelease,
+					Filters:   &hub.NotificationFilters{VersionConstraint: "invalid"},
+				},
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -89,6 +129,21 @@ func TestAdd(t *testing.T) {
 		assert.NoError(t, err)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("database query succeeded (organization subscription)", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, addSubscriptionDBQ, mock.Anything).Return(nil)
+		m := NewManager(db)
+
+		s := &hub.Subscription{
+			OrganizationID: orgID,
+			EventKind:      hub.SecurityAlert,
+		}
+		err := m.Add(ctx, s)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
 }
 
 func TestAddOptOut(t *testing.T) {
@@ -187,6 +242,29 @@ func TestDelete(t *testing.T) {
 					PackageID: "invalid",
 				},
 			},
+			{
+				"invalid repository id",
+				&hub.Subscription{
+					RepositoryID: "invalid",
+				},
+			},
+			{
+				"invalid organization id",
+				&hub.Subscription{
+					OrganizationID: "invalid",
+				},
+			},
+			{
+				"one of package, repository or organization id must be provided",
+				&hub.Subscription{},
+			},
+			{
+				"one of package, repository or organization id must be provided",
+				&hub.Subscription{
+					PackageID:    packageID,
+					RepositoryID: repositoryID,
+				},
+			},
 			{
 				"invalid event kind",
 				&hub.Subscription{
@@ -410,6 +488,78 @@ func TestGetOptOutListJSON(t *testing.T) {
 	})
 }
 
+func TestGetOrganizationsByUserJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, userID)
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetOrganizationsByUserJSON(context.Background())
+		})
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserOrgSubscriptionsDBQ, userID).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetOrganizationsByUserJSON(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserOrgSubscriptionsDBQ, userID).Return(nil, tests.ErrFakeDB)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetOrganizationsByUserJSON(ctx)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetRepositoriesByUserJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, userID)
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetRepositoriesByUserJSON(context.Background())
+		})
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserRepoSubscriptionsDBQ, userID).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetRepositoriesByUserJSON(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserRepoSubscriptionsDBQ, userID).Return(nil, tests.ErrFakeDB)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetRepositoriesByUserJSON(ctx)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestGetSubscriptors(t *testing.T) {
 	ctx := context.Background()
 	pkgNewReleaseEvent := &hub.Event{
@@ -525,3 +675,84 @@ func TestGetSubscriptors(t *testing.T) {
 		db.AssertExpectations(t)
 	})
 }
+
+func TestUnsubscribe(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			t      *hub.UnsubscribeToken
+		}{
+			{
+				"invalid user id",
+				&hub.UnsubscribeToken{
+					UserID: "invalid",
+				},
+			},
+			{
+				"invalid package id",
+				&hub.UnsubscribeToken{
+					UserID:    userID,
+					EventKind: hub.NewRelease,
+					PackageID: "invalid",
+				},
+			},
+			{
+				"invalid repository id",
+				&hub.UnsubscribeToken{
+					UserID:    userID,
+					EventKind: hub.RepositoryTrackingErrors,
+				},
+			},
+			{
+				"invalid event kind",
+				&hub.UnsubscribeToken{
+					UserID:       userID,
+					EventKind:    hub.RepositoryOwnershipClaim,
+					RepositoryID: repositoryID,
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(nil)
+				err := m.Unsubscribe(ctx, tc.t)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, unsubscribeDBQ, mock.Anything).Return(tests.ErrFakeDB)
+		m := NewManager(db)
+
+		err := m.Unsubscribe(ctx, &hub.UnsubscribeToken{
+			UserID:    userID,
+			EventKind: hub.NewRelease,
+			PackageID: packageID,
+		})
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, unsubscribeDBQ, mock.Anything).Return(nil)
+		m := NewManager(db)
+
+		err := m.Unsubscribe(ctx, &hub.UnsubscribeToken{
+			UserID:       userID,
+			EventKind:    hub.RepositoryTrackingErrors,
+			RepositoryID: repositoryID,
+		})
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
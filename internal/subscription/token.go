@@ -0,0 +1,69 @@
+package subscription
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// ErrUnsubscribeKeyNotSet indicates that the key used to sign the unsubscribe
+// tokens has not been configured.
+var ErrUnsubscribeKeyNotSet = errors.New("unsubscribe key not set")
+
+// NewUnsubscribeToken creates a new signed unsubscribe token for the user,
+// event kind and package or repository provided, valid for the given ttl.
+func NewUnsubscribeToken(key []byte, t *hub.UnsubscribeToken, ttl time.Duration) (string, error) {
+	if len(key) == 0 {
+		return "", ErrUnsubscribeKeyNotSet
+	}
+	t.ExpiresAt = time.Now().Add(ttl).Unix()
+	data, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + signUnsubscribeTokenPayload(key, payload), nil
+}
+
+// ParseUnsubscribeToken verifies the signature and expiration of the
+// unsubscribe token provided, returning the information it contains.
+func ParseUnsubscribeToken(key []byte, token string) (*hub.UnsubscribeToken, error) {
+	if len(key) == 0 {
+		return nil, ErrUnsubscribeKeyNotSet
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid token")
+	}
+	payload, signature := parts[0], parts[1]
+	if !hmac.Equal([]byte(signature), []byte(signUnsubscribeTokenPayload(key, payload))) {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid token")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid token")
+	}
+	t := &hub.UnsubscribeToken{}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid token")
+	}
+	if time.Unix(t.ExpiresAt, 0).Before(time.Now()) {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "token expired")
+	}
+	return t, nil
+}
+
+// signUnsubscribeTokenPayload returns the signature of the unsubscribe token
+// payload provided, encoded so that it can be used in urls.
+func signUnsubscribeTokenPayload(key []byte, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("unsubscribe:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
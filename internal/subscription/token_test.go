@@ -0,0 +1,96 @@
+package subscription
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsubscribeToken(t *testing.T) {
+	key := []byte("key")
+
+	t.Run("valid token", func(t *testing.T) {
+		t.Parallel()
+		token, err := NewUnsubscribeToken(key, &hub.UnsubscribeToken{
+			UserID:    userID,
+			EventKind: hub.NewRelease,
+			PackageID: packageID,
+		}, time.Hour)
+		require.NoError(t, err)
+
+		ut, err := ParseUnsubscribeToken(key, token)
+		require.NoError(t, err)
+		assert.Equal(t, userID, ut.UserID)
+		assert.Equal(t, hub.NewRelease, ut.EventKind)
+		assert.Equal(t, packageID, ut.PackageID)
+	})
+
+	t.Run("invalid tokens", func(t *testing.T) {
+		t.Parallel()
+		token, err := NewUnsubscribeToken(key, &hub.UnsubscribeToken{
+			UserID:       userID,
+			EventKind:    hub.RepositoryTrackingErrors,
+			RepositoryID: repositoryID,
+		}, time.Hour)
+		require.NoError(t, err)
+		tampered, err := NewUnsubscribeToken(key, &hub.UnsubscribeToken{
+			UserID:       "00000000-0000-0000-0000-000000000002",
+			EventKind:    hub.RepositoryTrackingErrors,
+			RepositoryID: repositoryID,
+		}, time.Hour)
+		require.NoError(t, err)
+
+		testCases := []struct {
+			token  string
+			key    []byte
+			errMsg string
+		}{
+			{"", key, "invalid token"},
+			{"invalid", key, "invalid token"},
+			{token, []byte("other key"), "invalid token"},
+			{tampered[:len(tampered)/2] + token[len(token)/2:], key, "invalid token"},
+		}
+		for _, tc := range testCases {
+			_, err := ParseUnsubscribeToken(tc.key, tc.token)
+			assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+			assert.Contains(t, err.Error(), tc.errMsg)
+		}
+	})
+
+	t.Run("key not set", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewUnsubscribeToken(nil, &hub.UnsubscribeToken{
+			UserID:    userID,
+			EventKind: hub.NewRelease,
+			PackageID: packageID,
+		}, time.Hour)
+		assert.Equal(t, ErrUnsubscribeKeyNotSet, err)
+
+		token, err := NewUnsubscribeToken(key, &hub.UnsubscribeToken{
+			UserID:    userID,
+			EventKind: hub.NewRelease,
+			PackageID: packageID,
+		}, time.Hour)
+		require.NoError(t, err)
+		_, err = ParseUnsubscribeToken([]byte(""), token)
+		assert.Equal(t, ErrUnsubscribeKeyNotSet, err)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		t.Parallel()
+		token, err := NewUnsubscribeToken(key, &hub.UnsubscribeToken{
+			UserID:    userID,
+			EventKind: hub.NewRelease,
+			PackageID: packageID,
+		}, -time.Hour)
+		require.NoError(t, err)
+
+		_, err = ParseUnsubscribeToken(key, token)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "token expired")
+	})
+}
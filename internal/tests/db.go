@@ -59,8 +59,9 @@ type TXMock struct {
 
 // Begin implements the pgx.Tx interface.
 func (m *TXMock) Begin(ctx context.Context) (pgx.Tx, error) {
-	// NOTE: not used
-	return nil, nil
+	args := m.Called(ctx)
+	tx, _ := args.Get(0).(pgx.Tx)
+	return tx, args.Error(1)
 }
 
 // Commit implements the pgx.Tx interface.
@@ -152,6 +153,8 @@ func (m *RowMock) Scan(dest ...interface{}) error {
 				*v = e.([]byte)
 			case *string:
 				*v = e.(string)
+			case *[]string:
+				*v = e.([]string)
 			case **string:
 				*v = e.(*string)
 			case *bool:
@@ -0,0 +1,122 @@
+package tracker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultNumWorkers    = 1
+	defaultInterval      = 30 * time.Minute
+	defaultMaxBackoff    = 24 * time.Hour
+	defaultLeaseDuration = 1 * time.Hour
+	schedulerInterval    = 1 * time.Minute
+)
+
+// Dispatcher handles a group of workers in charge of tracking the registered
+// repositories continuously. It's also in charge of scheduling periodically
+// the tracking jobs the workers will process. Several dispatchers (i.e.
+// tracker replicas) can be run concurrently, as tracking jobs are leased to
+// a single worker at a time.
+type Dispatcher struct {
+	jm              hub.TrackingJobManager
+	numWorkers      int
+	defaultInterval time.Duration
+	maxBackoff      time.Duration
+	workers         []*Worker
+}
+
+// NewDispatcher creates a new Dispatcher instance.
+func NewDispatcher(
+	svc *hub.TrackerServices,
+	jm hub.TrackingJobManager,
+	opts ...func(d *Dispatcher),
+) *Dispatcher {
+	d := &Dispatcher{
+		jm:              jm,
+		numWorkers:      defaultNumWorkers,
+		defaultInterval: getDuration(svc, "tracker.interval", defaultInterval),
+		maxBackoff:      getDuration(svc, "tracker.maxBackoff", defaultMaxBackoff),
+	}
+	for _, o := range opts {
+		o(d)
+	}
+	leaseDuration := getDuration(svc, "tracker.leaseDuration", defaultLeaseDuration)
+	d.workers = make([]*Worker, 0, d.numWorkers)
+	for i := 0; i < d.numWorkers; i++ {
+		d.workers = append(d.workers, NewWorker(svc, jm, leaseDuration))
+	}
+	return d
+}
+
+// WithNumWorkers allows providing a specific number of workers for a
+// Dispatcher instance.
+func WithNumWorkers(n int) func(d *Dispatcher) {
+	return func(d *Dispatcher) {
+		d.numWorkers = n
+	}
+}
+
+// Run starts the workers and the tracking jobs scheduler and lets them run
+// until the dispatcher is asked to stop via the context provided.
+func (d *Dispatcher) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	// Start workers
+	wwg := &sync.WaitGroup{}
+	wctx, stopWorkers := context.WithCancel(context.Background())
+	for _, w := range d.workers {
+		wwg.Add(1)
+		go w.Run(wctx, wwg)
+	}
+
+	// Schedule tracking jobs periodically until the dispatcher is asked to
+	// stop, then stop the workers
+	for {
+		if err := d.jm.Schedule(ctx, d.defaultInterval, d.maxBackoff); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("error scheduling tracking jobs")
+		}
+		select {
+		case <-time.After(schedulerInterval):
+		case <-ctx.Done():
+			stopWorkers()
+			wwg.Wait()
+			return
+		}
+	}
+}
+
+// DrainTrackingJobs processes, using the number of workers provided, all the
+// tracking jobs ready to be run until there are none left. It's used when the
+// tracker is run in one-shot mode, so that the tracking jobs requested on
+// demand (i.e. using the HTTP API or by push events) are processed as well.
+func DrainTrackingJobs(
+	ctx context.Context,
+	svc *hub.TrackerServices,
+	jm hub.TrackingJobManager,
+	numWorkers int,
+) {
+	leaseDuration := getDuration(svc, "tracker.leaseDuration", defaultLeaseDuration)
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			NewWorker(svc, jm, leaseDuration).Drain(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// getDuration returns the duration set in the configuration for the key
+// provided, or the default value provided when it is not set.
+func getDuration(svc *hub.TrackerServices, key string, defaultValue time.Duration) time.Duration {
+	if d := svc.Cfg.GetDuration(key); d > 0 {
+		return d
+	}
+	return defaultValue
+}
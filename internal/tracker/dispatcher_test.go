@@ -0,0 +1,41 @@
+package tracker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/trackingjob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDispatcher(t *testing.T) {
+	t.Parallel()
+
+	// Setup dispatcher
+	sw := newServicesWrapper()
+	sw.svc.Cfg.Set("tracker.interval", "1h")
+	jm := &trackingjob.ManagerMock{}
+	jm.On("Schedule", mock.Anything, 1*time.Hour, defaultMaxBackoff).Return(nil)
+	d := NewDispatcher(sw.svc, jm, WithNumWorkers(0))
+
+	// Run it
+	ctx, stopDispatcher := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go d.Run(ctx, &wg)
+
+	// Check tracking jobs are scheduled and it stops as expected when asked
+	// to do so
+	assert.Eventually(t, func() bool {
+		return len(jm.Calls) > 0
+	}, 2*time.Second, 100*time.Millisecond)
+	stopDispatcher()
+	assert.Eventually(t, func() bool {
+		wg.Wait()
+		return true
+	}, 2*time.Second, 100*time.Millisecond)
+	jm.AssertExpectations(t)
+}
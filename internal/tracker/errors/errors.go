@@ -50,28 +50,44 @@ func (c *Collector) Flush() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	for repositoryID, errors := range c.errors {
-		// Sort error lines before flushing them. Packages can be processed in
-		// a repository concurrently, and the order the errors are produced is
-		// not guaranteed. In order to be able to notify users when something
-		// goes wrong during repositories tracking, we need to be able to
-		// compare the errors produced among tracker executions.
-		sort.Strings(errors)
-
-		var allErrors strings.Builder
-		for i, err := range errors {
-			allErrors.WriteString(err)
-			if i < len(errors)-1 {
-				allErrors.WriteString("\n")
-			}
-		}
-		err := c.rm.SetLastTrackingResults(context.Background(), repositoryID, allErrors.String())
+	for repositoryID := range c.errors {
+		err := c.rm.SetLastTrackingResults(context.Background(), repositoryID, c.join(repositoryID))
 		if err != nil {
 			log.Error().Err(err).Str("repoID", repositoryID).Send()
 		}
 	}
 }
 
+// Get returns all errors collected for the repository provided aggregated as
+// a single text.
+func (c *Collector) Get(repositoryID string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.join(repositoryID)
+}
+
+// join aggregates all errors collected for the repository provided as a
+// single text.
+func (c *Collector) join(repositoryID string) string {
+	// Sort error lines before joining them. Packages can be processed in a
+	// repository concurrently, and the order the errors are produced is not
+	// guaranteed. In order to be able to notify users when something goes
+	// wrong during repositories tracking, we need to be able to compare the
+	// errors produced among tracker executions.
+	errors := c.errors[repositoryID]
+	sort.Strings(errors)
+
+	var allErrors strings.Builder
+	for i, err := range errors {
+		allErrors.WriteString(err)
+		if i < len(errors)-1 {
+			allErrors.WriteString("\n")
+		}
+	}
+	return allErrors.String()
+}
+
 // Init initializes the list of errors for the repository provided. This will
 // allow the errors collector to reset the errors from a previous tracker run
 // when no errors have been collected from the current run.
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/artifacthub/hub/internal/repo"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCollector(t *testing.T) {
@@ -23,6 +24,10 @@ func TestCollector(t *testing.T) {
 	ec.Append("repo2", "error2")
 	ec.Append("repo2", "error1")
 
+	// Check the errors collected can be read before flushing them
+	assert.Equal(t, "error1\nerror2", ec.Get("repo1"))
+	assert.Equal(t, "", ec.Get("repo3"))
+
 	// Flush errors and check the results were set as expected
 	rm.On("SetLastTrackingResults", context.Background(), "repo1", "error1\nerror2").Return(nil)
 	rm.On("SetLastTrackingResults", context.Background(), "repo2", "error1\nerror2").Return(nil)
@@ -92,27 +92,21 @@ func (s *TrackerSource) preparePackage(r *hub.Repository, md *RulesMetadata, pkg
 	version := sv.String()
 
 	// Prepare source link url
-	var repoBaseURL, pkgsPath, provider string
+	var repoBaseURL, pkgsPath string
 	matches := repo.GitRepoURLRE.FindStringSubmatch(r.URL)
 	if len(matches) >= 3 {
 		repoBaseURL = matches[1]
-		provider = matches[2]
 	}
 	if len(matches) == 4 {
 		pkgsPath = strings.TrimSuffix(matches[3], "/")
 	}
-	var blobPath string
-	switch provider {
-	case "github":
-		blobPath = "blob"
-	case "gitlab":
-		blobPath = "-/blob"
-	}
 	branch := r.Branch
 	if branch == "" {
 		branch = repo.DefaultBranch
 	}
-	sourceURL := fmt.Sprintf("%s/%s/%s/%s%s", repoBaseURL, blobPath, branch, pkgsPath, pkgPath)
+	provider := repo.GetGitProvider(s.i.Svc.Ctx, s.i.Svc.Hc, r.URL)
+	blobURL, _ := repo.GetGitFilesBaseURLs(provider, repoBaseURL, branch)
+	sourceURL := fmt.Sprintf("%s/%s%s", blobURL, pkgsPath, pkgPath)
 
 	// Prepare package from metadata
 	p := &hub.Package{
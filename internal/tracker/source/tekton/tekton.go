@@ -98,33 +98,23 @@ func (s *TrackerSource) preparePackage(
 	version string,
 ) (*hub.Package, error) {
 	// Prepare content and source urls
-	var repoBaseURL, pkgsPath, provider string
+	var repoBaseURL, pkgsPath string
 	matches := repo.GitRepoURLRE.FindStringSubmatch(r.URL)
 	if len(matches) >= 3 {
 		repoBaseURL = matches[1]
-		provider = matches[2]
 	}
 	if len(matches) == 4 {
 		pkgsPath = strings.TrimSuffix(matches[3], "/")
 	}
-	var blobPath, rawPath string
-	switch provider {
-	case "github":
-		blobPath = "blob"
-		rawPath = "raw"
-	case "gitlab":
-		blobPath = "-/blob"
-		rawPath = "-/raw"
-	}
 	branch := r.Branch
 	if branch == "" {
 		branch = repo.DefaultBranch
 	}
+	provider := repo.GetGitProvider(s.i.Svc.Ctx, s.i.Svc.Hc, r.URL)
+	blobURL, rawURL := repo.GetGitFilesBaseURLs(provider, repoBaseURL, branch)
 	pkgVersionPath := strings.TrimPrefix(pkgPath, s.i.BasePath)
-	contentURL := fmt.Sprintf("%s/%s/%s/%s%s/%s.yaml",
-		repoBaseURL, rawPath, branch, pkgsPath, pkgVersionPath, manifest.Name)
-	sourceURL := fmt.Sprintf("%s/%s/%s/%s%s/%s.yaml",
-		repoBaseURL, blobPath, branch, pkgsPath, pkgVersionPath, manifest.Name)
+	contentURL := fmt.Sprintf("%s/%s%s/%s.yaml", rawURL, pkgsPath, pkgVersionPath, manifest.Name)
+	sourceURL := fmt.Sprintf("%s/%s%s/%s.yaml", blobURL, pkgsPath, pkgVersionPath, manifest.Name)
 
 	// Prepare keywords
 	keywords := []string{
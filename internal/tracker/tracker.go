@@ -21,6 +21,8 @@ type Tracker struct {
 	r                  *hub.Repository
 	md                 *hub.RepositoryMetadata
 	packagesRegistered map[string]string
+	packagesAdded      int
+	packagesRemoved    int
 	basePath           string
 	logger             zerolog.Logger
 }
@@ -36,6 +38,11 @@ func New(svc *hub.TrackerServices, r *hub.Repository, logger zerolog.Logger) *Tr
 
 // Run initializes the tracking of the repository provided.
 func (t *Tracker) Run() error {
+	// Decrypt repository credentials (if any)
+	if err := t.decryptCredentials(); err != nil {
+		return fmt.Errorf("error decrypting repository credentials: %w", err)
+	}
+
 	// Check if repository has been updated since last time it was processed
 	remoteDigest, err := t.svc.Rm.GetRemoteDigest(t.svc.Ctx, t.r)
 	if err != nil {
@@ -97,6 +104,8 @@ func (t *Tracker) Run() error {
 		t.logger.Debug().Str("name", p.Name).Str("v", p.Version).Msg("registering package")
 		if err := t.svc.Pm.Register(t.svc.Ctx, p); err != nil {
 			t.warn(fmt.Errorf("error registering package %s version %s: %w", p.Name, p.Version, err))
+		} else if !ok {
+			t.packagesAdded++
 		}
 	}
 
@@ -122,6 +131,8 @@ func (t *Tracker) Run() error {
 				}
 				if err := t.svc.Pm.Unregister(t.svc.Ctx, p); err != nil {
 					t.warn(fmt.Errorf("error unregistering package %s version %s: %w", name, version, err))
+				} else {
+					t.packagesRemoved++
 				}
 			}
 		}
@@ -142,6 +153,34 @@ func (t *Tracker) Run() error {
 	return nil
 }
 
+// PackagesAdded returns the number of packages versions registered by the
+// tracker that were not registered before.
+func (t *Tracker) PackagesAdded() int {
+	return t.packagesAdded
+}
+
+// PackagesRemoved returns the number of packages versions unregistered by the
+// tracker because they are not available in the repository anymore.
+func (t *Tracker) PackagesRemoved() int {
+	return t.packagesRemoved
+}
+
+// decryptCredentials decrypts the credentials of the repository provided to
+// the tracker instance (if any), so that they can be used to access it.
+func (t *Tracker) decryptCredentials() error {
+	for _, v := range []*string{&t.r.AuthPass, &t.r.SSHKey} {
+		if *v == "" {
+			continue
+		}
+		plaintext, err := t.svc.Enc.Decrypt(t.svc.Ctx, *v)
+		if err != nil {
+			return err
+		}
+		*v = plaintext
+	}
+	return nil
+}
+
 // cloneRepository creates a local cope of the repository provided to the
 // tracker instance when applicable to the repository kind.
 func (t *Tracker) cloneRepository() (string, string, error) {
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/artifacthub/hub/internal/encryption"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/img"
 	"github.com/artifacthub/hub/internal/pkg"
@@ -42,6 +43,46 @@ func TestTracker(t *testing.T) {
 		Repository: r1,
 	}
 
+	t.Run("error decrypting repository credentials", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		r := &hub.Repository{
+			AuthPass: "encryptedPass",
+		}
+		sw := newServicesWrapper()
+		sw.enc.On("Decrypt", sw.svc.Ctx, "encryptedPass").Return("", tests.ErrFake)
+
+		// Run test and check expectations
+		err := New(sw.svc, r, zerolog.Nop()).Run()
+		assert.True(t, errors.Is(err, tests.ErrFake))
+		sw.assertExpectations(t)
+	})
+
+	t.Run("repository credentials are decrypted before being used", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		r := &hub.Repository{
+			AuthPass: "encryptedPass",
+			SSHKey:   "encryptedSSHKey",
+			Digest:   "digest",
+		}
+		sw := newServicesWrapper()
+		sw.enc.On("Decrypt", sw.svc.Ctx, "encryptedPass").Return("pass", nil)
+		sw.enc.On("Decrypt", sw.svc.Ctx, "encryptedSSHKey").Return("sshKey", nil)
+		sw.rm.On("GetRemoteDigest", sw.svc.Ctx, &hub.Repository{
+			AuthPass: "pass",
+			SSHKey:   "sshKey",
+			Digest:   "digest",
+		}).Return(r.Digest, nil)
+
+		// Run test and check expectations
+		err := New(sw.svc, r, zerolog.Nop()).Run()
+		assert.Nil(t, err)
+		sw.assertExpectations(t)
+	})
+
 	t.Run("error getting repository remote digest", func(t *testing.T) {
 		t.Parallel()
 
@@ -270,8 +311,10 @@ func TestTracker(t *testing.T) {
 		sw.pm.On("Register", sw.svc.Ctx, p1v1).Return(nil)
 
 		// Run test and check expectations
-		err := New(sw.svc, r1, zerolog.Nop()).Run()
+		tr := New(sw.svc, r1, zerolog.Nop())
+		err := tr.Run()
 		assert.Nil(t, err)
+		assert.Equal(t, 1, tr.PackagesAdded())
 		sw.assertExpectations(t)
 	})
 
@@ -292,8 +335,10 @@ func TestTracker(t *testing.T) {
 		sw.pm.On("Register", sw.svc.Ctx, p1v1).Return(nil)
 
 		// Run test and check expectations
-		err := New(sw.svc, r1, zerolog.Nop()).Run()
+		tr := New(sw.svc, r1, zerolog.Nop())
+		err := tr.Run()
 		assert.Nil(t, err)
+		assert.Equal(t, 0, tr.PackagesAdded())
 		sw.assertExpectations(t)
 	})
 
@@ -382,8 +427,10 @@ func TestTracker(t *testing.T) {
 		sw.pm.On("Unregister", sw.svc.Ctx, p1v1).Return(nil)
 
 		// Run test and check expectations
-		err := New(sw.svc, r1, zerolog.Nop()).Run()
+		tr := New(sw.svc, r1, zerolog.Nop())
+		err := tr.Run()
 		assert.Nil(t, err)
+		assert.Equal(t, 1, tr.PackagesRemoved())
 		sw.assertExpectations(t)
 	})
 
@@ -466,6 +513,7 @@ type servicesWrapper struct {
 	oe  *repo.OLMOCIExporterMock
 	ec  *trerrors.CollectorMock
 	hc  *tests.HTTPClientMock
+	enc *encryption.EncrypterMock
 	is  *img.StoreMock
 	src *source.Mock
 	svc *hub.TrackerServices
@@ -479,6 +527,7 @@ func newServicesWrapper() *servicesWrapper {
 	oe := &repo.OLMOCIExporterMock{}
 	ec := &trerrors.CollectorMock{}
 	hc := &tests.HTTPClientMock{}
+	enc := &encryption.EncrypterMock{}
 	is := &img.StoreMock{}
 	src := &source.Mock{}
 
@@ -492,6 +541,7 @@ func newServicesWrapper() *servicesWrapper {
 		Oe:       oe,
 		Ec:       ec,
 		Hc:       hc,
+		Enc:      enc,
 		Is:       is,
 		GithubRL: rate.NewLimiter(rate.Inf, 0),
 		SetupTrackerSource: func(i *hub.TrackerSourceInput) hub.TrackerSource {
@@ -507,6 +557,7 @@ func newServicesWrapper() *servicesWrapper {
 		oe:  oe,
 		ec:  ec,
 		hc:  hc,
+		enc: enc,
 		is:  is,
 		src: src,
 		svc: svc,
@@ -520,6 +571,7 @@ func (sw *servicesWrapper) assertExpectations(t *testing.T) {
 	sw.oe.AssertExpectations(t)
 	sw.ec.AssertExpectations(t)
 	sw.hc.AssertExpectations(t)
+	sw.enc.AssertExpectations(t)
 	sw.is.AssertExpectations(t)
 	sw.src.AssertExpectations(t)
 }
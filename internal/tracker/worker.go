@@ -0,0 +1,193 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	trerrors "github.com/artifacthub/hub/internal/tracker/errors"
+	"github.com/jackc/pgx/v4"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	pauseOnEmptyQueue = 30 * time.Second
+	pauseOnError      = 10 * time.Second
+
+	// leaseRenewalsPerPeriod represents how many times the lease of a job is
+	// renewed during the lease duration while it's being run.
+	leaseRenewalsPerPeriod = 3
+)
+
+// Worker is in charge of processing the tracking jobs scheduled, running the
+// tracker on the repositories they refer to.
+type Worker struct {
+	svc           *hub.TrackerServices
+	jm            hub.TrackingJobManager
+	leaseDuration time.Duration
+}
+
+// NewWorker creates a new Worker instance.
+func NewWorker(
+	svc *hub.TrackerServices,
+	jm hub.TrackingJobManager,
+	leaseDuration time.Duration,
+) *Worker {
+	return &Worker{
+		svc:           svc,
+		jm:            jm,
+		leaseDuration: leaseDuration,
+	}
+}
+
+// Run is the main loop of the worker. It calls processJob periodically until
+// it's asked to stop via the context provided.
+func (w *Worker) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for {
+		err := w.processJob(ctx)
+		switch {
+		case err == nil:
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+		case errors.Is(err, pgx.ErrNoRows):
+			select {
+			case <-time.After(pauseOnEmptyQueue):
+			case <-ctx.Done():
+				return
+			}
+		default:
+			select {
+			case <-time.After(pauseOnError):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// Drain processes the tracking jobs ready to be run until there are none left
+// or it's asked to stop via the context provided.
+func (w *Worker) Drain(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		if err := w.processJob(ctx); err != nil {
+			return
+		}
+	}
+}
+
+// processJob leases a tracking job ready to be run and tracks the repository
+// it refers to, recording the result once done.
+func (w *Worker) processJob(ctx context.Context) error {
+	// Lease tracking job
+	j, err := w.jm.Lease(ctx, w.leaseDuration)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Error().Err(err).Msg("error leasing tracking job")
+		}
+		return err
+	}
+
+	// Get repository to track
+	r, err := w.svc.Rm.GetByID(ctx, j.RepositoryID, true)
+	if err != nil {
+		log.Error().Err(err).Str("repoID", j.RepositoryID).Msg("error getting repository")
+		j.Status = hub.TrackingJobFailed
+		j.Errors = fmt.Sprintf("error getting repository: %s", err)
+		return w.finishJob(ctx, j)
+	}
+	if r.Disabled {
+		j.Status = hub.TrackingJobSucceeded
+		return w.finishJob(ctx, j)
+	}
+
+	// Track repository. Each job uses its own errors collector, so that the
+	// errors of the repository can be recorded in the job once it's done.
+	ec := trerrors.NewCollector(w.svc.Rm)
+	svc := *w.svc
+	svc.Ec = ec
+	logger := log.With().Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Logger()
+	t := New(&svc, r, logger)
+	stopLeaseRenewal := w.keepLeaseRenewed(ctx, j)
+	err = w.track(t, logger)
+	stopLeaseRenewal()
+	if err != nil {
+		logger.Error().Err(err).Send()
+		ec.Append(r.RepositoryID, err.Error())
+		j.Status = hub.TrackingJobFailed
+	} else {
+		j.Status = hub.TrackingJobSucceeded
+	}
+	ec.Flush()
+	j.Errors = ec.Get(r.RepositoryID)
+	j.PackagesAdded = t.PackagesAdded()
+	j.PackagesRemoved = t.PackagesRemoved()
+
+	// When the tracker is shutting down, the job may have been interrupted.
+	// Its lease will expire, so it'll be processed again later.
+	if err := w.svc.Ctx.Err(); err != nil {
+		return err
+	}
+	return w.finishJob(ctx, j)
+}
+
+// track runs the tracker provided, recovering from any panic that may occur
+// while doing it.
+func (w *Worker) track(t *Tracker, logger zerolog.Logger) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logger.Error().Bytes("stacktrace", debug.Stack()).Interface("recover", rec).Send()
+			err = fmt.Errorf("unexpected error tracking repository: %v", rec)
+		}
+	}()
+	return t.Run()
+}
+
+// keepLeaseRenewed renews periodically the lease of the tracking job provided
+// until the function returned is called, so that it's not leased again by
+// another worker while the repository is still being tracked.
+func (w *Worker) keepLeaseRenewed(ctx context.Context, j *hub.TrackingJob) func() {
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-time.After(w.leaseDuration / leaseRenewalsPerPeriod):
+				if err := w.jm.RenewLease(ctx, j, w.leaseDuration); err != nil {
+					log.Error().Err(err).Str("jobID", j.TrackingJobID).Msg("error renewing tracking job lease")
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		wg.Wait()
+	}
+}
+
+// finishJob records the result of the tracking job provided.
+func (w *Worker) finishJob(ctx context.Context, j *hub.TrackingJob) error {
+	if err := w.jm.Finish(ctx, j); err != nil {
+		log.Error().Err(err).Str("jobID", j.TrackingJobID).Msg("error finishing tracking job")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package tracker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/trackingjob"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorker(t *testing.T) {
+	leaseDuration := 1 * time.Hour
+	r := &hub.Repository{
+		RepositoryID: "repo1",
+		Name:         "repo1",
+		Kind:         hub.Helm,
+		URL:          "https://repo.url",
+		Digest:       "digest",
+	}
+
+	t.Run("error leasing tracking job", func(t *testing.T) {
+		t.Parallel()
+		ww := newWorkerWrapper()
+		ww.jm.On("Lease", ww.ctx, leaseDuration).Return(nil, tests.ErrFake)
+
+		w := NewWorker(ww.sw.svc, ww.jm, leaseDuration)
+		go w.Run(ww.ctx, ww.wg)
+		ww.assertExpectations(t)
+	})
+
+	t.Run("no tracking jobs ready to be run", func(t *testing.T) {
+		t.Parallel()
+		ww := newWorkerWrapper()
+		ww.jm.On("Lease", ww.ctx, leaseDuration).Return(nil, pgx.ErrNoRows)
+
+		w := NewWorker(ww.sw.svc, ww.jm, leaseDuration)
+		go w.Run(ww.ctx, ww.wg)
+		ww.assertExpectations(t)
+	})
+
+	t.Run("error getting repository", func(t *testing.T) {
+		t.Parallel()
+		j := newTrackingJob(r.RepositoryID)
+		ww := newWorkerWrapper()
+		ww.jm.On("Lease", ww.ctx, leaseDuration).Return(j, nil)
+		ww.sw.rm.On("GetByID", ww.ctx, r.RepositoryID).Return(nil, tests.ErrFake)
+		ww.jm.On("Finish", ww.ctx, j).Return(nil)
+
+		w := NewWorker(ww.sw.svc, ww.jm, leaseDuration)
+		go w.Run(ww.ctx, ww.wg)
+		ww.assertExpectations(t)
+		assert.Equal(t, hub.TrackingJobFailed, j.Status)
+		assert.Equal(t, "error getting repository: "+tests.ErrFake.Error(), j.Errors)
+	})
+
+	t.Run("repository is disabled", func(t *testing.T) {
+		t.Parallel()
+		j := newTrackingJob(r.RepositoryID)
+		ww := newWorkerWrapper()
+		ww.jm.On("Lease", ww.ctx, leaseDuration).Return(j, nil)
+		ww.sw.rm.On("GetByID", ww.ctx, r.RepositoryID).Return(&hub.Repository{
+			RepositoryID: r.RepositoryID,
+			Disabled:     true,
+		}, nil)
+		ww.jm.On("Finish", ww.ctx, j).Return(nil)
+
+		w := NewWorker(ww.sw.svc, ww.jm, leaseDuration)
+		go w.Run(ww.ctx, ww.wg)
+		ww.assertExpectations(t)
+		assert.Equal(t, hub.TrackingJobSucceeded, j.Status)
+	})
+
+	t.Run("error tracking repository", func(t *testing.T) {
+		t.Parallel()
+		j := newTrackingJob(r.RepositoryID)
+		expectedErrors := "error getting repository remote digest: " + tests.ErrFake.Error()
+		ww := newWorkerWrapper()
+		ww.jm.On("Lease", ww.ctx, leaseDuration).Return(j, nil)
+		ww.sw.rm.On("GetByID", ww.ctx, r.RepositoryID).Return(r, nil)
+		ww.sw.rm.On("GetRemoteDigest", ww.sw.svc.Ctx, r).Return("", tests.ErrFake)
+		ww.sw.rm.On("SetLastTrackingResults", context.Background(), r.RepositoryID, expectedErrors).Return(nil)
+		ww.jm.On("Finish", ww.ctx, j).Return(nil)
+
+		w := NewWorker(ww.sw.svc, ww.jm, leaseDuration)
+		go w.Run(ww.ctx, ww.wg)
+		ww.assertExpectations(t)
+		assert.Equal(t, hub.TrackingJobFailed, j.Status)
+		assert.Equal(t, expectedErrors, j.Errors)
+	})
+
+	t.Run("error finishing tracking job", func(t *testing.T) {
+		t.Parallel()
+		j := newTrackingJob(r.RepositoryID)
+		ww := newWorkerWrapper()
+		ww.jm.On("Lease", ww.ctx, leaseDuration).Return(j, nil)
+		ww.sw.rm.On("GetByID", ww.ctx, r.RepositoryID).Return(r, nil)
+		ww.sw.rm.On("GetRemoteDigest", ww.sw.svc.Ctx, r).Return(r.Digest, nil)
+		ww.jm.On("Finish", ww.ctx, j).Return(tests.ErrFake)
+
+		w := NewWorker(ww.sw.svc, ww.jm, leaseDuration)
+		go w.Run(ww.ctx, ww.wg)
+		ww.assertExpectations(t)
+	})
+
+	t.Run("repository tracked successfully", func(t *testing.T) {
+		t.Parallel()
+		j := newTrackingJob(r.RepositoryID)
+		ww := newWorkerWrapper()
+		ww.jm.On("Lease", ww.ctx, leaseDuration).Return(j, nil)
+		ww.sw.rm.On("GetByID", ww.ctx, r.RepositoryID).Return(r, nil)
+		ww.sw.rm.On("GetRemoteDigest", ww.sw.svc.Ctx, r).Return(r.Digest, nil)
+		ww.jm.On("Finish", ww.ctx, j).Return(nil)
+
+		w := NewWorker(ww.sw.svc, ww.jm, leaseDuration)
+		go w.Run(ww.ctx, ww.wg)
+		ww.assertExpectations(t)
+		assert.Equal(t, hub.TrackingJobSucceeded, j.Status)
+		assert.Equal(t, "", j.Errors)
+	})
+
+	t.Run("job lease renewed while tracking repository", func(t *testing.T) {
+		t.Parallel()
+		leaseDuration := 30 * time.Millisecond
+		j := newTrackingJob(r.RepositoryID)
+		ww := newWorkerWrapper()
+		ww.jm.On("Lease", ww.ctx, leaseDuration).Return(j, nil)
+		ww.sw.rm.On("GetByID", ww.ctx, r.RepositoryID).Return(r, nil)
+		ww.sw.rm.On("GetRemoteDigest", ww.sw.svc.Ctx, r).Return(r.Digest, nil).After(50 * time.Millisecond)
+		ww.jm.On("RenewLease", ww.ctx, j, leaseDuration).Return(nil)
+		ww.jm.On("Finish", ww.ctx, j).Return(nil)
+
+		w := NewWorker(ww.sw.svc, ww.jm, leaseDuration)
+		go w.Run(ww.ctx, ww.wg)
+		ww.assertExpectations(t)
+	})
+}
+
+func TestWorkerDrain(t *testing.T) {
+	leaseDuration := 1 * time.Hour
+	r := &hub.Repository{
+		RepositoryID: "repo1",
+		Name:         "repo1",
+		Kind:         hub.Helm,
+		URL:          "https://repo.url",
+		Digest:       "digest",
+	}
+
+	t.Run("pending jobs processed until none left", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		j := newTrackingJob(r.RepositoryID)
+		sw := newServicesWrapper()
+		jm := &trackingjob.ManagerMock{}
+		jm.On("Lease", ctx, leaseDuration).Return(j, nil).Once()
+		jm.On("Lease", ctx, leaseDuration).Return(nil, pgx.ErrNoRows).Once()
+		sw.rm.On("GetByID", ctx, r.RepositoryID).Return(r, nil)
+		sw.rm.On("GetRemoteDigest", sw.svc.Ctx, r).Return(r.Digest, nil)
+		jm.On("Finish", ctx, j).Return(nil)
+
+		w := NewWorker(sw.svc, jm, leaseDuration)
+		w.Drain(ctx)
+		jm.AssertExpectations(t)
+		sw.assertExpectations(t)
+		assert.Equal(t, hub.TrackingJobSucceeded, j.Status)
+	})
+
+	t.Run("error leasing tracking job", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		sw := newServicesWrapper()
+		jm := &trackingjob.ManagerMock{}
+		jm.On("Lease", ctx, leaseDuration).Return(nil, tests.ErrFake).Once()
+
+		w := NewWorker(sw.svc, jm, leaseDuration)
+		w.Drain(ctx)
+		jm.AssertExpectations(t)
+	})
+}
+
+type workerWrapper struct {
+	ctx        context.Context
+	stopWorker context.CancelFunc
+	wg         *sync.WaitGroup
+	jm         *trackingjob.ManagerMock
+	sw         *servicesWrapper
+}
+
+func newWorkerWrapper() *workerWrapper {
+	// Context and wait group used for Worker.Run()
+	ctx, stopWorker := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	return &workerWrapper{
+		ctx:        ctx,
+		stopWorker: stopWorker,
+		wg:         &wg,
+		jm:         &trackingjob.ManagerMock{},
+		sw:         newServicesWrapper(),
+	}
+}
+
+func (ww *workerWrapper) assertExpectations(t *testing.T) {
+	ww.stopWorker()
+	assert.Eventually(t, func() bool {
+		ww.wg.Wait()
+		return true
+	}, 2*time.Second, 100*time.Millisecond)
+
+	ww.jm.AssertExpectations(t)
+	ww.sw.assertExpectations(t)
+}
+
+func newTrackingJob(repositoryID string) *hub.TrackingJob {
+	return &hub.TrackingJob{
+		TrackingJobID: "job1",
+		RepositoryID:  repositoryID,
+		Status:        hub.TrackingJobRunning,
+	}
+}
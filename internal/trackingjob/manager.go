@@ -0,0 +1,75 @@
+package trackingjob
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+const (
+	// Database queries
+	finishTrackingJobDBQ     = `select finish_tracking_job($1::jsonb)`
+	leaseTrackingJobDBQ      = `select lease_tracking_job($1::interval)`
+	renewTrackingJobLeaseDBQ = `select renew_tracking_job_lease($1::uuid, $2::uuid, $3::interval)`
+	scheduleTrackingJobsDBQ  = `select schedule_tracking_jobs($1::interval, $2::interval)`
+)
+
+// Manager provides an API to manage tracking jobs.
+type Manager struct {
+	db hub.DB
+}
+
+// NewManager creates a new Manager instance.
+func NewManager(db hub.DB) *Manager {
+	return &Manager{
+		db: db,
+	}
+}
+
+// Finish records in the database the result of running the tracking job
+// provided.
+func (m *Manager) Finish(ctx context.Context, j *hub.TrackingJob) error {
+	// Validate input
+	if j.Status != hub.TrackingJobSucceeded && j.Status != hub.TrackingJobFailed {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid status")
+	}
+
+	// Update tracking job in database
+	jobJSON, _ := json.Marshal(j)
+	_, err := m.db.Exec(ctx, finishTrackingJobDBQ, jobJSON)
+	return err
+}
+
+// Lease returns the next tracking job ready to be run if available, leasing
+// it for the duration provided. If no jobs are available, pgx.ErrNoRows is
+// returned.
+func (m *Manager) Lease(ctx context.Context, leaseDuration time.Duration) (*hub.TrackingJob, error) {
+	var dataJSON []byte
+	if err := m.db.QueryRow(ctx, leaseTrackingJobDBQ, leaseDuration).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	var j *hub.TrackingJob
+	if err := json.Unmarshal(dataJSON, &j); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// RenewLease extends the lease of the tracking job provided for the duration
+// given. The job must still be leased with the same token.
+func (m *Manager) RenewLease(ctx context.Context, j *hub.TrackingJob, leaseDuration time.Duration) error {
+	_, err := m.db.Exec(ctx, renewTrackingJobLeaseDBQ, j.TrackingJobID, j.LeaseToken, leaseDuration)
+	return err
+}
+
+// Schedule registers the tracking jobs of the repositories that are due to be
+// processed. The interval provided is used for the repositories that do not
+// have a specific one, and the backoff applied to the repositories that keep
+// failing will be capped to the maximum backoff provided.
+func (m *Manager) Schedule(ctx context.Context, defaultInterval, maxBackoff time.Duration) error {
+	_, err := m.db.Exec(ctx, scheduleTrackingJobsDBQ, defaultInterval, maxBackoff)
+	return err
+}
@@ -0,0 +1,197 @@
+package trackingjob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	jobID  = "00000000-0000-0000-0000-000000000001"
+	repoID = "00000000-0000-0000-0000-000000000001"
+)
+
+func TestFinish(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			j      *hub.TrackingJob
+		}{
+			{
+				"invalid status",
+				&hub.TrackingJob{
+					TrackingJobID: jobID,
+				},
+			},
+			{
+				"invalid status",
+				&hub.TrackingJob{
+					TrackingJobID: jobID,
+					Status:        hub.TrackingJobRunning,
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(nil)
+
+				err := m.Finish(ctx, tc.j)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		j := &hub.TrackingJob{
+			TrackingJobID: jobID,
+			Status:        hub.TrackingJobFailed,
+			Errors:        "error1",
+			LeaseToken:    "leaseToken",
+		}
+		jobJSON, _ := json.Marshal(j)
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, finishTrackingJobDBQ, jobJSON).Return(tests.ErrFakeDB)
+		m := NewManager(db)
+
+		err := m.Finish(ctx, j)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("finish tracking job succeeded", func(t *testing.T) {
+		t.Parallel()
+		j := &hub.TrackingJob{
+			TrackingJobID: jobID,
+			Status:        hub.TrackingJobSucceeded,
+			LeaseToken:    "leaseToken",
+		}
+		jobJSON, _ := json.Marshal(j)
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, finishTrackingJobDBQ, jobJSON).Return(nil)
+		m := NewManager(db)
+
+		err := m.Finish(ctx, j)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestLease(t *testing.T) {
+	ctx := context.Background()
+	leaseDuration := 1 * time.Hour
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, leaseTrackingJobDBQ, leaseDuration).Return(nil, tests.ErrFakeDB)
+		m := NewManager(db)
+
+		j, err := m.Lease(ctx, leaseDuration)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, j)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("lease tracking job succeeded", func(t *testing.T) {
+		t.Parallel()
+		expectedJob := &hub.TrackingJob{
+			TrackingJobID: jobID,
+			RepositoryID:  repoID,
+			Status:        hub.TrackingJobRunning,
+			ScheduledAt:   1592299234,
+			StartedAt:     1592299235,
+			LeaseToken:    "00000000-0000-0000-0000-000000000001",
+		}
+
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, leaseTrackingJobDBQ, leaseDuration).Return([]byte(`
+		{
+			"tracking_job_id": "00000000-0000-0000-0000-000000000001",
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"status": "running",
+			"scheduled_at": 1592299234,
+			"started_at": 1592299235,
+			"lease_token": "00000000-0000-0000-0000-000000000001"
+		}
+		`), nil)
+		m := NewManager(db)
+
+		j, err := m.Lease(ctx, leaseDuration)
+		require.NoError(t, err)
+		assert.Equal(t, expectedJob, j)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestRenewLease(t *testing.T) {
+	ctx := context.Background()
+	leaseDuration := 1 * time.Hour
+	j := &hub.TrackingJob{
+		TrackingJobID: jobID,
+		Status:        hub.TrackingJobRunning,
+		LeaseToken:    "leaseToken",
+	}
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, renewTrackingJobLeaseDBQ, jobID, "leaseToken", leaseDuration).Return(tests.ErrFakeDB)
+		m := NewManager(db)
+
+		err := m.RenewLease(ctx, j, leaseDuration)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("renew tracking job lease succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, renewTrackingJobLeaseDBQ, jobID, "leaseToken", leaseDuration).Return(nil)
+		m := NewManager(db)
+
+		err := m.RenewLease(ctx, j, leaseDuration)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestSchedule(t *testing.T) {
+	ctx := context.Background()
+	defaultInterval := 30 * time.Minute
+	maxBackoff := 24 * time.Hour
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, scheduleTrackingJobsDBQ, defaultInterval, maxBackoff).Return(tests.ErrFakeDB)
+		m := NewManager(db)
+
+		err := m.Schedule(ctx, defaultInterval, maxBackoff)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("schedule tracking jobs succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, scheduleTrackingJobsDBQ, defaultInterval, maxBackoff).Return(nil)
+		m := NewManager(db)
+
+		err := m.Schedule(ctx, defaultInterval, maxBackoff)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
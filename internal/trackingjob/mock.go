@@ -0,0 +1,39 @@
+package trackingjob
+
+import (
+	"context"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/mock"
+)
+
+// ManagerMock is a mock implementation of the TrackingJobManager interface.
+type ManagerMock struct {
+	mock.Mock
+}
+
+// Finish implements the TrackingJobManager interface.
+func (m *ManagerMock) Finish(ctx context.Context, j *hub.TrackingJob) error {
+	args := m.Called(ctx, j)
+	return args.Error(0)
+}
+
+// Lease implements the TrackingJobManager interface.
+func (m *ManagerMock) Lease(ctx context.Context, leaseDuration time.Duration) (*hub.TrackingJob, error) {
+	args := m.Called(ctx, leaseDuration)
+	data, _ := args.Get(0).(*hub.TrackingJob)
+	return data, args.Error(1)
+}
+
+// RenewLease implements the TrackingJobManager interface.
+func (m *ManagerMock) RenewLease(ctx context.Context, j *hub.TrackingJob, leaseDuration time.Duration) error {
+	args := m.Called(ctx, j, leaseDuration)
+	return args.Error(0)
+}
+
+// Schedule implements the TrackingJobManager interface.
+func (m *ManagerMock) Schedule(ctx context.Context, defaultInterval, maxBackoff time.Duration) error {
+	args := m.Called(ctx, defaultInterval, maxBackoff)
+	return args.Error(0)
+}
@@ -3,6 +3,8 @@ package user
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,51 +12,202 @@ import (
 	"time"
 
 	"github.com/artifacthub/hub/internal/email"
+	"github.com/artifacthub/hub/internal/encryption"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/jackc/pgx/v4"
+	"github.com/rs/zerolog/log"
 	"github.com/satori/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 const (
 	// Database queries
-	checkUserAliasAvailDBQ = `select check_user_alias_availability($1::text)`
-	checkUserCredsDBQ      = `select user_id, password from "user" where email = $1 and password is not null and email_verified = true`
-	deleteSessionDBQ       = `delete from session where session_id = $1`
-	getAPIKeyUserIDDBQ     = `select user_id from api_key where key = $1`
-	getSessionDBQ          = `select user_id, floor(extract(epoch from created_at)) from session where session_id = $1`
-	getUserIDDBQ           = `select user_id from "user" where email = $1`
-	getUserPasswordDBQ     = `select password from "user" where user_id = $1 and password is not null`
-	getUserProfileDBQ      = `select get_user_profile($1::uuid)`
-	registerSessionDBQ     = `select register_session($1::jsonb)`
-	registerUserDBQ        = `select register_user($1::jsonb)`
-	updateUserPasswordDBQ  = `select update_user_password($1::uuid, $2::text, $3::text)`
-	updateUserProfileDBQ   = `select update_user_profile($1::uuid, $2::jsonb)`
-	verifyEmailDBQ         = `select verify_email($1::uuid)`
+	approveSessionDBQ              = `select approve_session($1::bytea, $2::bigint, $3::text)`
+	checkAPIKeyDBQ                 = `select user_id, scopes from check_api_key($1::bytea)`
+	checkUserAliasAvailDBQ         = `select check_user_alias_availability($1::text)`
+	checkUserCredsDBQ              = `select user_id, password from "user" where email = $1 and password is not null and email_verified = true`
+	deleteOtherSessionsDBQ         = `delete from session where user_id = $1 and session_id is distinct from $2`
+	deleteSessionDBQ               = `delete from session where session_id = $1`
+	deleteUserSessionDBQ           = `delete from session where user_id = $1 and encode(digest(session_id, 'sha256'), 'hex') = $2`
+	disableTFADBQ                  = `update "user" set tfa_enabled = false, tfa_secret = null, tfa_recovery_codes = null, tfa_last_used_step = null where user_id = $1`
+	enableTFADBQ                   = `update "user" set tfa_enabled = true, tfa_last_used_step = $2 where user_id = $1`
+	getSessionDBQ                  = `select user_id, floor(extract(epoch from created_at)), floor(extract(epoch from last_seen_at)) from session where session_id = $1 and approved = true`
+	getSessionTFAConfigDBQ         = `select get_user_tfa_config(user_id), floor(extract(epoch from created_at)) from session where session_id = $1 and approved = false`
+	getUserIDDBQ                   = `select user_id from "user" where email = $1`
+	getUserNotificationPrefsDBQ    = `select get_user_notification_preferences($1::uuid)`
+	getUserPasswordDBQ             = `select password from "user" where user_id = $1 and password is not null`
+	getUserProfileDBQ              = `select get_user_profile($1::uuid)`
+	getUserSessionsDBQ             = `select get_user_sessions($1::uuid, $2::bytea, $3::integer, $4::integer)`
+	getUserTFAConfigDBQ            = `select get_user_tfa_config($1::uuid)`
+	incrSessionTFAAttemptsDBQ      = `update session set tfa_attempts = tfa_attempts + 1 where session_id = $1 and approved = false returning tfa_attempts`
+	registerFailedTFAAttemptDBQ    = `select register_failed_tfa_attempt($1::bytea, $2::integer, $3::integer)`
+	registerPasswordResetCodeDBQ   = `select register_password_reset_code($1::text)`
+	registerSessionDBQ             = `select session_id, approved from register_session($1::jsonb)`
+	registerUserDBQ                = `select register_user($1::jsonb)`
+	resetUserPasswordDBQ           = `select reset_user_password($1::uuid, $2::text)`
+	setupTFADBQ                    = `update "user" set tfa_secret = $2, tfa_recovery_codes = $3 where user_id = $1 and tfa_enabled = false`
+	updateUserNotificationPrefsDBQ = `select update_user_notification_preferences($1::uuid, $2::jsonb)`
+	updateUserPasswordDBQ          = `select update_user_password($1::uuid, $2::text, $3::text)`
+	updateSessionLastSeenDBQ       = `update session set last_seen_at = current_timestamp where session_id = $1`
+	updateUserProfileDBQ           = `select update_user_profile($1::uuid, $2::jsonb)`
+	verifyEmailDBQ                 = `select verify_email($1::uuid)`
+
+	// quietHoursLayout represents the layout used by quiet hours start and
+	// end times.
+	quietHoursLayout = "15:04"
+
+	// DefaultSessionIdleTimeout represents the default period of inactivity
+	// after which a session expires.
+	DefaultSessionIdleTimeout = 7 * 24 * time.Hour
+
+	// DefaultSessionAbsoluteTimeout represents the default maximum lifetime of
+	// a session, regardless of its activity.
+	DefaultSessionAbsoluteTimeout = 30 * 24 * time.Hour
+
+	// sessionLastSeenUpdateInterval represents how often the last time a
+	// session was seen is updated, to avoid writing to the database on each
+	// request.
+	sessionLastSeenUpdateInterval = 1 * time.Minute
 )
 
 var (
 	// ErrInvalidPassword indicates that the password provided is not valid.
 	ErrInvalidPassword = errors.New("invalid password")
 
+	// ErrInvalidPasscode indicates that the two-factor authentication passcode
+	// provided is not valid.
+	ErrInvalidPasscode = errors.New("invalid passcode")
+
 	// ErrNotFound indicates that the user does not exist.
 	ErrNotFound = errors.New("user not found")
+
+	// errDBInvalidPasscode indicates that the passcode or recovery code
+	// provided to approve a session has already been used.
+	errDBInvalidPasscode = errors.New("ERROR: invalid_password (SQLSTATE 28P01)")
 )
 
+// tfaConfig represents the two-factor authentication configuration of a user.
+type tfaConfig struct {
+	Enabled       bool     `json:"enabled"`
+	Secret        string   `json:"secret"`
+	RecoveryCodes []string `json:"recovery_codes"`
+	Locked        bool     `json:"locked"`
+}
+
 // Manager provides an API to manage users.
 type Manager struct {
-	db hub.DB
-	es hub.EmailSender
+	db                     hub.DB
+	es                     hub.EmailSender
+	enc                    hub.Encrypter
+	sessionIdleTimeout     time.Duration
+	sessionAbsoluteTimeout time.Duration
 }
 
 // NewManager creates a new Manager instance.
-func NewManager(db hub.DB, es hub.EmailSender) *Manager {
-	return &Manager{
-		db: db,
-		es: es,
+func NewManager(db hub.DB, es hub.EmailSender, opts ...func(m *Manager)) *Manager {
+	m := &Manager{
+		db:                     db,
+		es:                     es,
+		sessionIdleTimeout:     DefaultSessionIdleTimeout,
+		sessionAbsoluteTimeout: DefaultSessionAbsoluteTimeout,
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	if m.enc == nil {
+		m.enc = encryption.NewEncrypter(nil)
+	}
+	return m
+}
+
+// WithEncrypter allows providing the encrypter used to encrypt the users
+// two-factor authentication secrets before storing them in the database.
+func WithEncrypter(enc hub.Encrypter) func(m *Manager) {
+	return func(m *Manager) {
+		m.enc = enc
 	}
 }
 
+// WithSessionTimeouts allows providing the idle and absolute timeouts used to
+// decide when sessions expire.
+func WithSessionTimeouts(idleTimeout, absoluteTimeout time.Duration) func(m *Manager) {
+	return func(m *Manager) {
+		m.sessionIdleTimeout = idleTimeout
+		m.sessionAbsoluteTimeout = absoluteTimeout
+	}
+}
+
+// ApproveSession approves the session provided, which belongs to a user who
+// has enabled two-factor authentication, using the passcode given. Recovery
+// codes can be used instead of passcodes, but only once. Passcodes cannot be
+// replayed either. Sessions pending approval expire after a few minutes, and
+// they are deleted once too many invalid passcodes have been provided. Users
+// providing too many invalid passcodes (across all their sessions) are locked
+// out for a while.
+func (m *Manager) ApproveSession(ctx context.Context, sessionID []byte, passcode string) error {
+	// Validate input
+	if len(sessionID) == 0 {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "session id not provided")
+	}
+	if passcode == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "passcode not provided")
+	}
+
+	// Get two-factor authentication configuration of session's user
+	var dataJSON []byte
+	var createdAt int64
+	err := m.db.QueryRow(ctx, getSessionTFAConfigDBQ, sessionID).Scan(&dataJSON, &createdAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid session id")
+		}
+		return err
+	}
+	if time.Since(time.Unix(createdAt, 0)) > tfaPendingSessionTimeout {
+		if _, err := m.db.Exec(ctx, deleteSessionDBQ, sessionID); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "session expired")
+	}
+	c := &tfaConfig{}
+	if err := json.Unmarshal(dataJSON, &c); err != nil {
+		return err
+	}
+	if !c.Enabled {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "two-factor authentication not enabled")
+	}
+	if c.Locked {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "too many invalid passcodes, please try again later")
+	}
+	secret, err := m.enc.Decrypt(ctx, c.Secret)
+	if err != nil {
+		return err
+	}
+
+	// Validate passcode (or recovery code) and approve session
+	var recoveryCode *string
+	step, ok := validateTOTP(secret, passcode, time.Now())
+	if !ok {
+		code, ok := matchTFARecoveryCode(c.RecoveryCodes, passcode)
+		if !ok {
+			return m.registerFailedSessionApproval(ctx, sessionID)
+		}
+		recoveryCode = &code
+	}
+	_, err = m.db.Exec(ctx, approveSessionDBQ, sessionID, step, recoveryCode)
+	if err != nil {
+		switch err.Error() {
+		case errDBInvalidPasscode.Error():
+			return m.registerFailedSessionApproval(ctx, sessionID)
+		case util.ErrDBNoDataFound.Error():
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid session id")
+		}
+		return err
+	}
+	return nil
+}
+
 // CheckAPIKey checks if the api key provided is valid.
 func (m *Manager) CheckAPIKey(ctx context.Context, key []byte) (*hub.CheckAPIKeyOutput, error) {
 	// Validate input
@@ -62,19 +215,24 @@ func (m *Manager) CheckAPIKey(ctx context.Context, key []byte) (*hub.CheckAPIKey
 		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "key not provided")
 	}
 
-	// Get key's user id from database
+	// Get key's user id and scopes from database (expired keys are ignored)
 	var userID string
-	err := m.db.QueryRow(ctx, getAPIKeyUserIDDBQ, key).Scan(&userID)
+	var scopes []string
+	err := m.db.QueryRow(ctx, checkAPIKeyDBQ, key).Scan(&userID, &scopes)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return &hub.CheckAPIKeyOutput{Valid: false}, nil
 		}
 		return nil, err
 	}
-	return &hub.CheckAPIKeyOutput{
+	output := &hub.CheckAPIKeyOutput{
 		Valid:  true,
 		UserID: userID,
-	}, nil
+	}
+	for _, scope := range scopes {
+		output.Scopes = append(output.Scopes, hub.APIKeyScope(scope))
+	}
+	return output, nil
 }
 
 // CheckAvailability checks the availability of a given value for the provided
@@ -148,24 +306,19 @@ func (m *Manager) CheckCredentials(
 	}, err
 }
 
-// CheckSession checks if the user session provided is valid.
-func (m *Manager) CheckSession(
-	ctx context.Context,
-	sessionID []byte,
-	duration time.Duration,
-) (*hub.CheckSessionOutput, error) {
+// CheckSession checks if the user session provided is valid. Sessions expire
+// after a period of inactivity or once they reach their maximum lifetime,
+// whatever happens first.
+func (m *Manager) CheckSession(ctx context.Context, sessionID []byte) (*hub.CheckSessionOutput, error) {
 	// Validate input
 	if len(sessionID) == 0 {
 		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "session id not provided")
 	}
-	if duration == 0 {
-		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "duration not provided")
-	}
 
 	// Get session details from database
 	var userID string
-	var createdAt int64
-	err := m.db.QueryRow(ctx, getSessionDBQ, sessionID).Scan(&userID, &createdAt)
+	var createdAt, lastSeenAt int64
+	err := m.db.QueryRow(ctx, getSessionDBQ, sessionID).Scan(&userID, &createdAt, &lastSeenAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return &hub.CheckSessionOutput{Valid: false}, nil
@@ -174,10 +327,19 @@ func (m *Manager) CheckSession(
 	}
 
 	// Check if the session has expired
-	if time.Unix(createdAt, 0).Add(duration).Before(time.Now()) {
+	now := time.Now()
+	if time.Unix(createdAt, 0).Add(m.sessionAbsoluteTimeout).Before(now) ||
+		time.Unix(lastSeenAt, 0).Add(m.sessionIdleTimeout).Before(now) {
 		return &hub.CheckSessionOutput{Valid: false}, nil
 	}
 
+	// Register session activity if needed
+	if time.Unix(lastSeenAt, 0).Add(sessionLastSeenUpdateInterval).Before(now) {
+		if _, err := m.db.Exec(ctx, updateSessionLastSeenDBQ, sessionID); err != nil {
+			return nil, err
+		}
+	}
+
 	return &hub.CheckSessionOutput{
 		Valid:  true,
 		UserID: userID,
@@ -196,6 +358,121 @@ func (m *Manager) DeleteSession(ctx context.Context, sessionID []byte) error {
 	return err
 }
 
+// DisableTFA disables two-factor authentication for the user doing the
+// request, provided that the passcode (or recovery code) given is valid.
+func (m *Manager) DisableTFA(ctx context.Context, passcode string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if passcode == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "passcode not provided")
+	}
+
+	// Validate passcode
+	c, err := m.getTFAConfig(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !c.Enabled {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "two-factor authentication not enabled")
+	}
+	secret, err := m.enc.Decrypt(ctx, c.Secret)
+	if err != nil {
+		return err
+	}
+	if _, ok := validateTOTP(secret, passcode, time.Now()); !ok {
+		if _, ok := matchTFARecoveryCode(c.RecoveryCodes, passcode); !ok {
+			return ErrInvalidPasscode
+		}
+	}
+
+	// Disable two-factor authentication in database
+	_, err = m.db.Exec(ctx, disableTFADBQ, userID)
+	return err
+}
+
+// EnableTFA enables two-factor authentication for the user doing the request,
+// provided that it has been set up and the passcode given is valid.
+func (m *Manager) EnableTFA(ctx context.Context, passcode string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if passcode == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "passcode not provided")
+	}
+
+	// Validate passcode
+	c, err := m.getTFAConfig(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if c.Enabled {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "two-factor authentication already enabled")
+	}
+	if c.Secret == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "two-factor authentication not set up")
+	}
+	secret, err := m.enc.Decrypt(ctx, c.Secret)
+	if err != nil {
+		return err
+	}
+	step, ok := validateTOTP(secret, passcode, time.Now())
+	if !ok {
+		return ErrInvalidPasscode
+	}
+
+	// Enable two-factor authentication in database (the passcode used cannot
+	// be used again to approve a session)
+	_, err = m.db.Exec(ctx, enableTFADBQ, userID, step)
+	return err
+}
+
+// GetNotificationPreferences returns the notification preferences of the
+// user provided.
+func (m *Manager) GetNotificationPreferences(
+	ctx context.Context,
+	userID string,
+) (*hub.NotificationPreferences, error) {
+	// Validate input
+	if _, err := uuid.FromString(userID); err != nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid user id")
+	}
+
+	// Get notification preferences from database
+	var dataJSON []byte
+	if err := m.db.QueryRow(ctx, getUserNotificationPrefsDBQ, userID).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	p := &hub.NotificationPreferences{}
+	if err := json.Unmarshal(dataJSON, &p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// GetNotificationPreferencesJSON returns the notification preferences of the
+// user doing the request as a json object.
+func (m *Manager) GetNotificationPreferencesJSON(ctx context.Context) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+	var dataJSON []byte
+	err := m.db.QueryRow(ctx, getUserNotificationPrefsDBQ, userID).Scan(&dataJSON)
+	return dataJSON, err
+}
+
+// getTFAConfig returns the two-factor authentication configuration of the
+// user provided.
+func (m *Manager) getTFAConfig(ctx context.Context, userID string) (*tfaConfig, error) {
+	var dataJSON []byte
+	if err := m.db.QueryRow(ctx, getUserTFAConfigDBQ, userID).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	c := &tfaConfig{}
+	if err := json.Unmarshal(dataJSON, &c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 // GetProfile returns the profile of the user doing the request.
 func (m *Manager) GetProfile(ctx context.Context) (*hub.User, error) {
 	dataJSON, err := m.GetProfileJSON(ctx)
@@ -218,6 +495,21 @@ func (m *Manager) GetProfileJSON(ctx context.Context) ([]byte, error) {
 	return profile, err
 }
 
+// GetSessionsJSON returns the active sessions of the user doing the request as
+// a json array. The current session provided will be flagged as such.
+func (m *Manager) GetSessionsJSON(ctx context.Context, currentSessionID []byte) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+	return util.DBQueryJSON(
+		ctx,
+		m.db,
+		getUserSessionsDBQ,
+		userID,
+		currentSessionID,
+		int64(m.sessionIdleTimeout.Seconds()),
+		int64(m.sessionAbsoluteTimeout.Seconds()),
+	)
+}
+
 // GetUserID returns the id of the user with the email provided.
 func (m *Manager) GetUserID(ctx context.Context, email string) (string, error) {
 	// Validate input
@@ -237,8 +529,93 @@ func (m *Manager) GetUserID(ctx context.Context, email string) (string, error) {
 	return userID, nil
 }
 
-// RegisterSession registers a user session in the database.
-func (m *Manager) RegisterSession(ctx context.Context, session *hub.Session) ([]byte, error) {
+// registerFailedSessionApproval registers a failed attempt to approve the
+// session provided, deleting it once the maximum number of attempts allowed
+// has been reached. The attempt is also registered for the session's user,
+// who will be locked out when too many attempts fail across all sessions.
+// ErrInvalidPasscode is returned if no other error occurs.
+func (m *Manager) registerFailedSessionApproval(ctx context.Context, sessionID []byte) error {
+	_, err := m.db.Exec(
+		ctx,
+		registerFailedTFAAttemptDBQ,
+		sessionID,
+		tfaUserMaxAttempts,
+		int64(tfaLockoutDuration.Seconds()),
+	)
+	if err != nil {
+		return err
+	}
+	var attempts int64
+	if err := m.db.QueryRow(ctx, incrSessionTFAAttemptsDBQ, sessionID).Scan(&attempts); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrInvalidPasscode
+		}
+		return err
+	}
+	if attempts >= tfaMaxAttempts {
+		if _, err := m.db.Exec(ctx, deleteSessionDBQ, sessionID); err != nil {
+			return err
+		}
+	}
+	return ErrInvalidPasscode
+}
+
+// RegisterPasswordResetCode registers a code that allows the user with the
+// email provided to reset the password, sending it by email. The base url
+// provided will be used to build the url the user will need to click to reset
+// the password. No error is returned when the email does not belong to any
+// user who can reset the password, so that registered emails are not exposed.
+// For the same reason, the email is sent asynchronously and errors sending it
+// are only logged.
+func (m *Manager) RegisterPasswordResetCode(ctx context.Context, userEmail, baseURL string) error {
+	// Validate input
+	if userEmail == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "email not provided")
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid base url")
+	}
+
+	// Register password reset code in database
+	var code *string
+	err = m.db.QueryRow(ctx, registerPasswordResetCodeDBQ, userEmail).Scan(&code)
+	if err != nil {
+		return err
+	}
+
+	// Send password reset code
+	if code != nil && m.es != nil {
+		templateData := map[string]string{
+			"link": fmt.Sprintf("%s/reset-password?code=%s", baseURL, *code),
+		}
+		var emailBody bytes.Buffer
+		if err := passwordResetTmpl.Execute(&emailBody, templateData); err != nil {
+			log.Error().Err(err).Msg("error preparing password reset email")
+			return nil
+		}
+		emailData := &email.Data{
+			To:      userEmail,
+			Subject: "Reset your password",
+			Body:    emailBody.Bytes(),
+		}
+		go func() {
+			if err := m.es.SendEmail(emailData); err != nil {
+				log.Error().Err(err).Msg("error sending password reset email")
+			}
+		}()
+	}
+
+	return nil
+}
+
+// RegisterSession registers a user session in the database. Sessions of users
+// who have enabled two-factor authentication must be approved before they can
+// be used.
+func (m *Manager) RegisterSession(
+	ctx context.Context,
+	session *hub.Session,
+) (*hub.RegisterSessionOutput, error) {
 	// Validate input
 	if session.UserID == "" {
 		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "user id not provided")
@@ -250,8 +627,15 @@ func (m *Manager) RegisterSession(ctx context.Context, session *hub.Session) ([]
 	// Register session in database
 	sessionJSON, _ := json.Marshal(session)
 	var sessionID []byte
-	err := m.db.QueryRow(ctx, registerSessionDBQ, sessionJSON).Scan(&sessionID)
-	return sessionID, err
+	var approved bool
+	err := m.db.QueryRow(ctx, registerSessionDBQ, sessionJSON).Scan(&sessionID, &approved)
+	if err != nil {
+		return nil, err
+	}
+	return &hub.RegisterSessionOutput{
+		SessionID: sessionID,
+		Approved:  approved,
+	}, nil
 }
 
 // RegisterUser registers the user provided in the database. When the user is
@@ -318,6 +702,128 @@ func (m *Manager) RegisterUser(ctx context.Context, user *hub.User, baseURL stri
 	return nil
 }
 
+// ResetPassword resets the password of the user the password reset code
+// provided belongs to. Codes can only be used once and expire after one hour.
+// All the user's sessions are invalidated once the password has been reset.
+func (m *Manager) ResetPassword(ctx context.Context, code, newPassword string) error {
+	// Validate input
+	if code == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "code not provided")
+	}
+	if _, err := uuid.FromString(code); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid code")
+	}
+	if newPassword == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "new password not provided")
+	}
+
+	// Hash new password
+	newHashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	// Reset user password in database
+	var reset bool
+	err = m.db.QueryRow(ctx, resetUserPasswordDBQ, code, string(newHashed)).Scan(&reset)
+	if err != nil {
+		return err
+	}
+	if !reset {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid or expired code")
+	}
+	return nil
+}
+
+// RevokeOtherSessions revokes all the sessions of the user doing the request
+// except the current one provided (if any).
+func (m *Manager) RevokeOtherSessions(ctx context.Context, currentSessionID []byte) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+	_, err := m.db.Exec(ctx, deleteOtherSessionsDBQ, userID, currentSessionID)
+	return err
+}
+
+// RevokeSession revokes the session of the user doing the request identified
+// by the id provided, as returned by GetSessionsJSON.
+func (m *Manager) RevokeSession(ctx context.Context, sessionID string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if b, err := hex.DecodeString(sessionID); err != nil || len(b) != sha256.Size {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid session id")
+	}
+
+	// Delete session from database
+	_, err := m.db.Exec(ctx, deleteUserSessionDBQ, userID, sessionID)
+	return err
+}
+
+// SetupTFA generates a new two-factor authentication secret and recovery codes
+// for the user doing the request, returning them along with the provisioning
+// uri as a json object. Two-factor authentication won't be enabled until a
+// valid passcode is provided using EnableTFA.
+func (m *Manager) SetupTFA(ctx context.Context) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Check two-factor authentication is not already enabled
+	c, err := m.getTFAConfig(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Enabled {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "two-factor authentication already enabled")
+	}
+
+	// Generate secret and recovery codes
+	secret, err := generateTFASecret()
+	if err != nil {
+		return nil, err
+	}
+	recoveryCodes, err := generateTFARecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	u, err := m.GetProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store them in database (secret encrypted, recovery codes hashed)
+	encryptedSecret, err := m.enc.Encrypt(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+	recoveryCodesHashes, err := hashTFARecoveryCodes(recoveryCodes)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.db.Exec(ctx, setupTFADBQ, userID, encryptedSecret, recoveryCodesHashes); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&hub.TFASetup{
+		ProvisioningURI: buildTFAProvisioningURI(secret, u.Email),
+		Secret:          secret,
+		RecoveryCodes:   recoveryCodes,
+	})
+}
+
+// UpdateNotificationPreferences updates the notification preferences of the
+// user doing the request in the database.
+func (m *Manager) UpdateNotificationPreferences(ctx context.Context, p *hub.NotificationPreferences) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if err := validateNotificationPreferences(p); err != nil {
+		return err
+	}
+
+	// Update notification preferences in database
+	pJSON, _ := json.Marshal(p)
+	_, err := m.db.Exec(ctx, updateUserNotificationPrefsDBQ, userID, pJSON)
+	return err
+}
+
 // UpdatePassword updates the user password in the database.
 func (m *Manager) UpdatePassword(ctx context.Context, old, new string) error {
 	userID := ctx.Value(hub.UserIDKey).(string)
@@ -365,6 +871,9 @@ func (m *Manager) UpdateProfile(ctx context.Context, user *hub.User) error {
 			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid profile image id")
 		}
 	}
+	if user.DeliveryPreference != nil && !isValidDeliveryPreference(*user.DeliveryPreference) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid delivery preference")
+	}
 
 	// Update user profile in database
 	userJSON, _ := json.Marshal(user)
@@ -386,3 +895,98 @@ func (m *Manager) VerifyEmail(ctx context.Context, code string) (bool, error) {
 	err := m.db.QueryRow(ctx, verifyEmailDBQ, code).Scan(&verified)
 	return verified, err
 }
+
+// isValidDeliveryPreference checks if the delivery preference provided is
+// supported.
+func isValidDeliveryPreference(p hub.DeliveryPreference) bool {
+	switch p {
+	case hub.ImmediateDelivery, hub.DailyDigestDelivery, hub.WeeklyDigestDelivery:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateNotificationPreferences checks if the notification preferences
+// provided are valid to be used as input for some database functions calls.
+func validateNotificationPreferences(p *hub.NotificationPreferences) error {
+	seen := make(map[hub.EventKind]struct{}, len(p.EventKinds))
+	for _, ekp := range p.EventKinds {
+		switch ekp.EventKind {
+		case hub.NewRelease, hub.SecurityAlert:
+		case hub.RepositoryTrackingErrors, hub.RepositoryOwnershipClaim:
+			if ekp.Digest {
+				return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "digest only available for packages events")
+			}
+		default:
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid event kind")
+		}
+		if _, ok := seen[ekp.EventKind]; ok {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "duplicated event kind")
+		}
+		seen[ekp.EventKind] = struct{}{}
+		if ekp.Email && ekp.Digest {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "email and digest cannot be both enabled")
+		}
+	}
+	if q := p.QuietHours; q != nil {
+		start, err := time.Parse(quietHoursLayout, q.Start)
+		if err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid quiet hours start")
+		}
+		end, err := time.Parse(quietHoursLayout, q.End)
+		if err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid quiet hours end")
+		}
+		if start.Equal(end) {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "quiet hours start and end must be different")
+		}
+		if q.TimeZone == "" {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "quiet hours time zone not provided")
+		}
+		if _, err := time.LoadLocation(q.TimeZone); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid quiet hours time zone")
+		}
+	}
+	return nil
+}
+
+// QuietHoursEnd checks if the time provided is within the quiet hours given,
+// returning when they will end in that case. Quiet hours may span midnight
+// (i.e. from 22:00 to 07:00).
+func QuietHoursEnd(q *hub.QuietHours, now time.Time) (time.Time, bool) {
+	if q == nil {
+		return time.Time{}, false
+	}
+	loc, err := time.LoadLocation(q.TimeZone)
+	if err != nil {
+		return time.Time{}, false
+	}
+	start, err := time.Parse(quietHoursLayout, q.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := time.Parse(quietHoursLayout, q.End)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	// Build the quiet hours period of the current day in the user's time
+	// zone, moving it back one day when it spans midnight and we are past it
+	now = now.In(loc)
+	at := func(t time.Time, dayOffset int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day()+dayOffset, t.Hour(), t.Minute(), 0, 0, loc)
+	}
+	periodStart, periodEnd := at(start, 0), at(end, 0)
+	if !end.After(start) {
+		if now.Before(periodEnd) {
+			periodStart = at(start, -1)
+		} else {
+			periodEnd = at(end, 1)
+		}
+	}
+	if now.Before(periodStart) || !now.Before(periodEnd) {
+		return time.Time{}, false
+	}
+	return periodEnd, true
+}
@@ -1,21 +1,251 @@
 package user
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/artifacthub/hub/internal/email"
+	"github.com/artifacthub/hub/internal/encryption"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 )
 
+func TestApproveSession(t *testing.T) {
+	ctx := context.Background()
+	sessionID := []byte("sessionID")
+	tfaConfigJSON := newTFAConfigJSON(t, true)
+	createdAt := time.Now().Unix()
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg    string
+			sessionID []byte
+			passcode  string
+		}{
+			{
+				"session id not provided",
+				nil,
+				"123456",
+			},
+			{
+				"passcode not provided",
+				sessionID,
+				"",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(nil, nil)
+
+				err := m.ApproveSession(ctx, tc.sessionID, tc.passcode)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("session not found", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionTFAConfigDBQ, sessionID).Return(nil, pgx.ErrNoRows)
+		m := NewManager(db, nil)
+
+		err := m.ApproveSession(ctx, sessionID, "123456")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error getting tfa config", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionTFAConfigDBQ, sessionID).Return(nil, tests.ErrFakeDB)
+		m := NewManager(db, nil)
+
+		err := m.ApproveSession(ctx, sessionID, "123456")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("session expired", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionTFAConfigDBQ, sessionID).Return([]interface{}{
+			tfaConfigJSON,
+			time.Now().Add(-tfaPendingSessionTimeout - time.Minute).Unix(),
+		}, nil)
+		db.On("Exec", ctx, deleteSessionDBQ, sessionID).Return(nil)
+		m := NewManager(db, nil)
+
+		err := m.ApproveSession(ctx, sessionID, "123456")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "session expired")
+		db.AssertExpectations(t)
+	})
+
+	t.Run("tfa not enabled", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionTFAConfigDBQ, sessionID).Return([]interface{}{
+			[]byte(`{"enabled": false}`),
+			createdAt,
+		}, nil)
+		m := NewManager(db, nil)
+
+		err := m.ApproveSession(ctx, sessionID, "123456")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "two-factor authentication not enabled")
+		db.AssertExpectations(t)
+	})
+
+	t.Run("invalid passcode", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionTFAConfigDBQ, sessionID).Return([]interface{}{tfaConfigJSON, createdAt}, nil)
+		db.On("Exec", ctx, registerFailedTFAAttemptDBQ, sessionID, tfaUserMaxAttempts, int64(tfaLockoutDuration.Seconds())).
+			Return(nil)
+		db.On("QueryRow", ctx, incrSessionTFAAttemptsDBQ, sessionID).Return(int64(1), nil)
+		m := NewManager(db, nil)
+
+		err := m.ApproveSession(ctx, sessionID, "invalid")
+		assert.Equal(t, ErrInvalidPasscode, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("invalid passcode: max attempts reached, session deleted", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionTFAConfigDBQ, sessionID).Return([]interface{}{tfaConfigJSON, createdAt}, nil)
+		db.On("Exec", ctx, registerFailedTFAAttemptDBQ, sessionID, tfaUserMaxAttempts, int64(tfaLockoutDuration.Seconds())).
+			Return(nil)
+		db.On("QueryRow", ctx, incrSessionTFAAttemptsDBQ, sessionID).Return(int64(tfaMaxAttempts), nil)
+		db.On("Exec", ctx, deleteSessionDBQ, sessionID).Return(nil)
+		m := NewManager(db, nil)
+
+		err := m.ApproveSession(ctx, sessionID, "invalid")
+		assert.Equal(t, ErrInvalidPasscode, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("invalid passcode: database error registering failed attempt", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionTFAConfigDBQ, sessionID).Return([]interface{}{tfaConfigJSON, createdAt}, nil)
+		db.On("Exec", ctx, registerFailedTFAAttemptDBQ, sessionID, tfaUserMaxAttempts, int64(tfaLockoutDuration.Seconds())).
+			Return(tests.ErrFakeDB)
+		m := NewManager(db, nil)
+
+		err := m.ApproveSession(ctx, sessionID, "invalid")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("user locked out: retry with a new session rejected", func(t *testing.T) {
+		t.Parallel()
+		newSessionID := []byte("newSessionID")
+		var c map[string]interface{}
+		require.NoError(t, json.Unmarshal(tfaConfigJSON, &c))
+		c["locked"] = true
+		lockedTFAConfigJSON, _ := json.Marshal(c)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionTFAConfigDBQ, newSessionID).Return([]interface{}{lockedTFAConfigJSON, createdAt}, nil)
+		m := NewManager(db, nil)
+
+		passcode, _ := generateTOTP(rfc6238Secret, time.Now())
+		err := m.ApproveSession(ctx, newSessionID, passcode)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "too many invalid passcodes")
+		db.AssertExpectations(t)
+	})
+
+	t.Run("session approved using passcode", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionTFAConfigDBQ, sessionID).Return([]interface{}{tfaConfigJSON, createdAt}, nil)
+		db.On("Exec", ctx, approveSessionDBQ, sessionID, mock.AnythingOfType("int64"), (*string)(nil)).Return(nil)
+		m := NewManager(db, nil)
+
+		passcode, _ := generateTOTP(rfc6238Secret, time.Now())
+		err := m.ApproveSession(ctx, sessionID, passcode)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("session approved using encrypted secret", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionTFAConfigDBQ, sessionID).Return([]interface{}{
+			[]byte(`{"enabled": true, "secret": "encryptedSecret"}`),
+			createdAt,
+		}, nil)
+		db.On("Exec", ctx, approveSessionDBQ, sessionID, mock.AnythingOfType("int64"), (*string)(nil)).Return(nil)
+		enc := &encryption.EncrypterMock{}
+		enc.On("Decrypt", ctx, "encryptedSecret").Return(rfc6238Secret, nil)
+		m := NewManager(db, nil, WithEncrypter(enc))
+
+		passcode, _ := generateTOTP(rfc6238Secret, time.Now())
+		err := m.ApproveSession(ctx, sessionID, passcode)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+		enc.AssertExpectations(t)
+	})
+
+	t.Run("passcode already used", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionTFAConfigDBQ, sessionID).Return([]interface{}{tfaConfigJSON, createdAt}, nil)
+		db.On("Exec", ctx, approveSessionDBQ, sessionID, mock.AnythingOfType("int64"), (*string)(nil)).
+			Return(errDBInvalidPasscode)
+		db.On("Exec", ctx, registerFailedTFAAttemptDBQ, sessionID, tfaUserMaxAttempts, int64(tfaLockoutDuration.Seconds())).
+			Return(nil)
+		db.On("QueryRow", ctx, incrSessionTFAAttemptsDBQ, sessionID).Return(int64(2), nil)
+		m := NewManager(db, nil)
+
+		passcode, _ := generateTOTP(rfc6238Secret, time.Now())
+		err := m.ApproveSession(ctx, sessionID, passcode)
+		assert.Equal(t, ErrInvalidPasscode, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("session approved using recovery code", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionTFAConfigDBQ, sessionID).Return([]interface{}{tfaConfigJSON, createdAt}, nil)
+		db.On("Exec", ctx, approveSessionDBQ, sessionID, int64(0), mock.MatchedBy(func(hash *string) bool {
+			return hash != nil && bcrypt.CompareHashAndPassword([]byte(*hash), []byte("code2")) == nil
+		})).Return(nil)
+		m := NewManager(db, nil)
+
+		err := m.ApproveSession(ctx, sessionID, "code2")
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error approving session using recovery code", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionTFAConfigDBQ, sessionID).Return([]interface{}{tfaConfigJSON, createdAt}, nil)
+		db.On("Exec", ctx, approveSessionDBQ, sessionID, int64(0), mock.Anything).Return(tests.ErrFakeDB)
+		m := NewManager(db, nil)
+
+		err := m.ApproveSession(ctx, sessionID, "code2")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestCheckAPIKey(t *testing.T) {
 	ctx := context.Background()
 
@@ -48,7 +278,7 @@ func TestCheckAPIKey(t *testing.T) {
 	t.Run("key not found in database", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getAPIKeyUserIDDBQ, []byte("key")).Return(nil, pgx.ErrNoRows)
+		db.On("QueryRow", ctx, checkAPIKeyDBQ, []byte("key")).Return(nil, pgx.ErrNoRows)
 		m := NewManager(db, nil)
 
 		output, err := m.CheckAPIKey(ctx, []byte("key"))
@@ -61,7 +291,7 @@ func TestCheckAPIKey(t *testing.T) {
 	t.Run("error getting key from database", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getAPIKeyUserIDDBQ, []byte("key")).Return(nil, tests.ErrFakeDB)
+		db.On("QueryRow", ctx, checkAPIKeyDBQ, []byte("key")).Return(nil, tests.ErrFakeDB)
 		m := NewManager(db, nil)
 
 		output, err := m.CheckAPIKey(ctx, []byte("key"))
@@ -73,13 +303,31 @@ func TestCheckAPIKey(t *testing.T) {
 	t.Run("valid key", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getAPIKeyUserIDDBQ, []byte("key")).Return("userID", nil)
+		db.On("QueryRow", ctx, checkAPIKeyDBQ, []byte("key")).Return([]interface{}{"userID", nil}, nil)
+		m := NewManager(db, nil)
+
+		output, err := m.CheckAPIKey(ctx, []byte("key"))
+		assert.NoError(t, err)
+		assert.True(t, output.Valid)
+		assert.Equal(t, "userID", output.UserID)
+		assert.Empty(t, output.Scopes)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("valid scoped key", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, checkAPIKeyDBQ, []byte("key")).Return([]interface{}{
+			"userID",
+			[]string{"read-only", "manage-webhooks"},
+		}, nil)
 		m := NewManager(db, nil)
 
 		output, err := m.CheckAPIKey(ctx, []byte("key"))
 		assert.NoError(t, err)
 		assert.True(t, output.Valid)
 		assert.Equal(t, "userID", output.UserID)
+		assert.Equal(t, []hub.APIKeyScope{hub.ReadOnlyScope, hub.ManageWebhooksScope}, output.Scopes)
 		db.AssertExpectations(t)
 	})
 }
@@ -252,22 +500,14 @@ func TestCheckSession(t *testing.T) {
 		testCases := []struct {
 			errMsg    string
 			sessionID []byte
-			duration  time.Duration
 		}{
 			{
 				"session id not provided",
 				nil,
-				10,
 			},
 			{
 				"session id not provided",
 				[]byte(""),
-				10,
-			},
-			{
-				"duration not provided",
-				[]byte("sessionID"),
-				0,
 			},
 		}
 		for _, tc := range testCases {
@@ -275,7 +515,7 @@ func TestCheckSession(t *testing.T) {
 			t.Run(tc.errMsg, func(t *testing.T) {
 				t.Parallel()
 				m := NewManager(nil, nil)
-				_, err := m.CheckSession(ctx, tc.sessionID, tc.duration)
+				_, err := m.CheckSession(ctx, tc.sessionID)
 				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
 				assert.Contains(t, err.Error(), tc.errMsg)
 			})
@@ -288,7 +528,7 @@ func TestCheckSession(t *testing.T) {
 		db.On("QueryRow", ctx, getSessionDBQ, []byte("sessionID")).Return(nil, pgx.ErrNoRows)
 		m := NewManager(db, nil)
 
-		output, err := m.CheckSession(ctx, []byte("sessionID"), 1*time.Hour)
+		output, err := m.CheckSession(ctx, []byte("sessionID"))
 		assert.NoError(t, err)
 		assert.False(t, output.Valid)
 		assert.Empty(t, output.UserID)
@@ -301,35 +541,114 @@ func TestCheckSession(t *testing.T) {
 		db.On("QueryRow", ctx, getSessionDBQ, []byte("sessionID")).Return(nil, tests.ErrFakeDB)
 		m := NewManager(db, nil)
 
-		output, err := m.CheckSession(ctx, []byte("sessionID"), 1*time.Hour)
+		output, err := m.CheckSession(ctx, []byte("sessionID"))
 		assert.Equal(t, tests.ErrFakeDB, err)
 		assert.Nil(t, output)
 		db.AssertExpectations(t)
 	})
 
 	t.Run("session has expired", func(t *testing.T) {
+		now := time.Now()
+		testCases := []struct {
+			description string
+			createdAt   int64
+			lastSeenAt  int64
+		}{
+			{
+				"absolute timeout reached",
+				now.Add(-DefaultSessionAbsoluteTimeout).Add(-1 * time.Minute).Unix(),
+				now.Unix(),
+			},
+			{
+				"idle timeout reached",
+				now.Add(-24 * time.Hour).Add(-DefaultSessionIdleTimeout).Unix(),
+				now.Add(-DefaultSessionIdleTimeout).Add(-1 * time.Minute).Unix(),
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getSessionDBQ, []byte("sessionID")).Return([]interface{}{
+					"userID",
+					tc.createdAt,
+					tc.lastSeenAt,
+				}, nil)
+				m := NewManager(db, nil)
+
+				output, err := m.CheckSession(ctx, []byte("sessionID"))
+				assert.NoError(t, err)
+				assert.False(t, output.Valid)
+				assert.Empty(t, output.UserID)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("session expired using custom timeouts", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getSessionDBQ, []byte("sessionID")).Return([]interface{}{"userID", int64(1)}, nil)
-		m := NewManager(db, nil)
+		db.On("QueryRow", ctx, getSessionDBQ, []byte("sessionID")).Return([]interface{}{
+			"userID",
+			time.Now().Add(-3 * time.Hour).Unix(),
+			time.Now().Add(-2 * time.Hour).Unix(),
+		}, nil)
+		m := NewManager(db, nil, WithSessionTimeouts(1*time.Hour, 24*time.Hour))
 
-		output, err := m.CheckSession(ctx, []byte("sessionID"), 1*time.Hour)
+		output, err := m.CheckSession(ctx, []byte("sessionID"))
 		assert.NoError(t, err)
 		assert.False(t, output.Valid)
 		assert.Empty(t, output.UserID)
 		db.AssertExpectations(t)
 	})
 
-	t.Run("valid session", func(t *testing.T) {
+	t.Run("error registering session activity", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionDBQ, []byte("sessionID")).Return([]interface{}{
+			"userID",
+			time.Now().Add(-24 * time.Hour).Unix(),
+			time.Now().Add(-1 * time.Hour).Unix(),
+		}, nil)
+		db.On("Exec", ctx, updateSessionLastSeenDBQ, []byte("sessionID")).Return(tests.ErrFakeDB)
+		m := NewManager(db, nil)
+
+		output, err := m.CheckSession(ctx, []byte("sessionID"))
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, output)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("valid session, activity registered", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionDBQ, []byte("sessionID")).Return([]interface{}{
+			"userID",
+			time.Now().Add(-24 * time.Hour).Unix(),
+			time.Now().Add(-1 * time.Hour).Unix(),
+		}, nil)
+		db.On("Exec", ctx, updateSessionLastSeenDBQ, []byte("sessionID")).Return(nil)
+		m := NewManager(db, nil)
+
+		output, err := m.CheckSession(ctx, []byte("sessionID"))
+		assert.NoError(t, err)
+		assert.True(t, output.Valid)
+		assert.Equal(t, "userID", output.UserID)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("valid session, activity recently registered", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
 		db.On("QueryRow", ctx, getSessionDBQ, []byte("sessionID")).Return([]interface{}{
 			"userID",
 			time.Now().Unix(),
+			time.Now().Unix(),
 		}, nil)
 		m := NewManager(db, nil)
 
-		output, err := m.CheckSession(ctx, []byte("sessionID"), 1*time.Hour)
+		output, err := m.CheckSession(ctx, []byte("sessionID"))
 		assert.NoError(t, err)
 		assert.True(t, output.Valid)
 		assert.Equal(t, "userID", output.UserID)
@@ -399,90 +718,392 @@ func TestDeleteSession(t *testing.T) {
 	})
 }
 
-func TestGetProfile(t *testing.T) {
+func TestDisableTFA(t *testing.T) {
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	tfaConfigJSON := newTFAConfigJSON(t, true)
 
 	t.Run("user id not found in ctx", func(t *testing.T) {
 		t.Parallel()
 		m := NewManager(nil, nil)
 		assert.Panics(t, func() {
-			_, _ = m.GetProfile(context.Background())
+			_ = m.DisableTFA(context.Background(), "123456")
 		})
 	})
 
-	t.Run("database error", func(t *testing.T) {
+	t.Run("passcode not provided", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil, nil)
+
+		err := m.DisableTFA(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "passcode not provided")
+	})
+
+	t.Run("database error getting tfa config", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getUserProfileDBQ, "userID").Return(nil, tests.ErrFakeDB)
+		db.On("QueryRow", ctx, getUserTFAConfigDBQ, "userID").Return(nil, tests.ErrFakeDB)
 		m := NewManager(db, nil)
 
-		profile, err := m.GetProfile(ctx)
+		err := m.DisableTFA(ctx, "123456")
 		assert.Equal(t, tests.ErrFakeDB, err)
-		assert.Nil(t, profile)
 		db.AssertExpectations(t)
 	})
 
-	t.Run("database query succeeded", func(t *testing.T) {
+	t.Run("tfa not enabled", func(t *testing.T) {
 		t.Parallel()
-		expectedProfile := &hub.User{
-			Alias:          "alias",
-			FirstName:      "first_name",
-			LastName:       "last_name",
-			Email:          "email",
-			ProfileImageID: "profile_image_id",
-		}
-
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getUserProfileDBQ, "userID").Return([]byte(`
-		{
-			"alias": "alias",
-			"first_name": "first_name",
-			"last_name": "last_name",
-			"email": "email",
-			"profile_image_id": "profile_image_id"
-		}
-		`), nil)
+		db.On("QueryRow", ctx, getUserTFAConfigDBQ, "userID").Return([]byte(`{"enabled": false}`), nil)
 		m := NewManager(db, nil)
 
-		profile, err := m.GetProfile(ctx)
-		assert.NoError(t, err)
-		assert.Equal(t, expectedProfile, profile)
+		err := m.DisableTFA(ctx, "123456")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "two-factor authentication not enabled")
 		db.AssertExpectations(t)
 	})
-}
-
-func TestGetProfileJSON(t *testing.T) {
-	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
-
-	t.Run("user id not found in ctx", func(t *testing.T) {
-		t.Parallel()
-		m := NewManager(nil, nil)
-		assert.Panics(t, func() {
-			_, _ = m.GetProfileJSON(context.Background())
-		})
-	})
 
-	t.Run("database query succeeded", func(t *testing.T) {
+	t.Run("invalid passcode", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getUserProfileDBQ, "userID").Return([]byte("dataJSON"), nil)
+		db.On("QueryRow", ctx, getUserTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
 		m := NewManager(db, nil)
 
-		data, err := m.GetProfileJSON(ctx)
-		assert.NoError(t, err)
-		assert.Equal(t, []byte("dataJSON"), data)
+		err := m.DisableTFA(ctx, "invalid")
+		assert.Equal(t, ErrInvalidPasscode, err)
 		db.AssertExpectations(t)
 	})
 
-	t.Run("database error", func(t *testing.T) {
+	t.Run("database error disabling tfa", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getUserProfileDBQ, "userID").Return(nil, tests.ErrFakeDB)
+		db.On("QueryRow", ctx, getUserTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
+		db.On("Exec", ctx, disableTFADBQ, "userID").Return(tests.ErrFakeDB)
 		m := NewManager(db, nil)
 
-		data, err := m.GetProfileJSON(ctx)
+		err := m.DisableTFA(ctx, "code1")
 		assert.Equal(t, tests.ErrFakeDB, err)
-		assert.Nil(t, data)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("tfa disabled successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
+		db.On("Exec", ctx, disableTFADBQ, "userID").Return(nil)
+		m := NewManager(db, nil)
+
+		passcode, _ := generateTOTP(rfc6238Secret, time.Now())
+		err := m.DisableTFA(ctx, passcode)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestEnableTFA(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	tfaConfigJSON := newTFAConfigJSON(t, false)
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_ = m.EnableTFA(context.Background(), "123456")
+		})
+	})
+
+	t.Run("passcode not provided", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil, nil)
+
+		err := m.EnableTFA(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "passcode not provided")
+	})
+
+	t.Run("invalid tfa config", func(t *testing.T) {
+		testCases := []struct {
+			errMsg        string
+			tfaConfigJSON []byte
+		}{
+			{
+				"two-factor authentication already enabled",
+				[]byte(`{"enabled": true, "secret": "` + rfc6238Secret + `"}`),
+			},
+			{
+				"two-factor authentication not set up",
+				[]byte(`{"enabled": false}`),
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getUserTFAConfigDBQ, "userID").Return(tc.tfaConfigJSON, nil)
+				m := NewManager(db, nil)
+
+				err := m.EnableTFA(ctx, "123456")
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("recovery codes cannot be used to enable tfa", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
+		m := NewManager(db, nil)
+
+		err := m.EnableTFA(ctx, "code1")
+		assert.Equal(t, ErrInvalidPasscode, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("tfa enabled successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
+		now := time.Now()
+		db.On("Exec", ctx, enableTFADBQ, "userID", tfaStep(now)).Return(nil)
+		m := NewManager(db, nil)
+
+		passcode, _ := generateTOTP(rfc6238Secret, now)
+		err := m.EnableTFA(ctx, passcode)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetNotificationPreferences(t *testing.T) {
+	ctx := context.Background()
+	userID := "00000000-0000-0000-0000-000000000001"
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil, nil)
+		_, err := m.GetNotificationPreferences(ctx, "invalid")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "invalid user id")
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserNotificationPrefsDBQ, userID).Return(nil, tests.ErrFakeDB)
+		m := NewManager(db, nil)
+
+		p, err := m.GetNotificationPreferences(ctx, userID)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, p)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		expectedPreferences := &hub.NotificationPreferences{
+			EventKinds: []*hub.EventKindNotificationPreference{
+				{
+					EventKind: hub.NewRelease,
+					Digest:    true,
+					Webhook:   true,
+				},
+			},
+			QuietHours: &hub.QuietHours{
+				Start:    "22:00",
+				End:      "07:00",
+				TimeZone: "Europe/Madrid",
+			},
+		}
+
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserNotificationPrefsDBQ, userID).Return([]byte(`
+		{
+			"event_kinds": [
+				{
+					"event_kind": 0,
+					"email": false,
+					"digest": true,
+					"webhook": true
+				}
+			],
+			"quiet_hours": {
+				"start": "22:00",
+				"end": "07:00",
+				"time_zone": "Europe/Madrid"
+			}
+		}
+		`), nil)
+		m := NewManager(db, nil)
+
+		p, err := m.GetNotificationPreferences(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPreferences, p)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetNotificationPreferencesJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetNotificationPreferencesJSON(context.Background())
+		})
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserNotificationPrefsDBQ, "userID").Return([]byte("dataJSON"), nil)
+		m := NewManager(db, nil)
+
+		data, err := m.GetNotificationPreferencesJSON(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), data)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserNotificationPrefsDBQ, "userID").Return(nil, tests.ErrFakeDB)
+		m := NewManager(db, nil)
+
+		data, err := m.GetNotificationPreferencesJSON(ctx)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, data)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetProfile(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetProfile(context.Background())
+		})
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserProfileDBQ, "userID").Return(nil, tests.ErrFakeDB)
+		m := NewManager(db, nil)
+
+		profile, err := m.GetProfile(ctx)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, profile)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		expectedProfile := &hub.User{
+			Alias:          "alias",
+			FirstName:      "first_name",
+			LastName:       "last_name",
+			Email:          "email",
+			ProfileImageID: "profile_image_id",
+		}
+
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserProfileDBQ, "userID").Return([]byte(`
+		{
+			"alias": "alias",
+			"first_name": "first_name",
+			"last_name": "last_name",
+			"email": "email",
+			"profile_image_id": "profile_image_id"
+		}
+		`), nil)
+		m := NewManager(db, nil)
+
+		profile, err := m.GetProfile(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedProfile, profile)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetProfileJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetProfileJSON(context.Background())
+		})
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserProfileDBQ, "userID").Return([]byte("dataJSON"), nil)
+		m := NewManager(db, nil)
+
+		data, err := m.GetProfileJSON(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), data)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserProfileDBQ, "userID").Return(nil, tests.ErrFakeDB)
+		m := NewManager(db, nil)
+
+		data, err := m.GetProfileJSON(ctx)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, data)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetSessionsJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	idleTimeout := int64(DefaultSessionIdleTimeout.Seconds())
+	absoluteTimeout := int64(DefaultSessionAbsoluteTimeout.Seconds())
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetSessionsJSON(context.Background(), []byte("sessionID"))
+		})
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserSessionsDBQ, "userID", []byte("sessionID"), idleTimeout, absoluteTimeout).
+			Return([]byte("dataJSON"), nil)
+		m := NewManager(db, nil)
+
+		data, err := m.GetSessionsJSON(ctx, []byte("sessionID"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), data)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserSessionsDBQ, "userID", []byte("sessionID"), idleTimeout, absoluteTimeout).
+			Return(nil, tests.ErrFakeDB)
+		m := NewManager(db, nil)
+
+		data, err := m.GetSessionsJSON(ctx, []byte("sessionID"))
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, data)
 		db.AssertExpectations(t)
 	})
 }
@@ -503,21 +1124,123 @@ func TestGetUserID(t *testing.T) {
 		db.On("QueryRow", ctx, getUserIDDBQ, "email").Return("userID", nil)
 		m := NewManager(db, nil)
 
-		userID, err := m.GetUserID(ctx, "email")
+		userID, err := m.GetUserID(ctx, "email")
+		assert.NoError(t, err)
+		assert.Equal(t, "userID", userID)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserIDDBQ, "email").Return("", tests.ErrFakeDB)
+		m := NewManager(db, nil)
+
+		userID, err := m.GetUserID(ctx, "email")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Empty(t, userID)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestRegisterPasswordResetCode(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg    string
+			userEmail string
+			baseURL   string
+		}{
+			{
+				"email not provided",
+				"",
+				"http://baseurl.com",
+			},
+			{
+				"invalid base url",
+				"email@email.com",
+				"invalid",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(nil, nil)
+
+				err := m.RegisterPasswordResetCode(ctx, tc.userEmail, tc.baseURL)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("password reset code registered in database", func(t *testing.T) {
+		code := "passwordResetCode"
+		testCases := []struct {
+			description         string
+			emailSenderResponse error
+		}{
+			{
+				"password reset code sent successfully",
+				nil,
+			},
+			{
+				"error sending password reset code",
+				email.ErrFakeSenderFailure,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, registerPasswordResetCodeDBQ, "email@email.com").Return(&code, nil)
+				es := &email.SenderMock{}
+				sent := make(chan struct{})
+				es.On("SendEmail", mock.MatchedBy(func(d *email.Data) bool {
+					return d.To == "email@email.com" &&
+						bytes.Contains(d.Body, []byte("http://baseurl.com/reset-password?code=passwordResetCode"))
+				})).Run(func(args mock.Arguments) {
+					close(sent)
+				}).Return(tc.emailSenderResponse)
+				m := NewManager(db, es)
+
+				err := m.RegisterPasswordResetCode(ctx, "email@email.com", "http://baseurl.com")
+				assert.NoError(t, err)
+				select {
+				case <-sent:
+				case <-time.After(2 * time.Second):
+					t.Error("password reset email was not sent")
+				}
+				db.AssertExpectations(t)
+				es.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("email does not belong to any user who can reset the password", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, registerPasswordResetCodeDBQ, "email@email.com").Return(nil, nil)
+		es := &email.SenderMock{}
+		m := NewManager(db, es)
+
+		err := m.RegisterPasswordResetCode(ctx, "email@email.com", "http://baseurl.com")
 		assert.NoError(t, err)
-		assert.Equal(t, "userID", userID)
 		db.AssertExpectations(t)
+		es.AssertExpectations(t)
 	})
 
-	t.Run("database error", func(t *testing.T) {
+	t.Run("database error registering password reset code", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getUserIDDBQ, "email").Return("", tests.ErrFakeDB)
+		db.On("QueryRow", ctx, registerPasswordResetCodeDBQ, "email@email.com").Return(nil, tests.ErrFakeDB)
 		m := NewManager(db, nil)
 
-		userID, err := m.GetUserID(ctx, "email")
+		err := m.RegisterPasswordResetCode(ctx, "email@email.com", "http://baseurl.com")
 		assert.Equal(t, tests.ErrFakeDB, err)
-		assert.Empty(t, userID)
 		db.AssertExpectations(t)
 	})
 }
@@ -561,12 +1284,16 @@ func TestRegisterSession(t *testing.T) {
 	t.Run("successful session registration", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, registerSessionDBQ, mock.Anything).Return([]byte("sessionID"), nil)
+		db.On("QueryRow", ctx, registerSessionDBQ, mock.Anything).Return([]interface{}{
+			[]byte("sessionID"),
+			true,
+		}, nil)
 		m := NewManager(db, nil)
 
-		sessionID, err := m.RegisterSession(ctx, s)
+		output, err := m.RegisterSession(ctx, s)
 		assert.NoError(t, err)
-		assert.Equal(t, []byte("sessionID"), sessionID)
+		assert.Equal(t, []byte("sessionID"), output.SessionID)
+		assert.True(t, output.Approved)
 		db.AssertExpectations(t)
 	})
 
@@ -576,9 +1303,9 @@ func TestRegisterSession(t *testing.T) {
 		db.On("QueryRow", ctx, registerSessionDBQ, mock.Anything).Return(nil, tests.ErrFakeDB)
 		m := NewManager(db, nil)
 
-		sessionID, err := m.RegisterSession(ctx, s)
+		output, err := m.RegisterSession(ctx, s)
 		assert.Equal(t, tests.ErrFakeDB, err)
-		assert.Nil(t, sessionID)
+		assert.Nil(t, output)
 		db.AssertExpectations(t)
 	})
 }
@@ -687,6 +1414,364 @@ func TestRegisterUser(t *testing.T) {
 	})
 }
 
+func TestResetPassword(t *testing.T) {
+	ctx := context.Background()
+	code := "00000000-0000-0000-0000-000000000001"
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg      string
+			code        string
+			newPassword string
+		}{
+			{
+				"code not provided",
+				"",
+				"password",
+			},
+			{
+				"invalid code",
+				"invalid",
+				"password",
+			},
+			{
+				"new password not provided",
+				code,
+				"",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(nil, nil)
+
+				err := m.ResetPassword(ctx, tc.code, tc.newPassword)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error resetting password", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, resetUserPasswordDBQ, code, mock.Anything).Return(false, tests.ErrFakeDB)
+		m := NewManager(db, nil)
+
+		err := m.ResetPassword(ctx, code, "password")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("invalid or expired code", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, resetUserPasswordDBQ, code, mock.Anything).Return(false, nil)
+		m := NewManager(db, nil)
+
+		err := m.ResetPassword(ctx, code, "password")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "invalid or expired code")
+		db.AssertExpectations(t)
+	})
+
+	t.Run("password reset successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, resetUserPasswordDBQ, code, mock.MatchedBy(func(hashed string) bool {
+			return bcrypt.CompareHashAndPassword([]byte(hashed), []byte("password")) == nil
+		})).Return(true, nil)
+		m := NewManager(db, nil)
+
+		err := m.ResetPassword(ctx, code, "password")
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestRevokeOtherSessions(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_ = m.RevokeOtherSessions(context.Background(), []byte("sessionID"))
+		})
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, deleteOtherSessionsDBQ, "userID", []byte("sessionID")).Return(nil)
+		m := NewManager(db, nil)
+
+		err := m.RevokeOtherSessions(ctx, []byte("sessionID"))
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, deleteOtherSessionsDBQ, "userID", []byte("sessionID")).Return(tests.ErrFakeDB)
+		m := NewManager(db, nil)
+
+		err := m.RevokeOtherSessions(ctx, []byte("sessionID"))
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	sessionID := strings.Repeat("a1", 32)
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_ = m.RevokeSession(context.Background(), sessionID)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			description string
+			sessionID   string
+		}{
+			{
+				"session id not provided",
+				"",
+			},
+			{
+				"session id is not hex encoded",
+				strings.Repeat("z", 64),
+			},
+			{
+				"session id has an invalid length",
+				"a1b2c3",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(nil, nil)
+				err := m.RevokeSession(ctx, tc.sessionID)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), "invalid session id")
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, deleteUserSessionDBQ, "userID", sessionID).Return(nil)
+		m := NewManager(db, nil)
+
+		err := m.RevokeSession(ctx, sessionID)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, deleteUserSessionDBQ, "userID", sessionID).Return(tests.ErrFakeDB)
+		m := NewManager(db, nil)
+
+		err := m.RevokeSession(ctx, sessionID)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestSetupTFA(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.SetupTFA(context.Background())
+		})
+	})
+
+	t.Run("tfa already enabled", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserTFAConfigDBQ, "userID").Return([]byte(`{"enabled": true}`), nil)
+		m := NewManager(db, nil)
+
+		_, err := m.SetupTFA(ctx)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error storing tfa config", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserTFAConfigDBQ, "userID").Return([]byte(`{"enabled": false}`), nil)
+		db.On("QueryRow", ctx, getUserProfileDBQ, "userID").Return([]byte(`{"email": "user1@email.com"}`), nil)
+		db.On("Exec", ctx, setupTFADBQ, "userID", mock.Anything, mock.Anything).Return(tests.ErrFakeDB)
+		m := NewManager(db, nil)
+
+		_, err := m.SetupTFA(ctx)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("tfa set up successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserTFAConfigDBQ, "userID").Return([]byte(`{"enabled": false}`), nil)
+		db.On("QueryRow", ctx, getUserProfileDBQ, "userID").Return([]byte(`{"email": "user1@email.com"}`), nil)
+		var storedRecoveryCodes []string
+		db.On("Exec", ctx, setupTFADBQ, "userID", "encryptedSecret", mock.Anything).
+			Run(func(args mock.Arguments) { storedRecoveryCodes = args.Get(4).([]string) }).
+			Return(nil)
+		enc := &encryption.EncrypterMock{}
+		enc.On("Encrypt", ctx, mock.Anything).Return("encryptedSecret", nil)
+		m := NewManager(db, nil, WithEncrypter(enc))
+
+		dataJSON, err := m.SetupTFA(ctx)
+		require.NoError(t, err)
+		var setup *hub.TFASetup
+		require.NoError(t, json.Unmarshal(dataJSON, &setup))
+		assert.Equal(t, buildTFAProvisioningURI(setup.Secret, "user1@email.com"), setup.ProvisioningURI)
+		assert.Len(t, setup.RecoveryCodes, tfaRecoveryCodes)
+		require.Len(t, storedRecoveryCodes, tfaRecoveryCodes)
+		for i, code := range setup.RecoveryCodes {
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(storedRecoveryCodes[i]), []byte(code)))
+		}
+		db.AssertExpectations(t)
+		enc.AssertExpectations(t)
+	})
+}
+
+func TestUpdateNotificationPreferences(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	p := &hub.NotificationPreferences{
+		EventKinds: []*hub.EventKindNotificationPreference{
+			{EventKind: hub.NewRelease, Digest: true},
+			{EventKind: hub.RepositoryTrackingErrors, Email: true, Webhook: true},
+		},
+		QuietHours: &hub.QuietHours{Start: "22:00", End: "07:00", TimeZone: "Europe/Madrid"},
+	}
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_ = m.UpdateNotificationPreferences(context.Background(), p)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			p      *hub.NotificationPreferences
+		}{
+			{
+				"invalid event kind",
+				&hub.NotificationPreferences{
+					EventKinds: []*hub.EventKindNotificationPreference{{EventKind: hub.EventKind(9)}},
+				},
+			},
+			{
+				"duplicated event kind",
+				&hub.NotificationPreferences{
+					EventKinds: []*hub.EventKindNotificationPreference{
+						{EventKind: hub.NewRelease},
+						{EventKind: hub.NewRelease},
+					},
+				},
+			},
+			{
+				"digest only available for packages events",
+				&hub.NotificationPreferences{
+					EventKinds: []*hub.EventKindNotificationPreference{
+						{EventKind: hub.RepositoryTrackingErrors, Digest: true},
+					},
+				},
+			},
+			{
+				"email and digest cannot be both enabled",
+				&hub.NotificationPreferences{
+					EventKinds: []*hub.EventKindNotificationPreference{
+						{EventKind: hub.SecurityAlert, Email: true, Digest: true},
+					},
+				},
+			},
+			{
+				"invalid quiet hours start",
+				&hub.NotificationPreferences{
+					QuietHours: &hub.QuietHours{Start: "25:00", End: "07:00", TimeZone: "UTC"},
+				},
+			},
+			{
+				"invalid quiet hours end",
+				&hub.NotificationPreferences{
+					QuietHours: &hub.QuietHours{Start: "22:00", End: "", TimeZone: "UTC"},
+				},
+			},
+			{
+				"quiet hours start and end must be different",
+				&hub.NotificationPreferences{
+					QuietHours: &hub.QuietHours{Start: "22:00", End: "22:00", TimeZone: "UTC"},
+				},
+			},
+			{
+				"quiet hours time zone not provided",
+				&hub.NotificationPreferences{
+					QuietHours: &hub.QuietHours{Start: "22:00", End: "07:00"},
+				},
+			},
+			{
+				"invalid quiet hours time zone",
+				&hub.NotificationPreferences{
+					QuietHours: &hub.QuietHours{Start: "22:00", End: "07:00", TimeZone: "Invalid/Zone"},
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(nil, nil)
+				err := m.UpdateNotificationPreferences(ctx, tc.p)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, updateUserNotificationPrefsDBQ, "userID", mock.Anything).Return(nil)
+		m := NewManager(db, nil)
+
+		err := m.UpdateNotificationPreferences(ctx, p)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, updateUserNotificationPrefsDBQ, "userID", mock.Anything).Return(tests.ErrFakeDB)
+		m := NewManager(db, nil)
+
+		err := m.UpdateNotificationPreferences(ctx, p)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestUpdatePassword(t *testing.T) {
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
 	oldHashed, _ := bcrypt.GenerateFromPassword([]byte("old"), bcrypt.DefaultCost)
@@ -778,6 +1863,7 @@ func TestUpdatePassword(t *testing.T) {
 
 func TestUpdateProfile(t *testing.T) {
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	invalidDeliveryPreference := hub.DeliveryPreference(9)
 
 	t.Run("user id not found in ctx", func(t *testing.T) {
 		t.Parallel()
@@ -800,6 +1886,10 @@ func TestUpdateProfile(t *testing.T) {
 				"invalid profile image id",
 				&hub.User{Alias: "user1", Email: "email", ProfileImageID: "invalid"},
 			},
+			{
+				"invalid delivery preference",
+				&hub.User{Alias: "user1", Email: "email", DeliveryPreference: &invalidDeliveryPreference},
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -870,3 +1960,94 @@ func TestVerifyEmail(t *testing.T) {
 		db.AssertExpectations(t)
 	})
 }
+
+func TestQuietHoursEnd(t *testing.T) {
+	overnight := &hub.QuietHours{Start: "22:00", End: "07:00", TimeZone: "Europe/Madrid"}
+	daytime := &hub.QuietHours{Start: "09:00", End: "17:30", TimeZone: "UTC"}
+
+	testCases := []struct {
+		description string
+		q           *hub.QuietHours
+		now         time.Time
+		expectedEnd time.Time
+		expectedIn  bool
+	}{
+		{
+			"no quiet hours",
+			nil,
+			time.Date(2020, 6, 16, 23, 0, 0, 0, time.UTC),
+			time.Time{},
+			false,
+		},
+		{
+			"overnight, before start",
+			overnight,
+			time.Date(2020, 6, 16, 19, 0, 0, 0, time.UTC), // 21:00 in Madrid
+			time.Time{},
+			false,
+		},
+		{
+			"overnight, before midnight",
+			overnight,
+			time.Date(2020, 6, 16, 21, 0, 0, 0, time.UTC), // 23:00 in Madrid
+			time.Date(2020, 6, 17, 5, 0, 0, 0, time.UTC),
+			true,
+		},
+		{
+			"overnight, after midnight",
+			overnight,
+			time.Date(2020, 6, 17, 1, 0, 0, 0, time.UTC), // 03:00 in Madrid
+			time.Date(2020, 6, 17, 5, 0, 0, 0, time.UTC),
+			true,
+		},
+		{
+			"overnight, after end",
+			overnight,
+			time.Date(2020, 6, 17, 5, 0, 0, 0, time.UTC), // 07:00 in Madrid
+			time.Time{},
+			false,
+		},
+		{
+			"daytime, within period",
+			daytime,
+			time.Date(2020, 6, 16, 12, 0, 0, 0, time.UTC),
+			time.Date(2020, 6, 16, 17, 30, 0, 0, time.UTC),
+			true,
+		},
+		{
+			"daytime, outside period",
+			daytime,
+			time.Date(2020, 6, 16, 18, 0, 0, 0, time.UTC),
+			time.Time{},
+			false,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			end, in := QuietHoursEnd(tc.q, tc.now)
+			assert.Equal(t, tc.expectedIn, in)
+			assert.True(t, tc.expectedEnd.Equal(end))
+		})
+	}
+}
+
+// newTFAConfigJSON returns the json representation of a two-factor
+// authentication configuration using the RFC 6238 test secret and the hashes
+// of the recovery codes code1 and code2.
+func newTFAConfigJSON(t *testing.T, enabled bool) []byte {
+	t.Helper()
+	hashes := make([]string, 0, 2)
+	for _, code := range []string{"code1", "code2"} {
+		hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.MinCost)
+		require.NoError(t, err)
+		hashes = append(hashes, string(hash))
+	}
+	c, _ := json.Marshal(&tfaConfig{
+		Enabled:       enabled,
+		Secret:        rfc6238Secret,
+		RecoveryCodes: hashes,
+	})
+	return c
+}
@@ -2,7 +2,6 @@ package user
 
 import (
 	"context"
-	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/stretchr/testify/mock"
@@ -13,6 +12,12 @@ type ManagerMock struct {
 	mock.Mock
 }
 
+// ApproveSession implements the UserManager interface.
+func (m *ManagerMock) ApproveSession(ctx context.Context, sessionID []byte, passcode string) error {
+	args := m.Called(ctx, sessionID, passcode)
+	return args.Error(0)
+}
+
 // CheckAPIKey implements the UserManager interface.
 func (m *ManagerMock) CheckAPIKey(ctx context.Context, key []byte) (*hub.CheckAPIKeyOutput, error) {
 	args := m.Called(ctx, key)
@@ -38,12 +43,8 @@ func (m *ManagerMock) CheckCredentials(
 }
 
 // CheckSession implements the UserManager interface.
-func (m *ManagerMock) CheckSession(
-	ctx context.Context,
-	sessionID []byte,
-	duration time.Duration,
-) (*hub.CheckSessionOutput, error) {
-	args := m.Called(ctx, sessionID, duration)
+func (m *ManagerMock) CheckSession(ctx context.Context, sessionID []byte) (*hub.CheckSessionOutput, error) {
+	args := m.Called(ctx, sessionID)
 	data, _ := args.Get(0).(*hub.CheckSessionOutput)
 	return data, args.Error(1)
 }
@@ -54,6 +55,35 @@ func (m *ManagerMock) DeleteSession(ctx context.Context, sessionID []byte) error
 	return args.Error(0)
 }
 
+// DisableTFA implements the UserManager interface.
+func (m *ManagerMock) DisableTFA(ctx context.Context, passcode string) error {
+	args := m.Called(ctx, passcode)
+	return args.Error(0)
+}
+
+// EnableTFA implements the UserManager interface.
+func (m *ManagerMock) EnableTFA(ctx context.Context, passcode string) error {
+	args := m.Called(ctx, passcode)
+	return args.Error(0)
+}
+
+// GetNotificationPreferences implements the UserManager interface.
+func (m *ManagerMock) GetNotificationPreferences(
+	ctx context.Context,
+	userID string,
+) (*hub.NotificationPreferences, error) {
+	args := m.Called(ctx, userID)
+	data, _ := args.Get(0).(*hub.NotificationPreferences)
+	return data, args.Error(1)
+}
+
+// GetNotificationPreferencesJSON implements the UserManager interface.
+func (m *ManagerMock) GetNotificationPreferencesJSON(ctx context.Context) ([]byte, error) {
+	args := m.Called(ctx)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // GetProfile implements the UserManager interface.
 func (m *ManagerMock) GetProfile(ctx context.Context) (*hub.User, error) {
 	args := m.Called(ctx)
@@ -68,16 +98,32 @@ func (m *ManagerMock) GetProfileJSON(ctx context.Context) ([]byte, error) {
 	return data, args.Error(1)
 }
 
+// GetSessionsJSON implements the UserManager interface.
+func (m *ManagerMock) GetSessionsJSON(ctx context.Context, currentSessionID []byte) ([]byte, error) {
+	args := m.Called(ctx, currentSessionID)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // GetUserID implements the UserManager interface.
 func (m *ManagerMock) GetUserID(ctx context.Context, email string) (string, error) {
 	args := m.Called(ctx)
 	return args.String(0), args.Error(1)
 }
 
+// RegisterPasswordResetCode implements the UserManager interface.
+func (m *ManagerMock) RegisterPasswordResetCode(ctx context.Context, userEmail, baseURL string) error {
+	args := m.Called(ctx, userEmail, baseURL)
+	return args.Error(0)
+}
+
 // RegisterSession implements the UserManager interface.
-func (m *ManagerMock) RegisterSession(ctx context.Context, session *hub.Session) ([]byte, error) {
+func (m *ManagerMock) RegisterSession(
+	ctx context.Context,
+	session *hub.Session,
+) (*hub.RegisterSessionOutput, error) {
 	args := m.Called(ctx, session)
-	data, _ := args.Get(0).([]byte)
+	data, _ := args.Get(0).(*hub.RegisterSessionOutput)
 	return data, args.Error(1)
 }
 
@@ -87,6 +133,37 @@ func (m *ManagerMock) RegisterUser(ctx context.Context, user *hub.User, baseURL
 	return args.Error(0)
 }
 
+// ResetPassword implements the UserManager interface.
+func (m *ManagerMock) ResetPassword(ctx context.Context, code, newPassword string) error {
+	args := m.Called(ctx, code, newPassword)
+	return args.Error(0)
+}
+
+// RevokeOtherSessions implements the UserManager interface.
+func (m *ManagerMock) RevokeOtherSessions(ctx context.Context, currentSessionID []byte) error {
+	args := m.Called(ctx, currentSessionID)
+	return args.Error(0)
+}
+
+// RevokeSession implements the UserManager interface.
+func (m *ManagerMock) RevokeSession(ctx context.Context, sessionID string) error {
+	args := m.Called(ctx, sessionID)
+	return args.Error(0)
+}
+
+// SetupTFA implements the UserManager interface.
+func (m *ManagerMock) SetupTFA(ctx context.Context) ([]byte, error) {
+	args := m.Called(ctx)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// UpdateNotificationPreferences implements the UserManager interface.
+func (m *ManagerMock) UpdateNotificationPreferences(ctx context.Context, p *hub.NotificationPreferences) error {
+	args := m.Called(ctx, p)
+	return args.Error(0)
+}
+
 // UpdatePassword implements the UserManager interface.
 func (m *ManagerMock) UpdatePassword(ctx context.Context, old, new string) error {
 	args := m.Called(ctx, old, new)
@@ -0,0 +1,168 @@
+package user
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" // #nosec
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// tfaIssuer represents the issuer of the two-factor authentication
+	// passcodes, displayed by the authenticator apps.
+	tfaIssuer = "Artifact Hub"
+
+	// tfaPeriod represents the number of seconds each passcode is valid for.
+	tfaPeriod = 30
+
+	// tfaDigits represents the number of digits of each passcode.
+	tfaDigits = 6
+
+	// tfaSkew represents the number of periods before and after the current
+	// one in which passcodes are still accepted, to tolerate clock drifts.
+	tfaSkew = 1
+
+	// tfaRecoveryCodes represents the number of recovery codes generated when
+	// setting up two-factor authentication.
+	tfaRecoveryCodes = 10
+
+	// tfaMaxAttempts represents the maximum number of invalid passcodes that
+	// can be provided to approve a session before it is deleted.
+	tfaMaxAttempts = 5
+
+	// tfaUserMaxAttempts represents the maximum number of invalid passcodes
+	// that a user can provide (across all sessions) before being locked out.
+	// The counter is reset when a session is approved.
+	tfaUserMaxAttempts = 10
+
+	// tfaLockoutDuration represents the period of time a user is locked out
+	// after providing too many invalid passcodes.
+	tfaLockoutDuration = 15 * time.Minute
+
+	// tfaPendingSessionTimeout represents the period of time a session can be
+	// pending approval before it expires.
+	tfaPendingSessionTimeout = 5 * time.Minute
+)
+
+// b32NoPadding represents the base32 encoding used by TOTP secrets.
+var b32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateTFASecret generates a new random TOTP secret, base32 encoded.
+func generateTFASecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return b32NoPadding.EncodeToString(secret), nil
+}
+
+// generateTFARecoveryCodes generates a new set of random recovery codes.
+func generateTFARecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, tfaRecoveryCodes)
+	for i := 0; i < tfaRecoveryCodes; i++ {
+		code := make([]byte, 5)
+		if _, err := rand.Read(code); err != nil {
+			return nil, err
+		}
+		codes = append(codes, hex.EncodeToString(code))
+	}
+	return codes, nil
+}
+
+// buildTFAProvisioningURI builds the URI used to provision the secret provided
+// in the authenticator apps (usually displayed as a QR code).
+func buildTFAProvisioningURI(secret, accountName string) string {
+	label := url.PathEscape(tfaIssuer + ":" + accountName)
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", tfaIssuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprintf("%d", tfaDigits))
+	params.Set("period", fmt.Sprintf("%d", tfaPeriod))
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, params.Encode())
+}
+
+// generateTOTP generates the passcode for the secret and time provided, as
+// described in RFC 6238.
+func generateTOTP(secret string, t time.Time) (string, error) {
+	return generateTOTPForStep(secret, tfaStep(t))
+}
+
+// generateTOTPForStep generates the passcode for the secret and time step
+// provided.
+func generateTOTPForStep(secret string, step int64) (string, error) {
+	key, err := b32NoPadding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("invalid secret: %w", err)
+	}
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+	mac := hmac.New(sha1.New, key)
+	_, _ = mac.Write(counter)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < tfaDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", tfaDigits, value%mod), nil
+}
+
+// tfaStep returns the time step the time provided belongs to.
+func tfaStep(t time.Time) int64 {
+	return t.Unix() / tfaPeriod
+}
+
+// validateTOTP checks if the passcode provided is valid for the secret given
+// at the time provided. The time step the passcode belongs to is returned, so
+// that it can be recorded to prevent passcodes from being replayed.
+func validateTOTP(secret, passcode string, t time.Time) (int64, bool) {
+	if len(passcode) != tfaDigits {
+		return 0, false
+	}
+	for step := tfaStep(t) - tfaSkew; step <= tfaStep(t)+tfaSkew; step++ {
+		expected, err := generateTOTPForStep(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(passcode)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// hashTFARecoveryCodes returns the hashes of the recovery codes provided,
+// which are the ones stored in the database.
+func hashTFARecoveryCodes(recoveryCodes []string) ([]string, error) {
+	hashes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, string(hash))
+	}
+	return hashes, nil
+}
+
+// matchTFARecoveryCode returns the hash of the recovery code matching the
+// passcode provided, if any.
+func matchTFARecoveryCode(recoveryCodesHashes []string, passcode string) (string, bool) {
+	for _, hash := range recoveryCodesHashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(passcode)) == nil {
+			return hash, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,112 @@
+package user
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret represents the base32 encoded secret used in the RFC 6238
+// test vectors ("12345678901234567890").
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateTFASecret(t *testing.T) {
+	secret, err := generateTFASecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+	_, err = generateTOTP(secret, time.Now())
+	assert.NoError(t, err)
+}
+
+func TestGenerateTFARecoveryCodes(t *testing.T) {
+	codes, err := generateTFARecoveryCodes()
+	require.NoError(t, err)
+	assert.Len(t, codes, tfaRecoveryCodes)
+	for _, code := range codes {
+		assert.Len(t, code, 10)
+	}
+}
+
+func TestBuildTFAProvisioningURI(t *testing.T) {
+	uri := buildTFAProvisioningURI("SECRET", "user1@email.com")
+	assert.Equal(t,
+		"otpauth://totp/Artifact%20Hub:user1@email.com?algorithm=SHA1&digits=6&issuer=Artifact+Hub&period=30&secret=SECRET",
+		uri,
+	)
+}
+
+func TestGenerateTOTP(t *testing.T) {
+	t.Run("invalid secret", func(t *testing.T) {
+		t.Parallel()
+		_, err := generateTOTP("invalid secret!", time.Now())
+		assert.Error(t, err)
+	})
+
+	t.Run("rfc 6238 test vectors", func(t *testing.T) {
+		testCases := []struct {
+			ts               int64
+			expectedPasscode string
+		}{
+			{59, "287082"},
+			{1111111109, "081804"},
+			{1111111111, "050471"},
+			{1234567890, "005924"},
+			{2000000000, "279037"},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.expectedPasscode, func(t *testing.T) {
+				t.Parallel()
+				passcode, err := generateTOTP(rfc6238Secret, time.Unix(tc.ts, 0))
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedPasscode, passcode)
+			})
+		}
+	})
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	previousPasscode, _ := generateTOTP(rfc6238Secret, now.Add(-tfaPeriod*time.Second))
+	nextPasscode, _ := generateTOTP(rfc6238Secret, now.Add(tfaPeriod*time.Second))
+	oldPasscode, _ := generateTOTP(rfc6238Secret, now.Add(-3*tfaPeriod*time.Second))
+
+	testCases := []struct {
+		secret       string
+		passcode     string
+		expectedStep int64
+		expectedOK   bool
+	}{
+		{rfc6238Secret, "005924", 41152263, true},
+		{strings.ToLower(rfc6238Secret), "005924", 41152263, true},
+		{rfc6238Secret, previousPasscode, 41152262, true},
+		{rfc6238Secret, nextPasscode, 41152264, true},
+		{rfc6238Secret, oldPasscode, 0, false},
+		{rfc6238Secret, "", 0, false},
+		{rfc6238Secret, "5924", 0, false},
+		{"invalid secret!", "005924", 0, false},
+	}
+	for _, tc := range testCases {
+		step, ok := validateTOTP(tc.secret, tc.passcode, now)
+		assert.Equal(t, tc.expectedOK, ok)
+		assert.Equal(t, tc.expectedStep, step)
+	}
+}
+
+func TestMatchTFARecoveryCode(t *testing.T) {
+	hashes, err := hashTFARecoveryCodes([]string{"code1", "code2"})
+	require.NoError(t, err)
+	assert.NotContains(t, hashes, "code1")
+
+	hash, ok := matchTFARecoveryCode(hashes, "code2")
+	assert.True(t, ok)
+	assert.Equal(t, hashes[1], hash)
+
+	_, ok = matchTFARecoveryCode(hashes, "code3")
+	assert.False(t, ok)
+	_, ok = matchTFARecoveryCode(nil, "code1")
+	assert.False(t, ok)
+}
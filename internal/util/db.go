@@ -15,10 +15,20 @@ import (
 	"github.com/spf13/viper"
 )
 
+const (
+	// dbListenPauseOnError represents the time DBListen waits before trying
+	// to listen again after an error.
+	dbListenPauseOnError = 10 * time.Second
+)
+
 var (
 	// ErrDBInsufficientPrivilege indicates that the user does not have the
 	// required privilege to perform the operation.
 	ErrDBInsufficientPrivilege = errors.New("ERROR: insufficient_privilege (SQLSTATE 42501)")
+
+	// ErrDBNoDataFound indicates that the item the operation was meant to be
+	// performed on was not found.
+	ErrDBNoDataFound = errors.New("ERROR: no_data_found (SQLSTATE P0002)")
 )
 
 // SetupDB creates a database connection pool using the configuration provided.
@@ -73,6 +83,44 @@ func DBTransact(ctx context.Context, db hub.DB, txFunc func(pgx.Tx) error) (err
 	return err
 }
 
+// DBListen listens for notifications on the database channel provided until
+// the context provided is done, calling onNotification every time one is
+// received. When something goes wrong (i.e. the connection is lost), it waits
+// a bit and starts listening again on a new connection.
+func DBListen(ctx context.Context, db hub.DB, channel string, onNotification func()) {
+	for {
+		err := dbListen(ctx, db, channel, onNotification)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Error().Err(err).Str("channel", channel).Msg("error listening to notifications channel")
+		select {
+		case <-time.After(dbListenPauseOnError):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// dbListen acquires a database connection and listens for notifications on
+// the channel provided until an error occurs.
+func dbListen(ctx context.Context, db hub.DB, channel string, onNotification func()) error {
+	conn, err := db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	if _, err := conn.Exec(ctx, "listen "+channel); err != nil {
+		return err
+	}
+	for {
+		if _, err := conn.Conn().WaitForNotification(ctx); err != nil {
+			return err
+		}
+		onNotification()
+	}
+}
+
 // DBQueryJSON is a helper that executes the query provided and returns a bytes
 // slice containing the json data returned from the database.
 func DBQueryJSON(ctx context.Context, db hub.DB, query string, args ...interface{}) ([]byte, error) {
@@ -0,0 +1,25 @@
+package util
+
+import (
+	"errors"
+
+	"github.com/artifacthub/hub/internal/encryption"
+	"github.com/spf13/viper"
+)
+
+// SetupEncrypter creates a new encrypter based on the configuration provided.
+// When no encryption key has been configured, the encrypter returned won't
+// encrypt data.
+func SetupEncrypter(cfg *viper.Viper) (*encryption.Encrypter, error) {
+	var kp encryption.KeyProvider
+	keyProvider := cfg.GetString("encryption.keyProvider")
+	switch keyProvider {
+	case "", "local":
+		if key := cfg.GetString("encryption.key"); key != "" {
+			kp = encryption.NewLocalKeyProvider(key)
+		}
+	default:
+		return nil, errors.New("invalid key provider")
+	}
+	return encryption.NewEncrypter(kp), nil
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupEncrypter(t *testing.T) {
+	t.Parallel()
+
+	// Check a valid key provider must be provided
+	cfg := viper.New()
+	cfg.Set("encryption.keyProvider", "invalid")
+	enc, err := SetupEncrypter(cfg)
+	require.Error(t, err)
+	require.Nil(t, enc)
+
+	// Check encrypter is not enabled when no key is configured
+	cfg = viper.New()
+	enc, err = SetupEncrypter(cfg)
+	require.NoError(t, err)
+	require.False(t, enc.Enabled())
+
+	// Check encrypter was setup successfully
+	cfg = viper.New()
+	cfg.Set("encryption.keyProvider", "local")
+	cfg.Set("encryption.key", "key")
+	enc, err = SetupEncrypter(cfg)
+	require.NoError(t, err)
+	require.True(t, enc.Enabled())
+}
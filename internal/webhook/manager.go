@@ -4,35 +4,57 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"html/template"
 	"net/url"
 
+	"github.com/artifacthub/hub/internal/encryption"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/notification"
+	"github.com/artifacthub/hub/internal/subscription"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/satori/uuid"
 )
 
 const (
 	// Database queries
-	addWebhookDBQ                 = `select add_webhook($1::uuid, $2::text, $3::jsonb)`
-	deleteWebhookDBQ              = `select delete_webhook($1::uuid, $2::uuid)`
-	getWebhooksSubscribedToPkgDBQ = `select get_webhooks_subscribed_to_package($1::int, $2::uuid)`
-	getOrgWebhooksDBQ             = `select get_org_webhooks($1::uuid, $2::text)`
-	getUserWebhooksDBQ            = `select get_user_webhooks($1::uuid)`
-	getWebhookDBQ                 = `select get_webhook($1::uuid, $2::uuid)`
-	updateWebhookDBQ              = `select update_webhook($1::uuid, $2::jsonb)`
+	addWebhookDBQ                  = `select add_webhook($1::uuid, $2::text, $3::jsonb)`
+	deleteWebhookDBQ               = `select delete_webhook($1::uuid, $2::uuid)`
+	getWebhooksSubscribedToPkgDBQ  = `select get_webhooks_subscribed_to_package($1::int, $2::uuid)`
+	getWebhooksSubscribedToRepoDBQ = `select get_webhooks_subscribed_to_repository($1::int, $2::uuid)`
+	getOrgWebhooksDBQ              = `select get_org_webhooks($1::uuid, $2::text)`
+	getUserWebhooksDBQ             = `select get_user_webhooks($1::uuid)`
+	getWebhookDBQ                  = `select get_webhook($1::uuid, $2::uuid)`
+	getWebhookDeliveriesDBQ        = `select get_webhook_deliveries($1::uuid, $2::uuid)`
+	getWebhookSecretDBQ            = `select get_webhook_secret($1::uuid, $2::uuid)`
+	redeliverWebhookDBQ            = `select redeliver_webhook_notification($1::uuid, $2::uuid, $3::uuid)`
+	updateWebhookDBQ               = `select update_webhook($1::uuid, $2::jsonb)`
 )
 
 // Manager provides an API to manage webhooks.
 type Manager struct {
-	db hub.DB
+	db  hub.DB
+	enc hub.Encrypter
 }
 
 // NewManager creates a new Manager instance.
-func NewManager(db hub.DB) *Manager {
-	return &Manager{
+func NewManager(db hub.DB, opts ...func(m *Manager)) *Manager {
+	m := &Manager{
 		db: db,
 	}
+	for _, o := range opts {
+		o(m)
+	}
+	if m.enc == nil {
+		m.enc = encryption.NewEncrypter(nil)
+	}
+	return m
+}
+
+// WithEncrypter allows providing the encrypter used to encrypt the webhooks
+// secrets before storing them in the database.
+func WithEncrypter(enc hub.Encrypter) func(m *Manager) {
+	return func(m *Manager) {
+		m.enc = enc
+	}
 }
 
 // Add adds the provided webhook to the database.
@@ -50,22 +72,29 @@ func (m *Manager) Add(ctx context.Context, orgName string, wh *hub.Webhook) erro
 	if err != nil || u.Scheme == "" || u.Host == "" {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid url")
 	}
-	if _, err := template.New("").Parse(wh.Template); err != nil {
+	if !isValidKind(wh.Kind) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid kind")
+	}
+	if encryption.IsEncrypted(wh.Secret) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid secret")
+	}
+	if _, err := notification.ParseTemplate(wh.Template); err != nil {
 		return fmt.Errorf("%w: %s %s", hub.ErrInvalidInput, "invalid template", err)
 	}
 	if len(wh.EventKinds) == 0 {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "no event kinds provided")
 	}
-	if len(wh.Packages) == 0 {
-		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "no packages provided")
+	if err := validateSubscriptions(wh); err != nil {
+		return err
 	}
-	for _, p := range wh.Packages {
-		if _, err := uuid.FromString(p.PackageID); err != nil {
-			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
-		}
+	if err := subscription.ValidateFilters(wh.Filters); err != nil {
+		return err
 	}
 
 	// Add webhook to the database
+	if err := m.encryptSecret(ctx, wh); err != nil {
+		return err
+	}
 	whJSON, _ := json.Marshal(wh)
 	_, err = m.db.Exec(ctx, addWebhookDBQ, userID, orgName, whJSON)
 	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
@@ -91,6 +120,20 @@ func (m *Manager) Delete(ctx context.Context, webhookID string) error {
 	return err
 }
 
+// GetDeliveriesJSON returns the last deliveries attempts of the provided
+// webhook as a json array.
+func (m *Manager) GetDeliveriesJSON(ctx context.Context, webhookID string) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if _, err := uuid.FromString(webhookID); err != nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid webhook id")
+	}
+
+	// Get webhook deliveries from database
+	return util.DBQueryJSON(ctx, m.db, getWebhookDeliveriesDBQ, userID, webhookID)
+}
+
 // GetJSON returns the requested webhook as a json object.
 func (m *Manager) GetJSON(ctx context.Context, webhookID string) ([]byte, error) {
 	userID := ctx.Value(hub.UserIDKey).(string)
@@ -111,6 +154,30 @@ func (m *Manager) GetJSON(ctx context.Context, webhookID string) ([]byte, error)
 	return dataJSON, nil
 }
 
+// GetSecret returns the decrypted secret of the provided webhook. An empty
+// string is returned when the webhook has no secret.
+func (m *Manager) GetSecret(ctx context.Context, webhookID string) (string, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if _, err := uuid.FromString(webhookID); err != nil {
+		return "", fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid webhook id")
+	}
+
+	// Get webhook secret from database and decrypt it
+	var secret *string
+	if err := m.db.QueryRow(ctx, getWebhookSecretDBQ, userID, webhookID).Scan(&secret); err != nil {
+		if err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+			return "", hub.ErrInsufficientPrivilege
+		}
+		return "", err
+	}
+	if secret == nil {
+		return "", nil
+	}
+	return m.enc.Decrypt(ctx, *secret)
+}
+
 // GetOwnedByOrgJSON returns the webhooks belonging to the provided organization
 // as a json array.
 func (m *Manager) GetOwnedByOrgJSON(ctx context.Context, orgName string) ([]byte, error) {
@@ -139,11 +206,16 @@ func (m *Manager) GetSubscribedTo(ctx context.Context, e *hub.Event) ([]*hub.Web
 	var dataJSON []byte
 	var err error
 	switch e.EventKind {
-	case hub.NewRelease:
+	case hub.NewRelease, hub.SecurityAlert:
 		if _, err := uuid.FromString(e.PackageID); err != nil {
 			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
 		}
 		dataJSON, err = util.DBQueryJSON(ctx, m.db, getWebhooksSubscribedToPkgDBQ, e.EventKind, e.PackageID)
+	case hub.RepositoryTrackingErrors, hub.RepositoryOwnershipClaim:
+		if _, err := uuid.FromString(e.RepositoryID); err != nil {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+		}
+		dataJSON, err = util.DBQueryJSON(ctx, m.db, getWebhooksSubscribedToRepoDBQ, e.EventKind, e.RepositoryID)
 	default:
 		return nil, nil
 	}
@@ -157,6 +229,32 @@ func (m *Manager) GetSubscribedTo(ctx context.Context, e *hub.Event) ([]*hub.Web
 	return webhooks, err
 }
 
+// Redeliver schedules the notification of the provided webhook delivery to be
+// delivered again.
+func (m *Manager) Redeliver(ctx context.Context, webhookID, webhookDeliveryID string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if _, err := uuid.FromString(webhookID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid webhook id")
+	}
+	if _, err := uuid.FromString(webhookDeliveryID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid webhook delivery id")
+	}
+
+	// Schedule notification to be delivered again
+	_, err := m.db.Exec(ctx, redeliverWebhookDBQ, userID, webhookID, webhookDeliveryID)
+	if err != nil {
+		switch err.Error() {
+		case util.ErrDBInsufficientPrivilege.Error():
+			return hub.ErrInsufficientPrivilege
+		case util.ErrDBNoDataFound.Error():
+			return hub.ErrNotFound
+		}
+	}
+	return err
+}
+
 // Update updates the provided webhook in the database.
 func (m *Manager) Update(ctx context.Context, wh *hub.Webhook) error {
 	userID := ctx.Value(hub.UserIDKey).(string)
@@ -175,22 +273,29 @@ func (m *Manager) Update(ctx context.Context, wh *hub.Webhook) error {
 	if err != nil || u.Scheme == "" || u.Host == "" {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid url")
 	}
-	if _, err := template.New("").Parse(wh.Template); err != nil {
+	if !isValidKind(wh.Kind) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid kind")
+	}
+	if encryption.IsEncrypted(wh.Secret) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid secret")
+	}
+	if _, err := notification.ParseTemplate(wh.Template); err != nil {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid template")
 	}
 	if len(wh.EventKinds) == 0 {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "no event kinds provided")
 	}
-	if len(wh.Packages) == 0 {
-		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "no packages provided")
+	if err := validateSubscriptions(wh); err != nil {
+		return err
 	}
-	for _, p := range wh.Packages {
-		if _, err := uuid.FromString(p.PackageID); err != nil {
-			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
-		}
+	if err := subscription.ValidateFilters(wh.Filters); err != nil {
+		return err
 	}
 
 	// Update webhook in database
+	if err := m.encryptSecret(ctx, wh); err != nil {
+		return err
+	}
 	whJSON, _ := json.Marshal(wh)
 	_, err = m.db.Exec(ctx, updateWebhookDBQ, userID, whJSON)
 	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
@@ -198,3 +303,60 @@ func (m *Manager) Update(ctx context.Context, wh *hub.Webhook) error {
 	}
 	return err
 }
+
+// encryptSecret encrypts the secret of the webhook provided (if any), so that
+// it's not stored in plaintext.
+func (m *Manager) encryptSecret(ctx context.Context, wh *hub.Webhook) error {
+	if wh.Secret == "" {
+		return nil
+	}
+	secret, err := m.enc.Encrypt(ctx, wh.Secret)
+	if err != nil {
+		return err
+	}
+	wh.Secret = secret
+	return nil
+}
+
+// validateSubscriptions checks the webhook provided is subscribed to some
+// packages or repositories, depending on the event kinds selected.
+func validateSubscriptions(wh *hub.Webhook) error {
+	var pkgEvents, repoEvents bool
+	for _, kind := range wh.EventKinds {
+		switch kind {
+		case hub.NewRelease, hub.SecurityAlert:
+			pkgEvents = true
+		case hub.RepositoryTrackingErrors, hub.RepositoryOwnershipClaim:
+			repoEvents = true
+		default:
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid event kind")
+		}
+	}
+	if pkgEvents && len(wh.Packages) == 0 {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "no packages provided")
+	}
+	for _, p := range wh.Packages {
+		if _, err := uuid.FromString(p.PackageID); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+		}
+	}
+	if repoEvents && len(wh.Repositories) == 0 {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "no repositories provided")
+	}
+	for _, r := range wh.Repositories {
+		if _, err := uuid.FromString(r.RepositoryID); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+		}
+	}
+	return nil
+}
+
+// isValidKind checks if the webhook kind provided is supported.
+func isValidKind(kind hub.WebhookKind) bool {
+	switch kind {
+	case hub.GenericWebhook, hub.SlackWebhook, hub.MicrosoftTeamsWebhook, hub.DiscordWebhook:
+		return true
+	default:
+		return false
+	}
+}
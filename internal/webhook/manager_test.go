@@ -2,9 +2,11 @@ package webhook
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
+	"github.com/artifacthub/hub/internal/encryption"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/util"
@@ -64,6 +66,15 @@ func TestAdd(t *testing.T) {
 					URL:  "invalidurl",
 				},
 			},
+			{
+				"invalid kind",
+				"org1",
+				&hub.Webhook{
+					Name: "webhook",
+					URL:  "http://webhook1.url",
+					Kind: hub.WebhookKind(99),
+				},
+			},
 			{
 				"invalid template",
 				"org1",
@@ -102,6 +113,62 @@ func TestAdd(t *testing.T) {
 					},
 				},
 			},
+			{
+				"invalid event kind",
+				"org1",
+				&hub.Webhook{
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					EventKinds: []hub.EventKind{hub.EventKind(99)},
+				},
+			},
+			{
+				"no repositories provided",
+				"org1",
+				&hub.Webhook{
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					EventKinds: []hub.EventKind{hub.RepositoryTrackingErrors},
+				},
+			},
+			{
+				"invalid repository id",
+				"org1",
+				&hub.Webhook{
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					EventKinds: []hub.EventKind{hub.RepositoryTrackingErrors},
+					Repositories: []*hub.Repository{
+						{RepositoryID: ""},
+					},
+				},
+			},
+			{
+				"invalid version constraint",
+				"org1",
+				&hub.Webhook{
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					EventKinds: []hub.EventKind{hub.NewRelease},
+					Packages: []*hub.Package{
+						{PackageID: validUUID},
+					},
+					Filters: &hub.NotificationFilters{VersionConstraint: "invalid"},
+				},
+			},
+			{
+				"invalid secret",
+				"org1",
+				&hub.Webhook{
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					Secret:     "enc:v1:key:data",
+					EventKinds: []hub.EventKind{hub.NewRelease},
+					Packages: []*hub.Package{
+						{PackageID: validUUID},
+					},
+				},
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -155,6 +222,39 @@ func TestAdd(t *testing.T) {
 		assert.NoError(t, err)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("error encrypting secret", func(t *testing.T) {
+		t.Parallel()
+		whWithSecret := *wh
+		whWithSecret.Secret = "secret"
+		enc := &encryption.EncrypterMock{}
+		enc.On("Encrypt", ctx, "secret").Return("", tests.ErrFake)
+		m := NewManager(nil, WithEncrypter(enc))
+
+		err := m.Add(ctx, "orgName", &whWithSecret)
+		assert.Equal(t, tests.ErrFake, err)
+		enc.AssertExpectations(t)
+	})
+
+	t.Run("add webhook with secret succeeded: secret is encrypted", func(t *testing.T) {
+		t.Parallel()
+		whWithSecret := *wh
+		whWithSecret.Secret = "secret"
+		enc := &encryption.EncrypterMock{}
+		enc.On("Encrypt", ctx, "secret").Return("encryptedSecret", nil)
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, addWebhookDBQ, "userID", "orgName", mock.MatchedBy(func(whJSON []byte) bool {
+			var whStored *hub.Webhook
+			_ = json.Unmarshal(whJSON, &whStored)
+			return whStored.Secret == "encryptedSecret"
+		})).Return(nil)
+		m := NewManager(db, WithEncrypter(enc))
+
+		err := m.Add(ctx, "orgName", &whWithSecret)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+		enc.AssertExpectations(t)
+	})
 }
 
 func TestDelete(t *testing.T) {
@@ -216,6 +316,67 @@ func TestDelete(t *testing.T) {
 	})
 }
 
+func TestGetDeliveriesJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetDeliveriesJSON(context.Background(), validUUID)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		_, err := m.GetDeliveriesJSON(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getWebhookDeliveriesDBQ, "userID", validUUID).Return(nil, tc.dbErr)
+				m := NewManager(db)
+
+				dataJSON, err := m.GetDeliveriesJSON(ctx, validUUID)
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("webhook deliveries data returned successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getWebhookDeliveriesDBQ, "userID", validUUID).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDeliveriesJSON(ctx, validUUID)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestGetJSON(t *testing.T) {
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
 
@@ -356,6 +517,99 @@ func TestGetOwnedByUserJSON(t *testing.T) {
 	})
 }
 
+func TestGetSecret(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetSecret(context.Background(), validUUID)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		_, err := m.GetSecret(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getWebhookSecretDBQ, "userID", validUUID).Return(nil, tc.dbErr)
+				m := NewManager(db)
+
+				secret, err := m.GetSecret(ctx, validUUID)
+				assert.Equal(t, tc.expectedError, err)
+				assert.Empty(t, secret)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("webhook has no secret", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getWebhookSecretDBQ, "userID", validUUID).Return((*string)(nil), nil)
+		m := NewManager(db)
+
+		secret, err := m.GetSecret(ctx, validUUID)
+		assert.NoError(t, err)
+		assert.Empty(t, secret)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("error decrypting secret", func(t *testing.T) {
+		t.Parallel()
+		encryptedSecret := "encryptedSecret"
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getWebhookSecretDBQ, "userID", validUUID).Return(&encryptedSecret, nil)
+		enc := &encryption.EncrypterMock{}
+		enc.On("Decrypt", ctx, "encryptedSecret").Return("", tests.ErrFake)
+		m := NewManager(db, WithEncrypter(enc))
+
+		secret, err := m.GetSecret(ctx, validUUID)
+		assert.Equal(t, tests.ErrFake, err)
+		assert.Empty(t, secret)
+		db.AssertExpectations(t)
+		enc.AssertExpectations(t)
+	})
+
+	t.Run("webhook secret returned successfully", func(t *testing.T) {
+		t.Parallel()
+		encryptedSecret := "encryptedSecret"
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getWebhookSecretDBQ, "userID", validUUID).Return(&encryptedSecret, nil)
+		enc := &encryption.EncrypterMock{}
+		enc.On("Decrypt", ctx, "encryptedSecret").Return("secret", nil)
+		m := NewManager(db, WithEncrypter(enc))
+
+		secret, err := m.GetSecret(ctx, validUUID)
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", secret)
+		db.AssertExpectations(t)
+		enc.AssertExpectations(t)
+	})
+}
+
 func TestGetSubscribedTo(t *testing.T) {
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
 	e := &hub.Event{
@@ -375,6 +629,13 @@ func TestGetSubscribedTo(t *testing.T) {
 					PackageID: "invalid",
 				},
 			},
+			{
+				"invalid repository id",
+				&hub.Event{
+					EventKind:    hub.RepositoryTrackingErrors,
+					RepositoryID: "invalid",
+				},
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -429,6 +690,119 @@ func TestGetSubscribedTo(t *testing.T) {
 		assert.Equal(t, "http://webhook2.url", w[1].URL)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("webhooks subscribed to repository returned successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getWebhooksSubscribedToRepoDBQ, hub.RepositoryTrackingErrors, validUUID).Return([]byte(`
+		[{
+			"webhook_id": "00000000-0000-0000-0000-000000000001",
+			"name": "webhook1",
+			"url": "http://webhook1.url",
+			"repositories": [{
+				"repository_id": "00000000-0000-0000-0000-000000000001",
+				"name": "repo1"
+			}]
+		}]
+		`), nil)
+		m := NewManager(db)
+
+		w, err := m.GetSubscribedTo(ctx, &hub.Event{
+			EventKind:    hub.RepositoryTrackingErrors,
+			RepositoryID: validUUID,
+		})
+		require.NoError(t, err)
+		require.Len(t, w, 1)
+		assert.Equal(t, "00000000-0000-0000-0000-000000000001", w[0].WebhookID)
+		require.Len(t, w[0].Repositories, 1)
+		assert.Equal(t, "repo1", w[0].Repositories[0].Name)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestRedeliver(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_ = m.Redeliver(context.Background(), validUUID, validUUID)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg            string
+			webhookID         string
+			webhookDeliveryID string
+		}{
+			{
+				"invalid webhook id",
+				"",
+				validUUID,
+			},
+			{
+				"invalid webhook delivery id",
+				validUUID,
+				"",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(nil)
+				err := m.Redeliver(ctx, tc.webhookID, tc.webhookDeliveryID)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+			{
+				util.ErrDBNoDataFound,
+				hub.ErrNotFound,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, redeliverWebhookDBQ, "userID", validUUID, validUUID).Return(tc.dbErr)
+				m := NewManager(db)
+
+				err := m.Redeliver(ctx, validUUID, validUUID)
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("redeliver succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, redeliverWebhookDBQ, "userID", validUUID, validUUID).Return(nil)
+		m := NewManager(db)
+
+		err := m.Redeliver(ctx, validUUID, validUUID)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
 }
 
 func TestUpdate(t *testing.T) {
@@ -486,6 +860,15 @@ func TestUpdate(t *testing.T) {
 					URL:       "invalidurl",
 				},
 			},
+			{
+				"invalid kind",
+				&hub.Webhook{
+					WebhookID: validUUID,
+					Name:      "webhook",
+					URL:       "http://webhook1.url",
+					Kind:      hub.WebhookKind(99),
+				},
+			},
 			{
 				"invalid template",
 				&hub.Webhook{
@@ -524,6 +907,62 @@ func TestUpdate(t *testing.T) {
 					},
 				},
 			},
+			{
+				"invalid event kind",
+				&hub.Webhook{
+					WebhookID:  validUUID,
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					EventKinds: []hub.EventKind{hub.EventKind(99)},
+				},
+			},
+			{
+				"no repositories provided",
+				&hub.Webhook{
+					WebhookID:  validUUID,
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					EventKinds: []hub.EventKind{hub.RepositoryOwnershipClaim},
+				},
+			},
+			{
+				"invalid repository id",
+				&hub.Webhook{
+					WebhookID:  validUUID,
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					EventKinds: []hub.EventKind{hub.RepositoryOwnershipClaim},
+					Repositories: []*hub.Repository{
+						{RepositoryID: ""},
+					},
+				},
+			},
+			{
+				"invalid version constraint",
+				&hub.Webhook{
+					WebhookID:  validUUID,
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					EventKinds: []hub.EventKind{hub.NewRelease},
+					Packages: []*hub.Package{
+						{PackageID: validUUID},
+					},
+					Filters: &hub.NotificationFilters{VersionConstraint: "invalid"},
+				},
+			},
+			{
+				"invalid secret",
+				&hub.Webhook{
+					WebhookID:  validUUID,
+					Name:       "webhook",
+					URL:        "http://webhook1.url",
+					Secret:     "enc:v1:key:data",
+					EventKinds: []hub.EventKind{hub.NewRelease},
+					Packages: []*hub.Package{
+						{PackageID: validUUID},
+					},
+				},
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -577,4 +1016,24 @@ func TestUpdate(t *testing.T) {
 		assert.NoError(t, err)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("update webhook succeeded: new secret is encrypted", func(t *testing.T) {
+		t.Parallel()
+		whWithSecret := *wh
+		whWithSecret.Secret = "secret"
+		enc := &encryption.EncrypterMock{}
+		enc.On("Encrypt", ctx, "secret").Return("encryptedSecret", nil)
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, updateWebhookDBQ, "userID", mock.MatchedBy(func(whJSON []byte) bool {
+			var whStored *hub.Webhook
+			_ = json.Unmarshal(whJSON, &whStored)
+			return whStored.Secret == "encryptedSecret"
+		})).Return(nil)
+		m := NewManager(db, WithEncrypter(enc))
+
+		err := m.Update(ctx, &whWithSecret)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+		enc.AssertExpectations(t)
+	})
 }
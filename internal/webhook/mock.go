@@ -45,6 +45,13 @@ func (m *ManagerMock) GetSubscribedTo(ctx context.Context, e *hub.Event) ([]*hub
 	return data, args.Error(1)
 }
 
+// GetDeliveriesJSON implements the WebhookManager interface.
+func (m *ManagerMock) GetDeliveriesJSON(ctx context.Context, webhookID string) ([]byte, error) {
+	args := m.Called(ctx, webhookID)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // GetJSON implements the WebhookManager interface.
 func (m *ManagerMock) GetJSON(ctx context.Context, webhookID string) ([]byte, error) {
 	args := m.Called(ctx, webhookID)
@@ -52,6 +59,18 @@ func (m *ManagerMock) GetJSON(ctx context.Context, webhookID string) ([]byte, er
 	return data, args.Error(1)
 }
 
+// GetSecret implements the WebhookManager interface.
+func (m *ManagerMock) GetSecret(ctx context.Context, webhookID string) (string, error) {
+	args := m.Called(ctx, webhookID)
+	return args.String(0), args.Error(1)
+}
+
+// Redeliver implements the WebhookManager interface.
+func (m *ManagerMock) Redeliver(ctx context.Context, webhookID, webhookDeliveryID string) error {
+	args := m.Called(ctx, webhookID, webhookDeliveryID)
+	return args.Error(0)
+}
+
 // Update implements the WebhookManager interface.
 func (m *ManagerMock) Update(ctx context.Context, wh *hub.Webhook) error {
 	args := m.Called(ctx, wh)
@@ -299,6 +299,7 @@ export const API = {
       displayName: 'display_name',
       authUser: 'auth_user',
       authPass: 'auth_pass',
+      clearAuthPass: 'clear_auth_pass',
       scannerDisabled: 'scanner_disabled',
     });
     return apiFetch(`${API_BASE_URL}/repositories${getUrlContext(fromOrgName)}/${repository.name}`, {
@@ -488,7 +489,11 @@ export const API = {
   },
 
   addWebhook: (webhook: Webhook, fromOrgName?: string): Promise<null | string> => {
-    const formattedWebhook = renameKeysInObject(webhook, { contentType: 'content_type', eventKinds: 'event_kinds' });
+    const formattedWebhook = renameKeysInObject(webhook, {
+      contentType: 'content_type',
+      eventKinds: 'event_kinds',
+      legacySecretHeader: 'legacy_secret_header',
+    });
     const formattedPackages = webhook.packages.map((packageItem: Package) => ({
       package_id: packageItem.packageId,
     }));
@@ -511,7 +516,11 @@ export const API = {
   },
 
   updateWebhook: (webhook: Webhook, fromOrgName?: string): Promise<null | string> => {
-    const formattedWebhook = renameKeysInObject(webhook, { contentType: 'content_type', eventKinds: 'event_kinds' });
+    const formattedWebhook = renameKeysInObject(webhook, {
+      contentType: 'content_type',
+      eventKinds: 'event_kinds',
+      legacySecretHeader: 'legacy_secret_header',
+    });
     const formattedPackages = webhook.packages.map((packageItem: Package) => ({
       package_id: packageItem.packageId,
     }));
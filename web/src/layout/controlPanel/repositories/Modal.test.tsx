@@ -576,7 +576,7 @@ describe('Repository Modal - repositories section', () => {
         mocked(API).saveImage.mockResolvedValue({ imageId: '123' });
         mocked(API).updateRepository.mockResolvedValue(null);
         const { getByTestId, getByText } = render(
-          <Modal {...defaultProps} repository={{ ...repoMock, authUser: 'username', authPass: 'pass123' }} />
+          <Modal {...defaultProps} repository={{ ...repoMock, authUser: 'username', authPassSet: true }} />
         );
 
         expect(getByText('Update repository')).toBeInTheDocument();
@@ -598,6 +598,37 @@ describe('Repository Modal - repositories section', () => {
 
         expect(onSuccessMock).toHaveBeenCalledTimes(1);
       });
+
+      it('calls update repository clearing the credentials', async () => {
+        (window as any).config = {
+          allowPrivateRepositories: 'true',
+        };
+
+        mocked(API).checkAvailability.mockResolvedValue(true);
+        mocked(API).saveImage.mockResolvedValue({ imageId: '123' });
+        mocked(API).updateRepository.mockResolvedValue(null);
+        const { getByTestId } = render(
+          <Modal {...defaultProps} repository={{ ...repoMock, authUser: 'username', authPassSet: true }} />
+        );
+
+        fireEvent.change(getByTestId('authUserInput'), { target: { value: '' } });
+        fireEvent.click(getByTestId('repoBtn'));
+
+        await waitFor(() => {
+          expect(API.updateRepository).toHaveBeenCalledTimes(1);
+          expect(API.updateRepository).toHaveBeenCalledWith(
+            {
+              ...repoMock,
+              authUser: '',
+              authPass: '',
+              clearAuthPass: true,
+            },
+            undefined
+          );
+        });
+
+        expect(onSuccessMock).toHaveBeenCalledTimes(1);
+      });
     });
   });
 });
@@ -123,6 +123,8 @@ const RepositoryModal = (props: Props) => {
     return validateAllFields().then((isValid: boolean) => {
       if (isValid) {
         const formData = new FormData(form);
+        const authUser = formData.get('authUser') as string;
+        const authPass = formData.get('authPass') as string;
         repository = {
           kind: selectedKind,
           name: !isUndefined(props.repository) ? props.repository.name : (formData.get('name') as string),
@@ -131,9 +133,14 @@ const RepositoryModal = (props: Props) => {
           displayName: formData.get('displayName') as string,
           disabled: isDisabled,
           scannerDisabled: isScannerDisabled,
-          authUser: formData.get('authUser') as string,
-          authPass: formData.get('authPass') as string,
+          authUser,
+          authPass,
         };
+        // The current password is kept when it is not provided, unless both
+        // credentials fields have been emptied
+        if (props.repository && props.repository.authPassSet && authUser === '' && authPass === '') {
+          repository.clearAuthPass = true;
+        }
       }
       setIsValidated(true);
       return { isValid, repository };
@@ -254,9 +261,9 @@ const RepositoryModal = (props: Props) => {
       case RepositoryKind.Helm:
         return undefined;
       case RepositoryKind.OLM:
-        return `((https://(github|gitlab).com/|${OCI_PREFIX})[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+)/?(.*)`;
+        return `((https://[A-Za-z0-9.-]+(:[0-9]+)?/|${OCI_PREFIX})[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+)/?(.*)`;
       default:
-        return '(https://(github|gitlab).com/[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+)/?(.*)';
+        return '(https://[A-Za-z0-9.-]+(:[0-9]+)?/[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+)/?(.*)';
     }
   };
 
@@ -462,7 +469,7 @@ const RepositoryModal = (props: Props) => {
                   label="Password"
                   name="authPass"
                   autoComplete="off"
-                  value={props.repository ? props.repository.authPass || '' : ''}
+                  placeholder={props.repository && props.repository.authPassSet ? 'Leave empty to keep current' : ''}
                   visiblePassword
                 />
               </div>
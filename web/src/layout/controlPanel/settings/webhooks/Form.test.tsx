@@ -105,7 +105,7 @@ describe('WebhookForm', () => {
       expect(getByText(/X-ArtifactHub-Secret/i)).toBeInTheDocument();
       expect(getByTestId('secretInput')).toBeInTheDocument();
       expect(getByText('Secret')).toBeInTheDocument();
-      expect(getByTestId('secretInput')).toHaveValue(mockWebhook.secret!);
+      expect(getByTestId('secretInput')).toHaveValue('');
 
       expect(getByText('Active')).toBeInTheDocument();
       expect(getByTestId('activeCheckbox')).toBeInTheDocument();
@@ -434,6 +434,7 @@ describe('WebhookForm', () => {
             active: true,
             description: '',
             secret: '',
+            legacySecretHeader: false,
             eventKinds: [0],
             packages: [mockSearch.data.packages![0]],
           },
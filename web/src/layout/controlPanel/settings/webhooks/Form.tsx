@@ -79,6 +79,11 @@ const WebhookForm = (props: Props) => {
     !isUndefined(props.webhook) ? props.webhook.eventKinds : [EventKind.NewPackageRelease]
   );
   const [isActive, setIsActive] = useState<boolean>(!isUndefined(props.webhook) ? props.webhook.active : true);
+  const [legacySecretHeader, setLegacySecretHeader] = useState<boolean>(
+    !isUndefined(props.webhook) && !isUndefined(props.webhook.legacySecretHeader)
+      ? props.webhook.legacySecretHeader
+      : false
+  );
   const [contentType, setContentType] = useState<string>(
     !isUndefined(props.webhook) && props.webhook.contentType ? props.webhook.contentType : ''
   );
@@ -183,6 +188,7 @@ const WebhookForm = (props: Props) => {
         name: formData.get('name') as string,
         url: formData.get('url') as string,
         secret: formData.get('secret') as string,
+        legacySecretHeader: legacySecretHeader,
         description: formData.get('description') as string,
         eventKinds: eventKinds,
         active: isActive,
@@ -358,18 +364,53 @@ const WebhookForm = (props: Props) => {
             </label>
             <div>
               <small className="form-text text-muted mb-2 mt-0">
-                If you provide a secret, we'll send it to you in the{' '}
-                <span className="font-weight-bold">X-ArtifactHub-Secret</span> header on each request. This will allow
-                you to validate that the request comes from ArtifactHub.
+                If you provide a secret, we'll sign each request and send you the signature in the{' '}
+                <span className="font-weight-bold">X-ArtifactHub-Signature</span> header, using the format{' '}
+                <span className="font-weight-bold">t=timestamp,v1=signature</span>. The signature is the HMAC-SHA256
+                of the timestamp and the payload joined by a dot, using the secret as key. This will allow you to
+                validate that the request comes from ArtifactHub and reject replayed requests.
               </small>
             </div>
             <div className="form-row">
               <div className="col-md-8">
-                <InputField type="text" name="secret" value={!isUndefined(props.webhook) ? props.webhook.secret : ''} />
+                <InputField
+                  type="text"
+                  name="secret"
+                  value=""
+                  placeholder={
+                    !isUndefined(props.webhook) && props.webhook.secretSet ? 'Secret set (leave empty to keep it)' : ''
+                  }
+                />
               </div>
             </div>
           </div>
 
+          <div className="mb-3">
+            <div className="custom-control custom-switch pl-0">
+              <input
+                data-testid="legacySecretHeaderCheckbox"
+                id="legacySecretHeader"
+                type="checkbox"
+                className={`custom-control-input ${styles.checkbox}`}
+                value="true"
+                onChange={() => setLegacySecretHeader(!legacySecretHeader)}
+                checked={legacySecretHeader}
+              />
+              <label
+                htmlFor="legacySecretHeader"
+                className={`custom-control-label font-weight-bold ${styles.label} ${styles.customControlRightLabel}`}
+              >
+                Send secret in legacy header
+              </label>
+            </div>
+
+            <small className="form-text text-muted mt-2">
+              When enabled, the secret will also be sent as is in the{' '}
+              <span className="font-weight-bold">X-ArtifactHub-Secret</span> header. Please use the signature instead
+              when possible.
+            </small>
+          </div>
+
           <div className="mb-3">
             <div className="custom-control custom-switch pl-0">
               <input
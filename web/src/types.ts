@@ -28,6 +28,8 @@ export interface Repository {
   private?: boolean;
   authUser?: string | null;
   authPass?: string | null;
+  authPassSet?: boolean;
+  clearAuthPass?: boolean;
   disabled?: boolean;
   scannerDisabled?: boolean;
 }
@@ -339,6 +341,8 @@ export interface Webhook extends TestWebhook {
   name: string;
   description?: string;
   secret?: string;
+  secretSet?: boolean;
+  legacySecretHeader?: boolean;
   active: boolean;
   packages: Package[];
   lastNotifications?: null | WebhookNotification[];
@@ -418,6 +422,8 @@ export enum AuthorizerAction {
   DeleteOrganizationMember = 'deleteOrganizationMember',
   DeleteOrganizationRepository = 'deleteOrganizationRepository',
   GetAuthorizationPolicy = 'getAuthorizationPolicy',
+  ManageOrganizationAPIKeys = 'manageOrganizationAPIKeys',
+  TrackOrganizationRepository = 'trackOrganizationRepository',
   TransferOrganizationRepository = 'transferOrganizationRepository',
   UpdateAuthorizationPolicy = 'updateAuthorizationPolicy',
   UpdateOrganization = 'updateOrganization',